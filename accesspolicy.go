@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Default access policies.
+const (
+	accessPolicyAllow = "allow"
+	accessPolicyDeny  = "deny"
+)
+
+// accessPolicy declares operations permitted on containers. Containers listed
+// in upload or download lists are permitted exactly these operations, denied
+// ones aren't permitted anything, others get the default policy.
+type accessPolicy struct {
+	ctx          context.Context
+	defaultAllow bool
+	containers   map[string][]string
+}
+
+func parseContainerIDs(list []string) error {
+	for _, s := range list {
+		if err := new(cid.ID).DecodeString(s); err != nil {
+			return fmt.Errorf("invalid container ID %s: %w", s, err)
+		}
+	}
+	return nil
+}
+
+// newAccessPolicy returns nil if the policy allows everything.
+func (a *app) newAccessPolicy(ctx context.Context) (*accessPolicy, error) {
+	p := &accessPolicy{
+		ctx:        ctx,
+		containers: make(map[string][]string),
+	}
+
+	switch def := a.cfg.GetString(cfgAccessPolicyDefault); def {
+	case accessPolicyAllow:
+		p.defaultAllow = true
+	case accessPolicyDeny:
+	default:
+		return nil, fmt.Errorf("unknown default access policy %q", def)
+	}
+
+	for section, operation := range map[string]string{
+		cfgAccessPolicyUpload:   operationUpload,
+		cfgAccessPolicyDownload: operationDownload,
+		cfgAccessPolicyDeny:     "",
+	} {
+		list := a.cfg.GetStringSlice(section)
+		if err := parseContainerIDs(list); err != nil {
+			return nil, fmt.Errorf("invalid access policy %s: %w", section, err)
+		}
+		for _, cnr := range list {
+			if operation != "" {
+				p.containers[cnr] = append(p.containers[cnr], operation)
+			} else if _, ok := p.containers[cnr]; !ok {
+				p.containers[cnr] = nil
+			}
+		}
+	}
+
+	if p.defaultAllow && len(p.containers) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+func (p *accessPolicy) permitted(operation string, cnrID *cid.ID) bool {
+	operations, ok := p.containers[cnrID.String()]
+	if !ok {
+		return p.defaultAllow
+	}
+	return containsString(operations, operation)
+}
+
+// containerAccess rejects requests to containers the route operation isn't
+// permitted on by the access policy with 403 status. Container names are
+// resolved, so the policy can't be bypassed with them. Copies and moves are
+// checked against both source and target containers.
+func (a *app) containerAccess(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.accessPolicy == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		for _, op := range requestContainers(route, c) {
+			if op.container == "" {
+				// Routes without container (e.g. container listing).
+				continue
+			}
+
+			cnrID, err := a.requestContainer(a.accessPolicy.ctx, c, op.container)
+			if err != nil {
+				a.log.Error("wrong container id", zap.Error(err))
+				response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+				return
+			}
+
+			if !a.accessPolicy.permitted(op.operation, cnrID) {
+				a.log.Warn("container access denied by policy",
+					zap.String("operation", op.operation), zap.Stringer("cid", cnrID))
+				response.Error(c, "access to container is denied", fasthttp.StatusForbidden)
+				return
+			}
+		}
+
+		h(c)
+	}
+}
+
+// requestContainer returns the container of the request, its name is resolved
+// once per request. Other containers (e.g. the target of copies) are resolved
+// every time.
+func (a *app) requestContainer(ctx context.Context, c *fasthttp.RequestCtx, scid string) (*cid.ID, error) {
+	if path, _ := c.UserValue("cid").(string); scid != path {
+		return utils.GetContainerID(utils.RequestContext(c, ctx), scid, a.resolver)
+	}
+	if cnrID := utils.RequestContainer(c); cnrID != nil {
+		return cnrID, nil
+	}
+
+	cnrID, err := utils.GetContainerID(utils.RequestContext(c, ctx), scid, a.resolver)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetRequestContainer(c, cnrID)
+	return cnrID, nil
+}
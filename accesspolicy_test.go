@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func testContainerID(name string) string {
+	var id cid.ID
+	id.SetSHA256(sha256.Sum256([]byte(name)))
+	return id.EncodeToString()
+}
+
+func TestContainerAccess(t *testing.T) {
+	public, private, readOnly, other := testContainerID("public"), testContainerID("private"),
+		testContainerID("read-only"), testContainerID("other")
+
+	h := func(c *fasthttp.RequestCtx) { c.SetStatusCode(fasthttp.StatusOK) }
+
+	newApp := func(def string) *app {
+		v := viper.New()
+		v.Set(cfgAccessPolicyDefault, def)
+		v.Set(cfgAccessPolicyUpload, []string{public})
+		v.Set(cfgAccessPolicyDownload, []string{public, readOnly})
+		v.Set(cfgAccessPolicyDeny, []string{private})
+		a := &app{cfg: v, log: zap.NewNop()}
+		var err error
+		a.accessPolicy, err = a.newAccessPolicy(context.Background())
+		require.NoError(t, err)
+		return a
+	}
+
+	serve := func(a *app, route, cnr string) int {
+		var c fasthttp.RequestCtx
+		c.SetUserValue("cid", cnr)
+		a.containerAccess(route, h)(&c)
+		return c.Response.StatusCode()
+	}
+
+	for _, tc := range []struct {
+		def, route, cnr string
+		status          int
+	}{
+		{accessPolicyAllow, routeUpload, public, fasthttp.StatusOK},
+		{accessPolicyAllow, routeGet, public, fasthttp.StatusOK},
+		{accessPolicyAllow, routeZip, readOnly, fasthttp.StatusOK},
+		{accessPolicyAllow, routeUpload, readOnly, fasthttp.StatusForbidden},
+		{accessPolicyAllow, routeGet, private, fasthttp.StatusForbidden},
+		{accessPolicyAllow, routeUpload, other, fasthttp.StatusOK},
+		{accessPolicyDeny, routeUpload, public, fasthttp.StatusOK},
+		{accessPolicyDeny, routeGetByAttribute, readOnly, fasthttp.StatusOK},
+		{accessPolicyDeny, routeGet, other, fasthttp.StatusForbidden},
+		{accessPolicyDeny, routeUpload, other, fasthttp.StatusForbidden},
+		{accessPolicyDeny, routeGet, "unresolvable-name", fasthttp.StatusBadRequest},
+		{accessPolicyDeny, routeContainers, "", fasthttp.StatusOK},
+	} {
+		require.Equal(t, tc.status, serve(newApp(tc.def), tc.route, tc.cnr), "%s %s %s", tc.def, tc.route, tc.cnr)
+	}
+
+	t.Run("allow everything", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		a.cfg.Set(cfgAccessPolicyDefault, accessPolicyAllow)
+		p, err := a.newAccessPolicy(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, p)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		a.cfg.Set(cfgAccessPolicyDefault, "maybe")
+		_, err := a.newAccessPolicy(context.Background())
+		require.Error(t, err)
+
+		a.cfg.Set(cfgAccessPolicyDefault, accessPolicyDeny)
+		a.cfg.Set(cfgAccessPolicyUpload, []string{"not-an-id"})
+		_, err = a.newAccessPolicy(context.Background())
+		require.Error(t, err)
+	})
+}
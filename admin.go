@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/fasthttp/router"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// attachAdmin adds admin API routes protected by the token.
+func (a *app) attachAdmin(ctx context.Context, r *router.Router, token string) {
+	r.POST("/admin/reload_key", a.adminAuth(token, func(c *fasthttp.RequestCtx) {
+		if err := a.ReloadKey(ctx); err != nil {
+			a.log.Error("could not reload gateway key", zap.Error(err))
+			response.Error(c, "could not reload gateway key: "+err.Error(), fasthttp.StatusInternalServerError)
+			return
+		}
+		a.log.Info("gateway key reloaded")
+		c.SetStatusCode(fasthttp.StatusOK)
+	}))
+
+	r.GET("/admin/peers", a.adminAuth(token, a.peerMonitor(ctx).handler))
+
+	r.GET("/admin/balance", a.adminAuth(token, a.balanceMonitor(ctx).handler(ctx)))
+
+	r.GET("/admin/config", a.adminAuth(token, a.configHandler))
+	r.PATCH("/admin/config", a.adminAuth(token, a.updateConfigHandler))
+
+	a.attachDrain(r, token)
+}
+
+// adminAuth requires the admin token in Authorization header.
+func (a *app) adminAuth(token string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	expected := []byte(bearerAuthPrefix + token)
+
+	return func(c *fasthttp.RequestCtx) {
+		if subtle.ConstantTimeCompare(c.Request.Header.Peek(fasthttp.HeaderAuthorization), expected) != 1 {
+			a.log.Warn("admin API access denied", zap.Stringer("remote", c.RemoteIP()))
+			response.Error(c, "access denied", fasthttp.StatusForbidden)
+			return
+		}
+
+		h(c)
+	}
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-http-gw/uploader"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// brokenScanner fails every scan, it replaces misconfigured scanner to keep
+// fail-closed containers closed.
+type brokenScanner struct{ err error }
+
+func (s brokenScanner) Scan(context.Context, io.Reader, int64) (string, error) {
+	return "", s.err
+}
+
+// newScanSettings returns the settings of antivirus checks of uploads,
+// scanning is disabled if there is no engine configured.
+func (a *app) newScanSettings() uploader.ScanSettings {
+	engine := a.cfg.GetString(cfgAntivirusEngine)
+	if engine == "" {
+		return uploader.ScanSettings{}
+	}
+
+	scanner, err := uploader.NewScanner(engine, a.cfg.GetString(cfgAntivirusAddress), a.cfg.GetDuration(cfgAntivirusTimeout))
+	if err != nil {
+		a.log.Error("invalid antivirus configuration, uploads can't be scanned", zap.Error(err))
+		scanner = brokenScanner{err: err}
+	}
+
+	a.log.Info("uploads are scanned by antivirus", zap.String("engine", engine),
+		zap.String("address", a.cfg.GetString(cfgAntivirusAddress)))
+	return uploader.ScanSettings{
+		Scanner:    scanner,
+		FailOpen:   a.cfg.GetBool(cfgAntivirusFailOpen),
+		Containers: fetchAntivirusContainers(a.cfg),
+	}
+}
+
+// fetchAntivirusContainers reads the containers to be scanned with their
+// fail-open policies from antivirus.containers section, the global policy is
+// used if the container doesn't set it.
+func fetchAntivirusContainers(v *viper.Viper) map[string]bool {
+	containers := make(map[string]bool)
+
+	for i := 0; ; i++ {
+		key := cfgAntivirusContainers + "." + strconv.Itoa(i) + "."
+
+		cnr := v.GetString(key + "container")
+		if cnr == "" {
+			break
+		}
+
+		failOpen := v.GetBool(cfgAntivirusFailOpen)
+		if v.IsSet(key + "fail_open") {
+			failOpen = v.GetBool(key + "fail_open")
+		}
+		containers[cnr] = failOpen
+	}
+
+	return containers
+}
@@ -0,0 +1,188 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	hdrAPIKey = "X-Api-Key"
+
+	// apiKeysCheckInterval is the interval to check API keys file for
+	// modifications with.
+	apiKeysCheckInterval = 5 * time.Second
+)
+
+type (
+	// apiKey grants the operations (all if empty) on the containers (any if
+	// empty). UploadQuota overrides the default upload quota if it's set.
+	apiKey struct {
+		Operations  []string
+		Containers  []string
+		UploadQuota int64
+	}
+
+	// apiKeyStore keeps API keys from the config and the file, the file is
+	// reloaded when it's modified. Keys are indexed by their hashes to avoid
+	// timing leaks of the lookup.
+	apiKeyStore struct {
+		log    *zap.Logger
+		static map[[sha256.Size]byte]apiKey
+		path   string
+
+		mu        sync.Mutex
+		keys      map[[sha256.Size]byte]apiKey
+		modTime   time.Time
+		lastCheck time.Time
+	}
+)
+
+// fetchAPIKeys reads indexed keys of the section.
+func fetchAPIKeys(v *viper.Viper, section string, keys map[[sha256.Size]byte]apiKey) {
+	for i := 0; ; i++ {
+		key := section + "." + strconv.Itoa(i) + "."
+
+		value := v.GetString(key + "key")
+		if value == "" {
+			break
+		}
+
+		keys[sha256.Sum256([]byte(value))] = apiKey{
+			Operations:  v.GetStringSlice(key + "operations"),
+			Containers:  v.GetStringSlice(key + "containers"),
+			UploadQuota: v.GetInt64(key + "upload_quota"),
+		}
+	}
+}
+
+// loadAPIKeysFile reads keys section of YAML, JSON or TOML file.
+func loadAPIKeysFile(path string) (map[[sha256.Size]byte]apiKey, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("could not read API keys file: %w", err)
+	}
+
+	keys := make(map[[sha256.Size]byte]apiKey)
+	fetchAPIKeys(v, "keys", keys)
+	return keys, nil
+}
+
+// newAPIKeyStore returns nil if there are no keys and no keys file configured.
+func (a *app) newAPIKeyStore() (*apiKeyStore, error) {
+	s := &apiKeyStore{
+		log:    a.log,
+		static: make(map[[sha256.Size]byte]apiKey),
+		path:   a.cfg.GetString(cfgAPIKeysFile),
+	}
+	fetchAPIKeys(a.cfg, cfgAPIKeys, s.static)
+
+	if s.path == "" {
+		if len(s.static) == 0 {
+			return nil, nil
+		}
+		s.keys = s.static
+		return s, nil
+	}
+
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("could not load API keys: %w", err)
+	}
+	return s, nil
+}
+
+// reload reads the keys file if it's modified since the last load.
+func (s *apiKeyStore) reload() error {
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("could not stat API keys file: %w", err)
+	}
+	if s.keys != nil && fi.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	fileKeys, err := loadAPIKeysFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[[sha256.Size]byte]apiKey, len(s.static)+len(fileKeys))
+	for hash, key := range fileKeys {
+		keys[hash] = key
+	}
+	for hash, key := range s.static {
+		keys[hash] = key
+	}
+
+	if s.keys != nil {
+		s.log.Info("API keys reloaded", zap.Int("count", len(keys)))
+	}
+	s.keys, s.modTime = keys, fi.ModTime()
+	return nil
+}
+
+// lookup returns permissions of the key, the keys file is checked for
+// modifications at most every apiKeysCheckInterval, previous keys are kept if
+// it can't be reloaded.
+func (s *apiKeyStore) lookup(value []byte) (apiKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path != "" && time.Since(s.lastCheck) >= apiKeysCheckInterval {
+		s.lastCheck = time.Now()
+		if err := s.reload(); err != nil {
+			s.log.Error("could not reload API keys", zap.Error(err))
+		}
+	}
+
+	key, ok := s.keys[sha256.Sum256(value)]
+	return key, ok
+}
+
+func (k apiKey) permitted(operation, cnr string) bool {
+	return (len(k.Operations) == 0 || containsString(k.Operations, operation)) &&
+		(len(k.Containers) == 0 || containsString(k.Containers, cnr))
+}
+
+// apiKeyAuth requires a known key in X-Api-Key header granting the route
+// operation on the requested containers.
+func (a *app) apiKeyAuth(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.apiKeys == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		value := c.Request.Header.Peek(hdrAPIKey)
+		if len(value) == 0 {
+			response.Error(c, "API key required", fasthttp.StatusUnauthorized)
+			return
+		}
+
+		key, ok := a.apiKeys.lookup(value)
+		if !ok {
+			a.log.Warn("unknown API key", zap.Stringer("remote", c.RemoteIP()))
+			response.Error(c, "invalid API key", fasthttp.StatusUnauthorized)
+			return
+		}
+
+		for _, op := range requestContainers(route, c) {
+			if !key.permitted(op.operation, op.container) {
+				a.log.Warn("container access denied for API key",
+					zap.String("operation", op.operation), zap.String("cid", op.container))
+				response.Error(c, "access to container is denied", fasthttp.StatusForbidden)
+				return
+			}
+		}
+
+		h(c)
+	}
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+keys:
+  0:
+    key: file-key
+    containers: [ cnr ]
+`), 0600))
+
+	v := viper.New()
+	v.Set(cfgAPIKeys+".0.key", "reader")
+	v.Set(cfgAPIKeys+".0.operations", []string{operationDownload})
+	v.Set(cfgAPIKeysFile, path)
+	a := &app{cfg: v, log: zap.NewNop()}
+	var err error
+	a.apiKeys, err = a.newAPIKeyStore()
+	require.NoError(t, err)
+
+	h := func(c *fasthttp.RequestCtx) { c.SetStatusCode(fasthttp.StatusOK) }
+
+	serve := func(route, cnr, key string) int {
+		var c fasthttp.RequestCtx
+		c.SetUserValue("cid", cnr)
+		if key != "" {
+			c.Request.Header.Set(hdrAPIKey, key)
+		}
+		a.apiKeyAuth(route, h)(&c)
+		return c.Response.StatusCode()
+	}
+
+	require.Equal(t, fasthttp.StatusUnauthorized, serve(routeGet, "cnr", ""))
+	require.Equal(t, fasthttp.StatusUnauthorized, serve(routeGet, "cnr", "unknown"))
+	require.Equal(t, fasthttp.StatusOK, serve(routeGet, "any", "reader"))
+	require.Equal(t, fasthttp.StatusForbidden, serve(routeUpload, "any", "reader"))
+	require.Equal(t, fasthttp.StatusOK, serve(routeUpload, "cnr", "file-key"))
+	require.Equal(t, fasthttp.StatusForbidden, serve(routeZip, "other", "file-key"))
+
+	t.Run("copy target", func(t *testing.T) {
+		copyTo := func(src, dst, key string) int {
+			var c fasthttp.RequestCtx
+			c.Request.SetRequestURI("/copy/" + src + "/oid?to=" + dst)
+			c.SetUserValue("cid", src)
+			c.Request.Header.Set(hdrAPIKey, key)
+			a.apiKeyAuth(routeCopy, h)(&c)
+			return c.Response.StatusCode()
+		}
+
+		require.Equal(t, fasthttp.StatusOK, copyTo("cnr", "cnr", "file-key"))
+		require.Equal(t, fasthttp.StatusForbidden, copyTo("cnr", "other", "file-key"))
+		require.Equal(t, fasthttp.StatusForbidden, copyTo("any", "other", "reader"))
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`
+keys:
+  0:
+    key: new-key
+`), 0600))
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		a.apiKeys.lastCheck = time.Time{}
+
+		require.Equal(t, fasthttp.StatusUnauthorized, serve(routeUpload, "cnr", "file-key"))
+		require.Equal(t, fasthttp.StatusOK, serve(routeUpload, "any", "new-key"))
+		require.Equal(t, fasthttp.StatusOK, serve(routeGet, "any", "reader"))
+	})
+}
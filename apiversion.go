@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"github.com/fasthttp/router"
+	"github.com/nspcc-dev/neofs-http-gw/downloader"
+	"github.com/nspcc-dev/neofs-http-gw/s3"
+	"github.com/nspcc-dev/neofs-http-gw/uploader"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-http-gw/webdav"
+	"github.com/valyala/fasthttp"
+)
+
+// API versions of gateway routes. Legacy routes without prefix are version 1.
+const (
+	apiV1 = 1
+	apiV2 = 2
+
+	apiV2Prefix = "/v2"
+)
+
+// routeRegistrar is a router or a group of routes with the common prefix.
+type routeRegistrar interface {
+	Handle(method, path string, handler fasthttp.RequestHandler)
+}
+
+// attachGatewayRoutes adds upload and download routes of the API version with
+// the path prefix. Handlers can get the version with utils.APIVersion to
+// change response formats of new versions without breaking the old ones.
+func (a *app) attachGatewayRoutes(r routeRegistrar, prefix string, version int, u *uploader.Uploader, d *downloader.Downloader) {
+	handle := func(method, path, route string, h fasthttp.RequestHandler) {
+		h = a.middlewares(route, h)
+		r.Handle(method, path, func(c *fasthttp.RequestCtx) {
+			utils.SetAPIVersion(c, version)
+			h(c)
+		})
+		a.routeNames.add(method, prefix+path, route)
+	}
+
+	handle(fasthttp.MethodPost, "/upload/{cid}", routeUpload, u.Upload)
+	a.log.Info("added path " + prefix + "/upload/{cid}")
+	if a.cfg.GetBool(cfgUploaderHeaderURLPath) {
+		handle(fasthttp.MethodPost, "/upload/{cid}/{path:*}", routeUpload, u.Upload)
+		a.log.Info("added path " + prefix + "/upload/{cid}/{path}")
+	}
+	handle(fasthttp.MethodPost, "/copy/{cid}/{oid}", routeCopy, u.Copy)
+	a.log.Info("added path " + prefix + "/copy/{cid}/{oid}")
+	if a.cfg.GetBool(cfgUploadMoveEnabled) {
+		handle(fasthttp.MethodPost, "/move/{cid}/{oid}", routeMove, u.Move)
+		a.log.Info("added path " + prefix + "/move/{cid}/{oid}")
+	}
+	handle(fasthttp.MethodPost, "/storagegroup/{cid}", routeStorageGroup, u.StorageGroup)
+	a.log.Info("added path " + prefix + "/storagegroup/{cid}")
+	handle(fasthttp.MethodGet, "/get/{cid}/{oid}", routeGet, d.DownloadByAddress)
+	handle(fasthttp.MethodHead, "/get/{cid}/{oid}", routeGet, d.HeadByAddress)
+	a.log.Info("added path " + prefix + "/get/{cid}/{oid}")
+	handle(fasthttp.MethodGet, "/by-address/{cid}/{oid}", routeByAddress, d.DownloadImmutable)
+	handle(fasthttp.MethodHead, "/by-address/{cid}/{oid}", routeByAddress, d.HeadImmutable)
+	a.log.Info("added path " + prefix + "/by-address/{cid}/{oid}")
+	handle(fasthttp.MethodGet, "/get_by_attribute/{cid}/{attr_key}/{attr_val:*}", routeGetByAttribute, d.DownloadByAttribute)
+	handle(fasthttp.MethodHead, "/get_by_attribute/{cid}/{attr_key}/{attr_val:*}", routeGetByAttribute, d.HeadByAttribute)
+	a.log.Info("added path " + prefix + "/get_by_attribute/{cid}/{attr_key}/{attr_val:*}")
+	handle(fasthttp.MethodGet, "/get_by_attribute/{cid}", routeGetByAttribute, d.DownloadByAttribute)
+	handle(fasthttp.MethodHead, "/get_by_attribute/{cid}", routeGetByAttribute, d.HeadByAttribute)
+	a.log.Info("added path " + prefix + "/get_by_attribute/{cid}")
+	handle(fasthttp.MethodGet, "/zip/{cid}/{prefix:*}", routeZip, d.DownloadZipped)
+	a.log.Info("added path " + prefix + "/zip/{cid}/{prefix}")
+	handle(fasthttp.MethodPost, "/search/{cid}", routeSearch, d.Search)
+	a.log.Info("added path " + prefix + "/search/{cid}")
+	handle(fasthttp.MethodGet, "/containers", routeContainers, d.ListContainers)
+	a.log.Info("added path " + prefix + "/containers")
+	handle(fasthttp.MethodGet, "/container/{cid}", routeContainer, d.ContainerInfo)
+	a.log.Info("added path " + prefix + "/container/{cid}")
+	handle(fasthttp.MethodGet, "/container/{cid}/eacl", routeContainerEACL, d.ContainerEACL)
+	a.log.Info("added path " + prefix + "/container/{cid}/eacl")
+	if a.cfg.GetBool(cfgContainerCreationEnabled) {
+		handle(fasthttp.MethodPut, "/container", routeCreateContainer, u.CreateContainer)
+		a.log.Info("added path " + prefix + "/container")
+	}
+	if a.cfg.GetBool(cfgContainerDeletionEnabled) {
+		handle(fasthttp.MethodDelete, "/container/{cid}", routeDeleteContainer, u.DeleteContainer)
+		a.log.Info("added path " + prefix + "/container/{cid} for deletion")
+	}
+	if a.cfg.GetBool(cfgContainerEACLEnabled) {
+		handle(fasthttp.MethodPut, "/container/{cid}/eacl", routeSetContainerEACL, u.SetContainerEACL)
+		a.log.Info("added path " + prefix + "/container/{cid}/eacl for changes")
+	}
+	handle(fasthttp.MethodGet, "/attributes/{cid}/{oid}", routeAttributes, d.AttributesByAddress)
+	a.log.Info("added path " + prefix + "/attributes/{cid}/{oid}")
+	handle(fasthttp.MethodGet, "/checksum/{cid}/{oid}", routeChecksum, d.ChecksumByAddress)
+	a.log.Info("added path " + prefix + "/checksum/{cid}/{oid}")
+	handle(fasthttp.MethodPost, "/archive/{cid}", routeArchive, d.DownloadArchive)
+	a.log.Info("added path " + prefix + "/archive/{cid}")
+	handle(fasthttp.MethodGet, "/jobs/{id}", routeJobs, u.JobStatus)
+	a.log.Info("added path " + prefix + "/jobs/{id}")
+	handle(fasthttp.MethodGet, "/progress/{id}", routeProgress, u.Progress)
+	a.log.Info("added path " + prefix + "/progress/{id}")
+}
+
+// handle adds the handler of the route with route middlewares, the name of
+// the route is added to routeNames.
+func (a *app) handle(r routeRegistrar, method, path, route string, h fasthttp.RequestHandler) {
+	r.Handle(method, path, a.middlewares(route, h))
+	a.routeNames.add(method, path, route)
+}
+
+// attachWebDAV adds the routes of WebDAV shares of the containers, write ones
+// are added only if they're enabled. They aren't versioned since the protocol
+// is defined by WebDAV clients.
+func (a *app) attachWebDAV(r *router.Router, h *webdav.Handler) {
+	writable := a.cfg.GetBool(cfgWebDAVWriteEnabled)
+	for _, path := range []string{webdav.Prefix + "/{cid}", webdav.Prefix + "/{cid}/{path:*}"} {
+		a.handle(r, fasthttp.MethodOptions, path, routeWebDAV, h.Options)
+		a.handle(r, webdav.MethodPropfind, path, routeWebDAV, h.Propfind)
+		a.handle(r, fasthttp.MethodGet, path, routeWebDAV, h.Get)
+		a.handle(r, fasthttp.MethodHead, path, routeWebDAV, h.Get)
+		if !writable {
+			continue
+		}
+		a.handle(r, fasthttp.MethodPut, path, routeWebDAVWrite, h.Put)
+		a.handle(r, fasthttp.MethodDelete, path, routeWebDAVWrite, h.Delete)
+		a.handle(r, webdav.MethodMkcol, path, routeWebDAVWrite, h.Mkcol)
+		a.handle(r, webdav.MethodMove, path, routeWebDAVWrite, h.Move)
+	}
+	a.log.Info("added path " + webdav.Prefix + "/{cid}/{path}")
+}
+
+// attachS3 adds the routes of S3-compatible object requests. They aren't
+// versioned since the protocol is defined by S3 clients.
+func (a *app) attachS3(r *router.Router, h *s3.Handler) {
+	path := s3.Prefix + "/{cid}/{key:*}"
+	a.handle(r, fasthttp.MethodGet, path, routeS3, h.GetObject)
+	a.handle(r, fasthttp.MethodHead, path, routeS3, h.GetObject)
+	a.handle(r, fasthttp.MethodPut, path, routeS3Write, h.PutObject)
+	a.log.Info("added path " + s3.Prefix + "/{bucket}/{key}")
+}
@@ -1,10 +1,17 @@
-package main
+// Package gateway implements NeoFS HTTP gateway. Besides the standalone
+// binary (see cmd/neofs-http-gw) it can be embedded into other services:
+// create the gateway with New and mount the handler returned by
+// App.Handler into own fasthttp server.
+package gateway
 
 import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
+	"net"
+	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/fasthttp/router"
 	"github.com/nspcc-dev/neo-go/cli/flags"
@@ -15,28 +22,98 @@ import (
 	"github.com/nspcc-dev/neofs-http-gw/downloader"
 	"github.com/nspcc-dev/neofs-http-gw/resolver"
 	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/s3"
 	"github.com/nspcc-dev/neofs-http-gw/uploader"
 	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-http-gw/webdav"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 type (
 	app struct {
 		log       *zap.Logger
-		pool      *pool.Pool
+		logLevel  *zap.AtomicLevel
+		pool      *utils.PoolHolder
 		cfg       *viper.Viper
 		webServer *fasthttp.Server
 		webDone   chan struct{}
 		resolver  *resolver.ContainerResolver
+		// routeNames are filled when routes are attached.
+		routeNames *routeNames
+
+		rateLimiter       *rateLimiter
+		uploadQuota       *uploadQuota
+		usage             *usageAccounting
+		audit             *auditLog
+		oidc              *oidcVerifier
+		apiKeys           *apiKeyStore
+		accessPolicy      *accessPolicy
+		identities        *identities
+		basicCreds        credentials
+		ipFilters         map[string]ipFilter
+		uploadLimiter     *concurrencyLimiter
+		downloadLimiter   *concurrencyLimiter
+		uploadBandwidth   *bandwidthLimit
+		downloadBandwidth *bandwidthLimit
+		webhooks          *webhooks
+		nats              *natsPublisher
+		notifiers         eventNotifiers
+		epochs            *utils.EpochTracker
+		// scan are the settings of antivirus checks of uploads.
+		scan uploader.ScanSettings
+		// runtime are the settings changed with admin API.
+		runtime runtimeValues
+		// secrets are the secrets loaded from files and Vault on start.
+		secrets map[string]string
+		// metrics is the registry of gateway metrics served at /metrics/.
+		metrics *prometheus.Registry
+
+		keyMu        sync.Mutex
+		key          *ecdsa.PrivateKey
+		probeKey     *keys.PrivateKey
+		peers        *peerMonitor
+		peersOnce    sync.Once
+		balances     *balanceMonitor
+		balancesOnce sync.Once
+		nodePools    *nodePools
+		peerDNS      *peerResolver
+		maintenance  int32
+		draining     int32
+		connecting   int32
+
+		httpMu      sync.Mutex
+		httpServers []*http.Server
 	}
 
 	// App is an interface for the main gateway function.
 	App interface {
 		Wait()
-		Serve(context.Context)
+		// Serve starts the servers and blocks until the context is done or
+		// any of them fails, errors of configuration and listeners are
+		// returned immediately.
+		Serve(context.Context) error
+		// Handler returns gateway request handler with all configured
+		// routes and middlewares. Background jobs it needs (notifications,
+		// circuit breakers, etc.) are started and live until the context is
+		// done. It's called once by Serve, embedding applications call it
+		// instead of Serve and Wait. If the gateway is started before NeoFS
+		// nodes are available, the handler rejects requests until it's
+		// connected.
+		Handler(context.Context) fasthttp.RequestHandler
+		// ReloadKey rereads the wallet or key file and replaces the key of
+		// the connection pool, the binary calls it on SIGHUP.
+		ReloadKey(context.Context) error
+		// Draining tells whether drain mode is enabled.
+		Draining() bool
+		// SetDraining enables or disables drain mode, the binary toggles it
+		// on SIGUSR1.
+		SetDraining(bool)
 	}
 
 	// Option is an application option.
@@ -53,6 +130,22 @@ func WithLogger(l *zap.Logger) Option {
 	}
 }
 
+// WithLogLevel returns Option to change the level of the logger at runtime.
+func WithLogLevel(lvl zap.AtomicLevel) Option {
+	return func(a *app) {
+		a.logLevel = &lvl
+	}
+}
+
+// WithMetricsRegistry returns Option to register gateway metrics in the given
+// registry instead of the own one, it's served at /metrics/ if metrics are
+// enabled.
+func WithMetricsRegistry(reg *prometheus.Registry) Option {
+	return func(a *app) {
+		a.metrics = reg
+	}
+}
+
 // WithConfig returns Option to use specific Viper configuration.
 func WithConfig(c *viper.Viper) Option {
 	return func(a *app) {
@@ -63,7 +156,9 @@ func WithConfig(c *viper.Viper) Option {
 	}
 }
 
-func newApp(ctx context.Context, opt ...Option) App {
+// New creates the gateway and connects to NeoFS, the connection pool and
+// background jobs live until the context is done.
+func New(ctx context.Context, opt ...Option) (App, error) {
 	var (
 		key *ecdsa.PrivateKey
 		err error
@@ -78,6 +173,17 @@ func newApp(ctx context.Context, opt ...Option) App {
 	for i := range opt {
 		opt[i](a)
 	}
+	if a.metrics == nil {
+		a.metrics = newMetricsRegistry()
+	}
+
+	if a.secrets, err = readSecrets(a.cfg); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+	a.setMaintenance(a.cfg.GetBool(cfgMaintenance))
+	if err = loadErrorTemplate(a.cfg); err != nil {
+		return nil, fmt.Errorf("failed to load error page template: %w", err)
+	}
 
 	// -- setup FastHTTP server --
 	a.webServer.Name = "neofs-http-gw"
@@ -91,45 +197,34 @@ func newApp(ctx context.Context, opt ...Option) App {
 	a.webServer.MaxRequestBodySize = a.cfg.GetInt(cfgWebMaxRequestBodySize)
 	a.webServer.DisablePreParseMultipartForm = true
 	a.webServer.StreamRequestBody = a.cfg.GetBool(cfgWebStreamRequestBody)
+	a.routeNames = newRouteNames()
+	a.webServer.HeaderReceived = limitUploadBodies(a.routeNames, routeRequestConfig(a.routeNames,
+		fetchRouteTimeouts(a.cfg, routeUpload),
+		fetchRouteTimeouts(a.cfg, routeGet),
+	), a.webServer.MaxRequestBodySize, a.cfg.GetInt(cfgWebUploadMaxMemory))
 	// -- -- -- -- -- -- -- -- -- -- -- -- -- --
-	key, err = getNeoFSKey(a)
-	if err != nil {
-		a.log.Fatal("failed to get neofs credentials", zap.Error(err))
-	}
-
-	var prm pool.InitParameters
-	prm.SetKey(key)
-	prm.SetNodeDialTimeout(a.cfg.GetDuration(cfgConTimeout))
-	prm.SetHealthcheckTimeout(a.cfg.GetDuration(cfgReqTimeout))
-	prm.SetClientRebalanceInterval(a.cfg.GetDuration(cfgRebalance))
-
-	for i := 0; ; i++ {
-		address := a.cfg.GetString(cfgPeers + "." + strconv.Itoa(i) + ".address")
-		weight := a.cfg.GetFloat64(cfgPeers + "." + strconv.Itoa(i) + ".weight")
-		priority := a.cfg.GetInt(cfgPeers + "." + strconv.Itoa(i) + ".priority")
-		if address == "" {
-			break
-		}
-		if weight <= 0 { // unspecified or wrong
-			weight = 1
-		}
-		if priority <= 0 { // unspecified or wrong
-			priority = 1
+	if a.cfg.GetBool(cmdMock) {
+		if err = a.startMock(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start NeoFS mock: %w", err)
 		}
-		prm.AddNode(pool.NewNodeParam(priority, address, weight))
-		a.log.Info("add connection", zap.String("address", address),
-			zap.Float64("weight", weight), zap.Int("priority", priority))
 	}
 
-	a.pool, err = pool.NewPool(prm)
+	key, err = getNeoFSKey(a, a.secrets)
 	if err != nil {
-		a.log.Fatal("failed to create connection pool", zap.Error(err))
+		return nil, fmt.Errorf("failed to get neofs credentials: %w", err)
 	}
 
-	err = a.pool.Dial(ctx)
+	clientPool, err := a.newPool(ctx, key)
 	if err != nil {
-		a.log.Fatal("failed to dial pool", zap.Error(err))
+		if !a.cfg.GetBool(cfgStartupWaitForNodes) {
+			return nil, fmt.Errorf("failed to create connection pool: %w", err)
+		}
+		a.log.Warn("could not connect to NeoFS, gateway isn't ready until it's connected", zap.Error(err))
+		a.connecting = 1
 	}
+	a.pool = utils.NewPoolHolder(clientPool)
+	a.key = key
+	a.nodePools = a.newNodePools(ctx)
 
 	resolveCfg := &resolver.Config{
 		NeoFS:      resolver.NewNeoFSResolver(a.pool),
@@ -145,13 +240,118 @@ func newApp(ctx context.Context, opt ...Option) App {
 	if len(order) != 0 {
 		a.resolver, err = resolver.NewResolver(order, resolveCfg)
 		if err != nil {
-			a.log.Fatal("failed to create resolver", zap.Error(err))
+			return nil, fmt.Errorf("failed to create resolver: %w", err)
 		}
 	} else {
 		a.log.Info("container resolver is disabled")
 	}
 
-	return a
+	if err = a.initComponents(ctx); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// initComponents creates the parts of the gateway which fail on invalid
+// configuration, so that New reports it instead of Handler.
+func (a *app) initComponents(ctx context.Context) error {
+	var err error
+
+	if err = registerMetrics(a.metrics); err != nil {
+		return fmt.Errorf("could not register metrics: %w", err)
+	}
+	if a.audit, err = a.newAuditLog(ctx); err != nil {
+		return err
+	}
+	if a.apiKeys, err = a.newAPIKeyStore(); err != nil {
+		return err
+	}
+	if a.accessPolicy, err = a.newAccessPolicy(ctx); err != nil {
+		return err
+	}
+	if a.identities, err = a.loadIdentities(ctx); err != nil {
+		return err
+	}
+	if a.nats, err = a.newNATSPublisher(); err != nil {
+		return err
+	}
+	a.webhooks = a.newWebhooks()
+	if a.basicCreds, err = loadCredentials(a.cfg, a.secrets); err != nil {
+		return err
+	}
+	if a.ipFilters, err = fetchIPFilters(a.cfg); err != nil {
+		return err
+	}
+	if a.probeKey, err = keys.NewPrivateKey(); err != nil {
+		return fmt.Errorf("could not generate key for peer probes: %w", err)
+	}
+	return nil
+}
+
+// peerInfo is NeoFS node connection parameters.
+type peerInfo struct {
+	Address  string
+	Weight   float64
+	Priority int
+}
+
+func fetchPeers(v *viper.Viper) []peerInfo {
+	var peers []peerInfo
+
+	for i := 0; ; i++ {
+		address := v.GetString(cfgPeers + "." + strconv.Itoa(i) + ".address")
+		weight := v.GetFloat64(cfgPeers + "." + strconv.Itoa(i) + ".weight")
+		priority := v.GetInt(cfgPeers + "." + strconv.Itoa(i) + ".priority")
+		if address == "" {
+			break
+		}
+		if weight <= 0 { // unspecified or wrong
+			weight = 1
+		}
+		if priority <= 0 { // unspecified or wrong
+			priority = 1
+		}
+		peers = append(peers, peerInfo{Address: address, Weight: weight, Priority: priority})
+	}
+
+	return peers
+}
+
+// newPool creates connection pool with the key given and dials it.
+func (a *app) newPool(ctx context.Context, key *ecdsa.PrivateKey) (*pool.Pool, error) {
+	peers := fetchPeers(a.cfg)
+	if a.peers != nil {
+		peers = activePeers(peers, a.peers.ejected())
+	}
+	return a.dialPool(ctx, key, peers)
+}
+
+// dialPool creates connection pool of the peers with the key given and dials it.
+func (a *app) dialPool(ctx context.Context, key *ecdsa.PrivateKey, peers []peerInfo) (*pool.Pool, error) {
+	var prm pool.InitParameters
+	prm.SetKey(key)
+	prm.SetNodeDialTimeout(a.cfg.GetDuration(cfgConTimeout))
+	prm.SetHealthcheckTimeout(a.cfg.GetDuration(cfgReqTimeout))
+	prm.SetClientRebalanceInterval(a.cfg.GetDuration(cfgRebalance))
+	prm.SetSessionExpirationDuration(a.cfg.GetUint64(cfgSessionExpiration))
+
+	for _, peer := range peers {
+		prm.AddNode(pool.NewNodeParam(peer.Priority, peer.Address, peer.Weight))
+		a.log.Info("add connection", zap.String("address", peer.Address),
+			zap.Float64("weight", peer.Weight), zap.Int("priority", peer.Priority))
+	}
+
+	p, err := pool.NewPool(prm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.Dial(ctx); err != nil {
+		return nil, fmt.Errorf("failed to dial pool: %w", err)
+	}
+
+	return p, nil
 }
 
 func remove(list []string, element string) []string {
@@ -163,7 +363,7 @@ func remove(list []string, element string) []string {
 	return list
 }
 
-func getNeoFSKey(a *app) (*ecdsa.PrivateKey, error) {
+func getNeoFSKey(a *app, secrets map[string]string) (*ecdsa.PrivateKey, error) {
 	walletPath := a.cfg.GetString(cmdWallet)
 	if len(walletPath) == 0 {
 		walletPath = a.cfg.GetString(cfgWalletPath)
@@ -183,8 +383,7 @@ func getNeoFSKey(a *app) (*ecdsa.PrivateKey, error) {
 	}
 
 	var password *string
-	if a.cfg.IsSet(cfgWalletPassphrase) {
-		pwd := a.cfg.GetString(cfgWalletPassphrase)
+	if pwd, ok := secretValue(a.cfg, secrets, cfgWalletPassphrase); ok {
 		password = &pwd
 	}
 
@@ -234,16 +433,155 @@ func (a *app) Wait() {
 	<-a.webDone // wait for web-server to be stopped
 }
 
-func (a *app) Serve(ctx context.Context) {
+func (a *app) Serve(ctx context.Context) error {
 	go func() {
 		<-ctx.Done()
 		a.log.Info("shutting down web server", zap.Error(a.webServer.Shutdown()))
+		a.shutdownHTTP2()
 		close(a.webDone)
 	}()
-	edts := a.cfg.GetBool(cfgUploaderHeaderEnableDefaultTimestamp)
-	uploadRoutes := uploader.New(ctx, a.AppParams(), edts)
-	downloadSettings := downloader.Settings{ZipCompression: a.cfg.GetBool(cfgZipCompression)}
+	a.webServer.Handler = a.Handler(ctx)
+
+	var (
+		err         error
+		certManager *autocert.Manager
+		servers     = fetchServers(a.cfg)
+	)
+	if a.cfg.GetBool(cfgTLSAutocertEnabled) {
+		if certManager, err = a.newAutocertManager(); err != nil {
+			return fmt.Errorf("could not configure automatic certificates: %w", err)
+		}
+	}
+
+	if redirectAddr := a.cfg.GetString(cfgTLSRedirectAddress); redirectAddr != "" {
+		var tlsBind string
+		for _, srv := range servers {
+			if srv.TLS.Enabled {
+				tlsBind = srv.Address
+				break
+			}
+		}
+
+		if tlsBind == "" {
+			a.log.Warn("redirect server won't be started since TLS is disabled")
+		} else {
+			a.startRedirectServer(ctx, redirectAddr, tlsBind, certManager)
+		}
+	}
+
+	activated, err := systemdListeners()
+	if err != nil {
+		return fmt.Errorf("could not use socket activation: %w", err)
+	} else if len(activated) != 0 {
+		a.log.Info("using socket activation")
+	}
+
+	listeners := make([]net.Listener, 0, len(servers))
+	for _, srv := range servers {
+		ln, err := a.listen(srv, certManager, activated)
+		if err != nil {
+			for i := range listeners {
+				_ = listeners[i].Close()
+			}
+			return fmt.Errorf("could not start server %s: %w", srv.Address, err)
+		}
+		listeners = append(listeners, ln)
+	}
+
+	errs := make(chan error, len(servers))
+	for i, srv := range servers {
+		go func(ln net.Listener, srv serverInfo) {
+			var err error
+			if srv.HTTP2 {
+				err = a.serveHTTP2(ln, srv.TLS.Enabled)
+			} else {
+				err = a.webServer.Serve(ln)
+			}
+			if err != nil {
+				errs <- fmt.Errorf("could not serve %s: %w", ln.Addr(), err)
+			}
+		}(listeners[i], srv)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err = <-errs:
+		return err
+	}
+}
+
+func (a *app) Handler(ctx context.Context) fasthttp.RequestHandler {
+	if a.isConnecting() {
+		return a.waitForNodes(ctx)
+	}
+
+	a.scan = a.newScanSettings()
+	uploadSettings := uploader.Settings{
+		DefaultTimestamp: a.cfg.GetBool(cfgUploaderHeaderEnableDefaultTimestamp),
+		PartContentType:  a.cfg.GetBool(cfgUploaderHeaderPartContentType),
+		MaxObjectSize:    fetchUploadSizeLimits(a.cfg),
+		AsyncUploads:     a.cfg.GetBool(cfgUploadJobsEnabled),
+		SpoolDir:         a.cfg.GetString(cfgUploadJobsSpoolDir),
+		JobTTL:           a.cfg.GetDuration(cfgUploadJobsTTL),
+		Progress:         a.cfg.GetBool(cfgUploadProgressEnabled),
+		ProgressInterval: a.cfg.GetDuration(cfgUploadProgressInterval),
+		MaxMemory:        a.cfg.GetInt(cfgWebUploadMaxMemory),
+		Scan:             a.scan,
+		Deduplicate:      a.cfg.GetBool(cfgUploadDedupEnabled),
+
+		ContainerCreation: uploader.ContainerWait{
+			Timeout:      a.cfg.GetDuration(cfgContainerCreationWaitTimeout),
+			PollInterval: a.cfg.GetDuration(cfgContainerCreationPollInterval),
+		},
+		ContainerDeletion: uploader.ContainerWait{
+			Timeout:      a.cfg.GetDuration(cfgContainerDeletionWaitTimeout),
+			PollInterval: a.cfg.GetDuration(cfgContainerDeletionPollInterval),
+		},
+		ContainerEACL: uploader.ContainerWait{
+			Timeout:      a.cfg.GetDuration(cfgContainerEACLWaitTimeout),
+			PollInterval: a.cfg.GetDuration(cfgContainerEACLPollInterval),
+		},
+
+		DuplicateAttributes: a.cfg.GetString(cfgUploadAttributesDuplicates),
+		AllowedTypes: uploader.TypeFilter{
+			ContentTypes: a.cfg.GetStringSlice(cfgUploadAllowedContentTypes),
+			Extensions:   a.cfg.GetStringSlice(cfgUploadAllowedExtensions),
+		},
+		ContainerAllowedTypes: fetchContainerAllowedTypes(a.cfg),
+	}
+	a.startNotifiers(ctx)
+	a.epochs = a.newEpochTracker(ctx)
+	uploadRoutes := uploader.New(ctx, a.AppParams(), uploadSettings)
+	downloadSettings := downloader.Settings{
+		ZipCompression:     a.cfg.GetBool(cfgZipCompression),
+		AttributeSelection: a.cfg.GetString(cfgAttributeSelection),
+		FailoverAttempts:   a.cfg.GetInt(cfgDownloadFailoverAttempts),
+		VerifyPayload:      a.cfg.GetBool(cfgDownloadVerifyPayload),
+		Thumbnails: downloader.ThumbnailSettings{
+			Enabled:         a.cfg.GetBool(cfgThumbnailsEnabled),
+			MaxSourceSize:   a.cfg.GetUint64(cfgThumbnailsMaxSourceSize),
+			MaxDimension:    a.cfg.GetInt(cfgThumbnailsMaxDimension),
+			MaxSourcePixels: a.cfg.GetInt64(cfgThumbnailsMaxPixels),
+			CacheSize:       a.cfg.GetInt(cfgThumbnailsCacheSize),
+		},
+	}
+	if downloadSettings.AttributeSelection != downloader.AttributeSelectionAny &&
+		downloadSettings.AttributeSelection != downloader.AttributeSelectionNewest {
+		a.log.Warn("unknown attribute selection policy, any object is selected",
+			zap.String("policy", downloadSettings.AttributeSelection))
+		downloadSettings.AttributeSelection = downloader.AttributeSelectionAny
+	}
 	downloadRoutes := downloader.New(ctx, a.AppParams(), downloadSettings)
+	a.rateLimiter = a.newRateLimiter()
+	a.uploadQuota = a.newUploadQuota()
+	a.usage = a.newUsageAccounting(ctx)
+	a.oidc = a.newOIDCVerifier()
+	a.uploadLimiter = a.newConcurrencyLimiter(cfgConcurrencyUpload)
+	a.uploadBandwidth = newBandwidthLimit(a.cfg, cfgThrottleUpload)
+	a.downloadBandwidth = newBandwidthLimit(a.cfg, cfgThrottleDownload)
+	a.downloadLimiter = a.newConcurrencyLimiter(cfgConcurrencyDownload)
+	a.checkMiddlewares()
 	// Configure router.
 	r := router.New()
 	r.RedirectTrailingSlash = true
@@ -253,42 +591,61 @@ func (a *app) Serve(ctx context.Context) {
 	r.MethodNotAllowed = func(r *fasthttp.RequestCtx) {
 		response.Error(r, "Method Not Allowed", fasthttp.StatusMethodNotAllowed)
 	}
-	r.POST("/upload/{cid}", a.logger(uploadRoutes.Upload))
-	a.log.Info("added path /upload/{cid}")
-	r.GET("/get/{cid}/{oid}", a.logger(downloadRoutes.DownloadByAddress))
-	r.HEAD("/get/{cid}/{oid}", a.logger(downloadRoutes.HeadByAddress))
-	a.log.Info("added path /get/{cid}/{oid}")
-	r.GET("/get_by_attribute/{cid}/{attr_key}/{attr_val:*}", a.logger(downloadRoutes.DownloadByAttribute))
-	r.HEAD("/get_by_attribute/{cid}/{attr_key}/{attr_val:*}", a.logger(downloadRoutes.HeadByAttribute))
-	a.log.Info("added path /get_by_attribute/{cid}/{attr_key}/{attr_val:*}")
-	r.GET("/zip/{cid}/{prefix:*}", a.logger(downloadRoutes.DownloadZipped))
-	a.log.Info("added path /zip/{cid}/{prefix}")
+	if a.cfg.GetBool(cfgLegacyRoutes) {
+		a.attachGatewayRoutes(r, "", apiV1, uploadRoutes, downloadRoutes)
+	}
+	a.attachGatewayRoutes(r.Group(apiV2Prefix), apiV2Prefix, apiV2, uploadRoutes, downloadRoutes)
+	if a.cfg.GetBool(cfgWebDAVEnabled) {
+		a.attachWebDAV(r, webdav.New(ctx, a.AppParams(), webdav.Settings{
+			Writable: a.cfg.GetBool(cfgWebDAVWriteEnabled),
+		}))
+	}
+	if a.cfg.GetBool(cfgS3Enabled) {
+		a.attachS3(r, s3.New(ctx, a.AppParams()))
+	}
 	// enable metrics
 	if a.cfg.GetBool(cmdMetrics) {
 		a.log.Info("added path /metrics/")
-		attachMetrics(r, a.log)
+		attachMetrics(r, a.metrics, a.log)
 	}
 	// enable pprof
 	if a.cfg.GetBool(cmdPprof) {
 		a.log.Info("added path /debug/pprof/")
 		attachProfiler(r)
 	}
-	bind := a.cfg.GetString(cfgListenAddress)
-	tlsCertPath := a.cfg.GetString(cfgTLSCertificate)
-	tlsKeyPath := a.cfg.GetString(cfgTLSKey)
-
-	a.webServer.Handler = r.Handler
-	var err error
-	if tlsCertPath == "" && tlsKeyPath == "" {
-		a.log.Info("running web server", zap.String("address", bind))
-		err = a.webServer.ListenAndServe(bind)
-	} else {
-		a.log.Info("running web server (TLS-enabled)", zap.String("address", bind))
-		err = a.webServer.ListenAndServeTLS(bind, tlsCertPath, tlsKeyPath)
+	// readiness probe
+	r.GET("/-/ready", a.readinessHandler)
+	a.log.Info("added path /-/ready")
+	r.GET("/-/version", a.versionHandler())
+	a.log.Info("added path /-/version")
+	r.GET("/-/openapi.json", a.openAPIHandler())
+	a.log.Info("added path /-/openapi.json")
+	r.GET("/-/network", a.networkHandler(ctx))
+	a.log.Info("added path /-/network")
+	if a.usage != nil {
+		usage := fasthttp.RequestHandler(a.usageHandler)
+		if token := a.secret(cfgAdminToken); token != "" {
+			usage = a.adminAuth(token, usage)
+		}
+		r.GET("/-/usage", usage)
+		a.log.Info("added path /-/usage")
 	}
-	if err != nil {
-		a.log.Fatal("could not start server", zap.Error(err))
+	a.startPeerResolver(ctx)
+	if a.cfg.GetBool(cfgCircuitBreakerEnabled) || a.cfg.GetInt(cfgPoolMinHealthyNodes) > 0 {
+		a.peerMonitor(ctx)
+	}
+	a.balanceMonitor(ctx)
+	// enable admin API
+	if token := a.secret(cfgAdminToken); token != "" {
+		a.log.Info("added paths /admin/reload_key, /admin/peers, /admin/balance, /admin/config, /admin/drain")
+		a.attachAdmin(ctx, r, token)
+	}
+	handler := r.Handler
+	if domain := a.cfg.GetString(cfgS3Domain); domain != "" && a.cfg.GetBool(cfgS3Enabled) {
+		a.routeNames.setS3Domain(domain)
+		handler = s3.VirtualHosts(domain, handler)
 	}
+	return a.chain(true, "", handler)
 }
 
 func (a *app) logger(h fasthttp.RequestHandler) fasthttp.RequestHandler {
@@ -302,10 +659,78 @@ func (a *app) logger(h fasthttp.RequestHandler) fasthttp.RequestHandler {
 	})
 }
 
+// fetchContainerAllowedTypes reads allowed upload types of the containers from
+// upload_allowed_types.containers section.
+func fetchContainerAllowedTypes(v *viper.Viper) map[string]uploader.TypeFilter {
+	filters := make(map[string]uploader.TypeFilter)
+
+	for i := 0; ; i++ {
+		key := cfgUploadAllowedContainers + "." + strconv.Itoa(i) + "."
+
+		cnr := v.GetString(key + "container")
+		if cnr == "" {
+			break
+		}
+
+		filters[cnr] = uploader.TypeFilter{
+			ContentTypes: v.GetStringSlice(key + "content_types"),
+			Extensions:   v.GetStringSlice(key + "extensions"),
+		}
+	}
+
+	return filters
+}
+
+// fetchUploadSizeLimits reads object size limits of the containers from
+// upload_size_limits section.
+func fetchUploadSizeLimits(v *viper.Viper) map[string]int64 {
+	limits := make(map[string]int64)
+
+	for i := 0; ; i++ {
+		key := cfgUploadSizeLimits + "." + strconv.Itoa(i) + "."
+
+		cnr := v.GetString(key + "container")
+		if cnr == "" {
+			break
+		}
+
+		limits[cnr] = v.GetInt64(key + "max_size")
+	}
+
+	return limits
+}
+
 func (a *app) AppParams() *utils.AppParams {
-	return &utils.AppParams{
+	params := &utils.AppParams{
 		Logger:   a.log,
 		Pool:     a.pool,
 		Resolver: a.resolver,
+		Notifier: a.notifier(),
+		Epochs:   a.epochs,
+		Retry: utils.RetryPolicy{
+			Retries:    a.cfg.GetInt(cfgPoolRetryRetries),
+			Backoff:    a.cfg.GetDuration(cfgPoolRetryBackoff),
+			MaxBackoff: a.cfg.GetDuration(cfgPoolRetryMaxBackoff),
+			Retryable:  a.cfg.GetStringSlice(cfgPoolRetryRetryable),
+		},
+		Hedge: utils.HedgePolicy{
+			Delay: a.cfg.GetDuration(cfgPoolHedgeDelay),
+		},
+		AttributeLimits: utils.AttributeLimits{
+			MaxCount:     a.cfg.GetInt(cfgUploadAttributesMaxCount),
+			MaxKeySize:   a.cfg.GetInt(cfgUploadAttributesMaxKeySize),
+			MaxValueSize: a.cfg.GetInt(cfgUploadAttributesMaxValueSize),
+			AllowSystem:  a.cfg.GetBool(cfgUploadAttributesAllowSystem),
+		},
+	}
+	if a.nodePools != nil {
+		params.Hedge.Nodes = a.nodePools.pair
+	}
+	if a.scan.Scanner != nil {
+		params.Scanned = func(scid string, cnrID *cid.ID) bool {
+			_, ok := a.scan.Policy(scid, cnrID)
+			return ok
+		}
 	}
+	return params
 }
@@ -0,0 +1,192 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Audit log outputs.
+const (
+	auditOutputFile   = "file"
+	auditOutputSyslog = "syslog"
+)
+
+// auditSyslogTag is the tag of audit records sent to syslog.
+const auditSyslogTag = "neofs-http-gw"
+
+// Results of audited requests.
+const (
+	auditResultSuccess = "success"
+	auditResultFailure = "failure"
+)
+
+type (
+	// auditClient is the identity of the client made the request, API key is
+	// represented by the prefix of its hash.
+	auditClient struct {
+		IP          string `json:"ip"`
+		APIKey      string `json:"api_key,omitempty"`
+		BearerOwner string `json:"bearer_owner,omitempty"`
+		User        string `json:"user,omitempty"`
+		Subject     string `json:"subject,omitempty"`
+	}
+
+	// auditRecord describes a mutating request. ObjectID is the object of
+	// the request path, Objects are the objects stored or deleted by it.
+	auditRecord struct {
+		Time      time.Time   `json:"time"`
+		Route     string      `json:"route"`
+		Method    string      `json:"method"`
+		Path      string      `json:"path"`
+		Client    auditClient `json:"client"`
+		Container string      `json:"container,omitempty"`
+		ObjectID  string      `json:"object_id,omitempty"`
+		Objects   []string    `json:"objects,omitempty"`
+		Status    int         `json:"status"`
+		Result    string      `json:"result"`
+	}
+
+	// auditLog writes records of mutating requests as JSON lines to an
+	// append-only file or to syslog.
+	auditLog struct {
+		log *zap.Logger
+
+		mu sync.Mutex
+		w  io.WriteCloser
+	}
+)
+
+// openAuditWriter opens the output of the audit log.
+func openAuditWriter(output, path, syslogAddress string) (io.WriteCloser, error) {
+	switch output {
+	case auditOutputFile:
+		if path == "" {
+			return nil, fmt.Errorf("audit log file isn't set")
+		}
+		return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	case auditOutputSyslog:
+		var network string
+		if syslogAddress != "" {
+			network = "udp"
+		}
+		return syslog.Dial(network, syslogAddress, syslog.LOG_INFO|syslog.LOG_DAEMON, auditSyslogTag)
+	default:
+		return nil, fmt.Errorf("unknown audit log output %q", output)
+	}
+}
+
+// newAuditLog returns nil if the audit log is disabled, it's closed when the
+// context is done.
+func (a *app) newAuditLog(ctx context.Context) (*auditLog, error) {
+	output := a.cfg.GetString(cfgAuditOutput)
+	if output == "" {
+		return nil, nil
+	}
+
+	w, err := openAuditWriter(output, a.cfg.GetString(cfgAuditFile), a.cfg.GetString(cfgAuditSyslogAddress))
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log: %w", err)
+	}
+
+	l := &auditLog{log: a.log, w: w}
+	go func() {
+		<-ctx.Done()
+		l.close()
+	}()
+	return l, nil
+}
+
+func (l *auditLog) write(rec auditRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		l.log.Error("could not encode audit record", zap.Error(err))
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.w == nil {
+		l.log.Error("audit log is closed", zap.ByteString("record", data))
+		return
+	}
+	if _, err = l.w.Write(data); err != nil {
+		l.log.Error("could not write audit record", zap.Error(err), zap.ByteString("record", data))
+	}
+}
+
+func (l *auditLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.w.Close(); err != nil {
+		l.log.Error("could not close audit log", zap.Error(err))
+	}
+	l.w = nil
+}
+
+// auditClientOf returns the identity of the client, values set by
+// authentication middlewares are read, so it's to be called after the
+// request is handled.
+func auditClientOf(c *fasthttp.RequestCtx) auditClient {
+	client := auditClient{IP: c.RemoteIP().String()}
+
+	if key := c.Request.Header.Peek(hdrAPIKey); len(key) != 0 {
+		hash := sha256.Sum256(key)
+		client.APIKey = hex.EncodeToString(hash[:8])
+	}
+	if issuer, ok := bearerIssuer(c); ok {
+		client.BearerOwner = issuer
+	}
+	client.User, _ = c.UserValue(basicAuthUserKey).(string)
+	client.Subject, _ = c.UserValue(oidcSubjectKey).(string)
+
+	return client
+}
+
+// auditRequests records mutating requests with their results to the audit
+// log, rejected requests are recorded too.
+func (a *app) auditRequests(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.audit == nil || routeOperation(route) != operationUpload {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		h(c)
+
+		rec := auditRecord{
+			Time:    time.Now(),
+			Route:   route,
+			Method:  string(c.Method()),
+			Path:    string(c.Path()),
+			Client:  auditClientOf(c),
+			Objects: utils.RequestObjects(c),
+			Status:  c.Response.StatusCode(),
+			Result:  auditResultSuccess,
+		}
+		if cnrID := utils.RequestContainer(c); cnrID != nil {
+			rec.Container = cnrID.String()
+		} else {
+			rec.Container, _ = c.UserValue("cid").(string)
+		}
+		rec.ObjectID, _ = c.UserValue("oid").(string)
+		if rec.Status >= fasthttp.StatusBadRequest {
+			rec.Result = auditResultFailure
+		}
+
+		a.audit.write(rec)
+	}
+}
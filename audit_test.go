@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestAuditRequests(t *testing.T) {
+	const cnr = "BJeErH9MWmf52VsR1mLWKkgF3pRm3FkubYxM7TZkBP4K"
+
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		l, err := a.newAuditLog(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, l)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	v := viper.New()
+	v.Set(cfgAuditOutput, auditOutputFile)
+	v.Set(cfgAuditFile, path)
+	a := &app{cfg: v, log: zap.NewNop()}
+	var err error
+	a.audit, err = a.newAuditLog(ctx)
+	require.NoError(t, err)
+
+	status := fasthttp.StatusOK
+	h := a.auditRequests(routeUpload, func(c *fasthttp.RequestCtx) {
+		if status == fasthttp.StatusOK {
+			c.SetUserValue(basicAuthUserKey, "alice")
+			utils.AddRequestObject(c, "2m8PtaoricLouCn5zE8hAFr3gZEBDCZFe9BEgVJTSocY")
+		}
+		c.SetStatusCode(status)
+	})
+
+	request := func() {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP("192.0.2.1")}, nil)
+		c.Request.Header.SetMethod(fasthttp.MethodPost)
+		c.Request.SetRequestURI("/upload/" + cnr)
+		c.Request.Header.Set(hdrAPIKey, "4c7a8a2a0e6b4d6f")
+		c.SetUserValue("cid", cnr)
+		h(&c)
+	}
+
+	request()
+	status = fasthttp.StatusForbidden
+	request()
+
+	a.auditRequests(routeGet, func(*fasthttp.RequestCtx) {})(new(fasthttp.RequestCtx))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var rec auditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	require.Equal(t, routeUpload, rec.Route)
+	require.Equal(t, fasthttp.MethodPost, rec.Method)
+	require.Equal(t, "/upload/"+cnr, rec.Path)
+	require.Equal(t, "192.0.2.1", rec.Client.IP)
+	require.Len(t, rec.Client.APIKey, 16)
+	require.Equal(t, "alice", rec.Client.User)
+	require.Equal(t, cnr, rec.Container)
+	require.Equal(t, []string{"2m8PtaoricLouCn5zE8hAFr3gZEBDCZFe9BEgVJTSocY"}, rec.Objects)
+	require.Equal(t, auditResultSuccess, rec.Result)
+
+	rec = auditRecord{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	require.Equal(t, fasthttp.StatusForbidden, rec.Status)
+	require.Equal(t, auditResultFailure, rec.Result)
+	require.Empty(t, rec.Objects)
+}
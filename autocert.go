@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newAutocertManager creates ACME certificate manager for the hosts specified
+// in the configuration.
+func (a *app) newAutocertManager() (*autocert.Manager, error) {
+	hosts := a.cfg.GetStringSlice(cfgTLSAutocertHosts)
+	if len(hosts) == 0 {
+		return nil, errors.New("no hosts specified for automatic certificates")
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Email:      a.cfg.GetString(cfgTLSAutocertEmail),
+	}
+
+	if cacheDir := a.cfg.GetString(cfgTLSAutocertCacheDir); cacheDir != "" {
+		m.Cache = autocert.DirCache(cacheDir)
+	} else {
+		a.log.Warn("certificate cache directory isn't specified, certificates will be requested on every start")
+	}
+
+	return m, nil
+}
@@ -0,0 +1,229 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// gasPrecision is the number of decimals of GAS token.
+const gasPrecision = 8
+
+// Currencies of the gateway account balance.
+const (
+	currencyNeoFS = "neofs"
+	currencyGAS   = "gas"
+)
+
+// accountBalance shows the balance of the gateway account.
+var accountBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "neofs_http_gw",
+	Name:      "account_balance",
+	Help:      "Balance of the gateway account in NeoFS (neofs) and in the sidechain (gas).",
+}, []string{"currency"})
+
+type (
+	// balanceAmount is the balance in the smallest units of the currency.
+	balanceAmount struct {
+		Value     int64   `json:"value"`
+		Precision uint32  `json:"precision"`
+		Amount    float64 `json:"amount"`
+	}
+
+	// balanceInfo is the balance of the gateway account returned by admin API.
+	balanceInfo struct {
+		Account string         `json:"account"`
+		NeoFS   balanceAmount  `json:"neofs"`
+		GAS     *balanceAmount `json:"gas,omitempty"`
+	}
+
+	// balanceMonitor requests balances of the gateway account, GAS balance
+	// is requested only if RPC endpoint is set.
+	balanceMonitor struct {
+		log      *zap.Logger
+		pool     *utils.PoolHolder
+		interval time.Duration
+		// dial creates RPC client, it's nil if RPC endpoint isn't set.
+		dial func() (*client.Client, error)
+
+		// fetch is replaced in tests.
+		fetch func(ctx context.Context) (*balanceInfo, error)
+
+		mu      sync.Mutex
+		rpc     *client.Client
+		gasHash util.Uint160
+	}
+)
+
+func newBalanceAmount(value int64, precision uint32) balanceAmount {
+	return balanceAmount{
+		Value:     value,
+		Precision: precision,
+		Amount:    float64(value) / math.Pow10(int(precision)),
+	}
+}
+
+func (a *app) newBalanceMonitor(ctx context.Context) *balanceMonitor {
+	m := &balanceMonitor{
+		log:      a.log,
+		pool:     a.pool,
+		interval: a.cfg.GetDuration(cfgBalanceRefreshInterval),
+	}
+	m.fetch = m.balances
+
+	if endpoint := a.cfg.GetString(cfgRPCEndpoint); endpoint != "" {
+		opts := client.Options{
+			DialTimeout:    a.cfg.GetDuration(cfgConTimeout),
+			RequestTimeout: a.cfg.GetDuration(cfgReqTimeout),
+		}
+		m.dial = func() (*client.Client, error) {
+			return client.New(ctx, endpoint, opts)
+		}
+	}
+	return m
+}
+
+// balanceMonitor returns the monitor of the gateway account balance, its
+// metric is refreshed in background if it's enabled.
+func (a *app) balanceMonitor(ctx context.Context) *balanceMonitor {
+	a.balancesOnce.Do(func() {
+		a.balances = a.newBalanceMonitor(ctx)
+		if a.balances.interval > 0 {
+			go a.balances.run(ctx)
+		}
+	})
+	return a.balances
+}
+
+func (m *balanceMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.refresh(ctx); err != nil && ctx.Err() == nil {
+			m.log.Warn("could not refresh account balance", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh requests the balances and updates the metric.
+func (m *balanceMonitor) refresh(ctx context.Context) (*balanceInfo, error) {
+	info, err := m.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accountBalance.WithLabelValues(currencyNeoFS).Set(info.NeoFS.Amount)
+	if info.GAS != nil {
+		accountBalance.WithLabelValues(currencyGAS).Set(info.GAS.Amount)
+	}
+	return info, nil
+}
+
+// balances requests the balance of the current pool owner in NeoFS and
+// the balance of GAS in the sidechain.
+func (m *balanceMonitor) balances(ctx context.Context) (*balanceInfo, error) {
+	clientPool := m.pool.Pool()
+	owner := clientPool.OwnerID()
+
+	var prm pool.PrmBalanceGet
+	prm.SetAccount(*owner)
+
+	res, err := clientPool.Balance(ctx, prm)
+	if err != nil {
+		return nil, fmt.Errorf("could not get neofs balance: %w", err)
+	}
+
+	info := &balanceInfo{
+		Account: owner.EncodeToString(),
+		NeoFS:   newBalanceAmount(res.Value(), res.Precision()),
+	}
+
+	if m.dial != nil {
+		gas, err := m.gasBalance(owner)
+		if err != nil {
+			return nil, fmt.Errorf("could not get gas balance: %w", err)
+		}
+		info.GAS = gas
+	}
+	return info, nil
+}
+
+// gasBalance requests GAS balance of the account from RPC endpoint, the
+// client is dialed on the first call and after failures.
+func (m *balanceMonitor) gasBalance(owner *user.ID) (*balanceAmount, error) {
+	acc, err := address.StringToUint160(owner.EncodeToString())
+	if err != nil {
+		return nil, fmt.Errorf("invalid account: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rpc == nil {
+		cli, err := m.dial()
+		if err != nil {
+			return nil, fmt.Errorf("could not create rpc client: %w", err)
+		}
+		if err = cli.Init(); err != nil {
+			return nil, fmt.Errorf("could not init rpc client: %w", err)
+		}
+		if m.gasHash, err = cli.GetNativeContractHash(nativenames.Gas); err != nil {
+			return nil, fmt.Errorf("could not get gas contract hash: %w", err)
+		}
+		m.rpc = cli
+	}
+
+	value, err := m.rpc.NEP17BalanceOf(m.gasHash, acc)
+	if err != nil {
+		m.rpc = nil
+		return nil, err
+	}
+
+	res := newBalanceAmount(value, gasPrecision)
+	return &res, nil
+}
+
+// handler returns the current balance of the gateway account, the metric is
+// updated as well.
+func (m *balanceMonitor) handler(ctx context.Context) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		info, err := m.refresh(utils.RequestContext(c, ctx))
+		if err != nil {
+			m.log.Error("could not get account balance", zap.Error(err))
+			response.Error(c, "could not get account balance: "+err.Error(), fasthttp.StatusBadGateway)
+			return
+		}
+
+		data, err := json.Marshal(info)
+		if err != nil {
+			m.log.Error("could not encode account balance", zap.Error(err))
+			response.Error(c, "could not encode account balance: "+err.Error(), fasthttp.StatusInternalServerError)
+			return
+		}
+
+		c.SetContentType("application/json")
+		c.SetBody(data)
+	}
+}
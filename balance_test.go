@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	a := &app{cfg: viper.New(), log: zap.NewNop()}
+	m := a.newBalanceMonitor(context.Background())
+	require.Nil(t, m.dial)
+
+	gas := newBalanceAmount(2000000000, gasPrecision)
+	m.fetch = func(context.Context) (*balanceInfo, error) {
+		return &balanceInfo{
+			Account: "NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM",
+			NeoFS:   newBalanceAmount(1500000000000, 12),
+			GAS:     &gas,
+		}, nil
+	}
+
+	var c fasthttp.RequestCtx
+	m.handler(context.Background())(&c)
+	require.Equal(t, fasthttp.StatusOK, c.Response.StatusCode())
+
+	var info balanceInfo
+	require.NoError(t, json.Unmarshal(c.Response.Body(), &info))
+	require.Equal(t, "NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM", info.Account)
+	require.EqualValues(t, 1500000000000, info.NeoFS.Value)
+	require.EqualValues(t, 12, info.NeoFS.Precision)
+	require.Equal(t, 1.5, info.NeoFS.Amount)
+	require.NotNil(t, info.GAS)
+	require.Equal(t, 20.0, info.GAS.Amount)
+
+	m.fetch = func(context.Context) (*balanceInfo, error) {
+		return nil, errors.New("connection refused")
+	}
+	c.Response.Reset()
+	m.handler(context.Background())(&c)
+	require.Equal(t, fasthttp.StatusBadGateway, c.Response.StatusCode())
+}
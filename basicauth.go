@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	basicAuthPrefix   = "Basic "
+	htpasswdSHAPrefix = "{SHA}"
+
+	// basicAuthUserKey is the request user value with the authenticated user.
+	basicAuthUserKey = "__basic_auth_user"
+)
+
+// credentials maps user names to passwords, password hashes from htpasswd
+// file are stored as is.
+type credentials map[string]string
+
+// loadHtpasswd reads bcrypt and SHA1 hashed entries of htpasswd file.
+func loadHtpasswd(path string, creds credentials) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open htpasswd file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		user, hash, ok := cut(entry, ":")
+		if !ok || user == "" {
+			return fmt.Errorf("invalid htpasswd entry at line %d", line)
+		}
+		if !strings.HasPrefix(hash, "$2") && !strings.HasPrefix(hash, htpasswdSHAPrefix) {
+			return fmt.Errorf("unsupported password hash of user %s, only bcrypt and SHA1 are supported", user)
+		}
+
+		creds[user] = hash
+	}
+
+	return scanner.Err()
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// verify checks the password of the user, stored password can be either
+// htpasswd hash or plain text.
+func (c credentials) verify(user, password string) bool {
+	stored, ok := c[user]
+	if !ok || stored == "" {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(stored, "$2"):
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	case strings.HasPrefix(stored, htpasswdSHAPrefix):
+		sum := sha1.Sum([]byte(password))
+		password = htpasswdSHAPrefix + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
+
+// parseBasicAuth extracts user name and password from Authorization header.
+func parseBasicAuth(h *fasthttp.RequestHeader) (string, string, bool) {
+	auth := string(h.Peek(fasthttp.HeaderAuthorization))
+	if !strings.HasPrefix(auth, basicAuthPrefix) {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, basicAuthPrefix))
+	if err != nil {
+		return "", "", false
+	}
+
+	return cut(string(decoded), ":")
+}
+
+// loadCredentials reads the configured user and htpasswd file entries. The
+// configured user must have a password.
+func loadCredentials(v *viper.Viper, secrets map[string]string) (credentials, error) {
+	creds := make(credentials)
+	if user := v.GetString(cfgBasicAuthUser); user != "" {
+		password, _ := secretValue(v, secrets, cfgBasicAuthPassword)
+		if password == "" {
+			return nil, fmt.Errorf("basic auth password of %s isn't set", user)
+		}
+		creds[user] = password
+	}
+	if path := v.GetString(cfgBasicAuthHtpasswd); path != "" {
+		if err := loadHtpasswd(path, creds); err != nil {
+			return nil, fmt.Errorf("could not load basic auth credentials: %w", err)
+		}
+	}
+	return creds, nil
+}
+
+// basicAuth requires HTTP Basic credentials for upload routes if the user or
+// htpasswd file is configured.
+func (a *app) basicAuth(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	creds := a.basicCreds
+	if routeOperation(route) != operationUpload || len(creds) == 0 {
+		return h
+	}
+
+	challenge := `Basic realm="` + a.cfg.GetString(cfgBasicAuthRealm) + `", charset="UTF-8"`
+
+	return func(c *fasthttp.RequestCtx) {
+		user, password, ok := parseBasicAuth(&c.Request.Header)
+		if !ok || !creds.verify(user, password) {
+			a.log.Warn("basic authentication failed", zap.String("user", user))
+			response.Error(c, "authentication required", fasthttp.StatusUnauthorized)
+			c.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, challenge)
+			return
+		}
+		c.SetUserValue(basicAuthUserKey, user)
+
+		h(c)
+	}
+}
@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBasicAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("bcrypt-pass"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	htpasswd := filepath.Join(t.TempDir(), ".htpasswd")
+	content := "# comment\n" +
+		"bob:" + string(hash) + "\n" +
+		"carol:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n" // password
+	require.NoError(t, os.WriteFile(htpasswd, []byte(content), 0600))
+
+	v := viper.New()
+	v.Set(cfgBasicAuthUser, "alice")
+	v.Set(cfgBasicAuthPassword, "secret")
+	v.Set(cfgBasicAuthHtpasswd, htpasswd)
+	v.Set(cfgBasicAuthRealm, "gate")
+	creds, err := loadCredentials(v, nil)
+	require.NoError(t, err)
+	a := &app{cfg: v, log: zap.NewNop(), basicCreds: creds}
+
+	h := func(c *fasthttp.RequestCtx) { c.SetStatusCode(fasthttp.StatusOK) }
+
+	serve := func(route, user, password string) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		if user != "" {
+			c.Request.Header.Set(fasthttp.HeaderAuthorization,
+				"Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+		}
+		a.basicAuth(route, h)(&c)
+		return &c
+	}
+
+	for _, tc := range []struct {
+		name, user, password string
+		status               int
+	}{
+		{name: "plain", user: "alice", password: "secret", status: fasthttp.StatusOK},
+		{name: "bcrypt", user: "bob", password: "bcrypt-pass", status: fasthttp.StatusOK},
+		{name: "sha1", user: "carol", password: "password", status: fasthttp.StatusOK},
+		{name: "wrong password", user: "bob", password: "secret", status: fasthttp.StatusUnauthorized},
+		{name: "unknown user", user: "dave", password: "secret", status: fasthttp.StatusUnauthorized},
+		{name: "no credentials", status: fasthttp.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := serve(routeUpload, tc.user, tc.password)
+			require.Equal(t, tc.status, c.Response.StatusCode())
+			if tc.status == fasthttp.StatusUnauthorized {
+				require.Equal(t, `Basic realm="gate", charset="UTF-8"`,
+					string(c.Response.Header.Peek(fasthttp.HeaderWWWAuthenticate)))
+			}
+		})
+	}
+
+	t.Run("download", func(t *testing.T) {
+		c := serve(routeGet, "", "")
+		require.Equal(t, fasthttp.StatusOK, c.Response.StatusCode())
+	})
+}
+
+func TestLoadHtpasswdUnsupported(t *testing.T) {
+	htpasswd := filepath.Join(t.TempDir(), ".htpasswd")
+	require.NoError(t, os.WriteFile(htpasswd, []byte("bob:$apr1$salt$hash\n"), 0600))
+
+	require.Error(t, loadHtpasswd(htpasswd, make(credentials)))
+}
+
+func TestLoadCredentialsNoPassword(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgBasicAuthUser, "alice")
+	_, err := loadCredentials(v, nil)
+	require.Error(t, err)
+
+	v.Set(cfgBasicAuthPassword, "vault:kv/gw#password")
+	_, err = loadCredentials(v, map[string]string{cfgBasicAuthPassword: ""})
+	require.Error(t, err)
+
+	require.False(t, credentials{"alice": ""}.verify("alice", ""))
+}
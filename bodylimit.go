@@ -0,0 +1,27 @@
+package gateway
+
+import "github.com/valyala/fasthttp"
+
+// limitUploadBodies makes the hook limit bodies of upload requests with
+// uploadLimit and bodies of other requests with limit. Bodies exceeding the
+// limit are streamed if request body streaming is enabled and rejected
+// otherwise, so that uploads never keep more than uploadLimit bytes in memory.
+func limitUploadBodies(names *routeNames, hook func(*fasthttp.RequestHeader) fasthttp.RequestConfig, limit, uploadLimit int) func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	if limit <= 0 {
+		limit = fasthttp.DefaultMaxRequestBodySize
+	}
+	if uploadLimit <= 0 || uploadLimit > limit {
+		uploadLimit = limit
+	}
+
+	return func(h *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		cfg := hook(h)
+		// The limit is set for every request, fasthttp keeps the last one
+		// for subsequent requests of the connection.
+		cfg.MaxRequestBodySize = limit
+		if routeOperation(names.lookup(h)) == operationUpload {
+			cfg.MaxRequestBodySize = uploadLimit
+		}
+		return cfg
+	}
+}
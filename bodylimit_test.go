@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLimitUploadBodies(t *testing.T) {
+	names := testRouteNames()
+	hook := limitUploadBodies(names, func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+		return fasthttp.RequestConfig{}
+	}, 4<<20, 1<<20)
+
+	limitFor := func(method, uri string) int {
+		var h fasthttp.RequestHeader
+		h.SetMethod(method)
+		h.SetRequestURI(uri)
+		return hook(&h).MaxRequestBodySize
+	}
+
+	require.Equal(t, 1<<20, limitFor(fasthttp.MethodPost, "/upload/cid"))
+	require.Equal(t, 1<<20, limitFor(fasthttp.MethodPut, "/webdav/cid/file"))
+	require.Equal(t, 4<<20, limitFor(fasthttp.MethodGet, "/get/cid/oid"))
+	require.Equal(t, 4<<20, limitFor(fasthttp.MethodGet, "/metrics"))
+
+	hook = limitUploadBodies(names, func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+		return fasthttp.RequestConfig{}
+	}, 0, 0)
+	require.Equal(t, fasthttp.DefaultMaxRequestBodySize, limitFor(fasthttp.MethodPost, "/upload/cid"))
+}
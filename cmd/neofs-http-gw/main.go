@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gateway "github.com/nspcc-dev/neofs-http-gw"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		v      = gateway.Settings()
+		l, lvl = gateway.NewLogger(v)
+	)
+	globalContext, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	app, err := gateway.New(globalContext, gateway.WithLogger(l), gateway.WithLogLevel(lvl), gateway.WithConfig(v))
+	if err != nil {
+		l.Fatal("failed to start application", zap.Error(err))
+	}
+	go handleSignals(globalContext, app, l)
+	go func() {
+		if err := app.Serve(globalContext); err != nil {
+			l.Fatal("failed to serve", zap.Error(err))
+		}
+	}()
+	app.Wait()
+}
+
+// handleSignals reloads the gateway key on SIGHUP and toggles drain mode on
+// SIGUSR1.
+func handleSignals(ctx context.Context, app gateway.App, l *zap.Logger) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP, syscall.SIGUSR1)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-ch:
+			switch sig {
+			case syscall.SIGHUP:
+				l.Info("SIGHUP received, reloading gateway key")
+				if err := app.ReloadKey(ctx); err != nil {
+					l.Error("could not reload gateway key", zap.Error(err))
+					continue
+				}
+				l.Info("gateway key reloaded")
+			case syscall.SIGUSR1:
+				draining := !app.Draining()
+				app.SetDraining(draining)
+				if draining {
+					l.Info("SIGUSR1 received, drain mode enabled")
+				} else {
+					l.Info("SIGUSR1 received, drain mode disabled")
+				}
+			}
+		}
+	}
+}
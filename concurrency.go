@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+)
+
+const concurrencySlotKey = "__concurrency_slot"
+
+// concurrencyLimiter limits the number of simultaneously processed requests,
+// requests over the limit wait in a bounded queue.
+type concurrencyLimiter struct {
+	slots   chan struct{}
+	queue   int32
+	waiting int32
+	timeout time.Duration
+}
+
+// newConcurrencyLimiter creates limiter for the section of concurrency
+// configuration, it returns nil if there is no limit.
+func (a *app) newConcurrencyLimiter(section string) *concurrencyLimiter {
+	limit := a.cfg.GetInt(section + ".max")
+	if limit <= 0 {
+		return nil
+	}
+
+	return &concurrencyLimiter{
+		slots:   make(chan struct{}, limit),
+		queue:   a.cfg.GetInt32(section + ".queue"),
+		timeout: a.cfg.GetDuration(section + ".queue_timeout"),
+	}
+}
+
+// acquire takes a slot waiting for it in the queue if all of them are busy.
+// It returns false if the queue is full or the slot isn't freed in time.
+func (l *concurrencyLimiter) acquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if atomic.AddInt32(&l.waiting, 1) > l.queue {
+		atomic.AddInt32(&l.waiting, -1)
+		return false
+	}
+	defer atomic.AddInt32(&l.waiting, -1)
+
+	if l.timeout <= 0 {
+		l.slots <- struct{}{}
+		return true
+	}
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// concurrencySlot releases the slot on close. It's stored in request user
+// values, so that the slot is held until the response body stream is sent:
+// fasthttp closes user values after the response is written.
+type concurrencySlot struct {
+	once    sync.Once
+	limiter *concurrencyLimiter
+}
+
+func (s *concurrencySlot) Close() error {
+	s.once.Do(s.limiter.release)
+	return nil
+}
+
+// concurrencyLimit limits simultaneous uploads or downloads depending on the
+// route, requests that can't be processed get 503 status.
+func (a *app) concurrencyLimit(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	l := a.downloadLimiter
+	if routeOperation(route) == operationUpload {
+		l = a.uploadLimiter
+	}
+
+	if l == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		if !l.acquire() {
+			response.Error(c, "too many requests in progress", fasthttp.StatusServiceUnavailable)
+			c.Response.Header.Set(fasthttp.HeaderRetryAfter, "1")
+			return
+		}
+		c.SetUserValue(concurrencySlotKey, &concurrencySlot{limiter: l})
+
+		h(c)
+	}
+}
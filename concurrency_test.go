@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New()}
+		require.Nil(t, a.newConcurrencyLimiter(cfgConcurrencyUpload))
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		l := &concurrencyLimiter{slots: make(chan struct{}, 1), queue: 1, timeout: time.Second}
+		require.True(t, l.acquire())
+
+		acquired := make(chan bool)
+		go func() { acquired <- l.acquire() }()
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&l.waiting) == 1 }, time.Second, time.Millisecond)
+
+		// the queue is full
+		require.False(t, l.acquire())
+
+		l.release()
+		require.True(t, <-acquired)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		l := &concurrencyLimiter{slots: make(chan struct{}, 1), queue: 1, timeout: time.Millisecond}
+		require.True(t, l.acquire())
+		require.False(t, l.acquire())
+	})
+
+	t.Run("slot is held until user values are closed", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgConcurrencyDownload+".max", 1)
+		a := &app{cfg: v}
+		a.downloadLimiter = a.newConcurrencyLimiter(cfgConcurrencyDownload)
+
+		h := a.concurrencyLimit(routeGet, func(c *fasthttp.RequestCtx) {
+			c.SetStatusCode(fasthttp.StatusOK)
+		})
+
+		var first, second fasthttp.RequestCtx
+		h(&first)
+		require.Equal(t, fasthttp.StatusOK, first.Response.StatusCode())
+
+		h(&second)
+		require.Equal(t, fasthttp.StatusServiceUnavailable, second.Response.StatusCode())
+
+		first.ResetUserValues()
+		second.Response.Reset()
+		h(&second)
+		require.Equal(t, fasthttp.StatusOK, second.Response.StatusCode())
+	})
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+const corsWildcard = "*"
+
+// corsSettings is CORS policy applied to all routes.
+type corsSettings struct {
+	allowOrigins     map[string]struct{}
+	allowAnyOrigin   bool
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           string
+}
+
+// cors adds CORS headers to the responses and answers preflight requests if
+// any origin is allowed in the configuration.
+func (a *app) cors(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	origins := a.cfg.GetStringSlice(cfgCORSAllowOrigins)
+	if len(origins) == 0 {
+		return h
+	}
+
+	s := &corsSettings{
+		allowOrigins:     make(map[string]struct{}, len(origins)),
+		allowMethods:     strings.Join(a.cfg.GetStringSlice(cfgCORSAllowMethods), ", "),
+		allowHeaders:     strings.Join(a.cfg.GetStringSlice(cfgCORSAllowHeaders), ", "),
+		exposeHeaders:    strings.Join(a.cfg.GetStringSlice(cfgCORSExposeHeaders), ", "),
+		allowCredentials: a.cfg.GetBool(cfgCORSAllowCredentials),
+	}
+	if maxAge := a.cfg.GetDuration(cfgCORSMaxAge); maxAge > 0 {
+		s.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	}
+	for _, origin := range origins {
+		if origin == corsWildcard {
+			s.allowAnyOrigin = true
+		}
+		s.allowOrigins[strings.ToLower(origin)] = struct{}{}
+	}
+
+	return s.handler(h)
+}
+
+func (s *corsSettings) handler(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		origin := string(c.Request.Header.Peek(fasthttp.HeaderOrigin))
+		if origin == "" {
+			h(c)
+			return
+		}
+
+		if !c.IsOptions() || len(c.Request.Header.Peek(fasthttp.HeaderAccessControlRequestMethod)) == 0 {
+			// headers are set after the handler, since error responses are reset
+			h(c)
+			c.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderOrigin)
+			if s.allowed(origin) {
+				s.setOrigin(c, origin)
+				if s.exposeHeaders != "" {
+					c.Response.Header.Set(fasthttp.HeaderAccessControlExposeHeaders, s.exposeHeaders)
+				}
+			}
+			return
+		}
+
+		c.SetStatusCode(fasthttp.StatusNoContent)
+		c.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderOrigin)
+		c.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAccessControlRequestMethod)
+		c.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAccessControlRequestHeaders)
+		if !s.allowed(origin) {
+			return
+		}
+
+		s.setOrigin(c, origin)
+		c.Response.Header.Set(fasthttp.HeaderAccessControlAllowMethods, s.allowMethods)
+		if s.allowHeaders != "" {
+			c.Response.Header.Set(fasthttp.HeaderAccessControlAllowHeaders, s.allowHeaders)
+		} else if requested := c.Request.Header.Peek(fasthttp.HeaderAccessControlRequestHeaders); len(requested) != 0 {
+			// no restrictions configured, so all requested headers are allowed
+			c.Response.Header.SetBytesV(fasthttp.HeaderAccessControlAllowHeaders, requested)
+		}
+		if s.maxAge != "" {
+			c.Response.Header.Set(fasthttp.HeaderAccessControlMaxAge, s.maxAge)
+		}
+	}
+}
+
+func (s *corsSettings) allowed(origin string) bool {
+	if s.allowAnyOrigin {
+		return true
+	}
+	_, ok := s.allowOrigins[strings.ToLower(origin)]
+	return ok
+}
+
+// setOrigin sets allowed origin header, credentials can't be used with the
+// wildcard, so the origin is reflected then.
+func (s *corsSettings) setOrigin(c *fasthttp.RequestCtx, origin string) {
+	if s.allowCredentials {
+		c.Response.Header.Set(fasthttp.HeaderAccessControlAllowOrigin, origin)
+		c.Response.Header.Set(fasthttp.HeaderAccessControlAllowCredentials, "true")
+		return
+	}
+
+	if s.allowAnyOrigin {
+		origin = corsWildcard
+	}
+	c.Response.Header.Set(fasthttp.HeaderAccessControlAllowOrigin, origin)
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestCORS(t *testing.T) {
+	handler := func(c *fasthttp.RequestCtx) {
+		c.Error("not found", fasthttp.StatusNotFound)
+	}
+
+	newHandler := func(origins []string, credentials bool) fasthttp.RequestHandler {
+		v := viper.New()
+		v.Set(cfgCORSAllowOrigins, origins)
+		v.Set(cfgCORSAllowMethods, []string{fasthttp.MethodGet, fasthttp.MethodPost})
+		v.Set(cfgCORSExposeHeaders, []string{"X-Object-Id"})
+		v.Set(cfgCORSAllowCredentials, credentials)
+		v.Set(cfgCORSMaxAge, time.Hour)
+
+		a := &app{cfg: v, log: zap.NewNop()}
+		return a.cors(handler)
+	}
+
+	newRequest := func(method, origin string) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Request.Header.SetMethod(method)
+		c.Request.SetRequestURI("/get/cid/oid")
+		if origin != "" {
+			c.Request.Header.Set(fasthttp.HeaderOrigin, origin)
+		}
+		return &c
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodGet, "https://app.example.com")
+		newHandler(nil, false)(c)
+
+		require.Equal(t, fasthttp.StatusNotFound, c.Response.StatusCode())
+		require.Empty(t, c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("simple request", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodGet, "https://app.example.com")
+		newHandler([]string{"https://app.example.com"}, false)(c)
+
+		require.Equal(t, fasthttp.StatusNotFound, c.Response.StatusCode())
+		require.Equal(t, "https://app.example.com", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+		require.Equal(t, "X-Object-Id", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlExposeHeaders)))
+	})
+
+	t.Run("unknown origin", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodGet, "https://evil.example.com")
+		newHandler([]string{"https://app.example.com"}, false)(c)
+
+		require.Equal(t, fasthttp.StatusNotFound, c.Response.StatusCode())
+		require.Empty(t, c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodGet, "https://app.example.com")
+		newHandler([]string{"*"}, false)(c)
+
+		require.Equal(t, "*", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+	})
+
+	t.Run("wildcard with credentials", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodGet, "https://app.example.com")
+		newHandler([]string{"*"}, true)(c)
+
+		require.Equal(t, "https://app.example.com", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+		require.Equal(t, "true", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowCredentials)))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodOptions, "https://app.example.com")
+		c.Request.Header.Set(fasthttp.HeaderAccessControlRequestMethod, fasthttp.MethodPost)
+		c.Request.Header.Set(fasthttp.HeaderAccessControlRequestHeaders, "X-Attribute-Filename")
+		newHandler([]string{"https://app.example.com"}, false)(c)
+
+		require.Equal(t, fasthttp.StatusNoContent, c.Response.StatusCode())
+		require.Equal(t, "https://app.example.com", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+		require.Equal(t, "GET, POST", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowMethods)))
+		require.Equal(t, "X-Attribute-Filename", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowHeaders)))
+		require.Equal(t, "3600", string(c.Response.Header.Peek(fasthttp.HeaderAccessControlMaxAge)))
+	})
+
+	t.Run("preflight from unknown origin", func(t *testing.T) {
+		c := newRequest(fasthttp.MethodOptions, "https://evil.example.com")
+		c.Request.Header.Set(fasthttp.HeaderAccessControlRequestMethod, fasthttp.MethodPost)
+		newHandler([]string{"https://app.example.com"}, false)(c)
+
+		require.Equal(t, fasthttp.StatusNoContent, c.Response.StatusCode())
+		require.Empty(t, c.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin))
+	})
+}
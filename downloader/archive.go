@@ -0,0 +1,297 @@
+package downloader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Archive formats.
+const (
+	archiveFormatZip = "zip"
+	archiveFormatTar = "tar"
+)
+
+// maxArchiveObjects is the maximum number of objects in archive request.
+const maxArchiveObjects = 1000
+
+type (
+	// archiveRequest is the body of archive request: object IDs and attribute
+	// selectors (every one is a set of attributes the object must have) of
+	// objects to put to the archive.
+	archiveRequest struct {
+		Format     string              `json:"format"`
+		Objects    []string            `json:"objects"`
+		Attributes []map[string]string `json:"attributes"`
+	}
+
+	// archiveWriter writes objects to an archive of some format.
+	archiveWriter interface {
+		// create adds the object header to the archive and returns the writer
+		// of its payload.
+		create(obj *object.Object) (io.Writer, error)
+		Flush() error
+		Close() error
+	}
+
+	zipArchive struct {
+		*zip.Writer
+		method uint16
+	}
+
+	tarArchive struct {
+		*tar.Writer
+	}
+)
+
+func (a zipArchive) create(obj *object.Object) (io.Writer, error) {
+	return a.CreateHeader(&zip.FileHeader{
+		Name:     archiveFilePath(obj),
+		Method:   a.method,
+		Modified: time.Now(),
+	})
+}
+
+func (a tarArchive) create(obj *object.Object) (io.Writer, error) {
+	err := a.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     archiveFilePath(obj),
+		Size:     int64(obj.PayloadSize()),
+		Mode:     0644,
+		ModTime:  time.Now(),
+	})
+	return a.Writer, err
+}
+
+// archiveFilePath returns the name of the object in archive: its FilePath,
+// FileName or ID.
+func archiveFilePath(obj *object.Object) string {
+	var name string
+	for _, attr := range obj.Attributes() {
+		switch attr.Key() {
+		case attributeFilePath:
+			return attr.Value()
+		case object.AttributeFileName:
+			name = attr.Value()
+		}
+	}
+	if name != "" {
+		return name
+	}
+
+	id, _ := obj.ID()
+	return id.String()
+}
+
+// parseArchiveRequest decodes and validates archive request body.
+func parseArchiveRequest(data []byte) (*archiveRequest, error) {
+	var req archiveRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	switch req.Format {
+	case "":
+		req.Format = archiveFormatZip
+	case archiveFormatZip, archiveFormatTar:
+	default:
+		return nil, fmt.Errorf("unknown archive format: %s", req.Format)
+	}
+
+	switch n := len(req.Objects) + len(req.Attributes); {
+	case n == 0:
+		return nil, errors.New("no objects to archive")
+	case n > maxArchiveObjects:
+		return nil, fmt.Errorf("number of objects must not exceed %d", maxArchiveObjects)
+	}
+
+	for _, attrs := range req.Attributes {
+		if len(attrs) == 0 {
+			return nil, errors.New("attribute selector is empty")
+		}
+	}
+
+	return &req, nil
+}
+
+// selectorFilters returns the attributes of the selector in stable order.
+func selectorFilters(attrs map[string]string) []searchFilter {
+	res := make([]searchFilter, 0, len(attrs))
+	for key, val := range attrs {
+		res = append(res, searchFilter{Key: key, Value: val})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+// archiveObjects returns the IDs of requested objects, attribute selectors are
+// resolved the same way as in attribute downloads.
+func (d *Downloader) archiveObjects(c *fasthttp.RequestCtx, req *archiveRequest, containerID *cid.ID) ([]oid.ID, error) {
+	ids := make([]oid.ID, 0, len(req.Objects)+len(req.Attributes))
+
+	for _, s := range req.Objects {
+		var id oid.ID
+		if err := id.DecodeString(s); err != nil {
+			return nil, fmt.Errorf("wrong object id %s: %w", s, err)
+		}
+		ids = append(ids, id)
+	}
+
+	for _, attrs := range req.Attributes {
+		res, err := d.searchByFilters(c, containerID, attributeSearchFilters(selectorFilters(attrs)))
+		if err != nil {
+			return nil, fmt.Errorf("could not search for objects: %w", err)
+		}
+
+		id, err := d.selectObject(c, res, *containerID)
+		res.Close()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("object not found: %v", attrs)
+			}
+			return nil, fmt.Errorf("read object list failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// DownloadArchive handles requests for the archive of objects listed in JSON
+// body.
+func (d *Downloader) DownloadArchive(c *fasthttp.RequestCtx) {
+	var (
+		scid, _    = c.UserValue("cid").(string)
+		log        = d.log.With(zap.String("cid", scid))
+		ctx        = utils.RequestContext(c, d.appCtx)
+		clientPool = d.pool.Acquire(c)
+	)
+
+	req, err := parseArchiveRequest(c.Request.Body())
+	if err != nil {
+		log.Error("invalid archive request", zap.Error(err))
+		response.Error(c, "invalid archive request: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	containerID, err := utils.GetContainerID(ctx, scid, d.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch and store bearer token", zap.Error(err))
+		response.Error(c, "could not fetch and store bearer token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	ids, err := d.archiveObjects(c, req, containerID)
+	if err != nil {
+		log.Error("could not select objects", zap.Error(err))
+		response.Error(c, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if req.Format == archiveFormatTar {
+		c.Response.Header.Set(fasthttp.HeaderContentType, "application/x-tar")
+	} else {
+		c.Response.Header.Set(fasthttp.HeaderContentType, "application/zip")
+	}
+	c.Response.Header.Set(fasthttp.HeaderContentDisposition, "attachment; filename=\"archive."+req.Format+"\"")
+	c.Response.SetStatusCode(http.StatusOK)
+
+	btoken := bearerToken(c)
+	key := utils.RequestKey(c)
+
+	utils.SetBodyStreamWriter(c, func(w *bufio.Writer) {
+		var aw archiveWriter
+		if req.Format == archiveFormatTar {
+			aw = tarArchive{tar.NewWriter(w)}
+		} else {
+			method := zip.Store
+			if d.settings.ZipCompression {
+				method = zip.Deflate
+			}
+			aw = zipArchive{Writer: zip.NewWriter(w), method: method}
+		}
+
+		var (
+			addr address.Address
+			buf  = utils.PayloadBuffers.Get()
+		)
+		defer utils.PayloadBuffers.Put(buf)
+		addr.SetContainerID(*containerID)
+
+		for _, id := range ids {
+			addr.SetObjectID(id)
+			if err := archiveObject(ctx, clientPool, d.retry, aw, addr, btoken, key, *buf); err != nil {
+				log.Error("file streaming failure", zap.Stringer("oid", id), zap.Error(err))
+				return
+			}
+		}
+
+		if err := aw.Close(); err != nil {
+			log.Error("file streaming failure", zap.Error(err))
+		}
+	})
+}
+
+func archiveObject(ctx context.Context, clientPool *pool.Pool, retry utils.RetryPolicy, aw archiveWriter, addr address.Address, btoken *bearer.Token, key *ecdsa.PrivateKey, buf []byte) error {
+	var prm pool.PrmObjectGet
+	prm.SetAddress(addr)
+	if btoken != nil {
+		prm.UseBearer(*btoken)
+	}
+	if key != nil {
+		prm.UseKey(key)
+	}
+
+	var resGet *pool.ResGetObject
+	err := retry.Do(ctx, func() error {
+		var err error
+		resGet, err = clientPool.GetObject(ctx, prm)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("get NeoFS object: %v", err)
+	}
+	defer resGet.Payload.Close()
+
+	objWriter, err := aw.create(&resGet.Header)
+	if err != nil {
+		return fmt.Errorf("archive create header: %v", err)
+	}
+
+	if _, err = io.CopyBuffer(objWriter, resGet.Payload, buf); err != nil {
+		return fmt.Errorf("copy object payload to archive: %v", err)
+	}
+
+	if err = aw.Flush(); err != nil {
+		return fmt.Errorf("flush archive writer: %v", err)
+	}
+
+	return nil
+}
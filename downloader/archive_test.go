@@ -0,0 +1,36 @@
+package downloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArchiveRequest(t *testing.T) {
+	req, err := parseArchiveRequest([]byte(`{"objects":["id"],"attributes":[{"FileName":"cat.jpeg"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, archiveFormatZip, req.Format)
+	require.Equal(t, []string{"id"}, req.Objects)
+	require.Equal(t, []map[string]string{{"FileName": "cat.jpeg"}}, req.Attributes)
+
+	req, err = parseArchiveRequest([]byte(`{"format":"tar","objects":["id"]}`))
+	require.NoError(t, err)
+	require.Equal(t, archiveFormatTar, req.Format)
+
+	for _, body := range []string{
+		`{"objects":`,
+		`{"format":"rar","objects":["id"]}`,
+		`{}`,
+		`{"attributes":[{}]}`,
+	} {
+		_, err = parseArchiveRequest([]byte(body))
+		require.Error(t, err, body)
+	}
+}
+
+func TestSelectorFilters(t *testing.T) {
+	require.Equal(t, []searchFilter{
+		{Key: "Project", Value: "x"},
+		{Key: "Type", Value: "report"},
+	}, selectorFilters(map[string]string{"Type": "report", "Project": "x"}))
+}
@@ -0,0 +1,123 @@
+package downloader
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+type (
+	checksumInfo struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	// objectHeader is the object header returned by GET /attributes/{cid}/{oid}.
+	objectHeader struct {
+		ObjectID        string            `json:"object_id"`
+		ContainerID     string            `json:"container_id"`
+		Owner           string            `json:"owner"`
+		Type            string            `json:"type"`
+		CreationEpoch   uint64            `json:"creation_epoch"`
+		PayloadSize     uint64            `json:"payload_size"`
+		PayloadChecksum *checksumInfo     `json:"payload_checksum,omitempty"`
+		HomomorphicHash *checksumInfo     `json:"homomorphic_hash,omitempty"`
+		Attributes      map[string]string `json:"attributes"`
+	}
+)
+
+func newChecksumInfo(cs checksum.Checksum, ok bool) *checksumInfo {
+	if !ok {
+		return nil
+	}
+	return &checksumInfo{Type: cs.Type().String(), Value: hex.EncodeToString(cs.Value())}
+}
+
+func newObjectHeader(obj *object.Object) objectHeader {
+	objID, _ := obj.ID()
+	cnrID, _ := obj.ContainerID()
+
+	res := objectHeader{
+		ObjectID:        objID.String(),
+		ContainerID:     cnrID.String(),
+		Type:            obj.Type().String(),
+		CreationEpoch:   obj.CreationEpoch(),
+		PayloadSize:     obj.PayloadSize(),
+		PayloadChecksum: newChecksumInfo(obj.PayloadChecksum()),
+		HomomorphicHash: newChecksumInfo(obj.PayloadHomomorphicHash()),
+		Attributes:      make(map[string]string),
+	}
+	if owner := obj.OwnerID(); owner != nil {
+		res.Owner = owner.String()
+	}
+	for _, attr := range obj.Attributes() {
+		res.Attributes[attr.Key()] = attr.Value()
+	}
+
+	return res
+}
+
+// objectHeader requests the object header with the credentials of the
+// request. It replies with the error and returns false if it fails.
+func (r request) objectHeader(clnt *pool.Pool, objectAddress *address.Address) (*object.Object, bool) {
+	var start = time.Now()
+	if err := tokens.StoreBearerToken(r.RequestCtx); err != nil {
+		r.log.Error("could not fetch and store bearer token", zap.Error(err))
+		response.Error(r.RequestCtx, "could not fetch and store bearer token", fasthttp.StatusBadRequest)
+		return nil, false
+	}
+
+	var prm pool.PrmObjectHead
+	prm.SetAddress(*objectAddress)
+	if btoken := bearerToken(r.RequestCtx); btoken != nil {
+		prm.UseBearer(*btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	var obj *object.Object
+	err := r.retry.Do(r.appCtx, func() error {
+		var err error
+		obj, err = r.hedgedHead(clnt, prm)
+		return err
+	})
+	if err != nil {
+		r.handleNeoFSErr(err, start)
+		return nil, false
+	}
+	return obj, true
+}
+
+func (r request) objectAttributes(clnt *pool.Pool, objectAddress *address.Address) {
+	obj, ok := r.objectHeader(clnt, objectAddress)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(newObjectHeader(obj))
+	if err != nil {
+		r.log.Error("could not encode object header", zap.Error(err))
+		response.Error(r.RequestCtx, "could not encode object header: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	r.SetContentType("application/json; charset=UTF-8")
+	r.SetBody(data)
+}
+
+// AttributesByAddress handles requests for object header in JSON using
+// simple cid/oid format.
+func (d *Downloader) AttributesByAddress(c *fasthttp.RequestCtx) {
+	d.byAddress(c, request.objectAttributes)
+}
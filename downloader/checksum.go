@@ -0,0 +1,75 @@
+package downloader
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// objectChecksums are the payload hashes returned by GET
+// /checksum/{cid}/{oid}.
+type objectChecksums struct {
+	ObjectID    string `json:"object_id"`
+	ContainerID string `json:"container_id"`
+	PayloadSize uint64 `json:"payload_size"`
+	SHA256      string `json:"sha256,omitempty"`
+	TZ          string `json:"tz,omitempty"`
+}
+
+// checksumValue returns hex-encoded checksum value if it's of the given type.
+func checksumValue(cs checksum.Checksum, ok bool, typ checksum.Type) string {
+	if !ok || cs.Type() != typ {
+		return ""
+	}
+	return hex.EncodeToString(cs.Value())
+}
+
+// newObjectChecksums returns hashes of the whole payload, the ones of the
+// parent object are used for link objects of split chains.
+func newObjectChecksums(obj *object.Object) objectChecksums {
+	if parent, _, ok := linkedParent(obj); ok {
+		obj = parent
+	}
+	objID, _ := obj.ID()
+	cnrID, _ := obj.ContainerID()
+	cs, csOK := obj.PayloadChecksum()
+	hh, hhOK := obj.PayloadHomomorphicHash()
+
+	return objectChecksums{
+		ObjectID:    objID.String(),
+		ContainerID: cnrID.String(),
+		PayloadSize: obj.PayloadSize(),
+		SHA256:      checksumValue(cs, csOK, checksum.SHA256),
+		TZ:          checksumValue(hh, hhOK, checksum.TZ),
+	}
+}
+
+func (r request) objectChecksums(clnt *pool.Pool, objectAddress *address.Address) {
+	obj, ok := r.objectHeader(clnt, objectAddress)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(newObjectChecksums(obj))
+	if err != nil {
+		r.log.Error("could not encode object checksums", zap.Error(err))
+		response.Error(r.RequestCtx, "could not encode object checksums: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	r.SetContentType("application/json; charset=UTF-8")
+	r.SetBody(data)
+}
+
+// ChecksumByAddress handles requests for payload hashes in JSON using simple
+// cid/oid format, the payload isn't transferred.
+func (d *Downloader) ChecksumByAddress(c *fasthttp.RequestCtx) {
+	d.byAddress(c, request.objectChecksums)
+}
@@ -0,0 +1,219 @@
+package downloader
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+type (
+	containerInfo struct {
+		ContainerID string `json:"container_id"`
+		Name        string `json:"name,omitempty"`
+	}
+
+	// containerDetails is the container description returned by GET
+	// /container/{cid}.
+	containerDetails struct {
+		ContainerID     string            `json:"container_id"`
+		Owner           string            `json:"owner"`
+		BasicACL        string            `json:"basic_acl"`
+		PlacementPolicy string            `json:"placement_policy"`
+		Attributes      map[string]string `json:"attributes"`
+	}
+)
+
+// ListContainers handles requests for containers of the owner given in owner
+// query argument. Container names are returned if they resolve to the
+// containers.
+func (d *Downloader) ListContainers(c *fasthttp.RequestCtx) {
+	var (
+		owner = string(c.QueryArgs().Peek("owner"))
+		log   = d.log.With(zap.String("owner", owner))
+		ctx   = utils.RequestContext(c, d.appCtx)
+	)
+
+	if owner == "" {
+		response.Error(c, "owner is required", fasthttp.StatusBadRequest)
+		return
+	}
+
+	var ownerID user.ID
+	if err := ownerID.DecodeString(owner); err != nil {
+		log.Error("wrong owner id", zap.Error(err))
+		response.Error(c, "wrong owner id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	clientPool := d.pool.Acquire(c)
+
+	var prm pool.PrmContainerList
+	prm.SetOwnerID(ownerID)
+
+	ids, err := clientPool.ListContainers(ctx, prm)
+	if err != nil {
+		log.Error("could not list containers", zap.Error(err))
+		response.Error(c, "could not list containers: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	res := make([]containerInfo, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, containerInfo{
+			ContainerID: id.String(),
+			Name:        d.containerName(c, clientPool, id),
+		})
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		log.Error("could not encode containers", zap.Error(err))
+		response.Error(c, "could not encode containers: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
+
+// containerName returns the native name of the container if it's resolved to
+// the container, otherwise an empty string.
+func (d *Downloader) containerName(c *fasthttp.RequestCtx, clientPool *pool.Pool, id cid.ID) string {
+	if d.containerResolver == nil {
+		return ""
+	}
+
+	ctx := utils.RequestContext(c, d.appCtx)
+
+	var prm pool.PrmContainerGet
+	prm.SetContainerID(id)
+
+	cnr, err := clientPool.GetContainer(ctx, prm)
+	if err != nil {
+		d.log.Debug("could not get container", zap.Stringer("cid", id), zap.Error(err))
+		return ""
+	}
+
+	name, _ := container.GetNativeNameWithZone(cnr)
+	if name == "" {
+		return ""
+	}
+
+	resolved, err := d.containerResolver.Resolve(ctx, name)
+	if err != nil || !resolved.Equals(id) {
+		return ""
+	}
+	return name
+}
+
+// ContainerInfo handles requests for the container placement policy, basic
+// ACL, attributes and owner.
+func (d *Downloader) ContainerInfo(c *fasthttp.RequestCtx) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		log     = d.log.With(zap.String("cid", scid))
+		ctx     = utils.RequestContext(c, d.appCtx)
+	)
+
+	cnrID, err := utils.GetContainerID(ctx, scid, d.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	var prm pool.PrmContainerGet
+	prm.SetContainerID(*cnrID)
+
+	cnr, err := d.pool.Acquire(c).GetContainer(ctx, prm)
+	if err != nil {
+		log.Error("could not get container", zap.Error(err))
+		code, errCode := fasthttp.StatusBadRequest, response.CodeBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			code, errCode = fasthttp.StatusNotFound, response.CodeContainerNotFound
+		}
+		response.ErrorCode(c, errCode, "could not get container: "+err.Error(), code)
+		return
+	}
+
+	res := newContainerDetails(*cnrID, cnr)
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		log.Error("could not encode container", zap.Error(err))
+		response.Error(c, "could not encode container: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
+
+// ContainerEACL handles requests for the extended ACL table of the container,
+// it's returned as JSON the table can be set with.
+func (d *Downloader) ContainerEACL(c *fasthttp.RequestCtx) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		log     = d.log.With(zap.String("cid", scid))
+		ctx     = utils.RequestContext(c, d.appCtx)
+	)
+
+	cnrID, err := utils.GetContainerID(ctx, scid, d.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	var prm pool.PrmContainerEACL
+	prm.SetContainerID(*cnrID)
+
+	table, err := d.pool.Acquire(c).GetEACL(ctx, prm)
+	if err != nil {
+		log.Error("could not get eacl", zap.Error(err))
+		code, errCode := fasthttp.StatusBadRequest, response.CodeBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			code, errCode = fasthttp.StatusNotFound, response.CodeNotFound
+		}
+		response.ErrorCode(c, errCode, "could not get eacl: "+err.Error(), code)
+		return
+	}
+
+	data, err := table.MarshalJSON()
+	if err != nil {
+		log.Error("could not encode eacl", zap.Error(err))
+		response.Error(c, "could not encode eacl: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
+
+func newContainerDetails(id cid.ID, cnr *container.Container) containerDetails {
+	res := containerDetails{
+		ContainerID: id.String(),
+		BasicACL:    "0x" + strconv.FormatUint(uint64(cnr.BasicACL()), 16),
+		Attributes:  make(map[string]string),
+	}
+	if owner := cnr.OwnerID(); owner != nil {
+		res.Owner = owner.String()
+	}
+	if p := cnr.PlacementPolicy(); p != nil {
+		res.PlacementPolicy = strings.Join(policy.Encode(p), " ")
+	}
+	for _, attr := range cnr.Attributes() {
+		res.Attributes[attr.Key()] = attr.Value()
+	}
+	return res
+}
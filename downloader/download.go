@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"io"
@@ -35,6 +36,21 @@ type request struct {
 	*fasthttp.RequestCtx
 	appCtx context.Context
 	log    *zap.Logger
+	// immutable is set for content-addressed requests which responses are
+	// cached forever.
+	immutable bool
+	// failoverAttempts is the number of times payload reading is resumed
+	// after failures.
+	failoverAttempts int
+	// retry is the policy of retrying failed NeoFS operations.
+	retry utils.RetryPolicy
+	// hedge is the policy of hedging object reads.
+	hedge utils.HedgePolicy
+	// thumbnails resizes images, it's nil if resizing is disabled.
+	thumbnails *thumbnailer
+	// verifyPayload enables payload checksum verification by default, verify
+	// query argument overrides it.
+	verifyPayload bool
 }
 
 var errObjectNotFound = errors.New("object not found")
@@ -110,13 +126,50 @@ func (r request) receiveFile(clnt *pool.Pool, objectAddress *address.Address) {
 	if btoken := bearerToken(r.RequestCtx); btoken != nil {
 		prm.UseBearer(*btoken)
 	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
 
-	rObj, err := clnt.GetObject(r.appCtx, prm)
+	var rObj *pool.ResGetObject
+	err = r.retry.Do(r.appCtx, func() error {
+		rObj, err = r.hedgedGet(clnt, prm)
+		return err
+	})
 	if err != nil {
 		r.handleNeoFSErr(err, start)
 		return
 	}
 
+	parent, payload, split := r.splitPayload(clnt, objectAddress, &rObj.Header)
+	if split {
+		_ = rObj.Payload.Close()
+		rObj.Header, rObj.Payload = *parent, payload
+	} else {
+		rObj.Payload = r.resumable(clnt, *objectAddress, rObj.Payload, rObj.Header.PayloadSize())
+	}
+
+	if r.immutable && r.immutableHeaders(&rObj.Header) {
+		_ = rObj.Payload.Close()
+		return
+	}
+
+	if r.shouldVerify() {
+		rObj.Payload = r.verifiedPayload(&rObj.Header, rObj.Payload)
+	}
+
+	if r.thumbnails != nil {
+		params, ok, err := parseThumbnailParams(r.QueryArgs(), r.thumbnails.settings.MaxDimension)
+		if err != nil {
+			_ = rObj.Payload.Close()
+			response.Error(r.RequestCtx, err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		if ok {
+			r.serveThumbnail(objectAddress, &rObj.Header, rObj.Payload, params)
+			return
+		}
+	}
+
 	// we can't close reader in this function, so how to do it?
 
 	if r.Request.URI().QueryArgs().GetBool("download") {
@@ -126,6 +179,7 @@ func (r request) receiveFile(clnt *pool.Pool, objectAddress *address.Address) {
 	payloadSize := rObj.Header.PayloadSize()
 
 	r.Response.Header.Set(fasthttp.HeaderContentLength, strconv.FormatUint(payloadSize, 10))
+	r.Response.Header.Set(fasthttp.HeaderAcceptRanges, "bytes")
 	var contentType string
 	for _, attr := range rObj.Header.Attributes() {
 		key := attr.Key()
@@ -158,6 +212,9 @@ func (r request) receiveFile(clnt *pool.Pool, objectAddress *address.Address) {
 
 	idsToResponse(&r.Response, &rObj.Header)
 
+	if len(contentType) == 0 {
+		contentType = contentTypeByName(filename)
+	}
 	if len(contentType) == 0 {
 		// determine the Content-Type from the payload head
 		var payloadHead []byte
@@ -186,7 +243,25 @@ func (r request) receiveFile(clnt *pool.Pool, objectAddress *address.Address) {
 
 	r.Response.Header.Set(fasthttp.HeaderContentDisposition, dis+"; filename="+path.Base(filename))
 
-	r.Response.SetBodyStream(rObj.Payload, int(payloadSize))
+	rng, partial, ok := r.requestedRange(&rObj.Header)
+	if !ok {
+		_ = rObj.Payload.Close()
+		return
+	}
+	if partial {
+		rObj.Payload, err = r.rangePayload(clnt, *objectAddress, rObj.Payload, split, rng)
+		if err != nil {
+			r.handleNeoFSErr(err, start)
+			return
+		}
+		r.setRangeHeaders(rng, payloadSize)
+		payloadSize = rng.length()
+	}
+
+	// media players start playback and seek as soon as headers arrive, so
+	// they're not to wait for the body to fill the write buffer
+	r.Response.ImmediateHeaderFlush = true
+	utils.SetBodyStream(r.RequestCtx, rObj.Payload, int(payloadSize))
 }
 
 // systemBackwardTranslator is used to convert headers looking like '__NEOFS__ATTR_NAME' to 'Neofs-Attr-Name'.
@@ -233,9 +308,10 @@ func (r *request) handleNeoFSErr(err error, start time.Time) {
 		zap.Error(err),
 	)
 	var (
-		msg   = fmt.Sprintf("could not receive object: %v", err)
-		code  = fasthttp.StatusBadRequest
-		cause = err
+		msg     = fmt.Sprintf("could not receive object: %v", err)
+		code    = fasthttp.StatusBadRequest
+		errCode = response.CodeBadRequest
+		cause   = err
 	)
 	for unwrap := errors.Unwrap(err); unwrap != nil; unwrap = errors.Unwrap(cause) {
 		cause = unwrap
@@ -244,23 +320,45 @@ func (r *request) handleNeoFSErr(err error, start time.Time) {
 	if strings.Contains(cause.Error(), "not found") ||
 		strings.Contains(cause.Error(), "can't fetch container info") {
 		code = fasthttp.StatusNotFound
+		errCode = response.CodeObjectNotFound
 		msg = errObjectNotFound.Error()
 	}
 
-	response.Error(r.RequestCtx, msg, code)
+	response.ErrorCode(r.RequestCtx, errCode, msg, code)
 }
 
 // Downloader is a download request handler.
 type Downloader struct {
 	appCtx            context.Context
 	log               *zap.Logger
-	pool              *pool.Pool
+	pool              *utils.PoolHolder
 	containerResolver *resolver.ContainerResolver
 	settings          Settings
+	retry             utils.RetryPolicy
+	hedge             utils.HedgePolicy
+	thumbnails        *thumbnailer
 }
 
+// Object selection policies of attribute downloads matching several objects.
+const (
+	// AttributeSelectionAny selects the first object found.
+	AttributeSelectionAny = "any"
+	// AttributeSelectionNewest selects the object with the latest Timestamp
+	// attribute (creation epoch and ID are compared if they're equal).
+	AttributeSelectionNewest = "newest"
+)
+
 type Settings struct {
-	ZipCompression bool
+	ZipCompression     bool
+	AttributeSelection string
+	// FailoverAttempts is the number of times payload reading is resumed from
+	// other nodes if the node fails in the middle of streaming.
+	FailoverAttempts int
+	// VerifyPayload enables checking payloads against their SHA-256 checksums
+	// while they're streamed.
+	VerifyPayload bool
+	// Thumbnails are the settings of image resizing.
+	Thumbnails ThumbnailSettings
 }
 
 // New creates an instance of Downloader using specified options.
@@ -271,14 +369,23 @@ func New(ctx context.Context, params *utils.AppParams, settings Settings) *Downl
 		pool:              params.Pool,
 		settings:          settings,
 		containerResolver: params.Resolver,
+		retry:             params.Retry,
+		hedge:             params.Hedge,
+		thumbnails:        newThumbnailer(settings.Thumbnails),
 	}
 }
 
 func (d *Downloader) newRequest(ctx *fasthttp.RequestCtx, log *zap.Logger) *request {
 	return &request{
 		RequestCtx: ctx,
-		appCtx:     d.appCtx,
+		appCtx:     utils.RequestContext(ctx, d.appCtx),
 		log:        log,
+
+		failoverAttempts: d.settings.FailoverAttempts,
+		retry:            d.retry,
+		hedge:            d.hedge,
+		thumbnails:       d.thumbnails,
+		verifyPayload:    d.settings.VerifyPayload,
 	}
 }
 
@@ -296,7 +403,7 @@ func (d *Downloader) byAddress(c *fasthttp.RequestCtx, f func(request, *pool.Poo
 		log      = d.log.With(zap.String("cid", idCnr), zap.String("oid", idObj))
 	)
 
-	cnrID, err := utils.GetContainerID(d.appCtx, idCnr, d.containerResolver)
+	cnrID, err := utils.GetContainerID(utils.RequestContext(c, d.appCtx), idCnr, d.containerResolver)
 	if err != nil {
 		log.Error("wrong container id", zap.Error(err))
 		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
@@ -314,11 +421,16 @@ func (d *Downloader) byAddress(c *fasthttp.RequestCtx, f func(request, *pool.Poo
 	addr.SetContainerID(*cnrID)
 	addr.SetObjectID(*objID)
 
-	f(*d.newRequest(c, log), d.pool, addr)
+	f(*d.newRequest(c, log), d.pool.Acquire(c), addr)
 }
 
-// DownloadByAttribute handles attribute-based download requests.
+// DownloadByAttribute handles attribute-based download requests. With list
+// query argument it returns the page of matching objects instead.
 func (d *Downloader) DownloadByAttribute(c *fasthttp.RequestCtx) {
+	if c.QueryArgs().GetBool("list") {
+		d.listByAttribute(c)
+		return
+	}
 	d.byAttribute(c, request.receiveFile)
 }
 
@@ -326,19 +438,24 @@ func (d *Downloader) DownloadByAttribute(c *fasthttp.RequestCtx) {
 func (d *Downloader) byAttribute(c *fasthttp.RequestCtx, f func(request, *pool.Pool, *address.Address)) {
 	var (
 		scid, _ = c.UserValue("cid").(string)
-		key, _  = url.QueryUnescape(c.UserValue("attr_key").(string))
-		val, _  = url.QueryUnescape(c.UserValue("attr_val").(string))
-		log     = d.log.With(zap.String("cid", scid), zap.String("attr_key", key), zap.String("attr_val", val))
+		attrs   = attributeFilters(c)
+		log     = d.log.With(zap.String("cid", scid), zap.Any("attributes", attrs))
 	)
 
-	containerID, err := utils.GetContainerID(d.appCtx, scid, d.containerResolver)
+	if len(attrs) == 0 {
+		log.Error("no attributes to search by")
+		response.Error(c, "no attributes to search by", fasthttp.StatusBadRequest)
+		return
+	}
+
+	containerID, err := utils.GetContainerID(utils.RequestContext(c, d.appCtx), scid, d.containerResolver)
 	if err != nil {
 		log.Error("wrong container id", zap.Error(err))
 		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
 		return
 	}
 
-	res, err := d.search(c, containerID, key, val, object.MatchStringEqual)
+	res, err := d.searchByFilters(c, containerID, attributeSearchFilters(attrs))
 	if err != nil {
 		log.Error("could not search for objects", zap.Error(err))
 		response.Error(c, "could not search for objects: "+err.Error(), fasthttp.StatusBadRequest)
@@ -347,13 +464,11 @@ func (d *Downloader) byAttribute(c *fasthttp.RequestCtx, f func(request, *pool.P
 
 	defer res.Close()
 
-	buf := make([]oid.ID, 1)
-
-	n, err := res.Read(buf)
-	if n == 0 {
+	objID, err := d.selectObject(c, res, *containerID)
+	if err != nil {
 		if errors.Is(err, io.EOF) {
 			log.Error("object not found", zap.Error(err))
-			response.Error(c, "object not found", fasthttp.StatusNotFound)
+			response.ErrorCode(c, response.CodeObjectNotFound, "object not found", fasthttp.StatusNotFound)
 			return
 		}
 
@@ -364,9 +479,108 @@ func (d *Downloader) byAttribute(c *fasthttp.RequestCtx, f func(request, *pool.P
 
 	var addrObj address.Address
 	addrObj.SetContainerID(*containerID)
-	addrObj.SetObjectID(buf[0])
+	addrObj.SetObjectID(objID)
 
-	f(*d.newRequest(c, log), d.pool, &addrObj)
+	f(*d.newRequest(c, log), d.pool.Acquire(c), &addrObj)
+}
+
+// listByAttribute responds with the page of objects having the attribute, the
+// page is set by offset and limit query arguments.
+func (d *Downloader) listByAttribute(c *fasthttp.RequestCtx) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		attrs   = attributeFilters(c)
+		log     = d.log.With(zap.String("cid", scid), zap.Any("attributes", attrs))
+		args    = c.QueryArgs()
+	)
+
+	if len(attrs) == 0 {
+		response.Error(c, "no attributes to search by", fasthttp.StatusBadRequest)
+		return
+	}
+
+	offset, limit := 0, 0
+	var err error
+	if args.Has("offset") {
+		if offset, err = args.GetUint("offset"); err != nil {
+			response.Error(c, "invalid offset", fasthttp.StatusBadRequest)
+			return
+		}
+	}
+	if args.Has("limit") {
+		if limit, err = args.GetUint("limit"); err != nil {
+			response.Error(c, "invalid limit", fasthttp.StatusBadRequest)
+			return
+		}
+	}
+	if limit, err = checkPage(offset, limit); err != nil {
+		response.Error(c, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	var attributes []string
+	if attrs := string(args.Peek("attributes")); attrs != "" {
+		attributes = strings.Split(attrs, ",")
+	}
+
+	containerID, err := utils.GetContainerID(utils.RequestContext(c, d.appCtx), scid, d.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch and store bearer token", zap.Error(err))
+		response.Error(c, "could not fetch and store bearer token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	d.searchResults(c, log, containerID, attributeSearchFilters(attrs), offset, limit, attributes)
+}
+
+// attributeQueryArgs are query arguments of attribute downloads which aren't
+// attributes to search by.
+var attributeQueryArgs = map[string]struct{}{
+	"download":   {},
+	"list":       {},
+	"offset":     {},
+	"limit":      {},
+	"attributes": {},
+	"w":          {},
+	"h":          {},
+	"format":     {},
+	"verify":     {},
+}
+
+// attributeFilters returns attributes to search objects by: the one from the
+// path or all query arguments (except for the known ones) if it's not set.
+func attributeFilters(c *fasthttp.RequestCtx) []searchFilter {
+	if key, ok := c.UserValue("attr_key").(string); ok {
+		key, _ = url.QueryUnescape(key)
+		val, _ := c.UserValue("attr_val").(string)
+		val, _ = url.QueryUnescape(val)
+		return []searchFilter{{Key: key, Value: val}}
+	}
+
+	var res []searchFilter
+	c.QueryArgs().VisitAll(func(key, value []byte) {
+		if _, ok := attributeQueryArgs[string(key)]; !ok {
+			res = append(res, searchFilter{Key: string(key), Value: string(value)})
+		}
+	})
+	return res
+}
+
+// attributeSearchFilters returns filters of root objects having all the
+// attributes.
+func attributeSearchFilters(attrs []searchFilter) object.SearchFilters {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	for _, attr := range attrs {
+		filters.AddFilter(attr.Key, attr.Value, object.MatchStringEqual)
+	}
+	return filters
 }
 
 func (d *Downloader) search(c *fasthttp.RequestCtx, cid *cid.ID, key, val string, op object.SearchMatchType) (*pool.ResObjectSearch, error) {
@@ -374,14 +588,31 @@ func (d *Downloader) search(c *fasthttp.RequestCtx, cid *cid.ID, key, val string
 	filters.AddRootFilter()
 	filters.AddFilter(key, val, op)
 
+	return d.searchByFilters(c, cid, filters)
+}
+
+func (d *Downloader) searchByFilters(c *fasthttp.RequestCtx, cid *cid.ID, filters object.SearchFilters) (*pool.ResObjectSearch, error) {
 	var prm pool.PrmObjectSearch
 	prm.SetContainerID(*cid)
 	prm.SetFilters(filters)
 	if btoken := bearerToken(c); btoken != nil {
 		prm.UseBearer(*btoken)
 	}
+	if key := utils.RequestKey(c); key != nil {
+		prm.UseKey(key)
+	}
 
-	return d.pool.SearchObjects(d.appCtx, prm)
+	var (
+		ctx        = utils.RequestContext(c, d.appCtx)
+		clientPool = d.pool.Acquire(c)
+		res        *pool.ResObjectSearch
+	)
+	err := d.retry.Do(ctx, func() error {
+		var err error
+		res, err = clientPool.SearchObjects(ctx, prm)
+		return err
+	})
+	return res, err
 }
 
 func (d *Downloader) addObjectToZip(zw *zip.Writer, obj *object.Object) (io.Writer, error) {
@@ -402,8 +633,10 @@ func (d *Downloader) DownloadZipped(c *fasthttp.RequestCtx) {
 	scid, _ := c.UserValue("cid").(string)
 	prefix, _ := url.QueryUnescape(c.UserValue("prefix").(string))
 	log := d.log.With(zap.String("cid", scid), zap.String("prefix", prefix))
+	ctx := utils.RequestContext(c, d.appCtx)
+	clientPool := d.pool.Acquire(c)
 
-	containerID, err := utils.GetContainerID(d.appCtx, scid, d.containerResolver)
+	containerID, err := utils.GetContainerID(ctx, scid, d.containerResolver)
 	if err != nil {
 		log.Error("wrong container id", zap.Error(err))
 		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
@@ -427,29 +660,35 @@ func (d *Downloader) DownloadZipped(c *fasthttp.RequestCtx) {
 	c.Response.Header.Set(fasthttp.HeaderContentDisposition, "attachment; filename=\"archive.zip\"")
 	c.Response.SetStatusCode(http.StatusOK)
 
-	c.SetBodyStreamWriter(func(w *bufio.Writer) {
+	utils.SetBodyStreamWriter(c, func(w *bufio.Writer) {
 		defer resSearch.Close()
 
 		zipWriter := zip.NewWriter(w)
 
-		var bufZip []byte
+		var bufZip *[]byte
 		var addr address.Address
+		defer func() {
+			if bufZip != nil {
+				utils.PayloadBuffers.Put(bufZip)
+			}
+		}()
 
 		empty := true
 		called := false
 		btoken := bearerToken(c)
+		key := utils.RequestKey(c)
 		addr.SetContainerID(*containerID)
 
 		errIter := resSearch.Iterate(func(id oid.ID) bool {
 			called = true
 
 			if empty {
-				bufZip = make([]byte, 3<<20) // the same as for upload
+				bufZip = utils.PayloadBuffers.Get()
 			}
 			empty = false
 
 			addr.SetObjectID(id)
-			if err = d.zipObject(zipWriter, addr, btoken, bufZip); err != nil {
+			if err = d.zipObject(ctx, clientPool, zipWriter, addr, btoken, key, *bufZip); err != nil {
 				return true
 			}
 
@@ -461,7 +700,7 @@ func (d *Downloader) DownloadZipped(c *fasthttp.RequestCtx) {
 			return
 		} else if !called {
 			log.Error("objects not found")
-			response.Error(c, "objects not found", fasthttp.StatusNotFound)
+			response.ErrorCode(c, response.CodeObjectNotFound, "objects not found", fasthttp.StatusNotFound)
 			return
 		}
 
@@ -477,14 +716,22 @@ func (d *Downloader) DownloadZipped(c *fasthttp.RequestCtx) {
 	})
 }
 
-func (d *Downloader) zipObject(zipWriter *zip.Writer, addr address.Address, btoken *bearer.Token, bufZip []byte) error {
+func (d *Downloader) zipObject(ctx context.Context, clientPool *pool.Pool, zipWriter *zip.Writer, addr address.Address, btoken *bearer.Token, key *ecdsa.PrivateKey, bufZip []byte) error {
 	var prm pool.PrmObjectGet
 	prm.SetAddress(addr)
 	if btoken != nil {
 		prm.UseBearer(*btoken)
 	}
+	if key != nil {
+		prm.UseKey(key)
+	}
 
-	resGet, err := d.pool.GetObject(d.appCtx, prm)
+	var resGet *pool.ResGetObject
+	err := d.retry.Do(ctx, func() error {
+		var err error
+		resGet, err = clientPool.GetObject(ctx, prm)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("get NeoFS object: %v", err)
 	}
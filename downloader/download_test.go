@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
 )
 
 func TestSystemBackwardTranslator(t *testing.T) {
@@ -21,3 +22,31 @@ func TestSystemBackwardTranslator(t *testing.T) {
 		require.Equal(t, expected[i], res)
 	}
 }
+
+func TestAttributeFilters(t *testing.T) {
+	t.Run("path", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		c.Request.SetRequestURI("/get_by_attribute/cid/File%2BName/cat+jpeg?Type=report")
+		c.SetUserValue("attr_key", "File%2BName")
+		c.SetUserValue("attr_val", "cat+jpeg")
+
+		require.Equal(t, []searchFilter{{Key: "File+Name", Value: "cat jpeg"}}, attributeFilters(&c))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		c.Request.SetRequestURI("/get_by_attribute/cid?Project=x&download=true&Type=report&limit=1")
+
+		require.Equal(t, []searchFilter{
+			{Key: "Project", Value: "x"},
+			{Key: "Type", Value: "report"},
+		}, attributeFilters(&c))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		c.Request.SetRequestURI("/get_by_attribute/cid?download=true")
+
+		require.Empty(t, attributeFilters(&c))
+	})
+}
@@ -0,0 +1,108 @@
+package downloader
+
+import (
+	"io"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"go.uber.org/zap"
+)
+
+// resumingReader continues reading the payload from the offset it's been
+// interrupted at with range requests which are likely to be served by other
+// nodes of the pool. Objects never change, so the rest of the payload is
+// consistent with the bytes already sent.
+type resumingReader struct {
+	r        io.ReadCloser
+	offset   uint64
+	size     uint64
+	attempts int
+	resume   func(offset, length uint64) (io.ReadCloser, error)
+	log      *zap.Logger
+}
+
+func (r *resumingReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.r.Read(p)
+		r.offset += uint64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if r.offset >= r.size {
+			return n, io.EOF
+		}
+		if r.attempts <= 0 {
+			return n, err
+		}
+		r.attempts--
+
+		_ = r.r.Close()
+		rr, rerr := r.resume(r.offset, r.size-r.offset)
+		if rerr != nil {
+			r.log.Error("could not resume payload reading", zap.Uint64("offset", r.offset),
+				zap.NamedError("read_error", err), zap.Error(rerr))
+			r.r = io.NopCloser(&errReader{err})
+			return n, err
+		}
+		r.log.Warn("payload reading resumed", zap.Uint64("offset", r.offset), zap.Error(err))
+		r.r = rr
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *resumingReader) Close() error {
+	return r.r.Close()
+}
+
+// errReader always fails with the error.
+type errReader struct{ err error }
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// resumable makes the payload of the object of the given size resume
+// reading from other nodes if the connection to the node fails.
+func (r request) resumable(clnt *pool.Pool, addr address.Address, payload io.ReadCloser, size uint64) io.ReadCloser {
+	return r.resumableRange(clnt, addr, payload, 0, size)
+}
+
+// resumableRange is like resumable, but the payload is read from the offset
+// to the end (exclusive) only.
+func (r request) resumableRange(clnt *pool.Pool, addr address.Address, payload io.ReadCloser, offset, end uint64) io.ReadCloser {
+	if r.failoverAttempts <= 0 {
+		return payload
+	}
+
+	return &resumingReader{
+		r:        payload,
+		offset:   offset,
+		size:     end,
+		attempts: r.failoverAttempts,
+		log:      r.log,
+		resume: func(offset, length uint64) (io.ReadCloser, error) {
+			return r.objectRange(clnt, addr, offset, length)
+		},
+	}
+}
+
+// objectRange requests the payload range with the credentials of the request.
+func (r request) objectRange(clnt *pool.Pool, addr address.Address, offset, length uint64) (io.ReadCloser, error) {
+	var prm pool.PrmObjectRange
+	prm.SetAddress(addr)
+	prm.SetOffset(offset)
+	prm.SetLength(length)
+	if btoken := bearerToken(r.RequestCtx); btoken != nil {
+		prm.UseBearer(*btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+	res, err := clnt.ObjectRange(r.appCtx, prm)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package downloader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingReader returns the error after n bytes of the data.
+type failingReader struct {
+	data string
+	n    int
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, r.err
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n := copy(p, r.data)
+	r.data, r.n = r.data[n:], r.n-n
+	return n, nil
+}
+
+func TestResumingReader(t *testing.T) {
+	const payload = "0123456789"
+	errNode := errors.New("node is down")
+
+	newReader := func(attempts int, resume func(offset, length uint64) (io.ReadCloser, error)) *resumingReader {
+		return &resumingReader{
+			r:        io.NopCloser(&failingReader{data: payload, n: 4, err: errNode}),
+			size:     uint64(len(payload)),
+			attempts: attempts,
+			resume:   resume,
+			log:      zap.NewNop(),
+		}
+	}
+
+	t.Run("resumed", func(t *testing.T) {
+		var offsets []uint64
+		r := newReader(2, func(offset, length uint64) (io.ReadCloser, error) {
+			offsets = append(offsets, offset)
+			require.Equal(t, uint64(len(payload))-offset, length)
+			if len(offsets) == 1 {
+				return io.NopCloser(&failingReader{data: payload[offset:], n: 3, err: errNode}), nil
+			}
+			return io.NopCloser(strings.NewReader(payload[offset:])), nil
+		})
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+		require.Equal(t, []uint64{4, 7}, offsets)
+	})
+
+	t.Run("no attempts left", func(t *testing.T) {
+		r := newReader(0, func(offset, length uint64) (io.ReadCloser, error) {
+			t.Fatal("unexpected resume")
+			return nil, nil
+		})
+
+		data, err := io.ReadAll(r)
+		require.ErrorIs(t, err, errNode)
+		require.Equal(t, payload[:4], string(data))
+	})
+
+	t.Run("resume error", func(t *testing.T) {
+		r := newReader(1, func(offset, length uint64) (io.ReadCloser, error) {
+			return nil, errors.New("no healthy nodes")
+		})
+
+		data, err := io.ReadAll(r)
+		require.ErrorIs(t, err, errNode)
+		require.Equal(t, payload[:4], string(data))
+	})
+}
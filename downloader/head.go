@@ -34,21 +34,42 @@ func (r request) headObject(clnt *pool.Pool, objectAddress *address.Address) {
 	}
 
 	btoken := bearerToken(r.RequestCtx)
+	key := utils.RequestKey(r.RequestCtx)
 
 	var prm pool.PrmObjectHead
 	prm.SetAddress(*objectAddress)
 	if btoken != nil {
 		prm.UseBearer(*btoken)
 	}
+	if key != nil {
+		prm.UseKey(key)
+	}
 
-	obj, err := clnt.HeadObject(r.appCtx, prm)
+	var obj *object.Object
+	err := r.retry.Do(r.appCtx, func() error {
+		var err error
+		obj, err = r.hedgedHead(clnt, prm)
+		return err
+	})
 	if err != nil {
 		r.handleNeoFSErr(err, start)
 		return
 	}
 
+	// the payload of the link object of a split chain starts in the first child
+	rangeAddress := *objectAddress
+	if parent, children, ok := linkedParent(obj); ok {
+		obj = parent
+		rangeAddress = childAddress(objectAddress, children[0])
+	}
+
+	if r.immutable && r.immutableHeaders(obj) {
+		return
+	}
+
 	r.Response.Header.Set(fasthttp.HeaderContentLength, strconv.FormatUint(obj.PayloadSize(), 10))
-	var contentType string
+	r.Response.Header.Set(fasthttp.HeaderAcceptRanges, "bytes")
+	var contentType, filename string
 	for _, attr := range obj.Attributes() {
 		key := attr.Key()
 		val := attr.Value()
@@ -57,6 +78,8 @@ func (r request) headObject(clnt *pool.Pool, objectAddress *address.Address) {
 		}
 		r.Response.Header.Set(utils.UserAttributeHeaderPrefix+key, val)
 		switch key {
+		case object.AttributeFileName:
+			filename = val
 		case object.AttributeTimestamp:
 			value, err := strconv.ParseInt(val, 10, 64)
 			if err != nil {
@@ -74,14 +97,20 @@ func (r request) headObject(clnt *pool.Pool, objectAddress *address.Address) {
 
 	idsToResponse(&r.Response, obj)
 
+	if len(contentType) == 0 {
+		contentType = contentTypeByName(filename)
+	}
 	if len(contentType) == 0 {
 		contentType, _, err = readContentType(obj.PayloadSize(), func(sz uint64) (io.Reader, error) {
 			var prmRange pool.PrmObjectRange
-			prmRange.SetAddress(*objectAddress)
+			prmRange.SetAddress(rangeAddress)
 			prmRange.SetLength(sz)
 			if btoken != nil {
 				prmRange.UseBearer(*btoken)
 			}
+			if key != nil {
+				prmRange.UseKey(key)
+			}
 
 			return clnt.ObjectRange(r.appCtx, prmRange)
 		})
@@ -0,0 +1,74 @@
+package downloader
+
+import (
+	"context"
+	"io"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+)
+
+// cancelingReader cancels the context of the hedged request streaming the
+// payload when it's closed.
+type cancelingReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
+
+// hedgePools returns the pools of the request and its duplicate: pools of two
+// different nodes if they're available, the request pool otherwise. Requests
+// sent to the node chosen by the client aren't sent to other nodes.
+func (r request) hedgePools(clnt *pool.Pool) [2]*pool.Pool {
+	if r.hedge.Delay > 0 && r.hedge.Nodes != nil && !utils.HasRequestPool(r.RequestCtx) {
+		if first, second, ok := r.hedge.Nodes(); ok {
+			return [2]*pool.Pool{first, second}
+		}
+	}
+	return [2]*pool.Pool{clnt, clnt}
+}
+
+// hedgeDo runs the operation with the pools of the attempts according to the
+// hedging policy.
+func (r request) hedgeDo(clnt *pool.Pool, op func(context.Context, *pool.Pool) (interface{}, error), discard func(interface{})) (interface{}, context.CancelFunc, error) {
+	pools := r.hedgePools(clnt)
+	return r.hedge.Do(r.appCtx, func(ctx context.Context, attempt int) (interface{}, error) {
+		return op(ctx, pools[attempt])
+	}, discard)
+}
+
+// hedgedGet gets the object issuing the duplicate request to another node if
+// the first one is slow, payloads of the requests which lost are closed.
+func (r request) hedgedGet(clnt *pool.Pool, prm pool.PrmObjectGet) (*pool.ResGetObject, error) {
+	res, cancel, err := r.hedgeDo(clnt, func(ctx context.Context, p *pool.Pool) (interface{}, error) {
+		return p.GetObject(ctx, prm)
+	}, func(res interface{}) {
+		_ = res.(*pool.ResGetObject).Payload.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	obj := res.(*pool.ResGetObject)
+	obj.Payload = &cancelingReader{ReadCloser: obj.Payload, cancel: cancel}
+	return obj, nil
+}
+
+// hedgedHead gets the object header issuing the duplicate request to another
+// node if the first one is slow.
+func (r request) hedgedHead(clnt *pool.Pool, prm pool.PrmObjectHead) (*object.Object, error) {
+	res, cancel, err := r.hedgeDo(clnt, func(ctx context.Context, p *pool.Pool) (interface{}, error) {
+		return p.HeadObject(ctx, prm)
+	}, func(interface{}) {})
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	return res.(*object.Object), nil
+}
@@ -0,0 +1,69 @@
+package downloader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHedgeDo(t *testing.T) {
+	var (
+		clnt   = new(pool.Pool)
+		first  = new(pool.Pool)
+		second = new(pool.Pool)
+	)
+
+	// hedged runs the request which first attempt is slow and returns the
+	// pools used by the attempts.
+	hedged := func(c *fasthttp.RequestCtx, nodes func() (*pool.Pool, *pool.Pool, bool)) []*pool.Pool {
+		r := request{
+			RequestCtx: c,
+			appCtx:     context.Background(),
+			hedge:      utils.HedgePolicy{Delay: 10 * time.Millisecond, Nodes: nodes},
+		}
+
+		var (
+			calls int32
+			used  = make(chan *pool.Pool, 2)
+		)
+		res, cancel, err := r.hedgeDo(clnt, func(ctx context.Context, p *pool.Pool) (interface{}, error) {
+			used <- p
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return p, nil
+		}, func(interface{}) {})
+		require.NoError(t, err)
+		cancel()
+
+		return []*pool.Pool{<-used, <-used, res.(*pool.Pool)}
+	}
+
+	nodes := func() (*pool.Pool, *pool.Pool, bool) { return first, second, true }
+
+	t.Run("other node", func(t *testing.T) {
+		used := hedged(new(fasthttp.RequestCtx), nodes)
+		require.Same(t, first, used[0])
+		require.Same(t, second, used[1])
+		require.Same(t, second, used[2])
+	})
+
+	t.Run("single node", func(t *testing.T) {
+		used := hedged(new(fasthttp.RequestCtx), func() (*pool.Pool, *pool.Pool, bool) { return nil, nil, false })
+		require.Equal(t, []*pool.Pool{clnt, clnt, clnt}, used)
+	})
+
+	t.Run("node chosen by client", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		utils.SetRequestPool(&c, clnt)
+		used := hedged(&c, nodes)
+		require.Equal(t, []*pool.Pool{clnt, clnt, clnt}, used)
+	})
+}
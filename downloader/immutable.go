@@ -0,0 +1,110 @@
+package downloader
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// immutableMaxAge is the cache lifetime of content-addressed responses, they
+// never change.
+const immutableMaxAge = "max-age=31536000, immutable"
+
+// DownloadImmutable handles content-addressed download requests.
+func (d *Downloader) DownloadImmutable(c *fasthttp.RequestCtx) {
+	d.byImmutableAddress(c, request.receiveFile)
+}
+
+// HeadImmutable handles content-addressed head requests.
+func (d *Downloader) HeadImmutable(c *fasthttp.RequestCtx) {
+	d.byImmutableAddress(c, request.headObject)
+}
+
+// byImmutableAddress is like byAddress, but the container is to be specified
+// by ID since names can be changed.
+func (d *Downloader) byImmutableAddress(c *fasthttp.RequestCtx, f func(request, *pool.Pool, *address.Address)) {
+	var (
+		idCnr, _ = c.UserValue("cid").(string)
+		idObj, _ = c.UserValue("oid").(string)
+		log      = d.log.With(zap.String("cid", idCnr), zap.String("oid", idObj))
+	)
+
+	var cnrID cid.ID
+	if err := cnrID.DecodeString(idCnr); err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	var objID oid.ID
+	if err := objID.DecodeString(idObj); err != nil {
+		log.Error("wrong object id", zap.Error(err))
+		response.Error(c, "wrong object id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	addr := address.NewAddress()
+	addr.SetContainerID(cnrID)
+	addr.SetObjectID(objID)
+
+	req := d.newRequest(c, log)
+	req.immutable = true
+	f(*req, d.pool.Acquire(c), addr)
+}
+
+// objectETag returns the entity tag made of the object payload checksum, empty
+// if there is no checksum.
+func objectETag(obj *object.Object) string {
+	cs, ok := obj.PayloadChecksum()
+	if !ok || len(cs.Value()) == 0 {
+		return ""
+	}
+	return `"` + hex.EncodeToString(cs.Value()) + `"`
+}
+
+// etagMatches tells whether If-None-Match header value matches the entity tag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// immutableHeaders sets caching headers of the content-addressed response.
+// Responses to requests with bearer tokens or gateway identities aren't to be
+// cached by shared caches. It replies with 304 status and returns true if the
+// client has the object already.
+func (r request) immutableHeaders(obj *object.Object) bool {
+	cacheControl := "public, " + immutableMaxAge
+	if bearerToken(r.RequestCtx) != nil || utils.RequestKey(r.RequestCtx) != nil {
+		cacheControl = "private, " + immutableMaxAge
+	}
+	r.Response.Header.Set(fasthttp.HeaderCacheControl, cacheControl)
+
+	etag := objectETag(obj)
+	if etag == "" {
+		return false
+	}
+	r.Response.Header.Set(fasthttp.HeaderETag, etag)
+
+	if etagMatches(string(r.Request.Header.Peek(fasthttp.HeaderIfNoneMatch)), etag) {
+		r.SetStatusCode(fasthttp.StatusNotModified)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package downloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagMatches(t *testing.T) {
+	const etag = `"0a1b"`
+
+	for header, expected := range map[string]bool{
+		``:                   false,
+		`"0a1b"`:             true,
+		`W/"0a1b"`:           true,
+		`*`:                  true,
+		`"ffff", "0a1b"`:     true,
+		`"ffff",W/"0a1b"`:    true,
+		`"ffff"`:             false,
+		`0a1b`:               false,
+		`"0a1b0a1b", "ffff"`: false,
+	} {
+		require.Equal(t, expected, etagMatches(header, etag), header)
+	}
+
+	require.False(t, etagMatches("*", ""))
+}
@@ -0,0 +1,177 @@
+package downloader
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+)
+
+// byteRange is the requested payload range, end is inclusive.
+type byteRange struct {
+	start, end uint64
+}
+
+func (b byteRange) length() uint64 {
+	return b.end - b.start + 1
+}
+
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// parseRange parses Range header value for the payload of the given size.
+// Only single byte ranges are supported, false is returned for other and
+// malformed values, so the full payload is to be sent. Ranges starting after
+// the payload end can't be satisfied.
+func parseRange(header string, size uint64) (byteRange, bool, error) {
+	spec := strings.TrimSpace(header)
+	if !strings.HasPrefix(spec, "bytes=") {
+		return byteRange{}, false, nil
+	}
+	spec = strings.TrimSpace(spec[len("bytes="):])
+	if strings.Contains(spec, ",") {
+		return byteRange{}, false, nil
+	}
+
+	i := strings.IndexByte(spec, '-')
+	if i < 0 {
+		return byteRange{}, false, nil
+	}
+	first, last := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+
+	if first == "" {
+		// suffix range: the last N bytes
+		n, err := strconv.ParseUint(last, 10, 64)
+		if err != nil {
+			return byteRange{}, false, nil
+		}
+		if n == 0 || size == 0 {
+			return byteRange{}, false, errRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return byteRange{start: size - n, end: size - 1}, true, nil
+	}
+
+	start, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return byteRange{}, false, nil
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseUint(last, 10, 64); err != nil || end < start {
+			return byteRange{}, false, nil
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	if start >= size {
+		return byteRange{}, false, errRangeNotSatisfiable
+	}
+	return byteRange{start: start, end: end}, true, nil
+}
+
+// requestedRange returns the range to be sent if the request has Range header
+// and If-Range (if any) matches the object. It replies with 416 status if
+// the range can't be satisfied.
+func (r request) requestedRange(obj *object.Object) (byteRange, bool, bool) {
+	header := string(r.Request.Header.Peek(fasthttp.HeaderRange))
+	if header == "" {
+		return byteRange{}, false, true
+	}
+
+	if ifRange := string(r.Request.Header.Peek(fasthttp.HeaderIfRange)); ifRange != "" {
+		etag := objectETag(obj)
+		lastModified := string(r.Response.Header.Peek(fasthttp.HeaderLastModified))
+		if ifRange != etag && ifRange != lastModified {
+			return byteRange{}, false, true
+		}
+	}
+
+	size := obj.PayloadSize()
+	rng, ok, err := parseRange(header, size)
+	if err != nil {
+		response.Error(r.RequestCtx, "requested range not satisfiable", fasthttp.StatusRequestedRangeNotSatisfiable)
+		r.Response.Header.Set(fasthttp.HeaderContentRange, "bytes */"+strconv.FormatUint(size, 10))
+		return byteRange{}, false, false
+	}
+	return rng, ok, true
+}
+
+// rangePayload returns the reader of the payload range. Ranges of regular
+// objects are requested from NeoFS, payloads of split objects are read from
+// the beginning skipping bytes before the range.
+func (r request) rangePayload(clnt *pool.Pool, addr address.Address, payload io.ReadCloser, split bool, rng byteRange) (io.ReadCloser, error) {
+	if split {
+		if _, err := io.CopyN(io.Discard, payload, int64(rng.start)); err != nil {
+			_ = payload.Close()
+			return nil, err
+		}
+		return readCloser{io.LimitReader(payload, int64(rng.length())), payload}, nil
+	}
+
+	_ = payload.Close()
+
+	var res io.ReadCloser
+	err := r.retry.Do(r.appCtx, func() error {
+		var err error
+		res, err = r.objectRange(clnt, addr, rng.start, rng.length())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.resumableRange(clnt, addr, res, rng.start, rng.end+1), nil
+}
+
+// setRangeHeaders makes the response partial.
+func (r request) setRangeHeaders(rng byteRange, size uint64) {
+	r.SetStatusCode(fasthttp.StatusPartialContent)
+	r.Response.Header.Set(fasthttp.HeaderContentRange, "bytes "+strconv.FormatUint(rng.start, 10)+"-"+
+		strconv.FormatUint(rng.end, 10)+"/"+strconv.FormatUint(size, 10))
+	r.Response.Header.Set(fasthttp.HeaderContentLength, strconv.FormatUint(rng.length(), 10))
+}
+
+// mediaTypes are content types of media files missing in the standard
+// library table.
+var mediaTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".webm": "video/webm",
+	".ogv":  "video/ogg",
+	".mov":  "video/quicktime",
+	".mkv":  "video/x-matroska",
+	".ts":   "video/mp2t",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".mpd":  "application/dash+xml",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+}
+
+// contentTypeByName returns the content type by the file name extension,
+// empty if it's unknown.
+func contentTypeByName(filename string) string {
+	ext := strings.ToLower(path.Ext(filename))
+	if ext == "" {
+		return ""
+	}
+	if typ, ok := mediaTypes[ext]; ok {
+		return typ
+	}
+	return mime.TypeByExtension(ext)
+}
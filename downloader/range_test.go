@@ -0,0 +1,50 @@
+package downloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		rng    byteRange
+		ok     bool
+		err    bool
+	}{
+		{header: "bytes=0-99", rng: byteRange{start: 0, end: 99}, ok: true},
+		{header: "bytes=100-", rng: byteRange{start: 100, end: 999}, ok: true},
+		{header: "bytes=900-2000", rng: byteRange{start: 900, end: 999}, ok: true},
+		{header: "bytes=-100", rng: byteRange{start: 900, end: 999}, ok: true},
+		{header: "bytes=-2000", rng: byteRange{start: 0, end: 999}, ok: true},
+		{header: " bytes= 5 - 5 ", rng: byteRange{start: 5, end: 5}, ok: true},
+		{header: "bytes=1000-", err: true},
+		{header: "bytes=-0", err: true},
+		{header: "bytes=0-1,5-6"},
+		{header: "bytes=5-1"},
+		{header: "bytes=a-"},
+		{header: "items=0-1"},
+		{header: "bytes=5"},
+	} {
+		rng, ok, err := parseRange(tc.header, 1000)
+		if tc.err {
+			require.ErrorIs(t, err, errRangeNotSatisfiable, tc.header)
+			continue
+		}
+		require.NoError(t, err, tc.header)
+		require.Equal(t, tc.ok, ok, tc.header)
+		require.Equal(t, tc.rng, rng, tc.header)
+	}
+
+	_, _, err := parseRange("bytes=0-", 0)
+	require.ErrorIs(t, err, errRangeNotSatisfiable)
+}
+
+func TestContentTypeByName(t *testing.T) {
+	require.Equal(t, "video/mp4", contentTypeByName("clips/cat.MP4"))
+	require.Equal(t, "video/webm", contentTypeByName("cat.webm"))
+	require.Equal(t, "image/png", contentTypeByName("cat.png"))
+	require.Empty(t, contentTypeByName("cat"))
+	require.Empty(t, contentTypeByName("cat.unknown-extension"))
+}
@@ -0,0 +1,275 @@
+package downloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+// searchMatchTypes are the match types of search filters accepted in requests.
+var searchMatchTypes = map[string]object.SearchMatchType{
+	"":                 object.MatchStringEqual,
+	"string_equal":     object.MatchStringEqual,
+	"string_not_equal": object.MatchStringNotEqual,
+	"not_present":      object.MatchNotPresent,
+	"common_prefix":    object.MatchCommonPrefix,
+}
+
+type (
+	searchFilter struct {
+		Match string `json:"match"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+
+	// searchRequest is the body of search request: object attribute filters,
+	// attributes to return and the page of results.
+	searchRequest struct {
+		Filters    []searchFilter `json:"filters"`
+		Attributes []string       `json:"attributes"`
+		Offset     int            `json:"offset"`
+		Limit      int            `json:"limit"`
+	}
+
+	searchObject struct {
+		ObjectID   string            `json:"object_id"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+	}
+
+	searchResponse struct {
+		Objects    []searchObject `json:"objects"`
+		NextOffset *int           `json:"next_offset,omitempty"`
+	}
+
+	// idIterator is the result of object search.
+	idIterator interface {
+		Iterate(func(oid.ID) bool) error
+	}
+)
+
+// checkPage validates the page of search results and returns the limit
+// (default one if it's zero).
+func checkPage(offset, limit int) (int, error) {
+	if offset < 0 {
+		return 0, errors.New("offset must not be negative")
+	}
+	switch {
+	case limit < 0:
+		return 0, errors.New("limit must not be negative")
+	case limit == 0:
+		return defaultSearchLimit, nil
+	case limit > maxSearchLimit:
+		return 0, fmt.Errorf("limit must not exceed %d", maxSearchLimit)
+	}
+	return limit, nil
+}
+
+// parseSearchRequest decodes and validates search request body.
+func parseSearchRequest(data []byte) (*searchRequest, object.SearchFilters, error) {
+	var req searchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	var err error
+	if req.Limit, err = checkPage(req.Offset, req.Limit); err != nil {
+		return nil, nil, err
+	}
+
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	for _, f := range req.Filters {
+		match, ok := searchMatchTypes[f.Match]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown match type: %s", f.Match)
+		}
+		if f.Key == "" {
+			return nil, nil, errors.New("filter key is empty")
+		}
+		filters.AddFilter(f.Key, f.Value, match)
+	}
+
+	return &req, filters, nil
+}
+
+// readPage returns at most limit IDs after offset ones and whether there are
+// more of them.
+func readPage(res idIterator, offset, limit int) ([]oid.ID, bool, error) {
+	var (
+		ids     = make([]oid.ID, 0, limit)
+		skipped int
+		more    bool
+	)
+
+	err := res.Iterate(func(id oid.ID) bool {
+		if skipped < offset {
+			skipped++
+			return false
+		}
+		if len(ids) == limit {
+			more = true
+			return true
+		}
+		ids = append(ids, id)
+		return false
+	})
+
+	return ids, more, err
+}
+
+// Search handles object search requests with JSON filters.
+func (d *Downloader) Search(c *fasthttp.RequestCtx) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		log     = d.log.With(zap.String("cid", scid))
+		ctx     = utils.RequestContext(c, d.appCtx)
+	)
+
+	req, filters, err := parseSearchRequest(c.Request.Body())
+	if err != nil {
+		log.Error("invalid search request", zap.Error(err))
+		response.Error(c, "invalid search request: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	containerID, err := utils.GetContainerID(ctx, scid, d.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch and store bearer token", zap.Error(err))
+		response.Error(c, "could not fetch and store bearer token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	d.searchResults(c, log, containerID, filters, req.Offset, req.Limit, req.Attributes)
+}
+
+// searchResults responds with the page of objects matching the filters and
+// their selected attributes.
+func (d *Downloader) searchResults(c *fasthttp.RequestCtx, log *zap.Logger, containerID *cid.ID, filters object.SearchFilters, offset, limit int, attributes []string) {
+	var (
+		start = time.Now()
+		ctx   = utils.RequestContext(c, d.appCtx)
+	)
+
+	btoken := bearerToken(c)
+	key := utils.RequestKey(c)
+	clientPool := d.pool.Acquire(c)
+
+	var prm pool.PrmObjectSearch
+	prm.SetContainerID(*containerID)
+	prm.SetFilters(filters)
+	if btoken != nil {
+		prm.UseBearer(*btoken)
+	}
+	if key != nil {
+		prm.UseKey(key)
+	}
+
+	var res *pool.ResObjectSearch
+	err := d.retry.Do(ctx, func() error {
+		var err error
+		res, err = clientPool.SearchObjects(ctx, prm)
+		return err
+	})
+	if err != nil {
+		log.Error("could not search for objects", zap.Error(err))
+		response.Error(c, "could not search for objects: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	ids, more, err := readPage(res, offset, limit)
+	res.Close()
+	if err != nil {
+		log.Error("read object list failed", zap.Error(err))
+		response.Error(c, "read object list failed: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	resp := searchResponse{Objects: make([]searchObject, 0, len(ids))}
+	if more {
+		next := offset + len(ids)
+		resp.NextOffset = &next
+	}
+
+	var addr address.Address
+	addr.SetContainerID(*containerID)
+
+	for _, id := range ids {
+		obj := searchObject{ObjectID: id.String()}
+
+		if len(attributes) != 0 {
+			addr.SetObjectID(id)
+
+			var prmHead pool.PrmObjectHead
+			prmHead.SetAddress(addr)
+			if btoken != nil {
+				prmHead.UseBearer(*btoken)
+			}
+			if key != nil {
+				prmHead.UseKey(key)
+			}
+
+			var header *object.Object
+			err := d.retry.Do(ctx, func() error {
+				var err error
+				header, err = clientPool.HeadObject(ctx, prmHead)
+				return err
+			})
+			if err != nil {
+				r := d.newRequest(c, log.With(zap.Stringer("oid", id)))
+				r.handleNeoFSErr(err, start)
+				return
+			}
+
+			obj.Attributes = selectAttributes(header, attributes)
+		}
+
+		resp.Objects = append(resp.Objects, obj)
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Error("could not encode search result", zap.Error(err))
+		response.Error(c, "could not encode search result: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
+
+// selectAttributes returns the object attributes with the keys given.
+func selectAttributes(obj *object.Object, keys []string) map[string]string {
+	res := make(map[string]string, len(keys))
+	for _, attr := range obj.Attributes() {
+		for _, key := range keys {
+			if attr.Key() == key {
+				res[key] = attr.Value()
+				break
+			}
+		}
+	}
+	return res
+}
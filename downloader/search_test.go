@@ -0,0 +1,71 @@
+package downloader
+
+import (
+	"testing"
+
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceIterator []oid.ID
+
+func (s sliceIterator) Iterate(f func(oid.ID) bool) error {
+	for _, id := range s {
+		if f(id) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestParseSearchRequest(t *testing.T) {
+	req, _, err := parseSearchRequest([]byte(`{"filters":[{"key":"FilePath","value":"cat.jpg"},{"match":"common_prefix","key":"FileName","value":"c"}],"attributes":["FilePath"]}`))
+	require.NoError(t, err)
+	require.Equal(t, defaultSearchLimit, req.Limit)
+	require.Equal(t, []string{"FilePath"}, req.Attributes)
+
+	for _, body := range []string{
+		`[]`,
+		`{"offset":-1}`,
+		`{"limit":100000}`,
+		`{"filters":[{"match":"regexp","key":"FilePath","value":".*"}]}`,
+		`{"filters":[{"value":"cat.jpg"}]}`,
+	} {
+		_, _, err = parseSearchRequest([]byte(body))
+		require.Error(t, err, body)
+	}
+}
+
+func TestReadPage(t *testing.T) {
+	res := make(sliceIterator, 5)
+
+	ids, more, err := readPage(res, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.True(t, more)
+
+	ids, more, err = readPage(res, 3, 2)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.False(t, more)
+
+	ids, more, err = readPage(res, 10, 2)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+	require.False(t, more)
+}
+
+func TestCheckPage(t *testing.T) {
+	limit, err := checkPage(10, 0)
+	require.NoError(t, err)
+	require.Equal(t, defaultSearchLimit, limit)
+
+	limit, err = checkPage(0, 5)
+	require.NoError(t, err)
+	require.Equal(t, 5, limit)
+
+	_, err = checkPage(-1, 5)
+	require.Error(t, err)
+	_, err = checkPage(0, maxSearchLimit+1)
+	require.Error(t, err)
+}
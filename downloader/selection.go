@@ -0,0 +1,130 @@
+package downloader
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+)
+
+// objectVersion is the order of the objects with the same attribute.
+type objectVersion struct {
+	id        oid.ID
+	timestamp int64
+	epoch     uint64
+}
+
+func newObjectVersion(id oid.ID, obj *object.Object) objectVersion {
+	v := objectVersion{id: id, epoch: obj.CreationEpoch()}
+	for _, attr := range obj.Attributes() {
+		if attr.Key() == object.AttributeTimestamp {
+			v.timestamp, _ = strconv.ParseInt(attr.Value(), 10, 64)
+			break
+		}
+	}
+	return v
+}
+
+// newerThan compares timestamps, creation epochs and IDs (to get the same
+// result regardless of search order) of the objects.
+func (v objectVersion) newerThan(other objectVersion) bool {
+	if v.timestamp != other.timestamp {
+		return v.timestamp > other.timestamp
+	}
+	if v.epoch != other.epoch {
+		return v.epoch > other.epoch
+	}
+	return v.id.String() > other.id.String()
+}
+
+// selectObject returns the object found according to the attribute selection
+// setting, io.EOF if there are none.
+func (d *Downloader) selectObject(c *fasthttp.RequestCtx, res *pool.ResObjectSearch, cnrID cid.ID) (oid.ID, error) {
+	if d.settings.AttributeSelection == AttributeSelectionNewest {
+		return d.newestObject(c, res, cnrID)
+	}
+	return firstObject(res)
+}
+
+// firstObject returns the first object found, io.EOF if there are none.
+func firstObject(res *pool.ResObjectSearch) (oid.ID, error) {
+	buf := make([]oid.ID, 1)
+
+	n, err := res.Read(buf)
+	if n == 0 {
+		if err == nil {
+			err = io.EOF
+		}
+		return oid.ID{}, err
+	}
+	return buf[0], nil
+}
+
+// newestObject heads all objects found and returns the newest one, io.EOF if
+// there are none.
+func (d *Downloader) newestObject(c *fasthttp.RequestCtx, res idIterator, cnrID cid.ID) (oid.ID, error) {
+	var ids []oid.ID
+	if err := res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return false
+	}); err != nil {
+		return oid.ID{}, err
+	}
+
+	switch len(ids) {
+	case 0:
+		return oid.ID{}, io.EOF
+	case 1:
+		return ids[0], nil
+	}
+
+	if err := tokens.StoreBearerToken(c); err != nil {
+		return oid.ID{}, err
+	}
+
+	var (
+		ctx        = utils.RequestContext(c, d.appCtx)
+		clientPool = d.pool.Acquire(c)
+		btoken     = bearerToken(c)
+		key        = utils.RequestKey(c)
+		newest     objectVersion
+		addr       address.Address
+	)
+	addr.SetContainerID(cnrID)
+
+	for i, id := range ids {
+		addr.SetObjectID(id)
+
+		var prm pool.PrmObjectHead
+		prm.SetAddress(addr)
+		if btoken != nil {
+			prm.UseBearer(*btoken)
+		}
+		if key != nil {
+			prm.UseKey(key)
+		}
+
+		var obj *object.Object
+		err := d.retry.Do(ctx, func() error {
+			var err error
+			obj, err = clientPool.HeadObject(ctx, prm)
+			return err
+		})
+		if err != nil {
+			return oid.ID{}, err
+		}
+
+		if v := newObjectVersion(id, obj); i == 0 || v.newerThan(newest) {
+			newest = v
+		}
+	}
+
+	return newest.id, nil
+}
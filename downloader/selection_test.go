@@ -0,0 +1,18 @@
+package downloader
+
+import (
+	"testing"
+
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectVersionNewerThan(t *testing.T) {
+	var id oid.ID
+
+	older := objectVersion{id: id, timestamp: 100, epoch: 20}
+	require.True(t, objectVersion{id: id, timestamp: 200, epoch: 10}.newerThan(older))
+	require.True(t, objectVersion{id: id, timestamp: 100, epoch: 21}.newerThan(older))
+	require.False(t, objectVersion{id: id, timestamp: 100, epoch: 20}.newerThan(older))
+	require.False(t, objectVersion{id: id, timestamp: 99, epoch: 30}.newerThan(older))
+}
@@ -0,0 +1,106 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+)
+
+// splitReader reads payloads of split object children one after another.
+type splitReader struct {
+	children []oid.ID
+	open     func(oid.ID) (io.ReadCloser, error)
+	cur      io.ReadCloser
+}
+
+func (s *splitReader) Read(p []byte) (int, error) {
+	for {
+		if s.cur == nil {
+			if len(s.children) == 0 {
+				return 0, io.EOF
+			}
+			var err error
+			if s.cur, err = s.open(s.children[0]); err != nil {
+				return 0, err
+			}
+			s.children = s.children[1:]
+		}
+
+		n, err := s.cur.Read(p)
+		if err == io.EOF {
+			err = s.cur.Close()
+			s.cur = nil
+			if n == 0 && err == nil {
+				continue
+			}
+		}
+		return n, err
+	}
+}
+
+func (s *splitReader) Close() error {
+	if s.cur == nil {
+		return nil
+	}
+	return s.cur.Close()
+}
+
+// linkedParent returns the parent header and children of the object if it's
+// the link object of a split chain.
+func linkedParent(obj *object.Object) (*object.Object, []oid.ID, bool) {
+	children, parent := obj.Children(), obj.Parent()
+	if len(children) == 0 || parent == nil {
+		return nil, nil, false
+	}
+	return parent, children, true
+}
+
+// childAddress returns the address of the child object in the same container.
+func childAddress(addr *address.Address, id oid.ID) address.Address {
+	var res address.Address
+	cnrID, _ := addr.ContainerID()
+	res.SetContainerID(cnrID)
+	res.SetObjectID(id)
+	return res
+}
+
+// splitPayload returns the parent header and the reader of its payload
+// assembled from the children if the object is the link object of a split
+// chain, so that clients get one continuous payload.
+func (r request) splitPayload(clnt *pool.Pool, addr *address.Address, obj *object.Object) (*object.Object, io.ReadCloser, bool) {
+	parent, children, ok := linkedParent(obj)
+	if !ok {
+		return nil, nil, false
+	}
+
+	btoken := bearerToken(r.RequestCtx)
+	key := utils.RequestKey(r.RequestCtx)
+	open := func(id oid.ID) (io.ReadCloser, error) {
+		var prm pool.PrmObjectGet
+		prm.SetAddress(childAddress(addr, id))
+		if btoken != nil {
+			prm.UseBearer(*btoken)
+		}
+		if key != nil {
+			prm.UseKey(key)
+		}
+
+		var res *pool.ResGetObject
+		err := r.retry.Do(r.appCtx, func() error {
+			var err error
+			res, err = clnt.GetObject(r.appCtx, prm)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get child object %s: %w", id, err)
+		}
+		return r.resumable(clnt, childAddress(addr, id), res.Payload, res.Header.PayloadSize()), nil
+	}
+
+	return parent, &splitReader{children: children, open: open}, true
+}
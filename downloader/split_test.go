@@ -0,0 +1,43 @@
+package downloader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitReader(t *testing.T) {
+	payloads := []string{"first ", "", "third"}
+	children := make([]oid.ID, len(payloads))
+
+	newReader := func(errAt int, err error) *splitReader {
+		var opened int
+		return &splitReader{
+			children: children,
+			open: func(oid.ID) (io.ReadCloser, error) {
+				i := opened
+				opened++
+				if i == errAt {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader(payloads[i])), nil
+			},
+		}
+	}
+
+	r := newReader(-1, nil)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "first third", string(data))
+	require.Empty(t, r.children)
+	require.NoError(t, r.Close())
+
+	errChild := errors.New("child error")
+	data, err = io.ReadAll(newReader(1, errChild))
+	require.ErrorIs(t, err, errChild)
+	require.Equal(t, "first ", string(data))
+}
@@ -0,0 +1,337 @@
+package downloader
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"sync"
+
+	// GIF images can be resized too (the first frame is used).
+	_ "image/gif"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Thumbnail output formats.
+const (
+	thumbnailJPEG = "jpeg"
+	thumbnailPNG  = "png"
+	// thumbnailWebP is recognized to be rejected explicitly, there is no WebP
+	// encoder in the Go standard library.
+	thumbnailWebP = "webp"
+
+	thumbnailJPEGQuality = 85
+)
+
+// ThumbnailSettings are the settings of on-the-fly image resizing.
+type ThumbnailSettings struct {
+	Enabled bool
+	// MaxSourceSize is the maximum payload size of images to be resized.
+	MaxSourceSize uint64
+	// MaxDimension limits requested width and height.
+	MaxDimension int
+	// MaxSourcePixels limits width x height of images to be resized, it's
+	// checked before the image is decoded. Zero means no limit.
+	MaxSourcePixels int64
+	// CacheSize is the total size of resized images kept in memory.
+	CacheSize int
+}
+
+// thumbnailParams are requested thumbnail size and format. Zero dimension
+// is calculated from the other one keeping the aspect ratio, empty format
+// means the format of the source image.
+type thumbnailParams struct {
+	width, height int
+	format        string
+}
+
+func (p thumbnailParams) String() string {
+	return fmt.Sprintf("%dx%d.%s", p.width, p.height, p.format)
+}
+
+// parseThumbnailParams reads w, h and format query arguments, false is
+// returned if there are none of them.
+func parseThumbnailParams(args *fasthttp.Args, maxDimension int) (thumbnailParams, bool, error) {
+	var p thumbnailParams
+	if !args.Has("w") && !args.Has("h") && !args.Has("format") {
+		return p, false, nil
+	}
+
+	for _, dim := range []struct {
+		name  string
+		value *int
+	}{{"w", &p.width}, {"h", &p.height}} {
+		if !args.Has(dim.name) {
+			continue
+		}
+		v, err := strconv.Atoi(string(args.Peek(dim.name)))
+		if err != nil || v <= 0 || v > maxDimension {
+			return p, false, fmt.Errorf("%s must be a number from 1 to %d", dim.name, maxDimension)
+		}
+		*dim.value = v
+	}
+
+	switch format := string(args.Peek("format")); format {
+	case "":
+	case thumbnailJPEG, "jpg":
+		p.format = thumbnailJPEG
+	case thumbnailPNG:
+		p.format = thumbnailPNG
+	case thumbnailWebP:
+		return p, false, errors.New("webp thumbnails aren't supported, jpeg and png are supported")
+	default:
+		return p, false, fmt.Errorf("unsupported format %q, jpeg and png are supported", format)
+	}
+	return p, true, nil
+}
+
+// thumbnailer resizes images and caches the results.
+type thumbnailer struct {
+	settings ThumbnailSettings
+	cache    *thumbnailCache
+}
+
+func newThumbnailer(settings ThumbnailSettings) *thumbnailer {
+	if !settings.Enabled {
+		return nil
+	}
+	return &thumbnailer{
+		settings: settings,
+		cache:    newThumbnailCache(settings.CacheSize),
+	}
+}
+
+// serveThumbnail writes resized image of the object payload, the payload is
+// closed.
+func (r request) serveThumbnail(addr *address.Address, hdr *object.Object, payload io.ReadCloser, p thumbnailParams) {
+	defer payload.Close()
+
+	key := addr.String() + "/" + p.String()
+	if thumb, ok := r.thumbnails.cache.get(key); ok {
+		r.writeThumbnail(hdr, thumb)
+		return
+	}
+
+	if hdr.PayloadSize() > r.thumbnails.settings.MaxSourceSize {
+		response.Error(r.RequestCtx, "image is too large to be resized", fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		r.log.Error("could not read image", zap.Error(err))
+		response.Error(r.RequestCtx, "could not read image: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	thumb, err := makeThumbnail(data, p, r.thumbnails.settings.MaxSourcePixels)
+	if errors.Is(err, errTooManyPixels) {
+		response.Error(r.RequestCtx, "image is too large to be resized", fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		r.log.Error("could not resize image", zap.Error(err))
+		response.Error(r.RequestCtx, "could not resize image: "+err.Error(), fasthttp.StatusUnsupportedMediaType)
+		return
+	}
+
+	r.thumbnails.cache.put(key, thumb)
+	r.writeThumbnail(hdr, thumb)
+}
+
+func (r request) writeThumbnail(hdr *object.Object, thumb *thumbnail) {
+	idsToResponse(&r.Response, hdr)
+	r.SetContentType(thumb.contentType)
+	r.SetBody(thumb.data)
+}
+
+// thumbnail is encoded resized image.
+type thumbnail struct {
+	contentType string
+	data        []byte
+}
+
+var (
+	errNotImage      = errors.New("payload is not a supported image")
+	errTooManyPixels = errors.New("image has too many pixels")
+)
+
+// makeThumbnail decodes the image, scales it down to fit the requested size
+// and encodes it. Images are never scaled up. The size of the image is checked
+// against maxPixels (if it's not zero) before it's decoded, so that small
+// payloads can't make the gateway allocate huge images.
+func makeThumbnail(data []byte, p thumbnailParams, maxPixels int64) (*thumbnail, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errNotImage
+	}
+	if maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, errTooManyPixels
+	}
+
+	src, srcFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errNotImage
+	}
+
+	format := p.format
+	if format == "" {
+		format = thumbnailPNG
+		if srcFormat == thumbnailJPEG {
+			format = thumbnailJPEG
+		}
+	}
+
+	w, h := fitSize(src.Bounds().Dx(), src.Bounds().Dy(), p.width, p.height)
+	dst := resize(src, w, h)
+
+	var buf bytes.Buffer
+	res := &thumbnail{}
+	switch format {
+	case thumbnailJPEG:
+		res.contentType = "image/jpeg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	default:
+		res.contentType = "image/png"
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.data = buf.Bytes()
+	return res, nil
+}
+
+// fitSize returns the size of the image scaled to fit into width x height
+// box keeping the aspect ratio. Zero box dimension isn't limited.
+func fitSize(srcW, srcH, width, height int) (int, int) {
+	scale := 1.0
+	if width > 0 && width < srcW {
+		scale = float64(width) / float64(srcW)
+	}
+	if height > 0 && float64(height) < float64(srcH)*scale {
+		scale = float64(height) / float64(srcH)
+	}
+
+	w, h := int(float64(srcW)*scale+0.5), int(float64(srcH)*scale+0.5)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// resize scales the image down averaging source pixels covered by every
+// destination one.
+func resize(src image.Image, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	in, ok := src.(*image.NRGBA)
+	if !ok || b.Min != (image.Point{}) {
+		in = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+	}
+	srcW, srcH := in.Bounds().Dx(), in.Bounds().Dy()
+	if srcW == w && srcH == h {
+		return in
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*srcH/h, (y+1)*srcH/h
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := x*srcW/w, (x+1)*srcW/w
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := in.Pix[sy*in.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			off := y*out.Stride + x*4
+			for c := 0; c < 4; c++ {
+				out.Pix[off+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return out
+}
+
+// thumbnailCache is LRU cache of thumbnails limited by their total size.
+type thumbnailCache struct {
+	mu      sync.Mutex
+	maxSize int
+	size    int
+	items   map[string]*list.Element
+	order   *list.List
+}
+
+type cachedThumbnail struct {
+	key   string
+	thumb *thumbnail
+}
+
+func newThumbnailCache(maxSize int) *thumbnailCache {
+	return &thumbnailCache{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *thumbnailCache) get(key string) (*thumbnail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cachedThumbnail).thumb, true
+}
+
+func (c *thumbnailCache) put(key string, thumb *thumbnail) {
+	if len(thumb.data) > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	c.items[key] = c.order.PushFront(&cachedThumbnail{key: key, thumb: thumb})
+	c.size += len(thumb.data)
+
+	for c.size > c.maxSize {
+		el := c.order.Back()
+		item := c.order.Remove(el).(*cachedThumbnail)
+		delete(c.items, item.key)
+		c.size -= len(item.thumb.data)
+	}
+}
@@ -0,0 +1,120 @@
+package downloader
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseThumbnailParams(t *testing.T) {
+	parse := func(query string) (thumbnailParams, bool, error) {
+		var args fasthttp.Args
+		args.Parse(query)
+		return parseThumbnailParams(&args, 1000)
+	}
+
+	_, ok, err := parse("download=true")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	p, ok, err := parse("w=320&h=240&format=jpg")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, thumbnailParams{width: 320, height: 240, format: thumbnailJPEG}, p)
+
+	p, ok, err = parse("format=png")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, thumbnailParams{format: thumbnailPNG}, p)
+
+	_, _, err = parse("format=webp")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "webp thumbnails aren't supported")
+
+	for _, query := range []string{"w=0", "h=-1", "w=1001", "w=abc", "format=gif"} {
+		_, _, err = parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	for _, tc := range []struct {
+		srcW, srcH, w, h int
+		expW, expH       int
+	}{
+		{srcW: 1000, srcH: 500, w: 320, h: 240, expW: 320, expH: 160},
+		{srcW: 500, srcH: 1000, w: 320, h: 240, expW: 120, expH: 240},
+		{srcW: 1000, srcH: 500, w: 100, expW: 100, expH: 50},
+		{srcW: 1000, srcH: 500, h: 100, expW: 200, expH: 100},
+		{srcW: 100, srcH: 50, w: 320, h: 240, expW: 100, expH: 50},
+		{srcW: 1000, srcH: 1, w: 10, expW: 10, expH: 1},
+	} {
+		w, h := fitSize(tc.srcW, tc.srcH, tc.w, tc.h)
+		require.Equal(t, [2]int{tc.expW, tc.expH}, [2]int{w, h}, tc)
+	}
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+
+	thumb, err := makeThumbnail(buf.Bytes(), thumbnailParams{width: 10}, 0)
+	require.NoError(t, err)
+	require.Equal(t, "image/png", thumb.contentType)
+
+	res, format, err := image.Decode(bytes.NewReader(thumb.data))
+	require.NoError(t, err)
+	require.Equal(t, "png", format)
+	require.Equal(t, image.Rect(0, 0, 10, 5), res.Bounds())
+	require.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(res.At(5, 2)))
+
+	thumb, err = makeThumbnail(buf.Bytes(), thumbnailParams{height: 4, format: thumbnailJPEG}, 800)
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", thumb.contentType)
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb.data))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", format)
+	require.Equal(t, [2]int{8, 4}, [2]int{cfg.Width, cfg.Height})
+
+	_, err = makeThumbnail(buf.Bytes(), thumbnailParams{width: 10}, 799)
+	require.ErrorIs(t, err, errTooManyPixels)
+
+	_, err = makeThumbnail([]byte("not an image"), thumbnailParams{width: 10}, 0)
+	require.ErrorIs(t, err, errNotImage)
+}
+
+func TestThumbnailCache(t *testing.T) {
+	c := newThumbnailCache(10)
+	thumb := func(size int) *thumbnail {
+		return &thumbnail{data: make([]byte, size)}
+	}
+
+	c.put("a", thumb(4))
+	c.put("b", thumb(4))
+	_, ok := c.get("a")
+	require.True(t, ok)
+
+	c.put("c", thumb(4)) // evicts least recently used b
+	_, ok = c.get("b")
+	require.False(t, ok)
+	_, ok = c.get("a")
+	require.True(t, ok)
+	_, ok = c.get("c")
+	require.True(t, ok)
+
+	c.put("d", thumb(11)) // larger than the cache
+	_, ok = c.get("d")
+	require.False(t, ok)
+	require.Equal(t, 8, c.size)
+}
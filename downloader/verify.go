@@ -0,0 +1,70 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"go.uber.org/zap"
+)
+
+var errPayloadCorrupted = errors.New("payload checksum mismatch")
+
+// verifyingReader hashes the payload while it's read and fails instead of
+// returning io.EOF if the hash doesn't match the expected one. The response
+// is aborted then, so clients get an incomplete reply instead of corrupted
+// data silently.
+type verifyingReader struct {
+	r        io.ReadCloser
+	h        hash.Hash
+	expected []byte
+	log      *zap.Logger
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+	if err == io.EOF {
+		if actual := v.h.Sum(nil); !bytes.Equal(actual, v.expected) {
+			v.log.Error("payload checksum mismatch",
+				zap.String("expected", hex.EncodeToString(v.expected)),
+				zap.String("actual", hex.EncodeToString(actual)))
+			return n, errPayloadCorrupted
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.r.Close()
+}
+
+// verifiedPayload makes the payload verified against SHA-256 checksum of the
+// object header. The payload is returned as is if there is no such checksum.
+func (r request) verifiedPayload(obj *object.Object, payload io.ReadCloser) io.ReadCloser {
+	cs, ok := obj.PayloadChecksum()
+	if !ok || cs.Type() != checksum.SHA256 {
+		r.log.Debug("payload isn't verified, there is no SHA-256 checksum")
+		return payload
+	}
+	return &verifyingReader{
+		r:        payload,
+		h:        sha256.New(),
+		expected: cs.Value(),
+		log:      r.log,
+	}
+}
+
+// shouldVerify tells whether the payload is to be verified, verify query
+// argument overrides the configured default.
+func (r request) shouldVerify() bool {
+	if args := r.QueryArgs(); args.Has("verify") {
+		return args.GetBool("verify")
+	}
+	return r.verifyPayload
+}
@@ -0,0 +1,34 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVerifyingReader(t *testing.T) {
+	payload := []byte("payload to be verified")
+	sum := sha256.Sum256(payload)
+
+	newReader := func(expected []byte) *verifyingReader {
+		return &verifyingReader{
+			r:        io.NopCloser(bytes.NewReader(payload)),
+			h:        sha256.New(),
+			expected: expected,
+			log:      zap.NewNop(),
+		}
+	}
+
+	data, err := io.ReadAll(newReader(sum[:]))
+	require.NoError(t, err)
+	require.Equal(t, payload, data)
+
+	corrupted := sum
+	corrupted[0]++
+	_, err = io.ReadAll(newReader(corrupted[:]))
+	require.ErrorIs(t, err, errPayloadCorrupted)
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/fasthttp/router"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+)
+
+// SetDraining enables or disables drain mode.
+func (a *app) SetDraining(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&a.draining, v)
+}
+
+// Draining tells whether drain mode is enabled.
+func (a *app) Draining() bool {
+	return atomic.LoadInt32(&a.draining) == 1
+}
+
+// drainMode rejects new requests with 503 status while the gateway is
+// draining, requests in progress aren't affected. Connections of the rejected
+// requests are closed, so load balancers switch to other gateways.
+func (a *app) drainMode(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	retryAfter := strconv.Itoa(int(math.Ceil(a.cfg.GetDuration(cfgDrainRetryAfter).Seconds())))
+
+	return func(c *fasthttp.RequestCtx) {
+		if a.Draining() {
+			response.Error(c, "gateway is draining", fasthttp.StatusServiceUnavailable)
+			c.Response.Header.Set(fasthttp.HeaderRetryAfter, retryAfter)
+			c.SetConnectionClose()
+			return
+		}
+
+		h(c)
+	}
+}
+
+// readinessHandler reports whether the gateway accepts new requests.
+func (a *app) readinessHandler(c *fasthttp.RequestCtx) {
+	switch {
+	case a.Draining():
+		response.Error(c, "draining", fasthttp.StatusServiceUnavailable)
+	case atomic.LoadInt32(&a.maintenance) == 1:
+		response.Error(c, "maintenance", fasthttp.StatusServiceUnavailable)
+	case a.lacksHealthyNodes():
+		response.Error(c, "not enough healthy nodes", fasthttp.StatusServiceUnavailable)
+	default:
+		c.SetStatusCode(fasthttp.StatusOK)
+		c.SetBodyString("ready")
+	}
+}
+
+// attachDrain adds admin API routes to enter (POST) and leave (DELETE) drain
+// mode.
+func (a *app) attachDrain(r *router.Router, token string) {
+	r.POST("/admin/drain", a.adminAuth(token, func(c *fasthttp.RequestCtx) {
+		a.SetDraining(true)
+		a.log.Info("drain mode enabled")
+		c.SetStatusCode(fasthttp.StatusOK)
+	}))
+	r.DELETE("/admin/drain", a.adminAuth(token, func(c *fasthttp.RequestCtx) {
+		a.SetDraining(false)
+		a.log.Info("drain mode disabled")
+		c.SetStatusCode(fasthttp.StatusOK)
+	}))
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestDrainMode(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgDrainRetryAfter, 1500*time.Millisecond)
+	a := &app{cfg: v, log: zap.NewNop()}
+
+	h := a.drainMode(func(c *fasthttp.RequestCtx) {
+		c.SetStatusCode(fasthttp.StatusNoContent)
+	})
+
+	var c fasthttp.RequestCtx
+	h(&c)
+	require.Equal(t, fasthttp.StatusNoContent, c.Response.StatusCode())
+	a.readinessHandler(&c)
+	require.Equal(t, fasthttp.StatusOK, c.Response.StatusCode())
+
+	a.SetDraining(true)
+
+	c.Response.Reset()
+	h(&c)
+	require.Equal(t, fasthttp.StatusServiceUnavailable, c.Response.StatusCode())
+	require.Equal(t, "2", string(c.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+	require.True(t, c.Response.ConnectionClose())
+
+	c.Response.Reset()
+	a.readinessHandler(&c)
+	require.Equal(t, fasthttp.StatusServiceUnavailable, c.Response.StatusCode())
+
+	a.SetDraining(false)
+	a.setMaintenance(true)
+
+	c.Response.Reset()
+	a.readinessHandler(&c)
+	require.Equal(t, fasthttp.StatusServiceUnavailable, c.Response.StatusCode())
+}
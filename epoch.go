@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// networkEpoch shows the current network epoch known to the gateway.
+var networkEpoch = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "neofs_http_gw",
+	Name:      "network_epoch",
+	Help:      "Current NeoFS network epoch.",
+})
+
+// newEpochTracker starts tracking the network epoch if it's enabled, nil is
+// returned otherwise.
+func (a *app) newEpochTracker(ctx context.Context) *utils.EpochTracker {
+	interval := a.cfg.GetDuration(cfgEpochRefreshInterval)
+	if interval <= 0 {
+		return nil
+	}
+
+	t := utils.NewEpochTracker(a.log, a.pool, interval, func(epoch uint64) {
+		networkEpoch.Set(float64(epoch))
+	})
+	t.Start(ctx)
+	return t
+}
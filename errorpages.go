@@ -0,0 +1,24 @@
+package gateway
+
+import (
+	"html/template"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/spf13/viper"
+)
+
+// loadErrorTemplate sets HTML template of error pages if it's configured.
+func loadErrorTemplate(v *viper.Viper) error {
+	path := v.GetString(cfgErrorsHTMLTemplate)
+	if path == "" {
+		response.SetHTMLTemplate(nil)
+		return nil
+	}
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return err
+	}
+	response.SetHTMLTemplate(t)
+	return nil
+}
@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+)
+
+// eventNotifiers passes object events to all the notifiers.
+type eventNotifiers []utils.EventNotifier
+
+// Notify implements utils.EventNotifier.
+func (n eventNotifiers) Notify(ev utils.ObjectEvent) {
+	for _, notifier := range n {
+		notifier.Notify(ev)
+	}
+}
+
+// startNotifiers starts delivery of object events to the configured webhooks
+// and NATS.
+func (a *app) startNotifiers(ctx context.Context) {
+	if a.webhooks != nil {
+		go a.webhooks.run(ctx)
+		a.notifiers = append(a.notifiers, a.webhooks)
+	}
+	if a.nats != nil {
+		go a.nats.run(ctx)
+		a.notifiers = append(a.notifiers, a.nats)
+	}
+}
+
+// notifier returns the notifier of object events, nil if there are none.
+func (a *app) notifier() utils.EventNotifier {
+	switch len(a.notifiers) {
+	case 0:
+		return nil
+	case 1:
+		return a.notifiers[0]
+	default:
+		return a.notifiers
+	}
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp"
+)
+
+// Modes of the gateway with less healthy nodes than required.
+const (
+	// degradedNone fails readiness probe only.
+	degradedNone = "none"
+	// degradedReadOnly rejects uploads and other mutating requests.
+	degradedReadOnly = "read_only"
+	// degradedUnavailable rejects all requests.
+	degradedUnavailable = "unavailable"
+)
+
+// healthyPeers shows the number of nodes passed the latest health check.
+var healthyPeers = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "neofs_http_gw",
+	Name:      "healthy_peers",
+	Help:      "Number of NeoFS nodes passed the latest health check.",
+})
+
+// healthy returns the number of nodes passed the latest check.
+func (m *peerMonitor) healthy() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int
+	for _, p := range m.peers {
+		if p.status.Healthy {
+			n++
+		}
+	}
+	return n
+}
+
+// lacksHealthyNodes reports whether there are less healthy nodes than
+// required, nodes are checked by the peer monitor started with the handler.
+func (a *app) lacksHealthyNodes() bool {
+	min := a.cfg.GetInt(cfgPoolMinHealthyNodes)
+	if min <= 0 || a.peers == nil {
+		return false
+	}
+	return a.peers.healthy() < min
+}
+
+// healthyNodesGuard rejects requests with 503 status while there are less
+// healthy nodes than required: mutating ones in read-only mode and all of them
+// in unavailable mode.
+func (a *app) healthyNodesGuard(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.cfg.GetInt(cfgPoolMinHealthyNodes) <= 0 {
+		return h
+	}
+	switch a.cfg.GetString(cfgPoolDegradedMode) {
+	case degradedUnavailable:
+	case degradedReadOnly:
+		if routeOperation(route) != operationUpload {
+			return h
+		}
+	default:
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		if a.lacksHealthyNodes() {
+			response.Error(c, "not enough healthy NeoFS nodes", fasthttp.StatusServiceUnavailable)
+			return
+		}
+
+		h(c)
+	}
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestHealthyNodesGuard(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgPeers+".0.address", "s01.neofs.devenv:8080")
+	v.Set(cfgPeers+".1.address", "s02.neofs.devenv:8080")
+	v.Set(cfgPoolMinHealthyNodes, 2)
+	a := &app{cfg: v, log: zap.NewNop()}
+
+	a.peers = a.newPeerMonitor()
+	failing := map[string]bool{"s02.neofs.devenv:8080": true}
+	a.peers.probe = func(_ context.Context, p *peerState) error {
+		if failing[p.status.Address] {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	a.peers.check(context.Background())
+	require.True(t, a.lacksHealthyNodes())
+
+	ok := func(c *fasthttp.RequestCtx) {
+		c.SetStatusCode(fasthttp.StatusOK)
+	}
+	status := func(h fasthttp.RequestHandler) int {
+		var c fasthttp.RequestCtx
+		h(&c)
+		return c.Response.StatusCode()
+	}
+
+	require.Equal(t, fasthttp.StatusServiceUnavailable, status(a.readinessHandler))
+
+	t.Run("none", func(t *testing.T) {
+		v.Set(cfgPoolDegradedMode, degradedNone)
+		require.Equal(t, fasthttp.StatusOK, status(a.healthyNodesGuard(routeUpload, ok)))
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		v.Set(cfgPoolDegradedMode, degradedReadOnly)
+		require.Equal(t, fasthttp.StatusServiceUnavailable, status(a.healthyNodesGuard(routeUpload, ok)))
+		require.Equal(t, fasthttp.StatusOK, status(a.healthyNodesGuard(routeGet, ok)))
+	})
+
+	v.Set(cfgPoolDegradedMode, degradedUnavailable)
+	h := a.healthyNodesGuard(routeGet, ok)
+	require.Equal(t, fasthttp.StatusServiceUnavailable, status(h))
+
+	failing = nil
+	a.peers.check(context.Background())
+	require.False(t, a.lacksHealthyNodes())
+	require.Equal(t, fasthttp.StatusOK, status(h))
+	require.Equal(t, fasthttp.StatusOK, status(a.readinessHandler))
+}
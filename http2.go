@@ -0,0 +1,216 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// serveHTTP2 serves the listener with net/http server supporting HTTP/2:
+// h2 is negotiated via ALPN on TLS listeners, h2c (prior knowledge and
+// upgrade) is used on plain text ones. HTTP/1.1 is served there too.
+func (a *app) serveHTTP2(ln net.Listener, tlsEnabled bool) error {
+	var h http.Handler = fasthttpAdaptor(a.webServer.Handler, a.webServer.HeaderReceived,
+		a.webServer.MaxRequestBodySize, a.webServer.StreamRequestBody, zap.NewStdLog(a.log))
+	if !tlsEnabled {
+		h = h2c.NewHandler(h, new(http2.Server))
+	}
+
+	srv := &http.Server{
+		Handler:      h,
+		ReadTimeout:  a.webServer.ReadTimeout,
+		WriteTimeout: a.webServer.WriteTimeout,
+		ErrorLog:     zap.NewStdLog(a.log),
+	}
+
+	a.httpMu.Lock()
+	a.httpServers = append(a.httpServers, srv)
+	a.httpMu.Unlock()
+
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shutdownHTTP2 gracefully stops all HTTP/2 servers.
+func (a *app) shutdownHTTP2() {
+	a.httpMu.Lock()
+	defer a.httpMu.Unlock()
+
+	for _, srv := range a.httpServers {
+		ctx, cancel := context.WithTimeout(context.Background(), a.webServer.ReadTimeout)
+		a.log.Info("shutting down HTTP/2 server", zap.Error(srv.Shutdown(ctx)))
+		cancel()
+	}
+}
+
+// errAdaptorTimeout is returned by request body reads and response writes
+// after the timeout of the route.
+var errAdaptorTimeout = errors.New("route timeout exceeded")
+
+// fasthttpAdaptor converts fasthttp request handler to net/http one, so that
+// the same routes and middlewares are used for both servers. Request body is
+// always streamed, it's limited to maxBodySize bytes if it's positive unless
+// streamBody is set (fasthttp server streams bodies exceeding the limit then).
+// The hook overrides the limit and the timeouts of the request like fasthttp
+// server's HeaderReceived does, the timeouts are checked on every read and
+// write since per-request deadlines aren't available.
+func fasthttpAdaptor(h fasthttp.RequestHandler, hook func(*fasthttp.RequestHeader) fasthttp.RequestConfig,
+	maxBodySize int, streamBody bool, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   fasthttp.RequestCtx
+			start = time.Now()
+		)
+
+		ctx.Init2(newAdaptorConn(r), logger, true)
+
+		ctx.Request.Header.SetMethod(r.Method)
+		ctx.Request.Header.SetRequestURI(r.URL.RequestURI())
+		ctx.Request.Header.SetHost(r.Host)
+		for key, values := range r.Header {
+			for _, value := range values {
+				ctx.Request.Header.Add(key, value)
+			}
+		}
+
+		var (
+			cfg   fasthttp.RequestConfig
+			limit = maxBodySize
+		)
+		if hook != nil {
+			cfg = hook(&ctx.Request.Header)
+		}
+		if cfg.MaxRequestBodySize > 0 {
+			limit = cfg.MaxRequestBodySize
+		}
+
+		if r.ContentLength != 0 {
+			var body io.Reader = r.Body
+			if limit > 0 && !streamBody {
+				body = http.MaxBytesReader(w, r.Body, int64(limit))
+			}
+			if cfg.ReadTimeout > 0 {
+				body = &deadlineReader{r: body, deadline: start.Add(cfg.ReadTimeout)}
+			}
+			ctx.Request.SetBodyStream(body, int(r.ContentLength))
+		}
+
+		ctx.Response.Header.SetNoDefaultContentType(true)
+
+		h(&ctx)
+
+		if !ctx.Response.IsBodyStream() && !ctx.IsHead() {
+			ctx.Response.Header.SetContentLength(len(ctx.Response.Body()))
+		}
+
+		header := w.Header()
+		ctx.Response.Header.VisitAll(func(key, value []byte) {
+			switch k := http.CanonicalHeaderKey(string(key)); k {
+			case "Connection", "Transfer-Encoding":
+				// Connection-specific headers are managed by net/http server.
+			default:
+				header.Add(k, string(value))
+			}
+		})
+		w.WriteHeader(ctx.Response.StatusCode())
+
+		var bw io.Writer = w
+		if cfg.WriteTimeout > 0 {
+			bw = &deadlineWriter{w: w, deadline: start.Add(cfg.WriteTimeout)}
+		}
+		if err := ctx.Response.BodyWriteTo(bw); err != nil {
+			logger.Printf("could not write response body: %v", err)
+		}
+
+		// closes user values like fasthttp server does
+		ctx.ResetUserValues()
+	})
+}
+
+// deadlineReader fails reads after the deadline.
+type deadlineReader struct {
+	r        io.Reader
+	deadline time.Time
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	if time.Now().After(d.deadline) {
+		return 0, errAdaptorTimeout
+	}
+	return d.r.Read(p)
+}
+
+// deadlineWriter fails writes after the deadline.
+type deadlineWriter struct {
+	w        io.Writer
+	deadline time.Time
+}
+
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	if time.Now().After(d.deadline) {
+		return 0, errAdaptorTimeout
+	}
+	return d.w.Write(p)
+}
+
+// adaptorConn is a stub connection passed to fasthttp request context. It
+// only provides remote address of the request.
+type adaptorConn struct {
+	remote net.Addr
+}
+
+// tlsAdaptorConn also provides TLS connection state, so that fasthttp treats
+// the request as a TLS one (see fasthttp.RequestCtx.IsTLS).
+type tlsAdaptorConn struct {
+	adaptorConn
+	state tls.ConnectionState
+}
+
+func newAdaptorConn(r *http.Request) net.Conn {
+	c := adaptorConn{remote: &net.TCPAddr{IP: net.IPv4zero}}
+
+	if host, port, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			p, _ := strconv.Atoi(port)
+			c.remote = &net.TCPAddr{IP: ip, Port: p}
+		}
+	}
+
+	if r.TLS != nil {
+		return &tlsAdaptorConn{adaptorConn: c, state: *r.TLS}
+	}
+	return &c
+}
+
+func (c *adaptorConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *adaptorConn) LocalAddr() net.Addr { return &net.TCPAddr{IP: net.IPv4zero} }
+
+func (c *adaptorConn) SetDeadline(time.Time) error { return nil }
+
+func (c *adaptorConn) SetReadDeadline(time.Time) error { return nil }
+
+func (c *adaptorConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *adaptorConn) Close() error { return nil }
+
+func (c *adaptorConn) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (c *adaptorConn) Write(p []byte) (int, error) { return len(p), nil }
+
+func (c *tlsAdaptorConn) Handshake() error { return nil }
+
+func (c *tlsAdaptorConn) ConnectionState() tls.ConnectionState { return c.state }
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestFasthttpAdaptor(t *testing.T) {
+	h := func(ctx *fasthttp.RequestCtx) {
+		require.Equal(t, "POST", string(ctx.Method()))
+		require.Equal(t, "/upload/cid?a=b", string(ctx.RequestURI()))
+		require.Equal(t, "gate.example.com", string(ctx.Host()))
+		require.Equal(t, "value", string(ctx.Request.Header.Peek("X-Attribute-Key")))
+		require.Equal(t, "192.0.2.1", ctx.RemoteIP().String())
+		require.True(t, ctx.IsTLS())
+
+		body, err := io.ReadAll(ctx.RequestBodyStream())
+		require.NoError(t, err)
+		require.Equal(t, "payload", string(body))
+
+		ctx.Response.Header.Set("X-Object-Id", "oid")
+		ctx.SetStatusCode(fasthttp.StatusCreated)
+		ctx.SetBodyString("ok")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://gate.example.com/upload/cid?a=b", strings.NewReader("payload"))
+	req.RemoteAddr = "192.0.2.1:12345"
+	req.TLS = new(tls.ConnectionState)
+	req.Header.Set("X-Attribute-Key", "value")
+
+	w := httptest.NewRecorder()
+	fasthttpAdaptor(h, nil, 0, false, log.New(io.Discard, "", 0)).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "oid", w.Header().Get("X-Object-Id"))
+	require.Equal(t, "2", w.Header().Get("Content-Length"))
+	require.Empty(t, w.Header().Get("Content-Type"))
+	require.Equal(t, "ok", w.Body.String())
+}
+
+func TestFasthttpAdaptorBodyLimit(t *testing.T) {
+	h := func(ctx *fasthttp.RequestCtx) {
+		require.False(t, ctx.IsTLS())
+
+		_, err := io.ReadAll(ctx.RequestBodyStream())
+		require.Error(t, err)
+		ctx.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload/cid", strings.NewReader("payload"))
+
+	w := httptest.NewRecorder()
+	fasthttpAdaptor(h, nil, 3, false, log.New(io.Discard, "", 0)).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestFasthttpAdaptorRequestConfig(t *testing.T) {
+	var hooked string
+	hook := func(h *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		hooked = string(h.RequestURI())
+		return fasthttp.RequestConfig{MaxRequestBodySize: 3, WriteTimeout: time.Nanosecond}
+	}
+
+	h := func(ctx *fasthttp.RequestCtx) {
+		_, err := io.ReadAll(ctx.RequestBodyStream())
+		require.Error(t, err)
+		ctx.SetBodyString("response")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload/cid", strings.NewReader("payload"))
+
+	w := httptest.NewRecorder()
+	fasthttpAdaptor(h, hook, 0, false, log.New(io.Discard, "", 0)).ServeHTTP(w, req)
+
+	require.Equal(t, "/upload/cid", hooked)
+	require.Empty(t, w.Body.String())
+}
@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const hdrIdentity = "X-Neofs-Identity"
+
+// identities are additional gateway keys selected per request by name (in
+// X-Neofs-Identity header) or by requested container.
+type identities struct {
+	ctx         context.Context
+	header      bool
+	byName      map[string]*ecdsa.PrivateKey
+	byContainer map[string]*ecdsa.PrivateKey
+}
+
+// loadIdentities reads wallets of identities section.
+func (a *app) loadIdentities(ctx context.Context) (*identities, error) {
+	ids := &identities{
+		ctx:         ctx,
+		header:      a.cfg.GetBool(cfgIdentitiesHeaderSelection),
+		byName:      make(map[string]*ecdsa.PrivateKey),
+		byContainer: make(map[string]*ecdsa.PrivateKey),
+	}
+
+	for i := 0; ; i++ {
+		key := cfgIdentities + "." + strconv.Itoa(i) + "."
+
+		name := a.cfg.GetString(key + "name")
+		if name == "" {
+			break
+		}
+		if _, ok := ids.byName[name]; ok {
+			return nil, fmt.Errorf("duplicate identity %s", name)
+		}
+
+		w, err := wallet.NewWalletFromFile(a.cfg.GetString(key + "wallet.path"))
+		if err != nil {
+			return nil, fmt.Errorf("could not open wallet of identity %s: %w", name, err)
+		}
+
+		var password *string
+		if a.cfg.IsSet(key + "wallet.passphrase") {
+			pwd := a.cfg.GetString(key + "wallet.passphrase")
+			password = &pwd
+		}
+
+		pk, err := getKeyFromWallet(w, a.cfg.GetString(key+"wallet.address"), password)
+		if err != nil {
+			return nil, fmt.Errorf("could not get key of identity %s: %w", name, err)
+		}
+
+		containers := a.cfg.GetStringSlice(key + "containers")
+		if err = parseContainerIDs(containers); err != nil {
+			return nil, fmt.Errorf("invalid containers of identity %s: %w", name, err)
+		}
+
+		ids.byName[name] = pk
+		for _, cnr := range containers {
+			if _, ok := ids.byContainer[cnr]; ok {
+				return nil, fmt.Errorf("container %s is mapped to several identities", cnr)
+			}
+			ids.byContainer[cnr] = pk
+		}
+
+		a.log.Info("identity loaded", zap.String("name", name), zap.Strings("containers", containers))
+	}
+
+	if len(ids.byName) == 0 {
+		return nil, nil
+	}
+	return ids, nil
+}
+
+// identitySelection selects the key to sign NeoFS requests with: the one named
+// in X-Neofs-Identity header (if it's allowed), the one mapped to the
+// requested container or the gateway key otherwise.
+func (a *app) identitySelection(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.identities == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		if name := c.Request.Header.Peek(hdrIdentity); len(name) != 0 {
+			if !a.identities.header {
+				response.Error(c, "identity selection by header is disabled", fasthttp.StatusBadRequest)
+				return
+			}
+
+			key, ok := a.identities.byName[string(name)]
+			if !ok {
+				response.Error(c, "unknown identity: "+string(name), fasthttp.StatusBadRequest)
+				return
+			}
+
+			utils.SetRequestKey(c, key)
+			h(c)
+			return
+		}
+
+		if scid, _ := c.UserValue("cid").(string); scid != "" && len(a.identities.byContainer) != 0 {
+			cnrID, err := utils.GetContainerID(utils.RequestContext(c, a.identities.ctx), scid, a.resolver)
+			if err == nil {
+				if key, ok := a.identities.byContainer[cnrID.String()]; ok {
+					utils.SetRequestKey(c, key)
+				}
+			}
+		}
+
+		h(c)
+	}
+}
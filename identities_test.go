@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestIdentitySelection(t *testing.T) {
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		return key
+	}
+	billing, archive := newKey(), newKey()
+	archiveCnr := testContainerID("archive")
+
+	a := &app{log: zap.NewNop(), identities: &identities{
+		ctx:         context.Background(),
+		header:      true,
+		byName:      map[string]*ecdsa.PrivateKey{"billing": billing, "archive": archive},
+		byContainer: map[string]*ecdsa.PrivateKey{archiveCnr: archive},
+	}}
+
+	var selected *ecdsa.PrivateKey
+	h := a.identitySelection(func(c *fasthttp.RequestCtx) {
+		selected = utils.RequestKey(c)
+		c.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	serve := func(cnr, identity string) int {
+		var c fasthttp.RequestCtx
+		c.SetUserValue("cid", cnr)
+		if identity != "" {
+			c.Request.Header.Set(hdrIdentity, identity)
+		}
+		selected = nil
+		h(&c)
+		return c.Response.StatusCode()
+	}
+
+	require.Equal(t, fasthttp.StatusOK, serve(testContainerID("other"), ""))
+	require.Nil(t, selected)
+
+	require.Equal(t, fasthttp.StatusOK, serve(archiveCnr, ""))
+	require.Equal(t, archive, selected)
+
+	require.Equal(t, fasthttp.StatusOK, serve(archiveCnr, "billing"))
+	require.Equal(t, billing, selected)
+
+	require.Equal(t, fasthttp.StatusBadRequest, serve(archiveCnr, "unknown"))
+
+	a.identities.header = false
+	require.Equal(t, fasthttp.StatusBadRequest, serve(archiveCnr, "billing"))
+}
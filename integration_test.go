@@ -1,4 +1,4 @@
-package main
+package gateway
 
 import (
 	"archive/zip"
@@ -78,8 +78,11 @@ func runServer() context.CancelFunc {
 	cancelCtx, cancel := context.WithCancel(context.Background())
 
 	v := getDefaultConfig()
-	l := newLogger(v)
-	application := newApp(cancelCtx, WithConfig(v), WithLogger(l))
+	l, _ := NewLogger(v)
+	application, err := New(cancelCtx, WithConfig(v), WithLogger(l))
+	if err != nil {
+		panic(err)
+	}
 	go application.Serve(cancelCtx)
 
 	return cancel
@@ -324,7 +327,7 @@ func createDockerContainer(ctx context.Context, t *testing.T, image string) test
 }
 
 func getDefaultConfig() *viper.Viper {
-	v := settings()
+	v := Settings()
 	v.SetDefault(cfgPeers+".0.address", "127.0.0.1:8080")
 	v.SetDefault(cfgPeers+".0.weight", 1)
 	v.SetDefault(cfgPeers+".0.priority", 1)
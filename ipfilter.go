@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// ipFilter is a pair of client networks lists, denied networks take
+// precedence, empty allowed list means any network.
+type ipFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// parseNetworks parses CIDR networks or single IP addresses.
+func parseNetworks(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+
+	for _, s := range list {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %s", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network: %w", err)
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f ipFilter) allowed(ip net.IP) bool {
+	if containsIP(f.deny, ip) {
+		return false
+	}
+	return len(f.allow) == 0 || containsIP(f.allow, ip)
+}
+
+// fetchIPFilter reads allowed and denied networks for the route: upload or
+// download lists if they are set, common ones otherwise.
+func fetchIPFilter(v *viper.Viper, route string) (ipFilter, error) {
+	section := cfgIPFilterDownload
+	if routeOperation(route) == operationUpload {
+		section = cfgIPFilterUpload
+	}
+
+	allow := v.GetStringSlice(section + ".allow")
+	if len(allow) == 0 {
+		allow = v.GetStringSlice(cfgIPFilterAllow)
+	}
+	deny := v.GetStringSlice(section + ".deny")
+	if len(deny) == 0 {
+		deny = v.GetStringSlice(cfgIPFilterDeny)
+	}
+
+	var (
+		f   ipFilter
+		err error
+	)
+	if f.allow, err = parseNetworks(allow); err != nil {
+		return f, err
+	}
+	if f.deny, err = parseNetworks(deny); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// fetchIPFilters reads filters of upload and download routes keyed by the
+// operation.
+func fetchIPFilters(v *viper.Viper) (map[string]ipFilter, error) {
+	filters := make(map[string]ipFilter, 2)
+	for _, route := range []string{routeUpload, routeGet} {
+		f, err := fetchIPFilter(v, route)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse IP filter of %s routes: %w", routeOperation(route), err)
+		}
+		filters[routeOperation(route)] = f
+	}
+	return filters, nil
+}
+
+// ipAccess rejects requests from the networks which aren't allowed for the
+// route with 403 status.
+func (a *app) ipAccess(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	f := a.ipFilters[routeOperation(route)]
+	if len(f.allow) == 0 && len(f.deny) == 0 {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		if !f.allowed(c.RemoteIP()) {
+			a.log.Warn("access denied for client address",
+				zap.String("route", route), zap.Stringer("remote", c.RemoteIP()))
+			response.Error(c, "access denied", fasthttp.StatusForbidden)
+			return
+		}
+
+		h(c)
+	}
+}
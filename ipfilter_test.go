@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworks(t *testing.T) {
+	nets, err := parseNetworks([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32", "::1"})
+	require.NoError(t, err)
+	require.Len(t, nets, 4)
+	require.Equal(t, "192.0.2.1/32", nets[1].String())
+	require.Equal(t, "::1/128", nets[3].String())
+
+	_, err = parseNetworks([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	_, err = parseNetworks([]string{"localhost"})
+	require.Error(t, err)
+}
+
+func TestFetchIPFilter(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgIPFilterDeny, []string{"192.0.2.1"})
+	v.Set(cfgIPFilterUpload+".allow", []string{"10.0.0.0/8"})
+
+	upload, err := fetchIPFilter(v, routeUpload)
+	require.NoError(t, err)
+	require.True(t, upload.allowed(net.ParseIP("10.1.2.3")))
+	require.False(t, upload.allowed(net.ParseIP("198.51.100.1")))
+	require.False(t, upload.allowed(net.ParseIP("192.0.2.1")))
+
+	download, err := fetchIPFilter(v, routeGet)
+	require.NoError(t, err)
+	require.True(t, download.allowed(net.ParseIP("198.51.100.1")))
+	require.False(t, download.allowed(net.ParseIP("192.0.2.1")))
+
+	filters, err := fetchIPFilters(v)
+	require.NoError(t, err)
+	require.Equal(t, upload, filters[operationUpload])
+
+	v.Set(cfgIPFilterDownload+".deny", []string{"localhost"})
+	_, err = fetchIPFilters(v)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ReloadKey reads the gateway key again (re-reading secret files) and
+// replaces the connection pool with the one using the new key. Requests in
+// progress finish with the previous pool, it's closed after them.
+func (a *app) ReloadKey(ctx context.Context) error {
+	a.keyMu.Lock()
+	defer a.keyMu.Unlock()
+
+	secrets, err := readSecrets(a.cfg)
+	if err != nil {
+		return fmt.Errorf("could not load secrets: %w", err)
+	}
+
+	walletPath := a.cfg.GetString(cmdWallet)
+	if walletPath == "" {
+		walletPath = a.cfg.GetString(cfgWalletPath)
+	}
+	if _, ok := secretValue(a.cfg, secrets, cfgWalletPassphrase); walletPath != "" && !ok {
+		return errors.New("wallet passphrase isn't set, it can't be entered interactively on reload")
+	}
+
+	key, err := getNeoFSKey(a, secrets)
+	if err != nil {
+		return fmt.Errorf("could not get neofs credentials: %w", err)
+	}
+
+	clientPool, err := a.newPool(ctx, key)
+	if err != nil {
+		return fmt.Errorf("could not create connection pool: %w", err)
+	}
+
+	a.key = key
+	if a.nodePools != nil {
+		a.nodePools.reset(a.cfg.GetDuration(cfgKeyRotationDrainTimeout))
+	}
+
+	drained := a.pool.Replace(clientPool, a.cfg.GetDuration(cfgKeyRotationDrainTimeout))
+	go func() {
+		<-drained
+		a.log.Info("previous connection pool is closed")
+	}()
+
+	return nil
+}
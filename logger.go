@@ -1,39 +1,27 @@
-package main
+package gateway
 
 import (
-	"context"
 	"fmt"
-	"os/signal"
-	"syscall"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-func main() {
-	var (
-		v = settings()
-		l = newLogger(v)
-	)
-	globalContext, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
-	app := newApp(globalContext, WithLogger(l), WithConfig(v))
-	go app.Serve(globalContext)
-	app.Wait()
-}
-
-// newLogger constructs a zap.Logger instance for current application.
+// NewLogger constructs a zap.Logger instance for current application and
+// returns it with its level which can be changed at runtime.
 // Panics on failure.
 //
 // Logger is built from zap's production logging configuration with:
-//  * parameterized level (debug by default)
-//  * console encoding
-//  * ISO8601 time encoding
+//   - parameterized level (debug by default)
+//   - console encoding
+//   - ISO8601 time encoding
+//   - syslog and GELF sinks if they're configured
 //
 // Logger records a stack trace for all messages at or above fatal level.
 //
 // See also zapcore.Level, zap.NewProductionConfig, zap.AddStacktrace.
-func newLogger(v *viper.Viper) *zap.Logger {
+func NewLogger(v *viper.Viper) (*zap.Logger, zap.AtomicLevel) {
 	var lvl zapcore.Level
 	lvlStr := v.GetString(cfgLoggerLevel)
 	err := lvl.UnmarshalText([]byte(lvlStr))
@@ -55,12 +43,20 @@ func newLogger(v *viper.Viper) *zap.Logger {
 	c.Encoding = "console"
 	c.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
+	sinks, err := newLogSinks(v, c.Level)
+	if err != nil {
+		panic(fmt.Sprintf("build log sinks: %v", err))
+	}
+
 	l, err := c.Build(
 		zap.AddStacktrace(zap.NewAtomicLevelAt(zap.FatalLevel)),
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(append([]zapcore.Core{core}, sinks...)...)
+		}),
 	)
 	if err != nil {
 		panic(fmt.Sprintf("build zap logger instance: %v", err))
 	}
 
-	return l
+	return l, c.Level
 }
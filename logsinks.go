@@ -0,0 +1,316 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+)
+
+// GELF transport protocols.
+const (
+	gelfProtocolUDP = "udp"
+	gelfProtocolTCP = "tcp"
+)
+
+const (
+	// gelfChunkSize is the maximum size of UDP datagram with GELF message,
+	// bigger messages are chunked.
+	gelfChunkSize = 8192
+	// gelfChunkHeaderSize is the size of the magic bytes, message ID,
+	// sequence number and count of every chunk.
+	gelfChunkHeaderSize = 12
+	// gelfMaxChunks is the maximum number of chunks of a message.
+	gelfMaxChunks = 128
+	// gelfDialTimeout is the timeout to connect to GELF TCP input.
+	gelfDialTimeout = 5 * time.Second
+)
+
+type (
+	// logSink sends log entries with their fields to an external system.
+	logSink interface {
+		send(ent zapcore.Entry, fields map[string]interface{}) error
+	}
+
+	// sinkCore is zapcore.Core passing entries with structured fields to
+	// the sink.
+	sinkCore struct {
+		zapcore.LevelEnabler
+		sink   logSink
+		fields []zapcore.Field
+	}
+
+	// syslogSink writes entries as JSON to syslog with the severity of their
+	// level.
+	syslogSink struct {
+		w *syslog.Writer
+	}
+
+	// gelfSink sends entries to Graylog in GELF format over UDP (chunked if
+	// needed) or TCP (null-byte delimited).
+	gelfSink struct {
+		address  string
+		protocol string
+		host     string
+
+		mu   sync.Mutex
+		conn net.Conn
+	}
+)
+
+// newLogSinks creates cores of the configured log sinks.
+func newLogSinks(v *viper.Viper, lvl zapcore.LevelEnabler) ([]zapcore.Core, error) {
+	var cores []zapcore.Core
+
+	if v.GetBool(cfgLoggerSyslogEnabled) {
+		var network string
+		address := v.GetString(cfgLoggerSyslogAddress)
+		if address != "" {
+			network = "udp"
+		}
+		w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, v.GetString(cfgLoggerSyslogTag))
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to syslog: %w", err)
+		}
+		cores = append(cores, &sinkCore{LevelEnabler: lvl, sink: &syslogSink{w: w}})
+	}
+
+	if address := v.GetString(cfgLoggerGELFAddress); address != "" {
+		protocol := v.GetString(cfgLoggerGELFProtocol)
+		if protocol != gelfProtocolUDP && protocol != gelfProtocolTCP {
+			return nil, fmt.Errorf("unknown GELF protocol %q", protocol)
+		}
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("could not get hostname: %w", err)
+		}
+		cores = append(cores, &sinkCore{LevelEnabler: lvl, sink: &gelfSink{
+			address:  address,
+			protocol: protocol,
+			host:     host,
+		}})
+	}
+
+	return cores, nil
+}
+
+// With implements zapcore.Core.
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	res := *c
+	res.fields = append(res.fields[:len(res.fields):len(res.fields)], fields...)
+	return &res
+}
+
+// Check implements zapcore.Core.
+func (c *sinkCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *sinkCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return c.sink.send(ent, enc.Fields)
+}
+
+// Sync implements zapcore.Core.
+func (c *sinkCore) Sync() error {
+	return nil
+}
+
+func (s *syslogSink) send(ent zapcore.Entry, fields map[string]interface{}) error {
+	msg := map[string]interface{}{
+		"level": ent.Level.String(),
+		"msg":   ent.Message,
+	}
+	if ent.LoggerName != "" {
+		msg["logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		msg["caller"] = ent.Caller.TrimmedPath()
+	}
+	for k, v := range fields {
+		if _, ok := msg[k]; !ok {
+			msg[k] = v
+		}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return s.w.Debug(string(data))
+	case zapcore.InfoLevel:
+		return s.w.Info(string(data))
+	case zapcore.WarnLevel:
+		return s.w.Warning(string(data))
+	case zapcore.ErrorLevel:
+		return s.w.Err(string(data))
+	default:
+		return s.w.Crit(string(data))
+	}
+}
+
+// gelfLevel returns syslog severity of the level.
+func gelfLevel(lvl zapcore.Level) int {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// gelfMessage encodes the entry in GELF 1.1 format, fields are added as
+// additional ones, values which aren't strings or numbers are encoded as JSON.
+func gelfMessage(host string, ent zapcore.Entry, fields map[string]interface{}) ([]byte, error) {
+	msg := map[string]interface{}{
+		"version":       "1.1",
+		"host":          host,
+		"short_message": ent.Message,
+		"timestamp":     float64(ent.Time.UnixNano()) / float64(time.Second),
+		"level":         gelfLevel(ent.Level),
+	}
+	if ent.LoggerName != "" {
+		msg["_logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		msg["_caller"] = ent.Caller.TrimmedPath()
+	}
+	if ent.Stack != "" {
+		msg["full_message"] = ent.Stack
+	}
+
+	for k, v := range fields {
+		// _id is reserved by GELF.
+		if k == "id" {
+			k = "id_"
+		}
+		switch v.(type) {
+		case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			v = string(data)
+		}
+		msg["_"+k] = v
+	}
+
+	return json.Marshal(msg)
+}
+
+func (s *gelfSink) send(ent zapcore.Entry, fields map[string]interface{}) error {
+	data, err := gelfMessage(s.host, ent, fields)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if s.conn, err = net.DialTimeout(s.protocol, s.address, gelfDialTimeout); err != nil {
+			return fmt.Errorf("could not connect to GELF input: %w", err)
+		}
+	}
+
+	if s.protocol == gelfProtocolTCP {
+		_, err = s.conn.Write(append(data, 0))
+	} else {
+		err = s.writeUDP(data)
+	}
+	if err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// writeUDP sends the message in a datagram, it's compressed and chunked if
+// it's too big.
+func (s *gelfSink) writeUDP(data []byte) error {
+	if len(data) <= gelfChunkSize {
+		_, err := s.conn.Write(data)
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	data = buf.Bytes()
+
+	chunks := gelfChunks(data)
+	if chunks == nil {
+		return fmt.Errorf("GELF message is too big: %d bytes", len(data))
+	}
+	for _, chunk := range chunks {
+		if _, err := s.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gelfChunks splits the message into GELF chunks, nil is returned if there
+// are too many of them.
+func gelfChunks(data []byte) [][]byte {
+	const payloadSize = gelfChunkSize - gelfChunkHeaderSize
+
+	count := int(math.Ceil(float64(len(data)) / payloadSize))
+	if count > gelfMaxChunks {
+		return nil
+	}
+
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * payloadSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-i*payloadSize)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*payloadSize:end]...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
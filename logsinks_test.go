@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGELFMessage(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Unix(1650000000, 500000000),
+		LoggerName: "gw",
+		Message:    "could not get object",
+	}
+	data, err := gelfMessage("host", ent, map[string]interface{}{
+		"cid":    "BJeErH9MWmf52VsR1mLWKkgF3pRm3FkubYxM7TZkBP4K",
+		"id":     "1",
+		"size":   uint64(42),
+		"errors": []string{"a", "b"},
+	})
+	require.NoError(t, err)
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &msg))
+	require.Equal(t, map[string]interface{}{
+		"version":       "1.1",
+		"host":          "host",
+		"short_message": "could not get object",
+		"timestamp":     1650000000.5,
+		"level":         float64(4),
+		"_logger":       "gw",
+		"_cid":          "BJeErH9MWmf52VsR1mLWKkgF3pRm3FkubYxM7TZkBP4K",
+		"_id_":          "1",
+		"_size":         float64(42),
+		"_errors":       `["a","b"]`,
+	}, msg)
+}
+
+func TestGELFChunks(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 2*gelfChunkSize)
+
+	chunks := gelfChunks(data)
+	require.Len(t, chunks, 3)
+
+	var joined []byte
+	for i, chunk := range chunks {
+		require.LessOrEqual(t, len(chunk), gelfChunkSize)
+		require.Equal(t, []byte{0x1e, 0x0f}, chunk[:2])
+		require.Equal(t, chunks[0][2:10], chunk[2:10])
+		require.Equal(t, []byte{byte(i), 3}, chunk[10:12])
+		joined = append(joined, chunk[gelfChunkHeaderSize:]...)
+	}
+	require.Equal(t, data, joined)
+
+	require.Nil(t, gelfChunks(make([]byte, gelfMaxChunks*gelfChunkSize)))
+}
+
+func TestGELFSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	v := viper.New()
+	v.Set(cfgLoggerGELFAddress, conn.LocalAddr().String())
+	v.Set(cfgLoggerGELFProtocol, gelfProtocolUDP)
+	sinks, err := newLogSinks(v, zapcore.InfoLevel)
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+
+	l := zap.New(sinks[0]).With(zap.String("cid", "container"))
+	l.Debug("skipped")
+	l.Info("uploaded", zap.Int("size", 10))
+	l.Info("big", zap.String("payload", strings.Repeat("x", 2*gelfChunkSize)))
+
+	read := func() []byte {
+		buf := make([]byte, gelfChunkSize)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return buf[:n]
+	}
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(read(), &msg))
+	require.Equal(t, "uploaded", msg["short_message"])
+	require.Equal(t, float64(6), msg["level"])
+	require.Equal(t, "container", msg["_cid"])
+	require.Equal(t, float64(10), msg["_size"])
+
+	chunk := read()
+	require.Equal(t, []byte{0x1e, 0x0f}, chunk[:2])
+	require.Equal(t, byte(1), chunk[11])
+	zr, err := gzip.NewReader(bytes.NewReader(chunk[gelfChunkHeaderSize:]))
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &msg))
+	require.Equal(t, "big", msg["short_message"])
+
+	t.Run("unknown protocol", func(t *testing.T) {
+		v.Set(cfgLoggerGELFProtocol, "http")
+		_, err := newLogSinks(v, zapcore.InfoLevel)
+		require.Error(t, err)
+	})
+}
+
+func TestSinkCoreWith(t *testing.T) {
+	var sent []map[string]interface{}
+	core := &sinkCore{LevelEnabler: zapcore.DebugLevel, sink: sinkFunc(func(_ zapcore.Entry, fields map[string]interface{}) error {
+		sent = append(sent, fields)
+		return nil
+	})}
+
+	l := zap.New(core).With(zap.String("a", "1"))
+	l.With(zap.String("b", "2")).Info("first")
+	l.With(zap.String("c", "3")).Info("second")
+
+	require.Equal(t, []map[string]interface{}{
+		{"a": "1", "b": "2"},
+		{"a": "1", "c": "3"},
+	}, sent)
+
+	core.sink = sinkFunc(func(zapcore.Entry, map[string]interface{}) error {
+		return errors.New("unavailable")
+	})
+	require.Error(t, core.Write(zapcore.Entry{}, nil))
+}
+
+type sinkFunc func(zapcore.Entry, map[string]interface{}) error
+
+func (f sinkFunc) send(ent zapcore.Entry, fields map[string]interface{}) error {
+	return f(ent, fields)
+}
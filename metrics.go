@@ -1,4 +1,4 @@
-package main
+package gateway
 
 import (
 	"github.com/fasthttp/router"
@@ -9,8 +9,36 @@ import (
 	"go.uber.org/zap"
 )
 
-func attachMetrics(r *router.Router, l *zap.Logger) {
-	r.GET("/metrics/", metricsHandler(prometheus.DefaultGatherer, l))
+// newMetricsRegistry returns the registry with Go runtime and process
+// metrics.
+func newMetricsRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// registerMetrics registers gateway metrics in the registry.
+func registerMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		containerBytes,
+		containerRequests,
+		healthyPeers,
+		peerCircuitOpen,
+		accountBalance,
+		networkEpoch,
+	} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func attachMetrics(r *router.Router, reg prometheus.Gatherer, l *zap.Logger) {
+	r.GET("/metrics/", metricsHandler(reg, l))
 }
 
 func metricsHandler(reg prometheus.Gatherer, logger *zap.Logger) fasthttp.RequestHandler {
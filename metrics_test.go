@@ -0,0 +1,17 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	reg := newMetricsRegistry()
+	require.NoError(t, registerMetrics(reg))
+	require.Error(t, registerMetrics(reg))
+
+	// Every gateway registers the metrics in its own registry.
+	require.NoError(t, registerMetrics(prometheus.NewRegistry()))
+}
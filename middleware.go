@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// middleware wraps the handler of the named route with some request
+// processing. Global middlewares get empty route name.
+type middleware func(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler
+
+// Names of middlewares to be used in the chain configuration.
+const (
+	mwSecurityHeaders = "security_headers"
+	mwCORS            = "cors"
+	mwLog             = "log"
+	mwDrain           = "drain"
+	mwMaintenance     = "maintenance"
+	mwHealthyNodes    = "healthy_nodes"
+	mwIPFilter        = "ip_filter"
+	mwRateLimit       = "rate_limit"
+	mwConcurrency     = "concurrency"
+	mwAudit           = "audit"
+	mwAPIKeys         = "api_keys"
+	mwOIDC            = "oidc"
+	mwBasicAuth       = "basic_auth"
+	mwClientCert      = "client_cert"
+	mwUploadQuota     = "upload_quota"
+	mwTimeout         = "timeout"
+	mwResponseHeaders = "response_headers"
+	mwAccessPolicy    = "access_policy"
+	mwUsage           = "usage"
+	mwIdentity        = "identity"
+	mwNodeOverride    = "node_override"
+)
+
+// defaultMiddlewares is the default chain, the first middleware is the
+// outermost one.
+var defaultMiddlewares = []string{
+	mwSecurityHeaders,
+	mwCORS,
+	mwLog,
+	mwDrain,
+	mwMaintenance,
+	mwHealthyNodes,
+	mwIPFilter,
+	mwRateLimit,
+	mwConcurrency,
+	mwAudit,
+	mwAPIKeys,
+	mwOIDC,
+	mwBasicAuth,
+	mwClientCert,
+	mwUploadQuota,
+	mwTimeout,
+	mwResponseHeaders,
+	mwAccessPolicy,
+	mwUsage,
+	mwIdentity,
+	mwNodeOverride,
+}
+
+// globalMiddlewares wrap the router, so they also process requests without
+// matching routes (e.g. CORS preflight).
+var globalMiddlewares = map[string]struct{}{
+	mwSecurityHeaders: {},
+	mwCORS:            {},
+}
+
+// knownMiddleware checks that the middleware with the name exists.
+func knownMiddleware(name string) bool {
+	for _, known := range defaultMiddlewares {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// anyRoute adapts route-independent wrapper to middleware.
+func anyRoute(f func(fasthttp.RequestHandler) fasthttp.RequestHandler) middleware {
+	return func(_ string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return f(h)
+	}
+}
+
+// knownMiddlewares returns all middlewares by their names.
+func (a *app) knownMiddlewares() map[string]middleware {
+	return map[string]middleware{
+		mwSecurityHeaders: anyRoute(a.securityHeaders),
+		mwCORS:            anyRoute(a.cors),
+		mwLog:             anyRoute(a.logger),
+		mwDrain:           anyRoute(a.drainMode),
+		mwMaintenance:     anyRoute(a.maintenanceMode),
+		mwHealthyNodes:    a.healthyNodesGuard,
+		mwIPFilter:        a.ipAccess,
+		mwRateLimit:       anyRoute(a.rateLimit),
+		mwConcurrency:     a.concurrencyLimit,
+		mwAudit:           a.auditRequests,
+		mwAPIKeys:         a.apiKeyAuth,
+		mwOIDC:            a.oidcAuth,
+		mwBasicAuth:       a.basicAuth,
+		mwClientCert:      anyRoute(a.clientCertAccess),
+		mwUploadQuota:     a.uploadQuotaLimit,
+		mwTimeout:         a.handlerTimeout,
+		mwResponseHeaders: a.responseHeaders,
+		mwAccessPolicy:    a.containerAccess,
+		mwUsage:           a.usageAccount,
+		mwIdentity:        anyRoute(a.identitySelection),
+		mwNodeOverride:    anyRoute(a.nodeOverride),
+	}
+}
+
+// chain wraps the handler with the configured middlewares, either global or
+// per-route ones. Unknown names are skipped, see checkMiddlewares.
+func (a *app) chain(global bool, route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	names := a.cfg.GetStringSlice(cfgMiddlewares)
+	known := a.knownMiddlewares()
+	for i := len(names) - 1; i >= 0; i-- {
+		if _, ok := globalMiddlewares[names[i]]; ok != global {
+			continue
+		}
+		if mw, ok := known[names[i]]; ok {
+			h = mw(route, h)
+		}
+	}
+	return h
+}
+
+// middlewares wraps the handler of the named route with all request
+// processing middlewares.
+func (a *app) middlewares(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return a.chain(false, route, h)
+}
+
+// checkMiddlewares logs unknown and disabled middlewares of the chain.
+func (a *app) checkMiddlewares() {
+	var (
+		names = a.cfg.GetStringSlice(cfgMiddlewares)
+		used  = make(map[string]struct{}, len(names))
+	)
+	for _, name := range names {
+		if !knownMiddleware(name) {
+			a.log.Warn("unknown middleware is ignored", zap.String("name", name))
+		}
+		used[name] = struct{}{}
+	}
+	for _, name := range defaultMiddlewares {
+		if _, ok := used[name]; !ok {
+			a.log.Warn("middleware is disabled", zap.String("name", name))
+		}
+	}
+}
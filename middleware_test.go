@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgSecurityNoSniff, true)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.SetDraining(true)
+
+	serve := func(global bool) *fasthttp.Response {
+		h := a.chain(global, routeGet, func(c *fasthttp.RequestCtx) {
+			c.SetStatusCode(fasthttp.StatusNoContent)
+		})
+
+		var c fasthttp.RequestCtx
+		h(&c)
+		return &c.Response
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		v.Set(cfgMiddlewares, []string{mwLog})
+		require.Equal(t, fasthttp.StatusNoContent, serve(false).StatusCode())
+		require.Empty(t, serve(true).Header.Peek(hdrContentTypeOptions))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		v.Set(cfgMiddlewares, []string{mwSecurityHeaders, mwDrain})
+		require.Equal(t, fasthttp.StatusServiceUnavailable, serve(false).StatusCode())
+
+		resp := serve(true)
+		require.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+		require.Equal(t, "nosniff", string(resp.Header.Peek(hdrContentTypeOptions)))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		v.Set(cfgMiddlewares, []string{"gzip", mwDrain})
+		require.Equal(t, fasthttp.StatusServiceUnavailable, serve(false).StatusCode())
+		require.Contains(t, validateConfig(v), "middlewares: unknown middleware gzip")
+	})
+}
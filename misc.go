@@ -1,4 +1,4 @@
-package main
+package gateway
 
 // Prefix is a prefix used for environment variables containing gateway
 // configuration.
@@ -7,4 +7,7 @@ const Prefix = "HTTP_GW"
 var (
 	// Version is the gateway version.
 	Version = "dev"
+
+	// Commit is the git commit the gateway is built from.
+	Commit = "unknown"
 )
@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/nspcc-dev/neofs-http-gw/mock"
+	"go.uber.org/zap"
+)
+
+// startMock runs in-memory NeoFS node on a local port and replaces configured
+// peers with it. The node is stopped when the context is done.
+func (a *app) startMock(ctx context.Context) error {
+	node, err := mock.NewNode()
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("could not listen for mock node: %w", err)
+	}
+
+	go func() {
+		if err := node.Serve(ln); err != nil {
+			a.log.Error("mock node stopped", zap.Error(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		node.Stop()
+	}()
+
+	// empty second address terminates peers list, so the configured ones
+	// aren't used
+	a.cfg.Set(cfgPeers+".0.address", ln.Addr().String())
+	a.cfg.Set(cfgPeers+".1.address", "")
+	a.cfg.Set(cfgRPCEndpoint, "")
+
+	a.log.Warn("serving requests from in-memory NeoFS mock, data won't be persisted",
+		zap.Stringer("address", ln.Addr()))
+	return nil
+}
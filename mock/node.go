@@ -0,0 +1,142 @@
+// Package mock implements in-memory NeoFS node serving NeoFS API over gRPC.
+// It's enough for the gateway to store, read and search objects without a real
+// NeoFS network: containers are created implicitly on the first object put,
+// objects aren't sliced and nothing is persisted.
+package mock
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	containergrpc "github.com/nspcc-dev/neofs-api-go/v2/container/grpc"
+	netmapgrpc "github.com/nspcc-dev/neofs-api-go/v2/netmap/grpc"
+	objectgrpc "github.com/nspcc-dev/neofs-api-go/v2/object/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+	"github.com/nspcc-dev/neofs-api-go/v2/session"
+	sessiongrpc "github.com/nspcc-dev/neofs-api-go/v2/session/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/signature"
+	"github.com/nspcc-dev/neofs-api-go/v2/status"
+	"google.golang.org/grpc"
+)
+
+const (
+	// MagicNumber is the magic of the mock network.
+	MagicNumber = 0x4d4f434b
+
+	// MsPerBlock and EpochDuration make epochs last for a minute.
+	MsPerBlock    = 1000
+	EpochDuration = 60
+
+	// MaxObjectSize is reported in network settings, objects aren't sliced
+	// by the node, so it's not a real limit.
+	MaxObjectSize = 64 << 20
+
+	chunkSize = 1 << 20
+)
+
+// apiVersion is NeoFS API version the node reports.
+var apiVersion = func() *refs.Version {
+	v := new(refs.Version)
+	v.SetMajor(2)
+	v.SetMinor(12)
+	return v
+}()
+
+// Node is in-memory NeoFS node.
+type Node struct {
+	key   *ecdsa.PrivateKey
+	start time.Time
+	srv   *grpc.Server
+
+	mu         sync.RWMutex
+	containers map[string]*storedContainer
+	seq        uint64
+}
+
+// NewNode creates the node with a random key.
+func NewNode() (*Node, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate node key: %w", err)
+	}
+
+	n := &Node{
+		key:        key,
+		start:      time.Now(),
+		srv:        grpc.NewServer(),
+		containers: make(map[string]*storedContainer),
+	}
+
+	containergrpc.RegisterContainerServiceServer(n.srv, containerService{n: n})
+	netmapgrpc.RegisterNetmapServiceServer(n.srv, netmapService{n: n})
+	objectgrpc.RegisterObjectServiceServer(n.srv, objectService{n: n})
+	sessiongrpc.RegisterSessionServiceServer(n.srv, sessionService{n: n})
+
+	return n, nil
+}
+
+// Serve accepts connections on the listener until the node is stopped.
+func (n *Node) Serve(ln net.Listener) error {
+	return n.srv.Serve(ln)
+}
+
+// Stop closes the listener and all connections.
+func (n *Node) Stop() {
+	n.srv.Stop()
+}
+
+// PublicKey returns compressed public key of the node.
+func (n *Node) PublicKey() []byte {
+	return elliptic.MarshalCompressed(elliptic.P256(), n.key.X, n.key.Y)
+}
+
+// Epoch returns the current epoch, it's incremented every EpochDuration blocks
+// since the node is created.
+func (n *Node) Epoch() uint64 {
+	return 1 + uint64(time.Since(n.start)/(EpochDuration*MsPerBlock*time.Millisecond))
+}
+
+// response is signed NeoFS API response.
+type response interface {
+	SetMetaHeader(*session.ResponseMetaHeader)
+}
+
+// sign sets response meta header with the status and signs the response.
+func (n *Node) sign(resp response, st *status.Status) error {
+	meta := new(session.ResponseMetaHeader)
+	meta.SetVersion(apiVersion)
+	meta.SetEpoch(n.Epoch())
+	meta.SetTTL(1)
+	meta.SetStatus(st)
+	resp.SetMetaHeader(meta)
+
+	return signature.SignServiceMessage(n.key, resp)
+}
+
+// newStatus creates failure status with the code globalized by the function.
+func newStatus(code status.Code, globalize func(*status.Code), msg string) *status.Status {
+	globalize(&code)
+
+	st := new(status.Status)
+	st.SetCode(code)
+	st.SetMessage(msg)
+	return st
+}
+
+// internalError creates common failure status.
+func internalError(msg string) *status.Status {
+	return newStatus(status.Internal, status.GlobalizeCommonFail, msg)
+}
+
+// uint64Param encodes network parameter value.
+func uint64Param(v uint64) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, v)
+	return data
+}
@@ -0,0 +1,222 @@
+package mock
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+	"github.com/nspcc-dev/neofs-api-go/v2/rpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/rpc/client"
+	"github.com/nspcc-dev/neofs-api-go/v2/signature"
+	"github.com/nspcc-dev/neofs-api-go/v2/status"
+	"github.com/stretchr/testify/require"
+)
+
+func startNode(t *testing.T) *client.Client {
+	n, err := NewNode()
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = n.Serve(ln) }()
+	t.Cleanup(n.Stop)
+
+	return client.New(client.WithNetworkAddress(ln.Addr().String()))
+}
+
+func putObject(t *testing.T, cli *client.Client, cnrID *refs.ContainerID, payload []byte, attrs ...object.Attribute) *refs.ObjectID {
+	owner := new(refs.OwnerID)
+	owner.SetValue([]byte("owner"))
+
+	hdr := new(object.Header)
+	hdr.SetContainerID(cnrID)
+	hdr.SetOwnerID(owner)
+	hdr.SetAttributes(attrs)
+
+	init := new(object.PutObjectPartInit)
+	init.SetHeader(hdr)
+	chunk := new(object.PutObjectPartChunk)
+	chunk.SetChunk(payload)
+
+	resp := new(object.PutResponse)
+	w, err := rpc.PutObject(cli, resp)
+	require.NoError(t, err)
+	for _, part := range []object.PutObjectPart{init, chunk} {
+		body := new(object.PutRequestBody)
+		body.SetObjectPart(part)
+		req := new(object.PutRequest)
+		req.SetBody(body)
+		require.NoError(t, w.Write(req))
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, signature.VerifyServiceMessage(resp))
+	require.Nil(t, resp.GetMetaHeader().GetStatus())
+
+	return resp.GetBody().GetObjectID()
+}
+
+func newAddress(cnrID *refs.ContainerID, objID *refs.ObjectID) *refs.Address {
+	addr := new(refs.Address)
+	addr.SetContainerID(cnrID)
+	addr.SetObjectID(objID)
+	return addr
+}
+
+func newAttribute(key, value string) object.Attribute {
+	var attr object.Attribute
+	attr.SetKey(key)
+	attr.SetValue(value)
+	return attr
+}
+
+func TestNodeObjects(t *testing.T) {
+	cli := startNode(t)
+
+	cnrID := new(refs.ContainerID)
+	cnrID.SetValue(make([]byte, 32))
+
+	payload := []byte("hello mock")
+	objID := putObject(t, cli, cnrID, payload, newAttribute("FileName", "hello.txt"))
+	otherID := putObject(t, cli, cnrID, []byte("other"), newAttribute("FileName", "other.txt"))
+	addr := newAddress(cnrID, objID)
+
+	t.Run("get", func(t *testing.T) {
+		body := new(object.GetRequestBody)
+		body.SetAddress(addr)
+		req := new(object.GetRequest)
+		req.SetBody(body)
+
+		r, err := rpc.GetObject(cli, req)
+		require.NoError(t, err)
+
+		var data []byte
+		var hdr *object.Header
+		for {
+			resp := new(object.GetResponse)
+			if err = r.Read(resp); errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			require.NoError(t, signature.VerifyServiceMessage(resp))
+
+			switch part := resp.GetBody().GetObjectPart().(type) {
+			case *object.GetObjectPartInit:
+				hdr = part.GetHeader()
+			case *object.GetObjectPartChunk:
+				data = append(data, part.GetChunk()...)
+			}
+		}
+		require.Equal(t, payload, data)
+		require.EqualValues(t, len(payload), hdr.GetPayloadLength())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		body := new(object.HeadRequestBody)
+		body.SetAddress(addr)
+		req := new(object.HeadRequest)
+		req.SetBody(body)
+
+		resp, err := rpc.HeadObject(cli, req)
+		require.NoError(t, err)
+		require.NoError(t, signature.VerifyServiceMessage(resp))
+
+		part, ok := resp.GetBody().GetHeaderPart().(*object.HeaderWithSignature)
+		require.True(t, ok)
+		require.Equal(t, "hello.txt", part.GetHeader().GetAttributes()[0].GetValue())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		search := func(filters ...object.SearchFilter) []refs.ObjectID {
+			body := new(object.SearchRequestBody)
+			body.SetContainerID(cnrID)
+			body.SetFilters(filters)
+			req := new(object.SearchRequest)
+			req.SetBody(body)
+
+			r, err := rpc.SearchObjects(cli, req)
+			require.NoError(t, err)
+
+			resp := new(object.SearchResponse)
+			require.NoError(t, r.Read(resp))
+			require.NoError(t, signature.VerifyServiceMessage(resp))
+			return resp.GetBody().GetIDList()
+		}
+
+		var filter object.SearchFilter
+		filter.SetMatchType(object.MatchStringEqual)
+		filter.SetKey("FileName")
+		filter.SetValue("other.txt")
+		require.Equal(t, []refs.ObjectID{*otherID}, search(filter))
+
+		filter.SetMatchType(object.MatchCommonPrefix)
+		filter.SetValue("")
+		require.Equal(t, []refs.ObjectID{*objID, *otherID}, search(filter))
+
+		filter.SetMatchType(object.MatchNotPresent)
+		require.Empty(t, search(filter))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		getRange := func(offset, length uint64) ([]byte, *status.Status) {
+			rng := new(object.Range)
+			rng.SetOffset(offset)
+			rng.SetLength(length)
+
+			body := new(object.GetRangeRequestBody)
+			body.SetAddress(addr)
+			body.SetRange(rng)
+			req := new(object.GetRangeRequest)
+			req.SetBody(body)
+
+			r, err := rpc.GetObjectRange(cli, req)
+			require.NoError(t, err)
+
+			var data []byte
+			for {
+				resp := new(object.GetRangeResponse)
+				if err = r.Read(resp); errors.Is(err, io.EOF) {
+					return data, nil
+				}
+				require.NoError(t, err)
+				if st := resp.GetMetaHeader().GetStatus(); st != nil {
+					return nil, st
+				}
+				data = append(data, resp.GetBody().GetRangePart().(*object.GetRangePartChunk).GetChunk()...)
+			}
+		}
+
+		data, st := getRange(6, 4)
+		require.Nil(t, st)
+		require.Equal(t, []byte("mock"), data)
+
+		_, st = getRange(6, 5)
+		require.NotNil(t, st)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		body := new(object.DeleteRequestBody)
+		body.SetAddress(addr)
+		req := new(object.DeleteRequest)
+		req.SetBody(body)
+
+		resp, err := rpc.DeleteObject(cli, req)
+		require.NoError(t, err)
+		require.NoError(t, signature.VerifyServiceMessage(resp))
+		require.Nil(t, resp.GetMetaHeader().GetStatus())
+
+		headBody := new(object.HeadRequestBody)
+		headBody.SetAddress(addr)
+		headReq := new(object.HeadRequest)
+		headReq.SetBody(headBody)
+
+		headResp, err := rpc.HeadObject(cli, headReq)
+		require.NoError(t, err)
+
+		code := object.StatusNotFound
+		object.GlobalizeFail(&code)
+		require.Equal(t, code, headResp.GetMetaHeader().GetStatus().Code())
+	})
+}
@@ -0,0 +1,350 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/object"
+	objectgrpc "github.com/nspcc-dev/neofs-api-go/v2/object/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+	"github.com/nspcc-dev/neofs-api-go/v2/signature"
+	"github.com/nspcc-dev/neofs-api-go/v2/status"
+	utilsig "github.com/nspcc-dev/neofs-api-go/v2/util/signature"
+)
+
+// storedObject is the object with the sequence number of its put.
+type storedObject struct {
+	obj *object.Object
+	seq uint64
+}
+
+// objectNotFound creates OBJECT_NOT_FOUND status.
+func objectNotFound() *status.Status {
+	return newStatus(object.StatusNotFound, object.GlobalizeFail, "object not found")
+}
+
+type objectService struct {
+	objectgrpc.UnimplementedObjectServiceServer
+	n *Node
+}
+
+// object returns the object by the address or not found status. Expired
+// objects are treated as removed.
+func (n *Node) object(addr *refs.Address) (*object.Object, *status.Status) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	c, ok := n.containers[string(addr.GetContainerID().GetValue())]
+	if !ok {
+		return nil, containerNotFound()
+	}
+	stored, ok := c.objects[string(addr.GetObjectID().GetValue())]
+	if !ok || expired(stored.obj.GetHeader(), n.Epoch()) {
+		return nil, objectNotFound()
+	}
+	return stored.obj, nil
+}
+
+// expired checks expiration epoch attribute of the header.
+func expired(hdr *object.Header, epoch uint64) bool {
+	for _, attr := range hdr.GetAttributes() {
+		if attr.GetKey() == object.SysAttributeExpEpoch {
+			exp, err := strconv.ParseUint(attr.GetValue(), 10, 64)
+			return err == nil && exp < epoch
+		}
+	}
+	return false
+}
+
+func (s objectService) Put(stream objectgrpc.ObjectService_PutServer) error {
+	var (
+		init    *object.PutObjectPartInit
+		payload []byte
+	)
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		var r object.PutRequest
+		if err = r.FromGRPCMessage(req); err != nil {
+			return err
+		}
+		switch part := r.GetBody().GetObjectPart().(type) {
+		case *object.PutObjectPartInit:
+			init = part
+		case *object.PutObjectPartChunk:
+			payload = append(payload, part.GetChunk()...)
+		}
+	}
+
+	var (
+		st   *status.Status
+		body = new(object.PutResponseBody)
+	)
+	if init == nil || init.GetHeader() == nil {
+		st = internalError("missing object header")
+	} else if id, err := s.n.store(init.GetHeader(), payload); err != nil {
+		st = internalError(err.Error())
+	} else {
+		body.SetObjectID(id)
+	}
+
+	var resp object.PutResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp.ToGRPCMessage().(*objectgrpc.PutResponse))
+}
+
+// store fills node-controlled header fields, signs the object and saves it
+// creating the container if it doesn't exist.
+func (n *Node) store(hdr *object.Header, payload []byte) (*refs.ObjectID, error) {
+	sum := sha256.Sum256(payload)
+	checksum := new(refs.Checksum)
+	checksum.SetType(refs.SHA256)
+	checksum.SetSum(sum[:])
+
+	hdr.SetVersion(apiVersion)
+	hdr.SetCreationEpoch(n.Epoch())
+	hdr.SetPayloadLength(uint64(len(payload)))
+	hdr.SetPayloadHash(checksum)
+
+	data, err := hdr.StableMarshal(nil)
+	if err != nil {
+		return nil, err
+	}
+	idSum := sha256.Sum256(data)
+	id := new(refs.ObjectID)
+	id.SetValue(idSum[:])
+
+	obj := new(object.Object)
+	obj.SetObjectID(id)
+	obj.SetHeader(hdr)
+	obj.SetPayload(payload)
+	err = utilsig.SignDataWithHandler(n.key, signature.StableMarshalerWrapper{SM: id}, func(sig *refs.Signature) {
+		obj.SetSignature(sig)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := string(hdr.GetContainerID().GetValue())
+	c, ok := n.containers[key]
+	if !ok {
+		c = &storedContainer{cnr: newContainer(hdr.GetOwnerID()), objects: make(map[string]*storedObject)}
+		n.containers[key] = c
+	}
+	n.seq++
+	c.objects[string(idSum[:])] = &storedObject{obj: obj, seq: n.seq}
+
+	return id, nil
+}
+
+func (s objectService) Get(req *objectgrpc.GetRequest, stream objectgrpc.ObjectService_GetServer) error {
+	var r object.GetRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return err
+	}
+
+	obj, st := s.n.object(r.GetBody().GetAddress())
+	if st != nil {
+		return s.sendGet(stream, new(object.GetObjectPartInit), st)
+	}
+
+	init := new(object.GetObjectPartInit)
+	init.SetObjectID(obj.GetObjectID())
+	init.SetSignature(obj.GetSignature())
+	init.SetHeader(obj.GetHeader())
+	if err := s.sendGet(stream, init, nil); err != nil {
+		return err
+	}
+
+	payload := obj.GetPayload()
+	for len(payload) > 0 {
+		n := chunkSize
+		if n > len(payload) {
+			n = len(payload)
+		}
+
+		chunk := new(object.GetObjectPartChunk)
+		chunk.SetChunk(payload[:n])
+		if err := s.sendGet(stream, chunk, nil); err != nil {
+			return err
+		}
+		payload = payload[n:]
+	}
+	return nil
+}
+
+func (s objectService) sendGet(stream objectgrpc.ObjectService_GetServer, part object.GetObjectPart, st *status.Status) error {
+	body := new(object.GetResponseBody)
+	body.SetObjectPart(part)
+
+	var resp object.GetResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return err
+	}
+	return stream.Send(resp.ToGRPCMessage().(*objectgrpc.GetResponse))
+}
+
+func (s objectService) Head(_ context.Context, req *objectgrpc.HeadRequest) (*objectgrpc.HeadResponse, error) {
+	var r object.HeadRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	body := new(object.HeadResponseBody)
+	obj, st := s.n.object(r.GetBody().GetAddress())
+	if st == nil {
+		part := new(object.HeaderWithSignature)
+		part.SetHeader(obj.GetHeader())
+		part.SetSignature(obj.GetSignature())
+		body.SetHeaderPart(part)
+	}
+
+	var resp object.HeadResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*objectgrpc.HeadResponse), nil
+}
+
+func (s objectService) Search(req *objectgrpc.SearchRequest, stream objectgrpc.ObjectService_SearchServer) error {
+	var r object.SearchRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return err
+	}
+
+	var (
+		st    *status.Status
+		found []*storedObject
+		epoch = s.n.Epoch()
+	)
+	s.n.mu.RLock()
+	if c, ok := s.n.containers[string(r.GetBody().GetContainerID().GetValue())]; !ok {
+		st = containerNotFound()
+	} else {
+		for _, stored := range c.objects {
+			if !expired(stored.obj.GetHeader(), epoch) && match(stored.obj, r.GetBody().GetFilters()) {
+				found = append(found, stored)
+			}
+		}
+	}
+	s.n.mu.RUnlock()
+
+	sort.Slice(found, func(i, j int) bool { return found[i].seq < found[j].seq })
+
+	ids := make([]refs.ObjectID, len(found))
+	for i := range found {
+		ids[i] = *found[i].obj.GetObjectID()
+	}
+
+	body := new(object.SearchResponseBody)
+	body.SetIDList(ids)
+
+	var resp object.SearchResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return err
+	}
+	return stream.Send(resp.ToGRPCMessage().(*objectgrpc.SearchResponse))
+}
+
+func (s objectService) GetRange(req *objectgrpc.GetRangeRequest, stream objectgrpc.ObjectService_GetRangeServer) error {
+	var r object.GetRangeRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return err
+	}
+
+	obj, st := s.n.object(r.GetBody().GetAddress())
+	if st != nil {
+		return s.sendRange(stream, nil, st)
+	}
+
+	payload := obj.GetPayload()
+	rng := r.GetBody().GetRange()
+	offset, length := rng.GetOffset(), rng.GetLength()
+	if offset > uint64(len(payload)) || length > uint64(len(payload))-offset {
+		return s.sendRange(stream, nil, internalError("out of range"))
+	}
+
+	payload = payload[offset : offset+length]
+	for {
+		n := chunkSize
+		if n > len(payload) {
+			n = len(payload)
+		}
+		if err := s.sendRange(stream, payload[:n], nil); err != nil {
+			return err
+		}
+		if payload = payload[n:]; len(payload) == 0 {
+			return nil
+		}
+	}
+}
+
+func (s objectService) sendRange(stream objectgrpc.ObjectService_GetRangeServer, data []byte, st *status.Status) error {
+	chunk := new(object.GetRangePartChunk)
+	chunk.SetChunk(data)
+
+	body := new(object.GetRangeResponseBody)
+	body.SetRangePart(chunk)
+
+	var resp object.GetRangeResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return err
+	}
+	return stream.Send(resp.ToGRPCMessage().(*objectgrpc.GetRangeResponse))
+}
+
+func (s objectService) Delete(_ context.Context, req *objectgrpc.DeleteRequest) (*objectgrpc.DeleteResponse, error) {
+	var r object.DeleteRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	addr := r.GetBody().GetAddress()
+	body := new(object.DeleteResponseBody)
+
+	var st *status.Status
+	s.n.mu.Lock()
+	if c, ok := s.n.containers[string(addr.GetContainerID().GetValue())]; !ok {
+		st = containerNotFound()
+	} else {
+		delete(c.objects, string(addr.GetObjectID().GetValue()))
+
+		tombID := make([]byte, sha256.Size)
+		_, _ = rand.Read(tombID)
+		tomb := new(refs.ObjectID)
+		tomb.SetValue(tombID)
+
+		tombAddr := new(refs.Address)
+		tombAddr.SetContainerID(addr.GetContainerID())
+		tombAddr.SetObjectID(tomb)
+		body.SetTombstone(tombAddr)
+	}
+	s.n.mu.Unlock()
+
+	var resp object.DeleteResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*objectgrpc.DeleteResponse), nil
+}
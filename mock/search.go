@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mr-tron/base58"
+	"github.com/nspcc-dev/neofs-api-go/v2/object"
+)
+
+// match checks that the object satisfies all the search filters.
+func match(obj *object.Object, filters []object.SearchFilter) bool {
+	for i := range filters {
+		key := filters[i].GetKey()
+		if key == object.FilterPropertyRoot || key == object.FilterPropertyPhy {
+			continue
+		}
+
+		value, ok := headerValue(obj, key)
+		if !matchValue(filters[i].GetMatchType(), value, ok, filters[i].GetValue()) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchValue compares the value with the filter one, ok reports whether the
+// object has the value at all.
+func matchValue(typ object.MatchType, value string, ok bool, filter string) bool {
+	switch typ {
+	case object.MatchStringEqual:
+		return ok && value == filter
+	case object.MatchStringNotEqual:
+		return ok && value != filter
+	case object.MatchNotPresent:
+		return !ok
+	case object.MatchCommonPrefix:
+		return ok && strings.HasPrefix(value, filter)
+	default:
+		return false
+	}
+}
+
+// headerValue returns string representation of the header field or the
+// attribute the filter key refers to.
+func headerValue(obj *object.Object, key string) (string, bool) {
+	hdr := obj.GetHeader()
+	switch key {
+	case object.FilterHeaderVersion:
+		v := hdr.GetVersion()
+		return fmt.Sprintf("v%d.%d", v.GetMajor(), v.GetMinor()), true
+	case object.FilterHeaderObjectID:
+		return base58.Encode(obj.GetObjectID().GetValue()), true
+	case object.FilterHeaderContainerID:
+		return base58.Encode(hdr.GetContainerID().GetValue()), true
+	case object.FilterHeaderOwnerID:
+		return base58.Encode(hdr.GetOwnerID().GetValue()), true
+	case object.FilterHeaderCreationEpoch:
+		return strconv.FormatUint(hdr.GetCreationEpoch(), 10), true
+	case object.FilterHeaderPayloadLength:
+		return strconv.FormatUint(hdr.GetPayloadLength(), 10), true
+	case object.FilterHeaderPayloadHash:
+		return hex.EncodeToString(hdr.GetPayloadHash().GetSum()), true
+	case object.FilterHeaderObjectType:
+		return hdr.GetObjectType().String(), true
+	}
+
+	for _, attr := range hdr.GetAttributes() {
+		if attr.GetKey() == key {
+			return attr.GetValue(), true
+		}
+	}
+	return "", false
+}
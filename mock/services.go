@@ -0,0 +1,247 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/container"
+	containergrpc "github.com/nspcc-dev/neofs-api-go/v2/container/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/netmap"
+	netmapgrpc "github.com/nspcc-dev/neofs-api-go/v2/netmap/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+	"github.com/nspcc-dev/neofs-api-go/v2/session"
+	sessiongrpc "github.com/nspcc-dev/neofs-api-go/v2/session/grpc"
+	"github.com/nspcc-dev/neofs-api-go/v2/status"
+)
+
+// basicACLPublicRW is basic ACL of implicitly created containers.
+const basicACLPublicRW = 0x1FBFBFFF
+
+// storedContainer is the container with its objects.
+type storedContainer struct {
+	cnr     *container.Container
+	objects map[string]*storedObject
+}
+
+// newContainer returns public container of the owner with REP 1 policy.
+func newContainer(owner *refs.OwnerID) *container.Container {
+	var replica netmap.Replica
+	replica.SetCount(1)
+
+	policy := new(netmap.PlacementPolicy)
+	policy.SetReplicas([]netmap.Replica{replica})
+
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+
+	cnr := new(container.Container)
+	cnr.SetVersion(apiVersion)
+	cnr.SetOwnerID(owner)
+	cnr.SetNonce(nonce)
+	cnr.SetBasicACL(basicACLPublicRW)
+	cnr.SetPlacementPolicy(policy)
+	return cnr
+}
+
+// containerNotFound creates CONTAINER_NOT_FOUND status.
+func containerNotFound() *status.Status {
+	return newStatus(container.StatusNotFound, container.GlobalizeFail, "container not found")
+}
+
+type containerService struct {
+	containergrpc.UnimplementedContainerServiceServer
+	n *Node
+}
+
+func (s containerService) Put(_ context.Context, req *containergrpc.PutRequest) (*containergrpc.PutResponse, error) {
+	var r container.PutRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	var (
+		st   *status.Status
+		body = new(container.PutResponseBody)
+	)
+	if cnr := r.GetBody().GetContainer(); cnr == nil {
+		st = internalError("missing container")
+	} else if data, err := cnr.StableMarshal(nil); err != nil {
+		st = internalError(err.Error())
+	} else {
+		sum := sha256.Sum256(data)
+		id := new(refs.ContainerID)
+		id.SetValue(sum[:])
+
+		s.n.mu.Lock()
+		s.n.containers[string(sum[:])] = &storedContainer{cnr: cnr, objects: make(map[string]*storedObject)}
+		s.n.mu.Unlock()
+
+		body.SetContainerID(id)
+	}
+
+	var resp container.PutResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*containergrpc.PutResponse), nil
+}
+
+func (s containerService) Delete(_ context.Context, req *containergrpc.DeleteRequest) (*containergrpc.DeleteResponse, error) {
+	var r container.DeleteRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	key := string(r.GetBody().GetContainerID().GetValue())
+
+	var st *status.Status
+	s.n.mu.Lock()
+	if _, ok := s.n.containers[key]; ok {
+		delete(s.n.containers, key)
+	} else {
+		st = containerNotFound()
+	}
+	s.n.mu.Unlock()
+
+	var resp container.DeleteResponse
+	resp.SetBody(new(container.DeleteResponseBody))
+	if err := s.n.sign(&resp, st); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*containergrpc.DeleteResponse), nil
+}
+
+func (s containerService) Get(_ context.Context, req *containergrpc.GetRequest) (*containergrpc.GetResponse, error) {
+	var r container.GetRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	var (
+		st   *status.Status
+		body = new(container.GetResponseBody)
+	)
+	s.n.mu.RLock()
+	if c, ok := s.n.containers[string(r.GetBody().GetContainerID().GetValue())]; ok {
+		body.SetContainer(c.cnr)
+	} else {
+		st = containerNotFound()
+	}
+	s.n.mu.RUnlock()
+
+	var resp container.GetResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, st); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*containergrpc.GetResponse), nil
+}
+
+func (s containerService) List(_ context.Context, req *containergrpc.ListRequest) (*containergrpc.ListResponse, error) {
+	var r container.ListRequest
+	if err := r.FromGRPCMessage(req); err != nil {
+		return nil, err
+	}
+
+	owner := string(r.GetBody().GetOwnerID().GetValue())
+
+	var ids []refs.ContainerID
+	s.n.mu.RLock()
+	for key, c := range s.n.containers {
+		if string(c.cnr.GetOwnerID().GetValue()) == owner {
+			var id refs.ContainerID
+			id.SetValue([]byte(key))
+			ids = append(ids, id)
+		}
+	}
+	s.n.mu.RUnlock()
+
+	body := new(container.ListResponseBody)
+	body.SetContainerIDs(ids)
+
+	var resp container.ListResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*containergrpc.ListResponse), nil
+}
+
+type netmapService struct {
+	netmapgrpc.UnimplementedNetmapServiceServer
+	n *Node
+}
+
+func (s netmapService) LocalNodeInfo(context.Context, *netmapgrpc.LocalNodeInfoRequest) (*netmapgrpc.LocalNodeInfoResponse, error) {
+	info := new(netmap.NodeInfo)
+	info.SetPublicKey(s.n.PublicKey())
+	info.SetState(netmap.Online)
+
+	body := new(netmap.LocalNodeInfoResponseBody)
+	body.SetVersion(apiVersion)
+	body.SetNodeInfo(info)
+
+	var resp netmap.LocalNodeInfoResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*netmapgrpc.LocalNodeInfoResponse), nil
+}
+
+func (s netmapService) NetworkInfo(context.Context, *netmapgrpc.NetworkInfoRequest) (*netmapgrpc.NetworkInfoResponse, error) {
+	var epochDuration, maxObjectSize netmap.NetworkParameter
+	epochDuration.SetKey([]byte("EpochDuration"))
+	epochDuration.SetValue(uint64Param(EpochDuration))
+	maxObjectSize.SetKey([]byte("MaxObjectSize"))
+	maxObjectSize.SetValue(uint64Param(MaxObjectSize))
+
+	cfg := new(netmap.NetworkConfig)
+	cfg.SetParameters(epochDuration, maxObjectSize)
+
+	info := new(netmap.NetworkInfo)
+	info.SetCurrentEpoch(s.n.Epoch())
+	info.SetMagicNumber(MagicNumber)
+	info.SetMsPerBlock(MsPerBlock)
+	info.SetNetworkConfig(cfg)
+
+	body := new(netmap.NetworkInfoResponseBody)
+	body.SetNetworkInfo(info)
+
+	var resp netmap.NetworkInfoResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*netmapgrpc.NetworkInfoResponse), nil
+}
+
+type sessionService struct {
+	sessiongrpc.UnimplementedSessionServiceServer
+	n *Node
+}
+
+// Create opens a session, tokens aren't checked by the node, so nothing is
+// stored.
+func (s sessionService) Create(context.Context, *sessiongrpc.CreateRequest) (*sessiongrpc.CreateResponse, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("could not generate session ID: %w", err)
+	}
+	id[6] = id[6]&0x0f | 0x40 // UUID version 4
+	id[8] = id[8]&0x3f | 0x80
+
+	body := new(session.CreateResponseBody)
+	body.SetID(id)
+	body.SetSessionKey(s.n.PublicKey())
+
+	var resp session.CreateResponse
+	resp.SetBody(body)
+	if err := s.n.sign(&resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.ToGRPCMessage().(*sessiongrpc.CreateResponse), nil
+}
@@ -0,0 +1,255 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const natsDefaultPort = "4222"
+
+type (
+	// natsPublisher publishes object events to NATS subjects of the
+	// containers. It speaks the plain text core NATS protocol and confirms
+	// every publication with PING, failed ones are retried once over a new
+	// connection.
+	natsPublisher struct {
+		log            *zap.Logger
+		address        string
+		user           string
+		password       string
+		token          string
+		subjects       map[string]string
+		defaultSubject string
+		timeout        time.Duration
+		queue          chan utils.ObjectEvent
+
+		conn net.Conn
+		r    *bufio.Reader
+	}
+
+	natsInfo struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+
+	natsConnect struct {
+		Verbose  bool   `json:"verbose"`
+		Pedantic bool   `json:"pedantic"`
+		Name     string `json:"name"`
+		Lang     string `json:"lang"`
+		Version  string `json:"version"`
+		User     string `json:"user,omitempty"`
+		Password string `json:"pass,omitempty"`
+		Token    string `json:"auth_token,omitempty"`
+	}
+)
+
+func fetchNATSSubjects(v *viper.Viper) map[string]string {
+	subjects := make(map[string]string)
+
+	for i := 0; ; i++ {
+		key := cfgNATSSubjects + "." + strconv.Itoa(i) + "."
+
+		cnr := v.GetString(key + "container")
+		if cnr == "" {
+			break
+		}
+
+		subjects[cnr] = v.GetString(key + "subject")
+	}
+
+	return subjects
+}
+
+// newNATSPublisher returns nil if NATS URL isn't configured.
+func (a *app) newNATSPublisher() (*natsPublisher, error) {
+	addr := a.cfg.GetString(cfgNATSURL)
+	if addr == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil || u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q, nats://host[:port] expected", addr)
+	}
+
+	p := &natsPublisher{
+		log:            a.log,
+		address:        u.Host,
+		user:           a.cfg.GetString(cfgNATSUser),
+		password:       a.secret(cfgNATSPassword),
+		token:          a.secret(cfgNATSToken),
+		subjects:       fetchNATSSubjects(a.cfg),
+		defaultSubject: a.cfg.GetString(cfgNATSDefaultSubject),
+		timeout:        a.cfg.GetDuration(cfgNATSTimeout),
+		queue:          make(chan utils.ObjectEvent, a.cfg.GetInt(cfgNATSQueueSize)),
+	}
+	if u.Port() == "" {
+		p.address = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	if u.User != nil && p.user == "" && p.token == "" {
+		p.user = u.User.Username()
+		p.password, _ = u.User.Password()
+	}
+
+	return p, nil
+}
+
+// subject returns the subject of the container events, empty if they
+// aren't published.
+func (p *natsPublisher) subject(cnrID string) string {
+	if subject, ok := p.subjects[cnrID]; ok {
+		return subject
+	}
+	return p.defaultSubject
+}
+
+// Notify implements utils.EventNotifier. Events are dropped if the queue is
+// full.
+func (p *natsPublisher) Notify(ev utils.ObjectEvent) {
+	if p.subject(ev.ContainerID) == "" {
+		return
+	}
+
+	select {
+	case p.queue <- ev:
+	default:
+		p.log.Warn("NATS queue is full, event is dropped",
+			zap.String("cid", ev.ContainerID), zap.String("oid", ev.ObjectID))
+	}
+}
+
+// run publishes queued events until the context is done.
+func (p *natsPublisher) run(ctx context.Context) {
+	defer p.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-p.queue:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				p.log.Error("could not encode NATS event", zap.Error(err))
+				continue
+			}
+
+			subject := p.subject(ev.ContainerID)
+			if err = p.publish(subject, data); err != nil {
+				p.close()
+				err = p.publish(subject, data)
+			}
+			if err != nil {
+				p.close()
+				p.log.Error("could not publish event to NATS", zap.String("subject", subject), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (p *natsPublisher) close() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// connect establishes the connection if there is none.
+func (p *natsPublisher) connect() error {
+	if p.conn != nil {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("tcp", p.address, p.timeout)
+	if err != nil {
+		return err
+	}
+	p.conn, p.r = conn, bufio.NewReader(conn)
+
+	if err = p.conn.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		return err
+	}
+
+	line, err := p.r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("could not read server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected server greeting: %s", strings.TrimSpace(line))
+	}
+	var info natsInfo
+	if err = json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("could not decode server info: %w", err)
+	}
+	if info.TLSRequired {
+		return errors.New("server requires TLS which isn't supported")
+	}
+
+	data, err := json.Marshal(natsConnect{
+		Name:     "neofs-http-gw",
+		Lang:     "go",
+		Version:  Version,
+		User:     p.user,
+		Password: p.password,
+		Token:    p.token,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = p.conn.Write([]byte("CONNECT " + string(data) + "\r\nPING\r\n")); err != nil {
+		return err
+	}
+	return p.waitPong()
+}
+
+// publish sends the message and waits for the server to process it.
+func (p *natsPublisher) publish(subject string, data []byte) error {
+	if err := p.connect(); err != nil {
+		return err
+	}
+	if err := p.conn.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		return err
+	}
+
+	msg := make([]byte, 0, len(subject)+len(data)+32)
+	msg = append(msg, "PUB "+subject+" "+strconv.Itoa(len(data))+"\r\n"...)
+	msg = append(msg, data...)
+	msg = append(msg, "\r\nPING\r\n"...)
+	if _, err := p.conn.Write(msg); err != nil {
+		return err
+	}
+	return p.waitPong()
+}
+
+// waitPong reads server messages until PONG replying to its PINGs.
+func (p *natsPublisher) waitPong() error {
+	for {
+		line, err := p.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err = p.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
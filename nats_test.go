@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// serveNATS accepts a connection and passes the published messages to the
+// channel.
+func serveNATS(t *testing.T, ln net.Listener, msgs chan<- [2]string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	_, err = conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+	require.NoError(t, err)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+
+		switch fields[0] {
+		case "CONNECT":
+			var c natsConnect
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &c))
+			require.Equal(t, "token", c.Token)
+		case "PING":
+			_, err = conn.Write([]byte("PONG\r\n"))
+			require.NoError(t, err)
+		case "PUB":
+			payload, err := r.ReadString('\n')
+			require.NoError(t, err)
+			msgs <- [2]string{fields[1], strings.TrimSpace(payload)}
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	msgs := make(chan [2]string, 1)
+	go serveNATS(t, ln, msgs)
+
+	v := viper.New()
+	v.Set(cfgNATSURL, "nats://"+ln.Addr().String())
+	v.Set(cfgNATSToken, "token")
+	v.Set(cfgNATSSubjects+".0.container", "cid")
+	v.Set(cfgNATSSubjects+".0.subject", "neofs.images")
+	v.Set(cfgNATSTimeout, time.Second)
+	v.Set(cfgNATSQueueSize, 1)
+
+	p, err := (&app{cfg: viper.New(), log: zap.NewNop()}).newNATSPublisher()
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	invalid := viper.New()
+	invalid.Set(cfgNATSURL, "http://localhost:4222")
+	_, err = (&app{cfg: invalid, log: zap.NewNop()}).newNATSPublisher()
+	require.Error(t, err)
+
+	p, err = (&app{cfg: v, log: zap.NewNop()}).newNATSPublisher()
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	require.Equal(t, "neofs.images", p.subject("cid"))
+	require.Empty(t, p.subject("other"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.run(ctx)
+
+	p.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "other", ObjectID: "skipped"})
+	p.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "cid", ObjectID: "oid"})
+
+	select {
+	case msg := <-msgs:
+		require.Equal(t, "neofs.images", msg[0])
+
+		var ev utils.ObjectEvent
+		require.NoError(t, json.Unmarshal([]byte(msg[1]), &ev))
+		require.Equal(t, "oid", ev.ObjectID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event isn't published")
+	}
+}
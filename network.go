@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+type (
+	// networkInfo is the network state returned by GET /-/network.
+	networkInfo struct {
+		Epoch               uint64      `json:"epoch"`
+		MsPerBlock          int64       `json:"ms_per_block"`
+		EpochDurationBlocks uint64      `json:"epoch_duration_blocks"`
+		EpochDuration       string      `json:"epoch_duration"`
+		MaxObjectSize       uint64      `json:"max_object_size"`
+		Fees                networkFees `json:"fees"`
+	}
+
+	// networkFees are prices of the network as they're set in the network
+	// configuration.
+	networkFees struct {
+		StoragePrice   uint64 `json:"storage_price"`
+		Audit          uint64 `json:"audit"`
+		Container      uint64 `json:"container"`
+		ContainerAlias uint64 `json:"container_alias"`
+		Withdraw       uint64 `json:"withdraw"`
+	}
+)
+
+func newNetworkInfo(state *utils.NetworkState) networkInfo {
+	return networkInfo{
+		Epoch:               state.Epoch,
+		MsPerBlock:          state.MsPerBlock,
+		EpochDurationBlocks: state.BlocksPerEpoch,
+		EpochDuration:       (time.Duration(state.MsPerBlock) * time.Millisecond * time.Duration(state.BlocksPerEpoch)).String(),
+		MaxObjectSize:       state.Parameters[utils.NetworkParamMaxObjectSize],
+		Fees: networkFees{
+			StoragePrice:   state.Parameters[utils.NetworkParamBasicIncomeRate],
+			Audit:          state.Parameters[utils.NetworkParamAuditFee],
+			Container:      state.Parameters[utils.NetworkParamContainerFee],
+			ContainerAlias: state.Parameters[utils.NetworkParamContainerAliasFee],
+			Withdraw:       state.Parameters[utils.NetworkParamWithdrawFee],
+		},
+	}
+}
+
+// networkHandler returns the current epoch, epoch duration, maximum object
+// size and fees of the network, the state of the epoch tracker is used if
+// it's enabled.
+func (a *app) networkHandler(ctx context.Context) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		var (
+			state  *utils.NetworkState
+			err    error
+			reqCtx = utils.RequestContext(c, ctx)
+		)
+		if a.epochs != nil {
+			state, err = a.epochs.State(reqCtx)
+		} else {
+			state, err = utils.FetchNetworkState(reqCtx, a.pool.Pool())
+		}
+		if err != nil {
+			a.log.Error("could not get network info", zap.Error(err))
+			response.Error(c, "could not get network info: "+err.Error(), fasthttp.StatusBadGateway)
+			return
+		}
+
+		data, err := json.Marshal(newNetworkInfo(state))
+		if err != nil {
+			a.log.Error("could not encode network info", zap.Error(err))
+			response.Error(c, "could not encode network info: "+err.Error(), fasthttp.StatusInternalServerError)
+			return
+		}
+
+		c.SetContentType("application/json; charset=UTF-8")
+		c.SetBody(data)
+	}
+}
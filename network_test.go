@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNetworkInfo(t *testing.T) {
+	info := newNetworkInfo(&utils.NetworkState{
+		Epoch:          42,
+		MsPerBlock:     15000,
+		BlocksPerEpoch: 240,
+		Parameters: map[string]uint64{
+			utils.NetworkParamEpochDuration:   240,
+			utils.NetworkParamMaxObjectSize:   64 << 20,
+			utils.NetworkParamBasicIncomeRate: 100000000,
+			utils.NetworkParamContainerFee:    1000,
+		},
+	})
+
+	require.EqualValues(t, 42, info.Epoch)
+	require.EqualValues(t, 240, info.EpochDurationBlocks)
+	require.Equal(t, "1h0m0s", info.EpochDuration)
+	require.EqualValues(t, 64<<20, info.MaxObjectSize)
+	require.EqualValues(t, 100000000, info.Fees.StoragePrice)
+	require.EqualValues(t, 1000, info.Fees.Container)
+	require.Zero(t, info.Fees.Audit)
+}
@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const hdrNode = "X-Neofs-Node"
+
+var errUnknownNode = errors.New("node isn't in the peer list")
+
+// nodePools keeps connection pools of single nodes for requests forcing the
+// node with X-Neofs-Node header and for hedged reads. Only configured peers
+// can be used, so the header can't make the gateway connect to arbitrary
+// addresses.
+type nodePools struct {
+	mu    sync.Mutex
+	peers map[string]peerInfo
+	pools map[string]*pool.Pool
+	// override is set if nodes can be chosen with X-Neofs-Node header.
+	override bool
+
+	// dial is replaced in tests.
+	dial func(peerInfo) (*pool.Pool, error)
+	// close is replaced in tests.
+	close func(*pool.Pool)
+}
+
+// newNodePools returns nil if neither node selection by header nor hedging is
+// enabled.
+func (a *app) newNodePools(ctx context.Context) *nodePools {
+	override := a.cfg.GetBool(cfgNodeOverrideEnabled)
+	if !override && a.cfg.GetDuration(cfgPoolHedgeDelay) <= 0 {
+		return nil
+	}
+
+	n := &nodePools{
+		peers:    make(map[string]peerInfo),
+		pools:    make(map[string]*pool.Pool),
+		override: override,
+		dial: func(peer peerInfo) (*pool.Pool, error) {
+			a.keyMu.Lock()
+			key := a.key
+			a.keyMu.Unlock()
+			return a.dialPool(ctx, key, []peerInfo{peer})
+		},
+		close: (*pool.Pool).Close,
+	}
+	for _, peer := range fetchPeers(a.cfg) {
+		n.peers[peer.Address] = peer
+	}
+	return n
+}
+
+// get returns the pool of the node, it's created on the first use. The lock
+// isn't held while dialing since dialing waits for the key which is changed
+// together with resetting the pools.
+func (n *nodePools) get(address string) (*pool.Pool, error) {
+	n.mu.Lock()
+	if p, ok := n.pools[address]; ok {
+		n.mu.Unlock()
+		return p, nil
+	}
+	peer, ok := n.peers[address]
+	n.mu.Unlock()
+	if !ok {
+		return nil, errUnknownNode
+	}
+
+	p, err := n.dial(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if existing, ok := n.pools[address]; ok {
+		n.close(p)
+		return existing, nil
+	}
+	n.pools[address] = p
+	return p, nil
+}
+
+// pair returns pools of two different nodes chosen randomly by their weights,
+// ok is false if there is a single node or they can't be connected.
+func (n *nodePools) pair() (*pool.Pool, *pool.Pool, bool) {
+	n.mu.Lock()
+	peers := make([]peerInfo, 0, len(n.peers))
+	for _, peer := range n.peers {
+		peers = append(peers, peer)
+	}
+	n.mu.Unlock()
+	if len(peers) < 2 {
+		return nil, nil, false
+	}
+
+	i := pickPeer(peers)
+	peers[0], peers[i] = peers[i], peers[0]
+	j := 1 + pickPeer(peers[1:])
+
+	first, err := n.get(peers[0].Address)
+	if err != nil {
+		return nil, nil, false
+	}
+	second, err := n.get(peers[j].Address)
+	if err != nil {
+		return nil, nil, false
+	}
+	return first, second, true
+}
+
+// pickPeer returns the index of the peer chosen randomly by weights.
+func pickPeer(peers []peerInfo) int {
+	var total float64
+	for _, peer := range peers {
+		total += peer.Weight
+	}
+	x := rand.Float64() * total
+	for i, peer := range peers {
+		if x -= peer.Weight; x < 0 {
+			return i
+		}
+	}
+	return len(peers) - 1
+}
+
+// reset drops the pools (e.g. when the gateway key is changed), they're
+// closed after the drain timeout.
+func (n *nodePools) reset(drainTimeout time.Duration) {
+	n.mu.Lock()
+	old := n.pools
+	n.pools = make(map[string]*pool.Pool)
+	n.mu.Unlock()
+
+	time.AfterFunc(drainTimeout, func() {
+		for _, p := range old {
+			n.close(p)
+		}
+	})
+}
+
+// nodeOverride makes NeoFS operations of the request go through the node
+// from X-Neofs-Node header.
+func (a *app) nodeOverride(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		address := c.Request.Header.Peek(hdrNode)
+		if len(address) == 0 {
+			h(c)
+			return
+		}
+		if a.nodePools == nil || !a.nodePools.override {
+			response.Error(c, "node selection by header is disabled", fasthttp.StatusBadRequest)
+			return
+		}
+
+		p, err := a.nodePools.get(string(address))
+		if err != nil {
+			a.log.Error("could not connect to the node", zap.ByteString("node", address), zap.Error(err))
+			code := fasthttp.StatusBadGateway
+			if errors.Is(err, errUnknownNode) {
+				code = fasthttp.StatusBadRequest
+			}
+			response.Error(c, "could not connect to the node "+string(address)+": "+err.Error(), code)
+			return
+		}
+
+		utils.SetRequestPool(c, p)
+		h(c)
+	}
+}
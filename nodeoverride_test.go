@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestNodeOverride(t *testing.T) {
+	a := &app{log: zap.NewNop(), pool: utils.NewPoolHolder(new(pool.Pool))}
+
+	var acquired *pool.Pool
+	h := a.nodeOverride(func(c *fasthttp.RequestCtx) {
+		acquired = a.pool.Acquire(c)
+	})
+	request := func(node string) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		if node != "" {
+			c.Request.Header.Set(hdrNode, node)
+		}
+		acquired = nil
+		h(&c)
+		return &c
+	}
+
+	c := request("s01.neofs.devenv:8080")
+	require.Equal(t, fasthttp.StatusBadRequest, c.Response.StatusCode())
+	require.Nil(t, acquired)
+
+	var (
+		dialed int
+		closed int32
+	)
+	a.nodePools = &nodePools{
+		override: true,
+		peers: map[string]peerInfo{
+			"s01.neofs.devenv:8080": {Address: "s01.neofs.devenv:8080"},
+			"s02.neofs.devenv:8080": {Address: "s02.neofs.devenv:8080"},
+		},
+		pools: make(map[string]*pool.Pool),
+		dial: func(peer peerInfo) (*pool.Pool, error) {
+			dialed++
+			if peer.Address == "s02.neofs.devenv:8080" {
+				return nil, errors.New("connection refused")
+			}
+			return new(pool.Pool), nil
+		},
+		close: func(*pool.Pool) { atomic.AddInt32(&closed, 1) },
+	}
+
+	request("")
+	require.Same(t, a.pool.Pool(), acquired)
+
+	request("s01.neofs.devenv:8080")
+	require.Same(t, a.nodePools.pools["s01.neofs.devenv:8080"], acquired)
+	request("s01.neofs.devenv:8080")
+	require.Same(t, a.nodePools.pools["s01.neofs.devenv:8080"], acquired)
+	require.Equal(t, 1, dialed)
+
+	c = request("s02.neofs.devenv:8080")
+	require.Equal(t, fasthttp.StatusBadGateway, c.Response.StatusCode())
+
+	c = request("evil.example.com:8080")
+	require.Equal(t, fasthttp.StatusBadRequest, c.Response.StatusCode())
+	require.Equal(t, 2, dialed)
+
+	a.nodePools.reset(0)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&closed) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestNodePoolsPair(t *testing.T) {
+	n := &nodePools{
+		peers: map[string]peerInfo{
+			"s01.neofs.devenv:8080": {Address: "s01.neofs.devenv:8080", Weight: 1},
+		},
+		pools: make(map[string]*pool.Pool),
+		dial:  func(peerInfo) (*pool.Pool, error) { return new(pool.Pool), nil },
+		close: func(*pool.Pool) {},
+	}
+
+	_, _, ok := n.pair()
+	require.False(t, ok)
+
+	n.peers["s02.neofs.devenv:8080"] = peerInfo{Address: "s02.neofs.devenv:8080", Weight: 0.1}
+	for i := 0; i < 10; i++ {
+		first, second, ok := n.pair()
+		require.True(t, ok)
+		require.NotSame(t, first, second)
+	}
+	require.Len(t, n.pools, 2)
+}
@@ -0,0 +1,463 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	// oidcClockSkew is the allowed clock difference with the issuer.
+	oidcClockSkew = time.Minute
+	// oidcMinRefreshInterval limits JWKS refetches caused by unknown key IDs.
+	oidcMinRefreshInterval = 10 * time.Second
+	oidcHTTPTimeout        = 10 * time.Second
+
+	bearerAuthPrefix = "Bearer "
+
+	// oidcSubjectKey is the request user value with the subject of the
+	// verified token.
+	oidcSubjectKey = "__oidc_subject"
+)
+
+var (
+	errTokenExpired = errors.New("token is expired")
+	errUnknownKey   = errors.New("unknown signing key")
+)
+
+type (
+	// oidcPermission grants the operations on the containers (any if empty)
+	// to tokens having the value in the claim.
+	oidcPermission struct {
+		Claim      string
+		Value      string
+		Operations []string
+		Containers []string
+	}
+
+	// oidcVerifier validates JWTs issued by OIDC provider.
+	oidcVerifier struct {
+		issuer          string
+		audience        string
+		jwksURL         string
+		refreshInterval time.Duration
+		permissions     []oidcPermission
+		client          *http.Client
+
+		mu          sync.Mutex
+		keys        map[string]crypto.PublicKey
+		fetched     time.Time
+		lastAttempt time.Time
+	}
+
+	jwtHeader struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	jsonWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+func fetchOIDCPermissions(v *viper.Viper) []oidcPermission {
+	var perms []oidcPermission
+
+	for i := 0; ; i++ {
+		key := cfgOIDCPermissions + "." + strconv.Itoa(i) + "."
+
+		claim := v.GetString(key + "claim")
+		if claim == "" {
+			break
+		}
+
+		perms = append(perms, oidcPermission{
+			Claim:      claim,
+			Value:      v.GetString(key + "value"),
+			Operations: v.GetStringSlice(key + "operations"),
+			Containers: v.GetStringSlice(key + "containers"),
+		})
+	}
+
+	return perms
+}
+
+// newOIDCVerifier returns nil if OIDC issuer isn't configured.
+func (a *app) newOIDCVerifier() *oidcVerifier {
+	issuer := a.cfg.GetString(cfgOIDCIssuer)
+	if issuer == "" {
+		return nil
+	}
+
+	return &oidcVerifier{
+		issuer:          issuer,
+		audience:        a.cfg.GetString(cfgOIDCAudience),
+		jwksURL:         a.cfg.GetString(cfgOIDCJWKSURL),
+		refreshInterval: a.cfg.GetDuration(cfgOIDCJWKSRefreshInterval),
+		permissions:     fetchOIDCPermissions(a.cfg),
+		client:          &http.Client{Timeout: oidcHTTPTimeout},
+	}
+}
+
+func (o *oidcVerifier) getJSON(url string, v interface{}) error {
+	resp, err := o.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status of %s: %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetchKeys discovers JWKS URL (if it's not configured) and fetches the keys.
+func (o *oidcVerifier) fetchKeys() (map[string]crypto.PublicKey, error) {
+	if o.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		err := o.getJSON(strings.TrimSuffix(o.issuer, "/")+"/.well-known/openid-configuration", &discovery)
+		if err != nil {
+			return nil, fmt.Errorf("could not discover OIDC provider: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.New("OIDC provider has no jwks_uri")
+		}
+		o.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := o.getJSON(o.jwksURL, &set); err != nil {
+		return nil, fmt.Errorf("could not fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// key returns the signing key with the ID. Keys are refetched if they are
+// stale or the key is unknown, old keys are kept if the refetch fails.
+func (o *oidcVerifier) key(kid string) (crypto.PublicKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	key, ok := o.lookup(kid)
+	stale := o.refreshInterval > 0 && now.Sub(o.fetched) > o.refreshInterval
+
+	if (!ok || stale) && now.Sub(o.lastAttempt) >= oidcMinRefreshInterval {
+		o.lastAttempt = now
+		keys, err := o.fetchKeys()
+		if err != nil && o.keys == nil {
+			return nil, err
+		}
+		if err == nil {
+			o.keys, o.fetched = keys, now
+			key, ok = o.lookup(kid)
+		}
+	}
+
+	if !ok {
+		return nil, errUnknownKey
+	}
+	return key, nil
+}
+
+// lookup finds the key by ID, the only key is used for tokens without ID.
+func (o *oidcVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(o.keys) == 1 {
+		for _, key := range o.keys {
+			return key, true
+		}
+	}
+	key, ok := o.keys[kid]
+	return key, ok
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// publicKey returns RSA or EC public key, keys of other types are skipped.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// verifySignature checks JWS signature of RS*, PS* and ES* algorithms.
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+		case "PS":
+			return rsa.VerifyPSS(pub, hash, digest, sig, nil)
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("algorithm %s doesn't match the key", alg)
+}
+
+// verify checks the token signature, issuer, audience and lifetime and
+// returns its claims.
+func (o *oidcVerifier) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	if len(header.Alg) != 5 {
+		return nil, fmt.Errorf("unsupported algorithm: %s", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	key, err := o.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err = verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != o.issuer {
+		return nil, fmt.Errorf("unexpected issuer: %s", iss)
+	}
+	if o.audience != "" && !claimContains(claims["aud"], o.audience) {
+		return nil, errors.New("token isn't issued for the gateway")
+	}
+
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("token has no expiration time")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return nil, errTokenExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("token isn't valid yet")
+	}
+
+	return claims, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// claimContains checks if the claim is the value, contains it in the list or
+// in space-separated string (like scope claim).
+func claimContains(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		for _, v := range strings.Fields(c) {
+			if v == value {
+				return true
+			}
+		}
+		return c == value
+	case []interface{}:
+		for _, v := range c {
+			if s, ok := v.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// permitted checks if the claims grant the operation on the container, any
+// valid token is permitted if there are no permissions configured.
+func (o *oidcVerifier) permitted(claims map[string]interface{}, operation, cnr string) bool {
+	if len(o.permissions) == 0 {
+		return true
+	}
+
+	for _, p := range o.permissions {
+		if !claimContains(claims[p.Claim], p.Value) {
+			continue
+		}
+		if len(p.Operations) != 0 && !containsString(p.Operations, operation) {
+			continue
+		}
+		if len(p.Containers) != 0 && !containsString(p.Containers, cnr) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// oidcAuth requires a valid JWT in Authorization header granting the route
+// operation on the requested containers. The header is removed after the
+// check, so NeoFS bearer token is to be passed in the cookie.
+func (a *app) oidcAuth(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if a.oidc == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		auth := c.Request.Header.Peek(fasthttp.HeaderAuthorization)
+		if !bytes.HasPrefix(auth, []byte(bearerAuthPrefix)) {
+			response.Error(c, "authentication required", fasthttp.StatusUnauthorized)
+			c.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+			return
+		}
+
+		claims, err := a.oidc.verify(string(auth[len(bearerAuthPrefix):]))
+		if err != nil {
+			a.log.Warn("invalid OIDC token", zap.Error(err))
+			response.Error(c, "invalid token", fasthttp.StatusUnauthorized)
+			c.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return
+		}
+
+		sub, _ := claims["sub"].(string)
+		c.SetUserValue(oidcSubjectKey, sub)
+		for _, op := range requestContainers(route, c) {
+			if !a.oidc.permitted(claims, op.operation, op.container) {
+				a.log.Warn("container access denied for OIDC token", zap.String("sub", sub),
+					zap.String("operation", op.operation), zap.String("cid", op.container))
+				response.Error(c, "access to container is denied", fasthttp.StatusForbidden)
+				return
+			}
+		}
+
+		c.Request.Header.Del(fasthttp.HeaderAuthorization)
+		h(c)
+	}
+}
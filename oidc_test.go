@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	header, err := json.Marshal(jwtHeader{Alg: alg, Kid: kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, err)
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return signed + "." + b64(sig)
+}
+
+func TestOIDCAuth(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string][]jsonWebKey{"keys": {
+			{Kty: "RSA", Kid: "rsa", N: b64(rsaKey.N.Bytes()), E: b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{Kty: "EC", Kid: "ec", Crv: "P-256", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())},
+		}})
+	})
+
+	v := viper.New()
+	v.Set(cfgOIDCIssuer, srv.URL)
+	v.Set(cfgOIDCAudience, "gate")
+	v.Set(cfgOIDCPermissions+".0.claim", "groups")
+	v.Set(cfgOIDCPermissions+".0.value", "readers")
+	v.Set(cfgOIDCPermissions+".0.operations", []string{operationDownload})
+	v.Set(cfgOIDCPermissions+".1.claim", "groups")
+	v.Set(cfgOIDCPermissions+".1.value", "writers")
+	v.Set(cfgOIDCPermissions+".1.containers", []string{"cnr"})
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.oidc = a.newOIDCVerifier()
+
+	exp := time.Now().Add(time.Hour).Unix()
+	claims := func(groups ...string) map[string]interface{} {
+		return map[string]interface{}{"iss": srv.URL, "aud": []string{"gate"}, "sub": "user", "exp": exp, "groups": groups}
+	}
+
+	var authorization string
+	h := func(c *fasthttp.RequestCtx) {
+		authorization = string(c.Request.Header.Peek(fasthttp.HeaderAuthorization))
+		c.SetStatusCode(fasthttp.StatusOK)
+	}
+
+	serve := func(route, cnr, token string) int {
+		var c fasthttp.RequestCtx
+		c.SetUserValue("cid", cnr)
+		if token != "" {
+			c.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer "+token)
+		}
+		a.oidcAuth(route, h)(&c)
+		return c.Response.StatusCode()
+	}
+
+	readerRSA := signJWT(t, "RS256", "rsa", rsaKey, claims("readers"))
+	writerEC := signJWT(t, "ES256", "ec", ecKey, claims("writers"))
+
+	require.Equal(t, fasthttp.StatusOK, serve(routeGet, "any", readerRSA))
+	require.Empty(t, authorization)
+	require.Equal(t, fasthttp.StatusForbidden, serve(routeUpload, "cnr", readerRSA))
+	require.Equal(t, fasthttp.StatusOK, serve(routeUpload, "cnr", writerEC))
+	require.Equal(t, fasthttp.StatusForbidden, serve(routeZip, "other", writerEC))
+	require.Equal(t, fasthttp.StatusUnauthorized, serve(routeGet, "any", ""))
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		expired := claims("readers")
+		expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		otherAudience := claims("readers")
+		otherAudience["aud"] = "other"
+
+		otherIssuer := claims("readers")
+		otherIssuer["iss"] = "https://example.com"
+
+		for name, token := range map[string]string{
+			"expired":        signJWT(t, "RS256", "rsa", rsaKey, expired),
+			"audience":       signJWT(t, "RS256", "rsa", rsaKey, otherAudience),
+			"issuer":         signJWT(t, "RS256", "rsa", rsaKey, otherIssuer),
+			"key mismatch":   signJWT(t, "RS256", "ec", rsaKey, claims("readers")),
+			"unknown key":    signJWT(t, "RS256", "other", rsaKey, claims("readers")),
+			"tampered":       readerRSA[:len(readerRSA)-4] + "AAAA",
+			"none algorithm": b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{}`)) + ".",
+			"malformed":      "token",
+		} {
+			require.Equal(t, fasthttp.StatusUnauthorized, serve(routeGet, "any", token), name)
+		}
+	})
+}
@@ -0,0 +1,867 @@
+package gateway
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// jsonObject is a JSON object of OpenAPI document.
+type jsonObject = map[string]interface{}
+
+// Security scheme names of OpenAPI document.
+const (
+	schemeNeoFSBearer       = "neofsBearerToken"
+	schemeNeoFSBearerCookie = "neofsBearerCookie"
+	schemeBasicAuth         = "basicAuth"
+	schemeOIDC              = "oidc"
+	schemeAPIKey            = "apiKey"
+	schemeAdminToken        = "adminToken"
+)
+
+func pathParam(name, description string) jsonObject {
+	return jsonObject{
+		"name":        name,
+		"in":          "path",
+		"required":    true,
+		"description": description,
+		"schema":      jsonObject{"type": "string"},
+	}
+}
+
+func headerParam(name, description string) jsonObject {
+	return jsonObject{
+		"name":        name,
+		"in":          "header",
+		"description": description,
+		"schema":      jsonObject{"type": "string"},
+	}
+}
+
+func queryParam(name, description, typ string) jsonObject {
+	return jsonObject{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      jsonObject{"type": typ},
+	}
+}
+
+func errorResponse(description string) jsonObject {
+	return jsonObject{
+		"description": description,
+		"content":     jsonObject{"text/plain": jsonObject{"schema": jsonObject{"type": "string"}}},
+	}
+}
+
+func jsonResponse(description string, schema jsonObject) jsonObject {
+	return jsonObject{
+		"description": description,
+		"content":     jsonObject{"application/json": jsonObject{"schema": schema}},
+	}
+}
+
+// gatewayErrors are the responses of middlewares common to all gateway routes.
+func gatewayErrors() jsonObject {
+	return jsonObject{
+		"400": errorResponse("Invalid request"),
+		"401": errorResponse("Authentication required"),
+		"403": errorResponse("Access denied"),
+		"429": errorResponse("Too many requests, see Retry-After header"),
+		"503": errorResponse("Gateway is in maintenance or drain mode (see Retry-After header) or lacks healthy nodes"),
+		"504": errorResponse("Request timeout"),
+	}
+}
+
+// withGatewayErrors adds common error responses to the route ones.
+func withGatewayErrors(responses jsonObject) jsonObject {
+	for code, resp := range gatewayErrors() {
+		if _, ok := responses[code]; !ok {
+			responses[code] = resp
+		}
+	}
+	return responses
+}
+
+// gatewaySecurity returns security requirements of the route: every
+// configured gateway authentication method (optionally with NeoFS bearer
+// token in cookie) or optional NeoFS bearer token if there are none.
+func (a *app) gatewaySecurity(route string) []jsonObject {
+	var schemes []string
+	if routeOperation(route) == operationUpload && (a.cfg.GetString(cfgBasicAuthUser) != "" || a.cfg.GetString(cfgBasicAuthHtpasswd) != "") {
+		schemes = append(schemes, schemeBasicAuth)
+	}
+	if a.oidc != nil {
+		schemes = append(schemes, schemeOIDC)
+	}
+	if a.apiKeys != nil {
+		schemes = append(schemes, schemeAPIKey)
+	}
+
+	if len(schemes) == 0 {
+		return []jsonObject{{}, {schemeNeoFSBearer: []string{}}, {schemeNeoFSBearerCookie: []string{}}}
+	}
+
+	res := make([]jsonObject, 0, 2*len(schemes))
+	for _, scheme := range schemes {
+		res = append(res,
+			jsonObject{scheme: []string{}},
+			jsonObject{scheme: []string{}, schemeNeoFSBearerCookie: []string{}},
+		)
+	}
+	return res
+}
+
+func (a *app) securitySchemes() jsonObject {
+	schemes := jsonObject{
+		schemeNeoFSBearer: jsonObject{
+			"type":        "http",
+			"scheme":      "bearer",
+			"description": "NeoFS bearer token (base64-encoded) to sign requests with",
+		},
+		schemeNeoFSBearerCookie: jsonObject{
+			"type":        "apiKey",
+			"in":          "cookie",
+			"name":        "Bearer",
+			"description": "NeoFS bearer token (base64-encoded) to sign requests with",
+		},
+	}
+	if a.cfg.GetString(cfgBasicAuthUser) != "" || a.cfg.GetString(cfgBasicAuthHtpasswd) != "" {
+		schemes[schemeBasicAuth] = jsonObject{"type": "http", "scheme": "basic"}
+	}
+	if a.oidc != nil {
+		schemes[schemeOIDC] = jsonObject{
+			"type":             "openIdConnect",
+			"openIdConnectUrl": strings.TrimSuffix(a.cfg.GetString(cfgOIDCIssuer), "/") + "/.well-known/openid-configuration",
+		}
+	}
+	if a.apiKeys != nil {
+		schemes[schemeAPIKey] = jsonObject{"type": "apiKey", "in": "header", "name": hdrAPIKey}
+	}
+	if a.secret(cfgAdminToken) != "" {
+		schemes[schemeAdminToken] = jsonObject{"type": "http", "scheme": "bearer", "description": "Admin API token"}
+	}
+	return schemes
+}
+
+func (a *app) downloadOperation(route, summary string, params []jsonObject, head bool) jsonObject {
+	responses := jsonObject{
+		"200": jsonObject{
+			"description": "Object payload with attributes in X-Attribute-* headers",
+			"headers": jsonObject{
+				"X-Object-Id":    jsonObject{"schema": jsonObject{"type": "string"}},
+				"X-Owner-Id":     jsonObject{"schema": jsonObject{"type": "string"}},
+				"X-Container-Id": jsonObject{"schema": jsonObject{"type": "string"}},
+			},
+			"content": jsonObject{"application/octet-stream": jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}}},
+		},
+		"404": errorResponse("Object not found"),
+	}
+	if route == routeByAddress {
+		headers := responses["200"].(jsonObject)["headers"].(jsonObject)
+		headers["ETag"] = jsonObject{"description": "Hex-encoded payload checksum", "schema": jsonObject{"type": "string"}}
+		headers["Cache-Control"] = jsonObject{"schema": jsonObject{"type": "string"}}
+		params = append(params, headerParam("If-None-Match", "ETag of the cached object"))
+		responses["304"] = jsonObject{"description": "Object isn't modified"}
+	}
+	if head {
+		responses["200"].(jsonObject)["description"] = "Object attributes in X-Attribute-* headers"
+		delete(responses["200"].(jsonObject), "content")
+	} else {
+		params = append(params, queryParam("download", "Set Content-Disposition to attachment", "boolean"))
+		if route == routeGetByAttribute {
+			params = append(params, queryParam("list", "Return JSON list of matching objects", "boolean"),
+				queryParam("offset", "Number of matching objects to skip in the list", "integer"),
+				queryParam("limit", "Maximum number of objects in the list", "integer"),
+				queryParam("attributes", "Comma-separated attributes of objects to list", "string"))
+			responses["200"].(jsonObject)["description"] = "Object payload or the list of matching objects"
+		}
+	}
+
+	params = append(params, a.identityParams()...)
+	return jsonObject{
+		"summary":    summary,
+		"parameters": params,
+		"responses":  withGatewayErrors(responses),
+		"security":   a.gatewaySecurity(route),
+	}
+}
+
+func (a *app) identityParams() []jsonObject {
+	if a.identities == nil || !a.identities.header {
+		return nil
+	}
+	return []jsonObject{headerParam(hdrIdentity, "Name of the gateway identity to sign requests with")}
+}
+
+// openAPIDocument describes the routes enabled in the configuration as
+// OpenAPI 3 document.
+func (a *app) openAPIDocument() jsonObject {
+	cid := pathParam("cid", "Container ID or NNS name")
+	oid := pathParam("oid", "Object ID")
+	cidID := pathParam("cid", "Container ID")
+	attrKey := pathParam("attr_key", "Attribute key")
+	attrVal := pathParam("attr_val", "Attribute value")
+	statusSchema := jsonObject{"type": "string"}
+	checksumSchema := jsonObject{
+		"type": "object",
+		"properties": jsonObject{
+			"type":  jsonObject{"type": "string"},
+			"value": jsonObject{"type": "string"},
+		},
+	}
+
+	attributeParams := []jsonObject{
+		cid,
+		headerParam(utils.UserAttributeHeaderPrefix+"*", "Object attributes, e.g. X-Attribute-FilePath"),
+		headerParam(utils.UserAttributeHeaderPrefix+"Neofs-Expiration-Epoch", "Epoch the object expires at"),
+		headerParam(utils.UserAttributeHeaderPrefix+"Neofs-Expiration-Duration", "Duration the object expires in, e.g. 24h"),
+		headerParam(utils.UserAttributeHeaderPrefix+"Neofs-Expiration-Timestamp", "Unix timestamp the object expires at"),
+		headerParam(utils.UserAttributeHeaderPrefix+"Neofs-Expiration-RFC3339", "RFC3339 time the object expires at"),
+	}
+	attributeParams = append(attributeParams, a.identityParams()...)
+	uploadParams := append([]jsonObject{}, attributeParams...)
+	uploadParams = append(uploadParams,
+		headerParam("X-Neofs-Lock-Until-Epoch", "Protect the object from deletion with LOCK object until the epoch"),
+		headerParam("X-Neofs-Lock-Duration", "Protect the object from deletion with LOCK object for the duration, e.g. 720h"),
+		queryParam("async", "Put the object in background and reply with the job", "boolean"),
+		queryParam("progress_id", "ID to get the upload progress at /progress/{id} with", "string"),
+	)
+
+	jobSchema := jsonObject{
+		"type": "object",
+		"properties": jsonObject{
+			"job_id":       jsonObject{"type": "string"},
+			"status":       jsonObject{"type": "string", "enum": []string{"pending", "done", "failed"}},
+			"container_id": jsonObject{"type": "string"},
+			"object_id":    jsonObject{"type": "string"},
+			"error":        jsonObject{"type": "string"},
+		},
+	}
+
+	gatewayPaths := jsonObject{
+		"/upload/{cid}": jsonObject{
+			"post": jsonObject{
+				"summary":    "Upload object",
+				"parameters": uploadParams,
+				"requestBody": jsonObject{
+					"required": true,
+					"content": jsonObject{"multipart/form-data": jsonObject{"schema": jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"file": jsonObject{"type": "string", "format": "binary"},
+						},
+					}}},
+				},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Object is uploaded", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":          jsonObject{"type": "string"},
+							"container_id":       jsonObject{"type": "string"},
+							"deduplicated":       jsonObject{"type": "boolean"},
+							"lock_id":            jsonObject{"type": "string"},
+							"locked_until_epoch": jsonObject{"type": "integer"},
+						},
+					}),
+					"202": jsonResponse("Upload job is created", jobSchema),
+					"413": errorResponse("Object is too large"),
+				}),
+				"security": a.gatewaySecurity(routeUpload),
+			},
+		},
+		"/copy/{cid}/{oid}": jsonObject{
+			"post": jsonObject{
+				"summary": "Copy object to the target container",
+				"parameters": append([]jsonObject{oid, queryParam("to", "Target container ID or NNS name", "string")},
+					attributeParams...),
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Object is copied", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":    jsonObject{"type": "string"},
+							"container_id": jsonObject{"type": "string"},
+						},
+					}),
+					"404": errorResponse("Object not found"),
+					"413": errorResponse("Object is too large"),
+				}),
+				"security": a.gatewaySecurity(routeCopy),
+			},
+		},
+		"/storagegroup/{cid}": jsonObject{
+			"post": jsonObject{
+				"summary":    "Create storage group of the objects for data audit",
+				"parameters": attributeParams,
+				"requestBody": jsonObject{
+					"required": true,
+					"content": jsonObject{"application/json": jsonObject{"schema": jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"members":          jsonObject{"type": "array", "items": jsonObject{"type": "string"}},
+							"expiration_epoch": jsonObject{"type": "integer"},
+							"lifetime":         jsonObject{"type": "string"},
+						},
+					}}},
+				},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Storage group is stored", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":    jsonObject{"type": "string"},
+							"container_id": jsonObject{"type": "string"},
+						},
+					}),
+				}),
+				"security": a.gatewaySecurity(routeStorageGroup),
+			},
+		},
+		"/jobs/{id}": jsonObject{
+			"get": jsonObject{
+				"summary":    "State of asynchronous upload",
+				"parameters": []jsonObject{pathParam("id", "Job ID")},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Upload job", jobSchema),
+					"404": errorResponse("Job not found"),
+				}),
+				"security": a.gatewaySecurity(routeJobs),
+			},
+		},
+		"/progress/{id}": jsonObject{
+			"get": jsonObject{
+				"summary":    "Upload progress as Server-Sent Events",
+				"parameters": []jsonObject{pathParam("id", "Progress ID given on upload")},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonObject{
+						"description": "Events with JSON data: status, received, stored, total and error",
+						"content":     jsonObject{"text/event-stream": jsonObject{"schema": jsonObject{"type": "string"}}},
+					},
+					"404": errorResponse("Upload progress is disabled"),
+				}),
+				"security": a.gatewaySecurity(routeProgress),
+			},
+		},
+		"/get/{cid}/{oid}": jsonObject{
+			"get":  a.downloadOperation(routeGet, "Download object", []jsonObject{cid, oid}, false),
+			"head": a.downloadOperation(routeGet, "Get object attributes", []jsonObject{cid, oid}, true),
+		},
+		"/by-address/{cid}/{oid}": jsonObject{
+			"get":  a.downloadOperation(routeByAddress, "Download object with immutable caching headers", []jsonObject{cidID, oid}, false),
+			"head": a.downloadOperation(routeByAddress, "Get object attributes with immutable caching headers", []jsonObject{cidID, oid}, true),
+		},
+		"/get_by_attribute/{cid}/{attr_key}/{attr_val}": jsonObject{
+			"get":  a.downloadOperation(routeGetByAttribute, "Download object by attribute", []jsonObject{cid, attrKey, attrVal}, false),
+			"head": a.downloadOperation(routeGetByAttribute, "Get attributes of object found by attribute", []jsonObject{cid, attrKey, attrVal}, true),
+		},
+		"/get_by_attribute/{cid}": jsonObject{
+			"get":  a.downloadOperation(routeGetByAttribute, "Download object having all attributes from query arguments", []jsonObject{cid}, false),
+			"head": a.downloadOperation(routeGetByAttribute, "Get attributes of object having all attributes from query arguments", []jsonObject{cid}, true),
+		},
+		"/zip/{cid}/{prefix}": jsonObject{
+			"get": jsonObject{
+				"summary":    "Download objects with the FilePath prefix as zip archive",
+				"parameters": append([]jsonObject{cid, pathParam("prefix", "FilePath prefix")}, a.identityParams()...),
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonObject{
+						"description": "Zip archive",
+						"content":     jsonObject{"application/zip": jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}}},
+					},
+					"404": errorResponse("No objects found"),
+				}),
+				"security": a.gatewaySecurity(routeZip),
+			},
+		},
+		"/search/{cid}": jsonObject{
+			"post": jsonObject{
+				"summary":    "Search for objects by attributes",
+				"parameters": append([]jsonObject{cid}, a.identityParams()...),
+				"requestBody": jsonObject{
+					"required": true,
+					"content": jsonObject{"application/json": jsonObject{"schema": jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"filters": jsonObject{"type": "array", "items": jsonObject{
+								"type": "object",
+								"properties": jsonObject{
+									"match": jsonObject{"type": "string", "enum": []string{"string_equal", "string_not_equal", "not_present", "common_prefix"}},
+									"key":   jsonObject{"type": "string"},
+									"value": jsonObject{"type": "string"},
+								},
+								"required": []string{"key"},
+							}},
+							"attributes": jsonObject{"type": "array", "items": jsonObject{"type": "string"}},
+							"offset":     jsonObject{"type": "integer", "minimum": 0},
+							"limit":      jsonObject{"type": "integer", "minimum": 0, "maximum": 1000},
+						},
+					}}},
+				},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Found objects", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"objects": jsonObject{"type": "array", "items": jsonObject{
+								"type": "object",
+								"properties": jsonObject{
+									"object_id":  jsonObject{"type": "string"},
+									"attributes": jsonObject{"type": "object", "additionalProperties": jsonObject{"type": "string"}},
+								},
+							}},
+							"next_offset": jsonObject{"type": "integer"},
+						},
+					}),
+				}),
+				"security": a.gatewaySecurity(routeSearch),
+			},
+		},
+		"/containers": jsonObject{
+			"get": jsonObject{
+				"summary": "List containers of the owner",
+				"parameters": []jsonObject{{
+					"name":        "owner",
+					"in":          "query",
+					"required":    true,
+					"description": "Owner account address",
+					"schema":      jsonObject{"type": "string"},
+				}},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Containers with names if they're resolvable", jsonObject{
+						"type": "array",
+						"items": jsonObject{
+							"type": "object",
+							"properties": jsonObject{
+								"container_id": jsonObject{"type": "string"},
+								"name":         jsonObject{"type": "string"},
+							},
+						},
+					}),
+				}),
+				"security": a.gatewaySecurity(routeContainers),
+			},
+		},
+		"/container/{cid}": jsonObject{
+			"get": jsonObject{
+				"summary":    "Container information",
+				"parameters": []jsonObject{cid},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Container information", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"container_id":     jsonObject{"type": "string"},
+							"owner":            jsonObject{"type": "string"},
+							"basic_acl":        jsonObject{"type": "string"},
+							"placement_policy": jsonObject{"type": "string"},
+							"attributes":       jsonObject{"type": "object", "additionalProperties": jsonObject{"type": "string"}},
+						},
+					}),
+					"404": errorResponse("Container not found"),
+				}),
+				"security": a.gatewaySecurity(routeContainer),
+			},
+		},
+		"/container/{cid}/eacl": jsonObject{
+			"get": jsonObject{
+				"summary":    "Extended ACL table of the container",
+				"parameters": []jsonObject{cid},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Extended ACL table in NeoFS JSON format", jsonObject{"type": "object"}),
+					"404": errorResponse("Extended ACL not found"),
+				}),
+				"security": a.gatewaySecurity(routeContainerEACL),
+			},
+		},
+		"/attributes/{cid}/{oid}": jsonObject{
+			"get": jsonObject{
+				"summary":    "Object header",
+				"parameters": append([]jsonObject{cid, oid}, a.identityParams()...),
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Object header", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":        jsonObject{"type": "string"},
+							"container_id":     jsonObject{"type": "string"},
+							"owner":            jsonObject{"type": "string"},
+							"type":             jsonObject{"type": "string"},
+							"creation_epoch":   jsonObject{"type": "integer"},
+							"payload_size":     jsonObject{"type": "integer"},
+							"payload_checksum": checksumSchema,
+							"homomorphic_hash": checksumSchema,
+							"attributes":       jsonObject{"type": "object", "additionalProperties": jsonObject{"type": "string"}},
+						},
+					}),
+					"404": errorResponse("Object not found"),
+				}),
+				"security": a.gatewaySecurity(routeAttributes),
+			},
+		},
+		"/checksum/{cid}/{oid}": jsonObject{
+			"get": jsonObject{
+				"summary":    "Object payload hashes",
+				"parameters": append([]jsonObject{cid, oid}, a.identityParams()...),
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Hex-encoded SHA-256 and homomorphic (Tillich-Zemor) hashes of the payload", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":    jsonObject{"type": "string"},
+							"container_id": jsonObject{"type": "string"},
+							"payload_size": jsonObject{"type": "integer"},
+							"sha256":       jsonObject{"type": "string"},
+							"tz":           jsonObject{"type": "string"},
+						},
+					}),
+					"404": errorResponse("Object not found"),
+				}),
+				"security": a.gatewaySecurity(routeChecksum),
+			},
+		},
+		"/archive/{cid}": jsonObject{
+			"post": jsonObject{
+				"summary":    "Download the listed objects as zip or tar archive",
+				"parameters": append([]jsonObject{cid}, a.identityParams()...),
+				"requestBody": jsonObject{
+					"required": true,
+					"content": jsonObject{"application/json": jsonObject{"schema": jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"format":  jsonObject{"type": "string", "enum": []string{"zip", "tar"}},
+							"objects": jsonObject{"type": "array", "items": jsonObject{"type": "string"}},
+							"attributes": jsonObject{"type": "array", "items": jsonObject{
+								"type":                 "object",
+								"additionalProperties": jsonObject{"type": "string"},
+							}},
+						},
+					}}},
+				},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonObject{
+						"description": "Archive",
+						"content": jsonObject{
+							"application/zip":   jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}},
+							"application/x-tar": jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}},
+						},
+					},
+				}),
+				"security": a.gatewaySecurity(routeArchive),
+			},
+		},
+	}
+
+	paths := jsonObject{
+		"/-/ready": jsonObject{
+			"get": jsonObject{
+				"summary": "Readiness probe",
+				"responses": jsonObject{
+					"200": jsonObject{"description": "Gateway accepts requests", "content": jsonObject{"text/plain": jsonObject{"schema": statusSchema}}},
+					"503": errorResponse("Gateway is in maintenance or drain mode or lacks healthy nodes"),
+				},
+			},
+		},
+		"/-/version": jsonObject{
+			"get": jsonObject{
+				"summary": "Build information",
+				"responses": jsonObject{
+					"200": jsonResponse("Build information", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"version":     jsonObject{"type": "string"},
+							"commit":      jsonObject{"type": "string"},
+							"go_version":  jsonObject{"type": "string"},
+							"sdk_version": jsonObject{"type": "string"},
+							"features":    jsonObject{"type": "array", "items": jsonObject{"type": "string"}},
+						},
+					}),
+				},
+			},
+		},
+		"/-/openapi.json": jsonObject{
+			"get": jsonObject{
+				"summary":   "OpenAPI document",
+				"responses": jsonObject{"200": jsonResponse("This document", jsonObject{"type": "object"})},
+			},
+		},
+		"/-/network": jsonObject{
+			"get": jsonObject{
+				"summary": "Network state",
+				"responses": jsonObject{
+					"200": jsonResponse("Current epoch, epoch duration, maximum object size and fees", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"epoch":                 jsonObject{"type": "integer"},
+							"ms_per_block":          jsonObject{"type": "integer"},
+							"epoch_duration_blocks": jsonObject{"type": "integer"},
+							"epoch_duration":        jsonObject{"type": "string"},
+							"max_object_size":       jsonObject{"type": "integer"},
+							"fees": jsonObject{
+								"type": "object",
+								"properties": jsonObject{
+									"storage_price":   jsonObject{"type": "integer"},
+									"audit":           jsonObject{"type": "integer"},
+									"container":       jsonObject{"type": "integer"},
+									"container_alias": jsonObject{"type": "integer"},
+									"withdraw":        jsonObject{"type": "integer"},
+								},
+							},
+						},
+					}),
+					"502": errorResponse("Network info isn't available"),
+				},
+			},
+		},
+	}
+
+	if a.cfg.GetBool(cfgUploaderHeaderURLPath) {
+		upload := make(jsonObject)
+		for k, v := range gatewayPaths["/upload/{cid}"].(jsonObject)["post"].(jsonObject) {
+			upload[k] = v
+		}
+		upload["summary"] = "Upload object with FilePath attribute set from the path"
+		upload["parameters"] = append([]jsonObject{
+			pathParam("path", "FilePath attribute, the path ending with a slash is the directory of the file"),
+		}, uploadParams...)
+		gatewayPaths["/upload/{cid}/{path}"] = jsonObject{"post": upload}
+	}
+
+	if a.cfg.GetBool(cfgUploadMoveEnabled) {
+		gatewayPaths["/move/{cid}/{oid}"] = jsonObject{
+			"post": jsonObject{
+				"summary": "Copy object with new attributes and delete the source one",
+				"parameters": append([]jsonObject{oid, queryParam("to", "Target container ID or NNS name, the source one by default", "string")},
+					attributeParams...),
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Object is moved", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"object_id":    jsonObject{"type": "string"},
+							"container_id": jsonObject{"type": "string"},
+						},
+					}),
+					"404": errorResponse("Object not found"),
+					"413": errorResponse("Object is too large"),
+					"500": errorResponse("Object is copied, but the source one isn't deleted"),
+				}),
+				"security": a.gatewaySecurity(routeMove),
+			},
+		}
+	}
+
+	if a.cfg.GetBool(cfgContainerCreationEnabled) {
+		gatewayPaths["/container"] = jsonObject{
+			"put": jsonObject{
+				"summary": "Create container",
+				"parameters": []jsonObject{
+					headerParam("X-Neofs-Session-Token", "Base64-encoded session token of container creation issued to the gateway key"),
+				},
+				"requestBody": jsonObject{
+					"required": true,
+					"content": jsonObject{"application/json": jsonObject{"schema": jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"placement_policy": jsonObject{"type": "string"},
+							"basic_acl":        jsonObject{"type": "string"},
+							"attributes":       jsonObject{"type": "object", "additionalProperties": jsonObject{"type": "string"}},
+							"name":             jsonObject{"type": "string"},
+							"zone":             jsonObject{"type": "string"},
+						},
+						"required": []string{"placement_policy"},
+					}}},
+				},
+				"responses": withGatewayErrors(jsonObject{
+					"200": jsonResponse("Container is created", jsonObject{
+						"type": "object",
+						"properties": jsonObject{
+							"container_id": jsonObject{"type": "string"},
+							"name":         jsonObject{"type": "string"},
+						},
+					}),
+					"504": errorResponse("Container is requested, but it isn't persisted yet"),
+				}),
+				"security": a.gatewaySecurity(routeCreateContainer),
+			},
+		}
+	}
+
+	if a.cfg.GetBool(cfgContainerDeletionEnabled) {
+		gatewayPaths["/container/{cid}"].(jsonObject)["delete"] = jsonObject{
+			"summary": "Delete container",
+			"parameters": []jsonObject{
+				cid,
+				headerParam("X-Neofs-Session-Token", "Base64-encoded session token of container deletion issued to the gateway key"),
+			},
+			"responses": withGatewayErrors(jsonObject{
+				"204": jsonObject{"description": "Container is deleted"},
+				"403": errorResponse("Container isn't owned by the gateway or the session token issuer"),
+				"404": errorResponse("Container not found"),
+				"504": errorResponse("Container deletion is requested, but it isn't removed yet"),
+			}),
+			"security": a.gatewaySecurity(routeDeleteContainer),
+		}
+	}
+
+	if a.cfg.GetBool(cfgContainerEACLEnabled) {
+		gatewayPaths["/container/{cid}/eacl"].(jsonObject)["put"] = jsonObject{
+			"summary": "Set extended ACL table of the container",
+			"parameters": []jsonObject{
+				cid,
+				headerParam("X-Neofs-Session-Token", "Base64-encoded session token of eACL change issued to the gateway key"),
+			},
+			"requestBody": jsonObject{
+				"required": true,
+				"content":  jsonObject{"application/json": jsonObject{"schema": jsonObject{"type": "object"}}},
+			},
+			"responses": withGatewayErrors(jsonObject{
+				"204": jsonObject{"description": "Extended ACL is applied"},
+				"403": errorResponse("Container isn't owned by the gateway or the session token issuer"),
+				"404": errorResponse("Container not found"),
+				"504": errorResponse("Extended ACL change is requested, but it isn't applied yet"),
+			}),
+			"security": a.gatewaySecurity(routeSetContainerEACL),
+		}
+	}
+
+	for path, item := range gatewayPaths {
+		if a.cfg.GetBool(cfgLegacyRoutes) {
+			paths[path] = item
+		}
+		paths[apiV2Prefix+path] = item
+	}
+
+	if a.cfg.GetBool(cfgUsageEnabled) {
+		usage := jsonObject{
+			"summary": "Traffic and requests per container since the gateway start",
+			"responses": jsonObject{
+				"200": jsonResponse("Usage report", jsonObject{
+					"type": "object",
+					"properties": jsonObject{
+						"since": jsonObject{"type": "string", "format": "date-time"},
+						"containers": jsonObject{
+							"type": "object",
+							"additionalProperties": jsonObject{
+								"type": "object",
+								"properties": jsonObject{
+									"ingress_bytes": jsonObject{"type": "integer"},
+									"egress_bytes":  jsonObject{"type": "integer"},
+									"uploads":       jsonObject{"type": "integer"},
+									"downloads":     jsonObject{"type": "integer"},
+								},
+							},
+						},
+					},
+				}),
+			},
+		}
+		if a.secret(cfgAdminToken) != "" {
+			usage["security"] = []jsonObject{{schemeAdminToken: []string{}}}
+			usage["responses"].(jsonObject)["403"] = errorResponse("Invalid admin token")
+		}
+		paths["/-/usage"] = jsonObject{"get": usage}
+	}
+
+	if a.cfg.GetBool(cmdMetrics) {
+		paths["/metrics/"] = jsonObject{
+			"get": jsonObject{
+				"summary":   "Prometheus metrics",
+				"responses": jsonObject{"200": jsonObject{"description": "Metrics in Prometheus text format"}},
+			},
+		}
+	}
+
+	if a.secret(cfgAdminToken) != "" {
+		a.addAdminPaths(paths)
+	}
+
+	return jsonObject{
+		"openapi": "3.0.3",
+		"info": jsonObject{
+			"title":   "NeoFS HTTP Gateway",
+			"version": Version,
+		},
+		"paths":      paths,
+		"components": jsonObject{"securitySchemes": a.securitySchemes()},
+	}
+}
+
+func (a *app) addAdminPaths(paths jsonObject) {
+	security := []jsonObject{{schemeAdminToken: []string{}}}
+	denied := errorResponse("Invalid admin token")
+	ok := jsonObject{"description": "Done"}
+
+	paths["/admin/reload_key"] = jsonObject{
+		"post": jsonObject{
+			"summary":   "Reload gateway key",
+			"security":  security,
+			"responses": jsonObject{"200": ok, "403": denied, "500": errorResponse("Key isn't reloaded")},
+		},
+	}
+	paths["/admin/peers"] = jsonObject{
+		"get": jsonObject{
+			"summary":  "State of NeoFS nodes",
+			"security": security,
+			"responses": jsonObject{
+				"200": jsonResponse("Node states", jsonObject{"type": "array", "items": jsonObject{"type": "object"}}),
+				"403": denied,
+			},
+		},
+	}
+	paths["/admin/balance"] = jsonObject{
+		"get": jsonObject{
+			"summary":  "Balance of the gateway account",
+			"security": security,
+			"responses": jsonObject{
+				"200": jsonResponse("NeoFS balance and GAS balance if RPC endpoint is set", jsonObject{"type": "object"}),
+				"403": denied,
+				"502": errorResponse("Balance isn't available"),
+			},
+		},
+	}
+	paths["/admin/config"] = jsonObject{
+		"get": jsonObject{
+			"summary":  "Effective configuration with secrets redacted",
+			"security": security,
+			"responses": jsonObject{
+				"200": jsonResponse("Configuration", jsonObject{"type": "object"}),
+				"403": denied,
+			},
+		},
+		"patch": jsonObject{
+			"summary":  "Change settings at runtime",
+			"security": security,
+			"requestBody": jsonObject{
+				"required": true,
+				"content":  jsonObject{"application/json": jsonObject{"schema": jsonObject{"type": "object"}}},
+			},
+			"responses": jsonObject{"200": ok, "400": errorResponse("Invalid settings"), "403": denied},
+		},
+	}
+	paths["/admin/drain"] = jsonObject{
+		"post": jsonObject{
+			"summary":   "Enable drain mode",
+			"security":  security,
+			"responses": jsonObject{"200": ok, "403": denied},
+		},
+		"delete": jsonObject{
+			"summary":   "Disable drain mode",
+			"security":  security,
+			"responses": jsonObject{"200": ok, "403": denied},
+		},
+	}
+}
+
+// openAPIHandler returns the handler of /-/openapi.json with the document
+// generated once.
+func (a *app) openAPIHandler() fasthttp.RequestHandler {
+	data, err := json.Marshal(a.openAPIDocument())
+	if err != nil {
+		a.log.Error("could not encode OpenAPI document", zap.Error(err))
+		return func(c *fasthttp.RequestCtx) {
+			response.Error(c, "could not encode OpenAPI document", fasthttp.StatusInternalServerError)
+		}
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		c.SetContentType("application/json")
+		c.SetBody(data)
+	}
+}
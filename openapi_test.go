@@ -0,0 +1,140 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	getDocument := func(t *testing.T, a *app) map[string]interface{} {
+		var c fasthttp.RequestCtx
+		a.openAPIHandler()(&c)
+		require.Equal(t, "application/json", string(c.Response.Header.ContentType()))
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(c.Response.Body(), &doc))
+		require.Equal(t, "3.0.3", doc["openapi"])
+		return doc
+	}
+
+	t.Run("default", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgLegacyRoutes, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths, "/upload/{cid}")
+		require.Contains(t, paths, "/get/{cid}/{oid}")
+		require.Contains(t, paths, "/get_by_attribute/{cid}/{attr_key}/{attr_val}")
+		require.Contains(t, paths, "/v2/get_by_attribute/{cid}")
+		require.Contains(t, paths, "/zip/{cid}/{prefix}")
+		require.Contains(t, paths, "/v2/upload/{cid}")
+		require.Contains(t, paths, "/v2/copy/{cid}/{oid}")
+		require.NotContains(t, paths, "/v2/move/{cid}/{oid}")
+		require.Contains(t, paths, "/v2/storagegroup/{cid}")
+		require.Contains(t, paths, "/v2/zip/{cid}/{prefix}")
+		require.Contains(t, paths, "/v2/search/{cid}")
+		require.Contains(t, paths, "/v2/containers")
+		require.Contains(t, paths, "/v2/container/{cid}")
+		require.Contains(t, paths, "/v2/container/{cid}/eacl")
+		require.NotContains(t, paths["/v2/container/{cid}/eacl"], "put")
+		require.Contains(t, paths, "/v2/attributes/{cid}/{oid}")
+		require.Contains(t, paths, "/v2/checksum/{cid}/{oid}")
+		require.Contains(t, paths, "/v2/by-address/{cid}/{oid}")
+		require.Contains(t, paths, "/v2/archive/{cid}")
+		require.Contains(t, paths, "/v2/jobs/{id}")
+		require.Contains(t, paths, "/v2/progress/{id}")
+		require.NotContains(t, paths, "/v2/container")
+		require.Contains(t, paths, "/-/network")
+		require.NotContains(t, paths, "/-/usage")
+		require.NotContains(t, paths, "/admin/config")
+		require.NotContains(t, paths, "/metrics/")
+
+		upload := paths["/upload/{cid}"].(map[string]interface{})["post"].(map[string]interface{})
+		require.Len(t, upload["security"], 3)
+	})
+
+	t.Run("upload with path", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgUploaderHeaderURLPath, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths, "/v2/upload/{cid}")
+		upload := paths["/v2/upload/{cid}/{path}"].(map[string]interface{})["post"].(map[string]interface{})
+		require.Len(t, upload["parameters"], len(paths["/v2/upload/{cid}"].(map[string]interface{})["post"].(map[string]interface{})["parameters"].([]interface{}))+1)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgUploadMoveEnabled, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths["/v2/move/{cid}/{oid}"], "post")
+	})
+
+	t.Run("container creation", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgContainerCreationEnabled, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths["/v2/container"], "put")
+		require.NotContains(t, paths["/v2/container/{cid}"], "delete")
+	})
+
+	t.Run("container deletion", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgContainerDeletionEnabled, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths["/v2/container/{cid}"], "get")
+		require.Contains(t, paths["/v2/container/{cid}"], "delete")
+		require.NotContains(t, paths, "/v2/container")
+	})
+
+	t.Run("eacl changes", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgContainerEACLEnabled, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop()})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths["/v2/container/{cid}/eacl"], "get")
+		require.Contains(t, paths["/v2/container/{cid}/eacl"], "put")
+	})
+
+	t.Run("auth and admin", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgBasicAuthUser, "user")
+		v.Set(cfgAdminToken, "secret")
+		v.Set(cmdMetrics, true)
+		v.Set(cfgUsageEnabled, true)
+		doc := getDocument(t, &app{cfg: v, log: zap.NewNop(), apiKeys: &apiKeyStore{}})
+
+		paths := doc["paths"].(map[string]interface{})
+		require.Contains(t, paths, "/admin/config")
+		require.Contains(t, paths, "/admin/balance")
+		require.Contains(t, paths["/-/usage"].(map[string]interface{})["get"], "security")
+		require.Contains(t, paths, "/metrics/")
+		require.NotContains(t, paths, "/upload/{cid}")
+		require.NotContains(t, paths, "/v2/upload/{cid}/{path}")
+
+		schemes := doc["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+		require.Contains(t, schemes, schemeBasicAuth)
+		require.Contains(t, schemes, schemeAPIKey)
+		require.Contains(t, schemes, schemeAdminToken)
+		require.NotContains(t, schemes, schemeOIDC)
+
+		upload := paths["/v2/upload/{cid}"].(map[string]interface{})["post"].(map[string]interface{})
+		require.Len(t, upload["security"], 4)
+		get := paths["/v2/get/{cid}/{oid}"].(map[string]interface{})["get"].(map[string]interface{})
+		require.Len(t, get["security"], 2)
+	})
+}
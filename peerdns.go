@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// peerResolver re-resolves host names of peers and reconnects to them when
+// their addresses change, so nodes behind dynamic DNS or Kubernetes services
+// can be moved.
+type peerResolver struct {
+	log      *zap.Logger
+	interval time.Duration
+	hosts    []string
+
+	// lookup is replaced in tests.
+	lookup func(ctx context.Context, host string) ([]string, error)
+	// changed is called when addresses of some host are changed.
+	changed func()
+	trigger chan struct{}
+	// addrs are sorted addresses of hosts joined with commas.
+	addrs map[string]string
+}
+
+// peerHost returns the host name of the peer address, it's empty if the peer
+// is specified by IP address.
+func peerHost(address string) string {
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return ""
+	}
+	return host
+}
+
+// newPeerResolver returns nil if re-resolution is disabled or all peers are
+// specified by IP addresses.
+func (a *app) newPeerResolver() *peerResolver {
+	interval := a.cfg.GetDuration(cfgPeerDNSRefreshInterval)
+	if interval <= 0 {
+		return nil
+	}
+
+	r := &peerResolver{
+		log:      a.log,
+		interval: interval,
+		lookup:   net.DefaultResolver.LookupHost,
+		trigger:  make(chan struct{}, 1),
+		addrs:    make(map[string]string),
+	}
+	seen := make(map[string]bool)
+	for _, peer := range fetchPeers(a.cfg) {
+		if host := peerHost(peer.Address); host != "" && !seen[host] {
+			seen[host] = true
+			r.hosts = append(r.hosts, host)
+		}
+	}
+	if len(r.hosts) == 0 {
+		return nil
+	}
+	return r
+}
+
+// startPeerResolver starts re-resolution of peers, the connection pool and
+// pools of single nodes are rebuilt when addresses change.
+func (a *app) startPeerResolver(ctx context.Context) {
+	a.peerDNS = a.newPeerResolver()
+	if a.peerDNS == nil {
+		return
+	}
+
+	a.peerDNS.changed = func() {
+		a.rebuildPool(ctx)
+		if a.nodePools != nil {
+			a.nodePools.reset(a.cfg.GetDuration(cfgKeyRotationDrainTimeout))
+		}
+	}
+	go a.peerDNS.run(ctx)
+}
+
+// resolve looks host names up and reports whether addresses of any of them
+// are changed since the previous lookup. Failed lookups are skipped, so the
+// previous addresses are kept.
+func (r *peerResolver) resolve(ctx context.Context) bool {
+	var changed bool
+	for _, host := range r.hosts {
+		addrs, err := r.lookup(ctx, host)
+		if err != nil {
+			r.log.Warn("could not resolve peer host", zap.String("host", host), zap.Error(err))
+			continue
+		}
+		sort.Strings(addrs)
+
+		joined := strings.Join(addrs, ",")
+		prev, ok := r.addrs[host]
+		r.addrs[host] = joined
+		if ok && prev != joined {
+			r.log.Info("peer host addresses changed", zap.String("host", host),
+				zap.Strings("addresses", addrs), zap.String("previous", prev))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// resolveNow makes the resolver look peers up without waiting for the
+// interval, e.g. after failed health checks.
+func (r *peerResolver) resolveNow() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (r *peerResolver) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if r.resolve(ctx) {
+			r.changed()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
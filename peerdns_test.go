@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPeerHost(t *testing.T) {
+	for address, host := range map[string]string{
+		"s01.neofs.devenv:8080":        "s01.neofs.devenv",
+		"grpcs://s01.neofs.devenv:443": "s01.neofs.devenv",
+		"192.0.2.1:8080":               "",
+		"grpc://[2001:db8::1]:8080":    "",
+		"s01.neofs.devenv":             "",
+	} {
+		require.Equal(t, host, peerHost(address), address)
+	}
+}
+
+func TestPeerResolver(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgPeers+".0.address", "192.0.2.1:8080")
+	a := &app{cfg: v, log: zap.NewNop()}
+	require.Nil(t, a.newPeerResolver())
+
+	v.Set(cfgPeerDNSRefreshInterval, time.Hour)
+	require.Nil(t, a.newPeerResolver())
+
+	v.Set(cfgPeers+".1.address", "grpc://s01.neofs.devenv:8080")
+	v.Set(cfgPeers+".2.address", "grpcs://s01.neofs.devenv:8081")
+	r := a.newPeerResolver()
+	require.NotNil(t, r)
+	require.Equal(t, []string{"s01.neofs.devenv"}, r.hosts)
+
+	var (
+		addrs = []string{"192.0.2.11", "192.0.2.10"}
+		err   error
+	)
+	r.lookup = func(context.Context, string) ([]string, error) {
+		return addrs, err
+	}
+
+	require.False(t, r.resolve(context.Background()))
+	addrs = []string{"192.0.2.10", "192.0.2.11"}
+	require.False(t, r.resolve(context.Background()))
+
+	err = errors.New("no such host")
+	require.False(t, r.resolve(context.Background()))
+
+	err = nil
+	addrs = []string{"192.0.2.12"}
+	require.True(t, r.resolve(context.Background()))
+	require.False(t, r.resolve(context.Background()))
+
+	t.Run("run", func(t *testing.T) {
+		changed := make(chan struct{}, 1)
+		r.changed = func() { changed <- struct{}{} }
+		addrs = []string{"192.0.2.13"}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go r.run(ctx)
+
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+			t.Fatal("change isn't detected")
+		}
+	})
+}
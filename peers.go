@@ -0,0 +1,329 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// peerHistorySize is the number of the latest probes recent errors are
+// counted in.
+const peerHistorySize = 10
+
+// States of node circuit breakers.
+const (
+	circuitClosed = "closed"
+	circuitOpen   = "open"
+)
+
+// peerCircuitOpen shows nodes ejected from the pool by circuit breakers.
+var peerCircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "neofs_http_gw",
+	Name:      "peer_circuit_open",
+	Help:      "Whether the node is ejected from the connection pool by the circuit breaker.",
+}, []string{"address"})
+
+type (
+	// peerStatus is the state of NeoFS node connection returned by admin API.
+	peerStatus struct {
+		Address      string    `json:"address"`
+		Priority     int       `json:"priority"`
+		Weight       float64   `json:"weight"`
+		Healthy      bool      `json:"healthy"`
+		LastCheck    time.Time `json:"last_check"`
+		LatencyMs    float64   `json:"latency_ms"`
+		LastError    string    `json:"last_error,omitempty"`
+		RecentErrors int       `json:"recent_errors"`
+		TotalErrors  uint64    `json:"total_errors"`
+		Circuit      string    `json:"circuit"`
+	}
+
+	peerState struct {
+		status  peerStatus
+		history []bool
+		cli     *client.Client
+		// failures is the number of consecutive failed probes.
+		failures int
+		// opened is the time of the last failure of the ejected node.
+		opened time.Time
+	}
+
+	// circuitBreaker ejects nodes from the pool after consecutive failures,
+	// they're returned after they've been healthy for the open timeout.
+	circuitBreaker struct {
+		failures    int
+		openTimeout time.Duration
+		// changed is called when nodes are ejected or returned.
+		changed func()
+	}
+
+	// peerMonitor probes NeoFS nodes with the interval of the pool rebalance
+	// since the pool doesn't expose the state of its connections.
+	peerMonitor struct {
+		log         *zap.Logger
+		interval    time.Duration
+		dialTimeout time.Duration
+		reqTimeout  time.Duration
+
+		// probe is replaced in tests.
+		probe func(ctx context.Context, p *peerState) error
+		// breaker is nil if circuit breakers are disabled.
+		breaker *circuitBreaker
+		// failed is called after checks with failed probes, it may be nil.
+		failed func()
+
+		mu    sync.Mutex
+		peers []*peerState
+	}
+)
+
+func (a *app) newPeerMonitor() *peerMonitor {
+	m := &peerMonitor{
+		log:         a.log,
+		interval:    a.cfg.GetDuration(cfgRebalance),
+		dialTimeout: a.cfg.GetDuration(cfgConTimeout),
+		reqTimeout:  a.cfg.GetDuration(cfgReqTimeout),
+	}
+
+	key := a.probeKey
+	m.probe = func(ctx context.Context, p *peerState) error {
+		return m.endpointInfo(ctx, p, key)
+	}
+
+	if a.cfg.GetBool(cfgCircuitBreakerEnabled) {
+		m.breaker = &circuitBreaker{
+			failures:    a.cfg.GetInt(cfgCircuitBreakerFailures),
+			openTimeout: a.cfg.GetDuration(cfgCircuitBreakerOpenTimeout),
+		}
+	}
+
+	for _, peer := range fetchPeers(a.cfg) {
+		m.peers = append(m.peers, &peerState{status: peerStatus{
+			Address:  peer.Address,
+			Priority: peer.Priority,
+			Weight:   peer.Weight,
+			Circuit:  circuitClosed,
+		}})
+	}
+
+	return m
+}
+
+// peerMonitor returns the monitor of NeoFS nodes, it's started on the first
+// call. Nodes ejected by circuit breakers are excluded from the pool which is
+// rebuilt on every change.
+func (a *app) peerMonitor(ctx context.Context) *peerMonitor {
+	a.peersOnce.Do(func() {
+		a.peers = a.newPeerMonitor()
+		if a.peers.breaker != nil {
+			a.peers.breaker.changed = func() { a.rebuildPool(ctx) }
+		}
+		if a.peerDNS != nil {
+			a.peers.failed = a.peerDNS.resolveNow
+		}
+		go a.peers.run(ctx)
+	})
+	return a.peers
+}
+
+// rebuildPool replaces the connection pool with the one using the nodes
+// which aren't ejected by circuit breakers.
+func (a *app) rebuildPool(ctx context.Context) {
+	a.keyMu.Lock()
+	defer a.keyMu.Unlock()
+
+	clientPool, err := a.newPool(ctx, a.key)
+	if err != nil {
+		a.log.Error("could not rebuild connection pool", zap.Error(err))
+		return
+	}
+	a.pool.Replace(clientPool, a.cfg.GetDuration(cfgKeyRotationDrainTimeout))
+	a.log.Info("connection pool is rebuilt")
+}
+
+// activePeers returns the peers which aren't ejected, all of them are
+// returned if every one is ejected since the pool can't be empty.
+func activePeers(peers []peerInfo, ejected map[string]bool) []peerInfo {
+	var res []peerInfo
+	for _, p := range peers {
+		if !ejected[p.Address] {
+			res = append(res, p)
+		}
+	}
+	if len(res) == 0 {
+		return peers
+	}
+	return res
+}
+
+// endpointInfo requests node info like the pool does to check node health,
+// the connection is re-established after failures.
+func (m *peerMonitor) endpointInfo(ctx context.Context, p *peerState, key *keys.PrivateKey) error {
+	if p.cli == nil {
+		var prmInit client.PrmInit
+		prmInit.ResolveNeoFSFailures()
+		prmInit.SetDefaultPrivateKey(key.PrivateKey)
+
+		cli := new(client.Client)
+		cli.Init(prmInit)
+
+		var prmDial client.PrmDial
+		prmDial.SetServerURI(p.status.Address)
+		prmDial.SetTimeout(m.dialTimeout)
+		if err := cli.Dial(prmDial); err != nil {
+			return err
+		}
+		p.cli = cli
+	}
+
+	if m.reqTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.reqTimeout)
+		defer cancel()
+	}
+
+	if _, err := p.cli.EndpointInfo(ctx, client.PrmEndpointInfo{}); err != nil {
+		_ = p.cli.Close()
+		p.cli = nil
+		return err
+	}
+	return nil
+}
+
+// check probes every node once and records the results.
+func (m *peerMonitor) check(ctx context.Context) {
+	var changed, failed bool
+	for _, p := range m.peers {
+		start := time.Now()
+		err := m.probe(ctx, p)
+		latency := time.Since(start)
+		failed = failed || err != nil
+
+		m.mu.Lock()
+		p.status.Healthy = err == nil
+		p.status.LastCheck = start
+		p.status.LatencyMs = float64(latency) / float64(time.Millisecond)
+		if err != nil {
+			p.status.LastError = err.Error()
+			p.status.TotalErrors++
+		}
+		p.history = append(p.history, err != nil)
+		if len(p.history) > peerHistorySize {
+			p.history = p.history[1:]
+		}
+		p.status.RecentErrors = 0
+		for _, failed := range p.history {
+			if failed {
+				p.status.RecentErrors++
+			}
+		}
+		if m.breaker != nil && m.breaker.update(p, err, start) {
+			changed = true
+			m.log.Warn("node circuit breaker state changed",
+				zap.String("address", p.status.Address), zap.String("circuit", p.status.Circuit))
+		}
+		m.mu.Unlock()
+	}
+
+	healthyPeers.Set(float64(m.healthy()))
+
+	if failed && m.failed != nil {
+		m.failed()
+	}
+
+	if changed && m.breaker.changed != nil {
+		m.breaker.changed()
+	}
+}
+
+// update records the result of the node probe, it reports whether the node is
+// ejected or returned.
+func (b *circuitBreaker) update(p *peerState, err error, now time.Time) bool {
+	if err != nil {
+		p.failures++
+		if p.status.Circuit == circuitOpen {
+			p.opened = now
+			return false
+		}
+		if p.failures < b.failures {
+			return false
+		}
+		p.status.Circuit = circuitOpen
+		p.opened = now
+		peerCircuitOpen.WithLabelValues(p.status.Address).Set(1)
+		return true
+	}
+
+	p.failures = 0
+	if p.status.Circuit == circuitClosed || now.Sub(p.opened) < b.openTimeout {
+		return false
+	}
+	p.status.Circuit = circuitClosed
+	peerCircuitOpen.WithLabelValues(p.status.Address).Set(0)
+	return true
+}
+
+// ejected returns addresses of the nodes ejected by circuit breakers.
+func (m *peerMonitor) ejected() map[string]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make(map[string]bool)
+	for _, p := range m.peers {
+		if p.status.Circuit == circuitOpen {
+			res[p.status.Address] = true
+		}
+	}
+	return res
+}
+
+func (m *peerMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			for _, p := range m.peers {
+				if p.cli != nil {
+					_ = p.cli.Close()
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *peerMonitor) statuses() []peerStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]peerStatus, len(m.peers))
+	for i, p := range m.peers {
+		res[i] = p.status
+	}
+	return res
+}
+
+func (m *peerMonitor) handler(c *fasthttp.RequestCtx) {
+	data, err := json.Marshal(m.statuses())
+	if err != nil {
+		m.log.Error("could not encode peers", zap.Error(err))
+		response.Error(c, "could not encode peers: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json")
+	c.SetBody(data)
+}
@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestPeerMonitor(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgPeers+".0.address", "s01.neofs.devenv:8080")
+	v.Set(cfgPeers+".0.weight", 0.9)
+	v.Set(cfgPeers+".1.address", "s02.neofs.devenv:8080")
+	a := &app{cfg: v, log: zap.NewNop()}
+
+	m := a.newPeerMonitor()
+	failing := map[string]bool{"s02.neofs.devenv:8080": true}
+	m.probe = func(_ context.Context, p *peerState) error {
+		if failing[p.status.Address] {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	for i := 0; i < peerHistorySize+2; i++ {
+		m.check(context.Background())
+	}
+	failing = nil
+	m.check(context.Background())
+
+	var c fasthttp.RequestCtx
+	m.handler(&c)
+	require.Equal(t, "application/json", string(c.Response.Header.ContentType()))
+
+	var statuses []peerStatus
+	require.NoError(t, json.Unmarshal(c.Response.Body(), &statuses))
+	require.Len(t, statuses, 2)
+
+	require.Equal(t, "s01.neofs.devenv:8080", statuses[0].Address)
+	require.Equal(t, 0.9, statuses[0].Weight)
+	require.Equal(t, 1, statuses[0].Priority)
+	require.True(t, statuses[0].Healthy)
+	require.Zero(t, statuses[0].RecentErrors)
+
+	require.True(t, statuses[1].Healthy)
+	require.Equal(t, "connection refused", statuses[1].LastError)
+	require.Equal(t, peerHistorySize-1, statuses[1].RecentErrors)
+	require.EqualValues(t, peerHistorySize+2, statuses[1].TotalErrors)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgPeers+".0.address", "s01.neofs.devenv:8080")
+	v.Set(cfgPeers+".1.address", "s02.neofs.devenv:8080")
+	v.Set(cfgCircuitBreakerEnabled, true)
+	v.Set(cfgCircuitBreakerFailures, 2)
+	v.Set(cfgCircuitBreakerOpenTimeout, time.Hour)
+	a := &app{cfg: v, log: zap.NewNop()}
+
+	m := a.newPeerMonitor()
+	var changes int
+	m.breaker.changed = func() { changes++ }
+
+	failing := true
+	m.probe = func(_ context.Context, p *peerState) error {
+		if failing && p.status.Address == "s02.neofs.devenv:8080" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	m.check(context.Background())
+	require.Empty(t, m.ejected())
+	m.check(context.Background())
+	require.Equal(t, map[string]bool{"s02.neofs.devenv:8080": true}, m.ejected())
+	require.Equal(t, 1, changes)
+	require.Equal(t, circuitOpen, m.statuses()[1].Circuit)
+
+	// the node isn't returned until it's healthy for the open timeout
+	failing = false
+	m.check(context.Background())
+	require.Len(t, m.ejected(), 1)
+	require.Equal(t, 1, changes)
+
+	m.peers[1].opened = time.Now().Add(-2 * time.Hour)
+	m.check(context.Background())
+	require.Empty(t, m.ejected())
+	require.Equal(t, 2, changes)
+	require.Equal(t, circuitClosed, m.statuses()[1].Circuit)
+}
+
+func TestActivePeers(t *testing.T) {
+	peers := []peerInfo{{Address: "s01"}, {Address: "s02"}}
+
+	require.Equal(t, peers[1:], activePeers(peers, map[string]bool{"s01": true}))
+	require.Equal(t, peers, activePeers(peers, map[string]bool{"s01": true, "s02": true}))
+	require.Equal(t, peers, activePeers(peers, nil))
+}
@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of configuration values.
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// configSource returns the source the value of the key is taken from in the
+// order of viper precedence.
+func configSource(v *viper.Viper, flags *pflag.FlagSet, key string) string {
+	flagName := key
+	if strings.HasPrefix(key, cfgPeers+".") {
+		flagName = cfgPeers
+	}
+	if f := flags.Lookup(flagName); f != nil && f.Changed {
+		return sourceFlag
+	}
+	if _, ok := os.LookupEnv(Prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))); ok {
+		return sourceEnv
+	}
+	if v.InConfig(key) {
+		return sourceFile
+	}
+	return sourceDefault
+}
+
+// printConfig writes the effective configuration as YAML with secrets redacted,
+// every value is commented with its source.
+func printConfig(w io.Writer, v *viper.Viper, source func(key string) string) error {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		if _, ok := ignore[key]; ok {
+			continue
+		}
+
+		var value yaml.Node
+		if err := value.Encode(printableValue(key, v.Get(key))); err != nil {
+			return fmt.Errorf("could not encode %s: %w", key, err)
+		}
+		value.LineComment = source(key)
+
+		parent := root
+		path := strings.Split(key, ".")
+		for _, name := range path[:len(path)-1] {
+			parent = mappingChild(parent, name)
+		}
+		if parent != nil {
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]}, &value)
+		}
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// printableValue redacts secrets and makes durations human-readable.
+func printableValue(key string, value interface{}) interface{} {
+	if isSecretKey(key) {
+		return redactedValue
+	}
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
+
+// mappingChild returns the mapping of the node with the name creating it if
+// needed. Nil is returned if the name is taken by a value.
+func mappingChild(node *yaml.Node, name string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			if child := node.Content[i+1]; child.Kind == yaml.MappingNode {
+				return child
+			}
+			return nil
+		}
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+	return child
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintConfig(t *testing.T) {
+	v := viper.New()
+	v.SetDefault(cfgReqTimeout, 15*time.Second)
+	v.Set(cfgPeers+".0.address", "s01.neofs.devenv:8080")
+	v.Set(cfgWalletPassphrase, "pwd")
+	v.Set(cfgAdminToken, "")
+	v.Set(cfgAPIKeys+".0.key", "4c7a8a2a0e6b4d6f")
+	v.Set(cfgRateLimitKey, "ip")
+	v.Set(cmdHelp, false)
+
+	sources := map[string]string{cfgPeers + ".0.address": sourceFlag}
+	var buf bytes.Buffer
+	require.NoError(t, printConfig(&buf, v, func(key string) string {
+		if src, ok := sources[key]; ok {
+			return src
+		}
+		return sourceDefault
+	}))
+
+	require.Equal(t, `admin:
+  token: <redacted> # default
+api_keys:
+  keys:
+    0:
+      key: <redacted> # default
+peers:
+  0:
+    address: s01.neofs.devenv:8080 # flag
+rate_limit:
+  key: ip # default
+request_timeout: 15s # default
+wallet:
+  passphrase: <redacted> # default
+`, buf.String())
+}
@@ -0,0 +1,327 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// uploadQuotaSlots is the number of parts the quota window is split into,
+// uploaded bytes are forgotten with the precision of a part.
+const uploadQuotaSlots = 60
+
+var errUploadQuotaExceeded = errors.New("upload quota exceeded")
+
+type (
+	// quotaSlot is the number of bytes uploaded by the client since the start
+	// of the slot.
+	quotaSlot struct {
+		start time.Time
+		bytes int64
+	}
+
+	// quotaUsage is the number of bytes uploaded by the client within the
+	// window, slots are ordered by their start.
+	quotaUsage struct {
+		slots []*quotaSlot
+		total int64
+	}
+
+	// uploadQuota limits the number of bytes every client uploads within the
+	// rolling window. Clients are identified by API keys, issuers of bearer
+	// tokens or IP addresses.
+	uploadQuota struct {
+		limit  int64
+		window time.Duration
+		owners map[string]struct{}
+
+		mu        sync.Mutex
+		clients   map[string]*quotaUsage
+		lastSweep time.Time
+	}
+)
+
+// newUploadQuota creates upload quota from the configuration, it returns nil
+// if the quota isn't set.
+func (a *app) newUploadQuota() *uploadQuota {
+	q := &uploadQuota{
+		limit:   a.cfg.GetInt64(cfgUploadQuotaBytes),
+		window:  a.cfg.GetDuration(cfgUploadQuotaWindow),
+		owners:  a.bearerOwners(cfgUploadQuotaBearerOwners),
+		clients: make(map[string]*quotaUsage),
+	}
+	if q.limit <= 0 {
+		return nil
+	}
+	if q.window <= 0 {
+		a.log.Warn("invalid upload quota window, default one is used",
+			zap.Duration("window", q.window))
+		q.window = defaultUploadQuotaWindow
+	}
+	return q
+}
+
+// prune drops slots which are out of the window.
+func (u *quotaUsage) prune(window time.Duration, now time.Time) {
+	var i int
+	for i < len(u.slots) && now.Sub(u.slots[i].start) >= window {
+		u.total -= u.slots[i].bytes
+		i++
+	}
+	u.slots = u.slots[i:]
+}
+
+// usage returns the usage of the client within the window, states of idle
+// clients are dropped from time to time. It must be called with the mutex
+// held.
+func (q *uploadQuota) usage(key string, now time.Time) *quotaUsage {
+	if now.Sub(q.lastSweep) > rateLimitSweepInterval {
+		for k, usage := range q.clients {
+			if usage.prune(q.window, now); len(usage.slots) == 0 {
+				delete(q.clients, k)
+			}
+		}
+		q.lastSweep = now
+	}
+
+	usage, ok := q.clients[key]
+	if !ok {
+		usage = new(quotaUsage)
+		q.clients[key] = usage
+	}
+	usage.prune(q.window, now)
+	return usage
+}
+
+// add charges the usage for n bytes in the current slot.
+func (q *uploadQuota) add(usage *quotaUsage, n int64, now time.Time) *quotaSlot {
+	var slot *quotaSlot
+	if last := len(usage.slots) - 1; last >= 0 && now.Sub(usage.slots[last].start) < q.window/uploadQuotaSlots {
+		slot = usage.slots[last]
+	} else {
+		slot = &quotaSlot{start: now}
+		usage.slots = append(usage.slots, slot)
+	}
+	slot.bytes += n
+	usage.total += n
+	return slot
+}
+
+// reserve charges the client for n bytes if it doesn't exceed the limit,
+// otherwise it returns the time until enough bytes are out of the window.
+func (q *uploadQuota) reserve(key string, limit, n int64, now time.Time) (*quotaSlot, time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := q.usage(key, now)
+	if usage.total+n > limit {
+		freed := usage.total + n - limit
+		for _, slot := range usage.slots {
+			if freed -= slot.bytes; freed <= 0 {
+				return nil, slot.start.Add(q.window).Sub(now), false
+			}
+		}
+		return nil, q.window, false
+	}
+
+	return q.add(usage, n, now), 0, true
+}
+
+// quotaReader charges the client for bytes of the upload of unknown size
+// (e.g. chunked one) while they're read, reading fails once the quota is
+// exceeded.
+type quotaReader struct {
+	r     io.Reader
+	q     *uploadQuota
+	key   string
+	limit int64
+
+	read     int64
+	slots    map[*quotaSlot]int64
+	exceeded bool
+	wait     time.Duration
+}
+
+func (r *quotaReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errUploadQuotaExceeded
+	}
+
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		slot, wait, ok := r.q.reserve(r.key, r.limit, int64(n), time.Now())
+		if !ok {
+			r.exceeded, r.wait = true, wait
+			return 0, errUploadQuotaExceeded
+		}
+		r.slots[slot] += int64(n)
+	}
+	return n, err
+}
+
+func (r *quotaReader) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// refund returns all bytes charged while reading.
+func (r *quotaReader) refund() {
+	for slot, n := range r.slots {
+		r.q.refund(r.key, slot, n)
+	}
+}
+
+// charge charges the client for n bytes regardless of the limit, it's used
+// for bytes which are known only after they're stored.
+func (q *uploadQuota) charge(key string, n int64, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.add(q.usage(key, now), n, now)
+}
+
+// refund returns n bytes charged in the slot to the client.
+func (q *uploadQuota) refund(key string, slot *quotaSlot, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage, ok := q.clients[key]
+	if !ok {
+		return
+	}
+	for _, s := range usage.slots {
+		if s == slot {
+			s.bytes -= n
+			usage.total -= n
+			return
+		}
+	}
+}
+
+// uploadClient returns the client identity and its quota: a known API key
+// (with its own quota if it's set), the issuer of a valid bearer token if
+// it's one of the configured owners or the client IP.
+func (a *app) uploadClient(c *fasthttp.RequestCtx) (string, int64) {
+	limit := a.uploadQuota.limit
+
+	if value := c.Request.Header.Peek(hdrAPIKey); len(value) != 0 && a.apiKeys != nil {
+		if key, ok := a.apiKeys.lookup(value); ok {
+			if key.UploadQuota > 0 {
+				limit = key.UploadQuota
+			}
+			hash := sha256.Sum256(value)
+			return "api_key:" + hex.EncodeToString(hash[:]), limit
+		}
+	}
+
+	if issuer, ok := knownBearerIssuer(c, a.uploadQuota.owners); ok {
+		return "bearer_owner:" + issuer, limit
+	}
+
+	return "ip:" + c.RemoteIP().String(), limit
+}
+
+// uploadQuotaLimit charges clients for bytes they upload, uploads exceeding
+// the whole quota get 413 status and uploads exceeding the rest of it get 429
+// status with Retry-After header. Content-Length is reserved before the
+// upload and replaced with the size of stored payload after it, so that
+// copies are charged too. Streamed uploads of unknown size are charged while
+// they're read and stopped once the quota is exceeded. Failed uploads aren't
+// charged.
+func (a *app) uploadQuotaLimit(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	q := a.uploadQuota
+	if q == nil || routeOperation(route) != operationUpload {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		size := int64(c.Request.Header.ContentLength())
+		if size < 0 && !c.Request.IsBodyStream() {
+			size = int64(len(c.Request.Body()))
+		}
+
+		key, limit := a.uploadClient(c)
+		if size < 0 {
+			a.streamedUploadQuota(c, h, key, limit)
+			return
+		}
+		if size > limit {
+			quotaTooLarge(c, limit)
+			return
+		}
+
+		slot, wait, ok := q.reserve(key, limit, size, time.Now())
+		if !ok {
+			a.log.Warn("upload quota exceeded", zap.String("client", key), zap.Int64("size", size))
+			quotaExceeded(c, wait)
+			return
+		}
+
+		h(c)
+
+		if stored, ok := utils.StoredBytes(c); ok {
+			q.refund(key, slot, size)
+			q.charge(key, stored, time.Now())
+		} else if c.Response.StatusCode() >= fasthttp.StatusBadRequest {
+			q.refund(key, slot, size)
+		}
+	}
+}
+
+// streamedUploadQuota charges the client for the upload of unknown size while
+// it's read, the response of the handler is replaced if the quota is
+// exceeded.
+func (a *app) streamedUploadQuota(c *fasthttp.RequestCtx, h fasthttp.RequestHandler, key string, limit int64) {
+	q := a.uploadQuota
+	r := &quotaReader{
+		r:     c.RequestBodyStream(),
+		q:     q,
+		key:   key,
+		limit: limit,
+		slots: make(map[*quotaSlot]int64),
+	}
+	c.Request.SetBodyStream(r, -1)
+
+	h(c)
+
+	switch stored, ok := utils.StoredBytes(c); {
+	case r.exceeded:
+		r.refund()
+		a.log.Warn("upload quota exceeded", zap.String("client", key), zap.Int64("read", r.read))
+		if r.read > limit {
+			quotaTooLarge(c, limit)
+		} else {
+			quotaExceeded(c, r.wait)
+		}
+	case ok:
+		r.refund()
+		q.charge(key, stored, time.Now())
+	case c.Response.StatusCode() >= fasthttp.StatusBadRequest:
+		r.refund()
+	}
+}
+
+// quotaTooLarge replies to the upload bigger than the whole quota.
+func quotaTooLarge(c *fasthttp.RequestCtx, limit int64) {
+	response.Error(c, "upload exceeds quota of "+strconv.FormatInt(limit, 10)+" bytes",
+		fasthttp.StatusRequestEntityTooLarge)
+}
+
+// quotaExceeded replies to the upload exceeding the rest of the quota.
+func quotaExceeded(c *fasthttp.RequestCtx, wait time.Duration) {
+	response.Error(c, "upload quota exceeded", fasthttp.StatusTooManyRequests)
+	c.Response.Header.Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,147 @@
+package gateway
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestUploadQuotaReserve(t *testing.T) {
+	q := &uploadQuota{limit: 100, window: time.Hour, clients: make(map[string]*quotaUsage)}
+	now := time.Now()
+
+	_, _, ok := q.reserve("a", 100, 60, now)
+	require.True(t, ok)
+	slot, _, ok := q.reserve("a", 100, 30, now.Add(10*time.Minute))
+	require.True(t, ok)
+
+	_, wait, ok := q.reserve("a", 100, 20, now.Add(20*time.Minute))
+	require.False(t, ok)
+	require.Equal(t, 40*time.Minute, wait)
+
+	q.refund("a", slot, 30)
+	_, _, ok = q.reserve("a", 100, 40, now.Add(20*time.Minute))
+	require.True(t, ok)
+
+	_, _, ok = q.reserve("b", 100, 100, now)
+	require.True(t, ok)
+	_, _, ok = q.reserve("a", 100, 60, now.Add(time.Hour))
+	require.True(t, ok)
+}
+
+func TestUploadQuotaLimit(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		require.Nil(t, a.newUploadQuota())
+	})
+
+	v := viper.New()
+	v.Set(cfgUploadQuotaBytes, 100)
+	v.Set(cfgUploadQuotaWindow, time.Hour)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.uploadQuota = a.newUploadQuota()
+
+	status := fasthttp.StatusOK
+	h := a.uploadQuotaLimit(routeUpload, func(c *fasthttp.RequestCtx) {
+		c.SetStatusCode(status)
+	})
+
+	request := func(ip string, size int) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP(ip)}, nil)
+		c.Request.Header.SetContentLength(size)
+		h(&c)
+		return &c
+	}
+
+	require.Equal(t, fasthttp.StatusRequestEntityTooLarge, request("192.0.2.1", 101).Response.StatusCode())
+
+	status = fasthttp.StatusBadRequest
+	require.Equal(t, fasthttp.StatusBadRequest, request("192.0.2.1", 80).Response.StatusCode())
+	status = fasthttp.StatusOK
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.1", 80).Response.StatusCode())
+
+	c := request("192.0.2.1", 30)
+	require.Equal(t, fasthttp.StatusTooManyRequests, c.Response.StatusCode())
+	require.Equal(t, "3600", string(c.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.2", 30).Response.StatusCode())
+}
+
+func TestUploadQuotaStoredBytes(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgUploadQuotaBytes, 100)
+	v.Set(cfgUploadQuotaWindow, time.Hour)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.uploadQuota = a.newUploadQuota()
+
+	var stored int64
+	h := a.uploadQuotaLimit(routeCopy, func(c *fasthttp.RequestCtx) {
+		utils.AddStoredBytes(c, stored)
+		c.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	request := func() *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP("192.0.2.1")}, nil)
+		c.Request.Header.SetContentLength(0)
+		h(&c)
+		return &c
+	}
+
+	// copies have no body, the stored payload is charged
+	stored = 120
+	require.Equal(t, fasthttp.StatusOK, request().Response.StatusCode())
+	stored = 0
+	require.Equal(t, fasthttp.StatusTooManyRequests, request().Response.StatusCode())
+}
+
+func TestUploadQuotaStreamed(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgUploadQuotaBytes, 100)
+	v.Set(cfgUploadQuotaWindow, time.Hour)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.uploadQuota = a.newUploadQuota()
+
+	var read int
+	h := a.uploadQuotaLimit(routeUpload, func(c *fasthttp.RequestCtx) {
+		data, err := io.ReadAll(c.RequestBodyStream())
+		read = len(data)
+		if err != nil {
+			c.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		c.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	request := func(ip string, size int) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP(ip)}, nil)
+		c.Request.SetBodyStream(iotest.OneByteReader(strings.NewReader(strings.Repeat("a", size))), -1)
+		h(&c)
+		return &c
+	}
+
+	// Chunked upload is stopped once it's bigger than the whole quota.
+	require.Equal(t, fasthttp.StatusRequestEntityTooLarge, request("192.0.2.1", 150).Response.StatusCode())
+	require.Equal(t, 100, read)
+
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.1", 80).Response.StatusCode())
+
+	c := request("192.0.2.1", 30)
+	require.Equal(t, fasthttp.StatusTooManyRequests, c.Response.StatusCode())
+	require.Equal(t, "3600", string(c.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+	require.Equal(t, 20, read)
+
+	// Refunded bytes of stopped uploads can be used.
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.1", 20).Response.StatusCode())
+}
@@ -0,0 +1,277 @@
+package gateway
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Rate limiter keys.
+const (
+	rateLimitKeyIP          = "ip"
+	rateLimitKeyBearerOwner = "bearer_owner"
+)
+
+// rateLimitSweepInterval is the interval to drop limiter states of idle
+// clients with.
+const rateLimitSweepInterval = time.Minute
+
+type (
+	// tokenBucket is a token bucket which is allowed to be in debt, so that
+	// the cost of the request can be charged after it's served.
+	tokenBucket struct {
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+	}
+
+	clientLimits struct {
+		requests  *tokenBucket
+		bandwidth *tokenBucket
+	}
+
+	// rateLimiter limits requests rate and bandwidth per client.
+	rateLimiter struct {
+		key    string
+		owners map[string]struct{}
+
+		requests, requestsBurst   float64
+		bandwidth, bandwidthBurst float64
+
+		mu        sync.Mutex
+		clients   map[string]*clientLimits
+		lastSweep time.Time
+	}
+)
+
+func newTokenBucket(rate, burst float64, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = math.Max(rate, 1)
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// take takes n tokens if there are enough of them, otherwise it returns the
+// time to wait for them.
+func (b *tokenBucket) take(now time.Time, n float64) (time.Duration, bool) {
+	b.refill(now)
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0, true
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// charge takes n tokens unconditionally.
+func (b *tokenBucket) charge(now time.Time, n float64) {
+	b.refill(now)
+	b.tokens -= n
+}
+
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// newRateLimiter creates rate limiter from the configuration, it returns nil
+// if neither requests nor bandwidth are limited.
+func (a *app) newRateLimiter() *rateLimiter {
+	l := &rateLimiter{
+		key:            a.cfg.GetString(cfgRateLimitKey),
+		owners:         a.bearerOwners(cfgRateLimitBearerOwners),
+		requests:       a.cfg.GetFloat64(cfgRateLimitRequests),
+		requestsBurst:  a.cfg.GetFloat64(cfgRateLimitRequestsBurst),
+		bandwidth:      a.cfg.GetFloat64(cfgRateLimitBandwidth),
+		bandwidthBurst: a.cfg.GetFloat64(cfgRateLimitBandwidthBurst),
+		clients:        make(map[string]*clientLimits),
+	}
+
+	if l.requests <= 0 && l.bandwidth <= 0 {
+		return nil
+	}
+
+	if l.key != rateLimitKeyIP && l.key != rateLimitKeyBearerOwner {
+		a.log.Warn("unknown rate limit key, client IP is used",
+			zap.String("key", l.key))
+		l.key = rateLimitKeyIP
+	}
+
+	return l
+}
+
+// setLimits changes the limits, states of the clients are dropped, so they get
+// buckets with the new limits.
+func (l *rateLimiter) setLimits(requests, requestsBurst, bandwidth, bandwidthBurst float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests, l.requestsBurst = requests, requestsBurst
+	l.bandwidth, l.bandwidthBurst = bandwidth, bandwidthBurst
+	l.clients = make(map[string]*clientLimits)
+}
+
+// rateLimit rejects requests of the clients exceeding the limits with 429
+// status code. Request and response body bytes are charged as they're read
+// and sent.
+func (a *app) rateLimit(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	l := a.rateLimiter
+	if l == nil {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		limits := l.limits(l.clientKey(c), time.Now())
+
+		if wait, ok := l.allow(limits, time.Now()); !ok {
+			response.Error(c, "rate limit exceeded", fasthttp.StatusTooManyRequests)
+			c.Response.Header.Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			return
+		}
+
+		if limits.bandwidth == nil {
+			h(c)
+			return
+		}
+
+		charge := func(n int) {
+			l.mu.Lock()
+			limits.bandwidth.charge(time.Now(), float64(n))
+			l.mu.Unlock()
+		}
+
+		if c.Request.IsBodyStream() {
+			c.Request.SetBodyStream(utils.CountingReader(c.RequestBodyStream(), charge), c.Request.Header.ContentLength())
+		} else {
+			charge(len(c.Request.Body()))
+		}
+		utils.AddBodyCounter(c, charge)
+
+		h(c)
+
+		if !c.Response.IsBodyStream() && !c.IsHead() {
+			charge(len(c.Response.Body()))
+		}
+	}
+}
+
+// clientKey returns the issuer of the bearer token if the limiter is keyed by
+// token owners and the request has a valid token of a known owner, otherwise
+// it returns client IP.
+func (l *rateLimiter) clientKey(c *fasthttp.RequestCtx) string {
+	if l.key == rateLimitKeyBearerOwner {
+		if issuer, ok := knownBearerIssuer(c, l.owners); ok {
+			return issuer
+		}
+	}
+
+	return c.RemoteIP().String()
+}
+
+// bearerOwners returns the set of bearer token issuers from the configuration
+// section, invalid ones are skipped.
+func (a *app) bearerOwners(section string) map[string]struct{} {
+	owners := make(map[string]struct{})
+	for _, s := range a.cfg.GetStringSlice(section) {
+		var id user.ID
+		if err := id.DecodeString(s); err != nil {
+			a.log.Warn("invalid bearer token owner", zap.String("section", section),
+				zap.String("owner", s), zap.Error(err))
+			continue
+		}
+		owners[id.EncodeToString()] = struct{}{}
+	}
+	return owners
+}
+
+// bearerIssuer returns the issuer of the valid bearer token of the request.
+// Anyone can sign a token with a fresh key, so the issuer identifies the
+// client only if it's known beforehand.
+func bearerIssuer(c *fasthttp.RequestCtx) (string, bool) {
+	if tokens.StoreBearerToken(c) != nil {
+		return "", false
+	}
+	tkn, err := tokens.LoadBearerToken(c)
+	if err != nil || tkn.VerifySignature() != nil {
+		return "", false
+	}
+	issuer, ok := tkn.Issuer()
+	if !ok {
+		return "", false
+	}
+	return issuer.EncodeToString(), true
+}
+
+// knownBearerIssuer returns the issuer of the valid bearer token of the
+// request if it's one of the owners.
+func knownBearerIssuer(c *fasthttp.RequestCtx, owners map[string]struct{}) (string, bool) {
+	issuer, ok := bearerIssuer(c)
+	if !ok {
+		return "", false
+	}
+	if _, ok = owners[issuer]; !ok {
+		return "", false
+	}
+	return issuer, true
+}
+
+func (l *rateLimiter) limits(key string, now time.Time) *clientLimits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		for k, limits := range l.clients {
+			if (limits.requests == nil || limits.requests.full(now)) &&
+				(limits.bandwidth == nil || limits.bandwidth.full(now)) {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	limits, ok := l.clients[key]
+	if !ok {
+		limits = new(clientLimits)
+		if l.requests > 0 {
+			limits.requests = newTokenBucket(l.requests, l.requestsBurst, now)
+		}
+		if l.bandwidth > 0 {
+			limits.bandwidth = newTokenBucket(l.bandwidth, l.bandwidthBurst, now)
+		}
+		l.clients[key] = limits
+	}
+
+	return limits
+}
+
+// allow takes a request token and checks that the client isn't in bandwidth
+// debt, it returns the time to wait otherwise.
+func (l *rateLimiter) allow(limits *clientLimits, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits.bandwidth != nil {
+		if wait, ok := limits.bandwidth.take(now, 0); !ok {
+			return wait, false
+		}
+	}
+	if limits.requests != nil {
+		return limits.requests.take(now, 1)
+	}
+	return 0, true
+}
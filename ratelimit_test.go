@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(2, 3, now)
+
+	for i := 0; i < 3; i++ {
+		_, ok := b.take(now, 1)
+		require.True(t, ok)
+	}
+
+	wait, ok := b.take(now, 1)
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	_, ok = b.take(now.Add(500*time.Millisecond), 1)
+	require.True(t, ok)
+
+	b.charge(now.Add(500*time.Millisecond), 4)
+	wait, ok = b.take(now.Add(500*time.Millisecond), 0)
+	require.False(t, ok)
+	require.Equal(t, 2*time.Second, wait)
+
+	require.True(t, b.full(now.Add(time.Minute)))
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		require.Nil(t, a.newRateLimiter())
+	})
+
+	v := viper.New()
+	v.Set(cfgRateLimitKey, rateLimitKeyIP)
+	v.Set(cfgRateLimitRequests, 1)
+	v.Set(cfgRateLimitRequestsBurst, 2)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.rateLimiter = a.newRateLimiter()
+
+	h := a.rateLimit(func(c *fasthttp.RequestCtx) {
+		c.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	request := func(ip string) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP(ip)}, nil)
+		h(&c)
+		return &c
+	}
+
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.1").Response.StatusCode())
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.1").Response.StatusCode())
+
+	c := request("192.0.2.1")
+	require.Equal(t, fasthttp.StatusTooManyRequests, c.Response.StatusCode())
+	require.Equal(t, "1", string(c.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+
+	require.Equal(t, fasthttp.StatusOK, request("192.0.2.2").Response.StatusCode())
+}
+
+func TestRateLimitBandwidth(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgRateLimitBandwidth, 10)
+	v.Set(cfgRateLimitBandwidthBurst, 10)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.rateLimiter = a.newRateLimiter()
+
+	h := a.rateLimit(func(c *fasthttp.RequestCtx) {
+		// chunked response without Content-Length
+		utils.SetBodyStream(c, strings.NewReader("0123456789abcdef"), -1)
+	})
+
+	request := func() *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP("192.0.2.1")}, nil)
+		h(&c)
+		return &c
+	}
+
+	c := request()
+	require.Equal(t, fasthttp.StatusOK, c.Response.StatusCode())
+	require.Equal(t, "0123456789abcdef", string(c.Response.Body()))
+
+	c = request()
+	require.Equal(t, fasthttp.StatusTooManyRequests, c.Response.StatusCode())
+}
+
+func TestRateLimitBearerOwner(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgRateLimitKey, rateLimitKeyBearerOwner)
+	v.Set(cfgRateLimitRequests, 1)
+	v.Set(cfgRateLimitBearerOwners, []string{"NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM", "invalid"})
+	a := &app{cfg: v, log: zap.NewNop()}
+	l := a.newRateLimiter()
+
+	require.Equal(t, map[string]struct{}{"NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM": {}}, l.owners)
+
+	var c fasthttp.RequestCtx
+	c.Init(new(fasthttp.Request), &net.TCPAddr{IP: net.ParseIP("192.0.2.1")}, nil)
+	c.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer invalid")
+	require.Equal(t, "192.0.2.1", l.clientKey(&c))
+}
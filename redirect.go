@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const acmeChallengePathPrefix = "/.well-known/acme-challenge/"
+
+// startRedirectServer runs plain HTTP listener answering with redirects to
+// the HTTPS listener bound to tlsBind. If certificate manager is provided,
+// ACME HTTP-01 challenges are answered too.
+func (a *app) startRedirectServer(ctx context.Context, addr, tlsBind string, m *autocert.Manager) {
+	handler := redirectHandler(httpsPort(tlsBind))
+	if m != nil {
+		challengeHandler := fasthttpadaptor.NewFastHTTPHandler(m.HTTPHandler(nil))
+		redirect := handler
+		handler = func(c *fasthttp.RequestCtx) {
+			if bytes.HasPrefix(c.Path(), []byte(acmeChallengePathPrefix)) {
+				challengeHandler(c)
+				return
+			}
+			redirect(c)
+		}
+	}
+
+	redirectServer := &fasthttp.Server{
+		Name:                  a.webServer.Name,
+		Handler:               handler,
+		NoDefaultServerHeader: true,
+		NoDefaultContentType:  true,
+		ReadTimeout:           a.webServer.ReadTimeout,
+		WriteTimeout:          a.webServer.WriteTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		a.log.Info("shutting down redirect server", zap.Error(redirectServer.Shutdown()))
+	}()
+
+	go func() {
+		a.log.Info("running redirect server", zap.String("address", addr))
+		if err := redirectServer.ListenAndServe(addr); err != nil {
+			a.log.Error("could not start redirect server", zap.Error(err))
+		}
+	}()
+}
+
+// redirectHandler answers with permanent redirects to the same host and URI
+// using HTTPS scheme and the specified port (omitted if empty).
+func redirectHandler(port string) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		host := string(c.Host())
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]")
+		}
+
+		if port != "" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") { // IPv6 literal
+			host = "[" + host + "]"
+		}
+
+		c.Redirect("https://"+host+string(c.RequestURI()), fasthttp.StatusMovedPermanently)
+	}
+}
+
+// httpsPort returns the port of the HTTPS listener to be used in redirects,
+// it's empty for the default port.
+func httpsPort(bind string) string {
+	_, port, err := net.SplitHostPort(bind)
+	if err != nil || port == "443" {
+		return ""
+	}
+	return port
+}
@@ -0,0 +1,65 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	cases := []struct {
+		name     string
+		port     string
+		host     string
+		uri      string
+		expected string
+	}{
+		{
+			name:     "default port",
+			host:     "gate.example.com",
+			uri:      "/get/cid/oid?download=true",
+			expected: "https://gate.example.com/get/cid/oid?download=true",
+		},
+		{
+			name:     "custom port",
+			port:     "8443",
+			host:     "gate.example.com:8080",
+			uri:      "/upload/cid",
+			expected: "https://gate.example.com:8443/upload/cid",
+		},
+		{
+			name:     "ipv6",
+			port:     "8443",
+			host:     "[::1]:8080",
+			uri:      "/",
+			expected: "https://[::1]:8443/",
+		},
+		{
+			name:     "ipv6 default port",
+			host:     "[::1]",
+			uri:      "/",
+			expected: "https://[::1]/",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var c fasthttp.RequestCtx
+			c.Request.SetRequestURI(tc.uri)
+			c.Request.SetHost(tc.host)
+
+			redirectHandler(tc.port)(&c)
+
+			require.Equal(t, fasthttp.StatusMovedPermanently, c.Response.StatusCode())
+			require.Equal(t, tc.expected, string(c.Response.Header.Peek(fasthttp.HeaderLocation)))
+		})
+	}
+}
+
+func TestHTTPSPort(t *testing.T) {
+	require.Equal(t, "", httpsPort("0.0.0.0:443"))
+	require.Equal(t, "8443", httpsPort("0.0.0.0:8443"))
+	require.Equal(t, "8443", httpsPort("[::]:8443"))
+	require.Equal(t, "", httpsPort("bad address"))
+}
@@ -6,17 +6,21 @@ import (
 	"fmt"
 
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
-	"github.com/nspcc-dev/neofs-sdk-go/pool"
 )
 
+// NetworkInfoSource is a source of NeoFS network information, e.g. pool.Pool.
+type NetworkInfoSource interface {
+	NetworkInfo(context.Context) (*netmap.NetworkInfo, error)
+}
+
 // NeoFSResolver represents virtual connection to the NeoFS network.
 // It implements resolver.NeoFS.
 type NeoFSResolver struct {
-	pool *pool.Pool
+	pool NetworkInfoSource
 }
 
 // NewNeoFSResolver creates new NeoFSResolver using provided pool.Pool.
-func NewNeoFSResolver(p *pool.Pool) *NeoFSResolver {
+func NewNeoFSResolver(p NetworkInfoSource) *NeoFSResolver {
 	return &NeoFSResolver{pool: p}
 }
 
@@ -1,7 +1,182 @@
 package response
 
-import "github.com/valyala/fasthttp"
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
-func Error(r *fasthttp.RequestCtx, msg string, code int) {
-	r.Error(msg+"\n", code)
+	"github.com/valyala/fasthttp"
+)
+
+// Machine-readable error codes, they're stable and can be relied on by
+// clients. Codes of other errors are derived from HTTP status.
+const (
+	CodeBadRequest        = "BAD_REQUEST"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeAccessDenied      = "ACCESS_DENIED"
+	CodeNotFound          = "NOT_FOUND"
+	CodeObjectNotFound    = "OBJECT_NOT_FOUND"
+	CodeContainerNotFound = "CONTAINER_NOT_FOUND"
+	CodeInfected          = "INFECTED"
+	CodeMethodNotAllowed  = "METHOD_NOT_ALLOWED"
+	CodeTimeout           = "TIMEOUT"
+	CodeConflict          = "CONFLICT"
+	CodeTooLarge          = "TOO_LARGE"
+	CodeUnsupportedType   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRangeNotSatisfied = "RANGE_NOT_SATISFIABLE"
+	CodeTooManyRequests   = "TOO_MANY_REQUESTS"
+	CodeInternal          = "INTERNAL"
+	CodeBadGateway        = "BAD_GATEWAY"
+	CodeUnavailable       = "UNAVAILABLE"
+	CodeGatewayTimeout    = "GATEWAY_TIMEOUT"
+	CodeNotImplemented    = "NOT_IMPLEMENTED"
+	CodeUnknown           = "UNKNOWN"
+)
+
+const (
+	contentTypeJSON        = "application/json"
+	contentTypeHTML        = "text/html"
+	contentTypeHTMLCharset = "text/html; charset=utf-8"
+)
+
+var statusCodes = map[int]string{
+	fasthttp.StatusBadRequest:                   CodeBadRequest,
+	fasthttp.StatusUnauthorized:                 CodeUnauthorized,
+	fasthttp.StatusForbidden:                    CodeAccessDenied,
+	fasthttp.StatusNotFound:                     CodeNotFound,
+	fasthttp.StatusMethodNotAllowed:             CodeMethodNotAllowed,
+	fasthttp.StatusRequestTimeout:               CodeTimeout,
+	fasthttp.StatusConflict:                     CodeConflict,
+	fasthttp.StatusRequestEntityTooLarge:        CodeTooLarge,
+	fasthttp.StatusUnsupportedMediaType:         CodeUnsupportedType,
+	fasthttp.StatusRequestedRangeNotSatisfiable: CodeRangeNotSatisfied,
+	fasthttp.StatusTooManyRequests:              CodeTooManyRequests,
+	fasthttp.StatusInternalServerError:          CodeInternal,
+	fasthttp.StatusNotImplemented:               CodeNotImplemented,
+	fasthttp.StatusBadGateway:                   CodeBadGateway,
+	fasthttp.StatusServiceUnavailable:           CodeUnavailable,
+	fasthttp.StatusGatewayTimeout:               CodeGatewayTimeout,
+}
+
+// ErrorPage is the data HTML error template is executed with.
+type ErrorPage struct {
+	Status     int
+	StatusText string
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+type errorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
+var htmlTemplate atomic.Value
+
+// SetHTMLTemplate sets the template of error pages for clients preferring
+// HTML, nil disables them.
+func SetHTMLTemplate(t *template.Template) {
+	htmlTemplate.Store(t)
+}
+
+// Error writes the error response with the code derived from the status.
+func Error(r *fasthttp.RequestCtx, msg string, status int) {
+	ErrorCode(r, StatusCode(status), msg, status)
+}
+
+// ErrorCode writes the error response with the given machine-readable code.
+// It's JSON if the client accepts it, HTML page if it prefers HTML and the
+// template is set, and plain text otherwise. Request ID is the one logged for
+// the request.
+func ErrorCode(r *fasthttp.RequestCtx, code, msg string, status int) {
+	requestID := strconv.FormatUint(r.ID(), 10)
+
+	switch preferredType(r.Request.Header.Peek(fasthttp.HeaderAccept)) {
+	case contentTypeJSON:
+		data, err := json.Marshal(errorBody{Code: code, Message: msg, RequestID: requestID})
+		if err != nil {
+			break
+		}
+		r.Response.Reset()
+		r.SetStatusCode(status)
+		r.SetContentType(contentTypeJSON)
+		r.SetBody(data)
+		return
+	case contentTypeHTML:
+		t, _ := htmlTemplate.Load().(*template.Template)
+		if t == nil {
+			break
+		}
+		var buf bytes.Buffer
+		err := t.Execute(&buf, ErrorPage{
+			Status:     status,
+			StatusText: fasthttp.StatusMessage(status),
+			Code:       code,
+			Message:    msg,
+			RequestID:  requestID,
+		})
+		if err != nil {
+			break
+		}
+		r.Response.Reset()
+		r.SetStatusCode(status)
+		r.SetContentType(contentTypeHTMLCharset)
+		r.SetBody(buf.Bytes())
+		return
+	}
+
+	r.Error(msg+"\n", status)
+}
+
+// StatusCode returns machine-readable code of the HTTP status.
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return CodeUnknown
+}
+
+// preferredType returns JSON or HTML content type if the client explicitly
+// accepts it with higher quality than the other one and plain text. Empty
+// string means plain text.
+func preferredType(accept []byte) string {
+	var (
+		best   string
+		bestQ  = 0.0
+		plainQ = -1.0
+	)
+	for _, part := range strings.Split(string(accept), ",") {
+		params := strings.Split(part, ";")
+		media := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+		for _, param := range params[1:] {
+			if v := strings.TrimSpace(param); strings.HasPrefix(v, "q=") {
+				if parsed, err := strconv.ParseFloat(v[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch {
+		case media == "text/plain":
+			plainQ = q
+		case media == contentTypeJSON || strings.HasSuffix(media, "+json"):
+			if q > bestQ {
+				best, bestQ = contentTypeJSON, q
+			}
+		case media == contentTypeHTML:
+			if q > bestQ {
+				best, bestQ = contentTypeHTML, q
+			}
+		}
+	}
+	if bestQ <= 0 || plainQ >= bestQ {
+		return ""
+	}
+	return best
 }
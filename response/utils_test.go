@@ -0,0 +1,71 @@
+package response
+
+import (
+	"encoding/json"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestPreferredType(t *testing.T) {
+	for accept, expected := range map[string]string{
+		"":                                   "",
+		"*/*":                                "",
+		"application/json":                   contentTypeJSON,
+		"application/problem+json":           contentTypeJSON,
+		"text/plain, application/json":       "",
+		"text/plain;q=0.5, application/json": contentTypeJSON,
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": contentTypeHTML,
+		"text/html;q=0.5, application/json":                               contentTypeJSON,
+		"application/json;q=0":                                            "",
+	} {
+		require.Equal(t, expected, preferredType([]byte(accept)), accept)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	serve := func(accept string) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.Request.Header.Set(fasthttp.HeaderAccept, accept)
+		c.Response.Header.Set("X-Stale", "1")
+		ErrorCode(&c, CodeObjectNotFound, "object not found", fasthttp.StatusNotFound)
+		require.Equal(t, fasthttp.StatusNotFound, c.Response.StatusCode())
+		require.Empty(t, c.Response.Header.Peek("X-Stale"))
+		return &c
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		c := serve("*/*")
+		require.Equal(t, "object not found\n", string(c.Response.Body()))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		c := serve("application/json")
+		require.Equal(t, contentTypeJSON, string(c.Response.Header.ContentType()))
+
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(c.Response.Body(), &body))
+		require.Equal(t, map[string]string{
+			"code":       CodeObjectNotFound,
+			"message":    "object not found",
+			"request_id": "0",
+		}, body)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		c := serve("text/html")
+		require.Equal(t, "object not found\n", string(c.Response.Body()))
+
+		SetHTMLTemplate(template.Must(template.New("").Parse(`<h1>{{.Status}} {{.StatusText}}</h1><p>{{.Code}}: {{.Message}}</p>`)))
+		t.Cleanup(func() { SetHTMLTemplate(nil) })
+
+		c = serve("text/html")
+		require.Equal(t, contentTypeHTMLCharset, string(c.Response.Header.ContentType()))
+		require.Equal(t, "<h1>404 Not Found</h1><p>OBJECT_NOT_FOUND: object not found</p>", string(c.Response.Body()))
+	})
+
+	require.Equal(t, CodeAccessDenied, StatusCode(fasthttp.StatusForbidden))
+	require.Equal(t, CodeUnknown, StatusCode(fasthttp.StatusTeapot))
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"net/textproto"
+	"strconv"
+
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+)
+
+// responseHeaderRule is a set of static headers added to successful responses
+// of the route and container (any if empty).
+type responseHeaderRule struct {
+	Route     string
+	Container string
+	Headers   map[string]string
+}
+
+// fetchResponseHeaderRules reads response_headers section. Header names are
+// canonicalized since viper keeps map keys in lower case.
+func fetchResponseHeaderRules(v *viper.Viper) []responseHeaderRule {
+	var rules []responseHeaderRule
+
+	for i := 0; ; i++ {
+		key := cfgResponseHeaders + "." + strconv.Itoa(i) + "."
+
+		headers := v.GetStringMapString(key + "headers")
+		if len(headers) == 0 {
+			break
+		}
+
+		rule := responseHeaderRule{
+			Route:     v.GetString(key + "route"),
+			Container: v.GetString(key + "container"),
+			Headers:   make(map[string]string, len(headers)),
+		}
+		for name, value := range headers {
+			rule.Headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// responseHeaders adds headers from the rules matching the route and the
+// requested container to successful responses.
+func (a *app) responseHeaders(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	var rules []responseHeaderRule
+	for _, rule := range fetchResponseHeaderRules(a.cfg) {
+		if rule.Route == "" || rule.Route == route {
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		h(c)
+
+		if c.Response.StatusCode() >= fasthttp.StatusBadRequest {
+			return
+		}
+
+		cnr, _ := c.UserValue("cid").(string)
+		for _, rule := range rules {
+			if rule.Container != "" && rule.Container != cnr {
+				continue
+			}
+			for name, value := range rule.Headers {
+				c.Response.Header.Set(name, value)
+			}
+		}
+	}
+}
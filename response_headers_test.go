@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgResponseHeaders+".0.route", routeGet)
+	v.Set(cfgResponseHeaders+".0.container", "images")
+	v.Set(cfgResponseHeaders+".0.headers", map[string]string{"cache-control": "public, max-age=86400"})
+	v.Set(cfgResponseHeaders+".1.headers", map[string]string{"x-served-by": "gate-1"})
+	a := &app{cfg: v}
+
+	require.Equal(t, []responseHeaderRule{
+		{Route: routeGet, Container: "images", Headers: map[string]string{"Cache-Control": "public, max-age=86400"}},
+		{Headers: map[string]string{"X-Served-By": "gate-1"}},
+	}, fetchResponseHeaderRules(v))
+
+	request := func(route, cnr string, status int) *fasthttp.RequestCtx {
+		var c fasthttp.RequestCtx
+		c.SetUserValue("cid", cnr)
+		a.responseHeaders(route, func(c *fasthttp.RequestCtx) {
+			c.SetStatusCode(status)
+		})(&c)
+		return &c
+	}
+
+	c := request(routeGet, "images", fasthttp.StatusOK)
+	require.Equal(t, "public, max-age=86400", string(c.Response.Header.Peek("Cache-Control")))
+	require.Equal(t, "gate-1", string(c.Response.Header.Peek("X-Served-By")))
+
+	c = request(routeGet, "documents", fasthttp.StatusOK)
+	require.Empty(t, c.Response.Header.Peek("Cache-Control"))
+	require.Equal(t, "gate-1", string(c.Response.Header.Peek("X-Served-By")))
+
+	c = request(routeZip, "images", fasthttp.StatusOK)
+	require.Empty(t, c.Response.Header.Peek("Cache-Control"))
+
+	c = request(routeGet, "images", fasthttp.StatusNotFound)
+	require.Empty(t, c.Response.Header.Peek("Cache-Control"))
+	require.Empty(t, c.Response.Header.Peek("X-Served-By"))
+}
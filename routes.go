@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"sync"
+
+	"github.com/fasthttp/router"
+	"github.com/nspcc-dev/neofs-http-gw/s3"
+	"github.com/valyala/fasthttp"
+)
+
+// Route names which route middlewares and response header rules refer to.
+const (
+	routeUpload           = "upload"
+	routeCopy             = "copy"
+	routeMove             = "move"
+	routeStorageGroup     = "storagegroup"
+	routeCreateContainer  = "container_create"
+	routeDeleteContainer  = "container_delete"
+	routeSetContainerEACL = "container_eacl_write"
+	routeGet              = "get"
+	routeByAddress        = "by_address"
+	routeGetByAttribute   = "get_by_attribute"
+	routeZip              = "zip"
+	routeSearch           = "search"
+	routeContainers       = "containers"
+	routeContainer        = "container"
+	routeContainerEACL    = "container_eacl"
+	routeAttributes       = "attributes"
+	routeChecksum         = "checksum"
+	routeArchive          = "archive"
+	routeJobs             = "jobs"
+	routeProgress         = "progress"
+	routeWebDAV           = "webdav"
+	routeWebDAVWrite      = "webdav_write"
+	routeS3               = "s3"
+	routeS3Write          = "s3_write"
+)
+
+// Operations granted by access permissions.
+const (
+	operationUpload   = "upload"
+	operationDownload = "download"
+)
+
+// routeOperation returns the operation performed by the route.
+func routeOperation(route string) string {
+	if route == routeUpload || route == routeCopy || route == routeMove || route == routeStorageGroup ||
+		route == routeCreateContainer || route == routeDeleteContainer || route == routeSetContainerEACL ||
+		route == routeWebDAVWrite || route == routeS3Write {
+		return operationUpload
+	}
+	return operationDownload
+}
+
+// containerOperation is the operation the request performs on the container
+// specified by ID or by name.
+type containerOperation struct {
+	container string
+	operation string
+}
+
+// requestContainers returns the containers the request to the route operates
+// on, the container is empty for routes without it. Copies read the source
+// container and write to the target one given with "to" argument, moves also
+// write to the source container since the source object is deleted.
+func requestContainers(route string, c *fasthttp.RequestCtx) []containerOperation {
+	scid, _ := c.UserValue("cid").(string)
+	to := string(c.QueryArgs().Peek("to"))
+
+	var res []containerOperation
+	switch route {
+	case routeCopy:
+		res = append(res, containerOperation{container: scid, operation: operationDownload})
+	case routeMove:
+		res = append(res, containerOperation{container: scid, operation: operationDownload},
+			containerOperation{container: scid, operation: operationUpload})
+	default:
+		return []containerOperation{{container: scid, operation: routeOperation(route)}}
+	}
+	if to != "" && to != scid {
+		res = append(res, containerOperation{container: to, operation: operationUpload})
+	}
+	return res
+}
+
+// routeNameKey is the user value route names are passed with by routeNames.
+const routeNameKey = "__route_name"
+
+// routeNames finds names of the routes requests go to before they're routed,
+// so that fasthttp hooks treat requests like route middlewares do. Routes are
+// added along with the handlers and matched the same way, they can be added
+// while the server is running if it waits for NeoFS nodes.
+type routeNames struct {
+	mu       sync.RWMutex
+	r        *router.Router
+	s3Domain string
+	ctxPool  sync.Pool
+}
+
+func newRouteNames() *routeNames {
+	return &routeNames{
+		r:       router.New(),
+		ctxPool: sync.Pool{New: func() interface{} { return new(fasthttp.RequestCtx) }},
+	}
+}
+
+// add adds the name of the route with the method and the path.
+func (n *routeNames) add(method, path, route string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.r.Handle(method, path, func(c *fasthttp.RequestCtx) {
+		c.SetUserValue(routeNameKey, route)
+	})
+}
+
+// setS3Domain makes virtual-hosted-style S3 requests to the domain matched.
+func (n *routeNames) setS3Domain(domain string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.s3Domain = domain
+}
+
+// lookup returns the name of the route the request goes to, it's empty if
+// there is no such route. Virtual-hosted-style S3 requests are matched as
+// path-style ones if the S3 domain is set.
+func (n *routeNames) lookup(h *fasthttp.RequestHeader) string {
+	var uri fasthttp.URI
+	if err := uri.Parse(nil, h.RequestURI()); err != nil {
+		return ""
+	}
+	path := string(uri.Path())
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.s3Domain != "" {
+		if bucket := s3.VirtualHostBucket(n.s3Domain, h.Host()); bucket != "" {
+			path = s3.Prefix + "/" + bucket + path
+		}
+	}
+
+	handler, _ := n.r.Lookup(string(h.Method()), path, nil)
+	if handler == nil {
+		return ""
+	}
+
+	c := n.ctxPool.Get().(*fasthttp.RequestCtx)
+	defer n.ctxPool.Put(c)
+	handler(c)
+	route, _ := c.UserValue(routeNameKey).(string)
+	c.ResetUserValues()
+	return route
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fasthttp/router"
+	"github.com/nspcc-dev/neofs-http-gw/s3"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-http-gw/webdav"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// testRouteNames returns the names of some gateway, WebDAV and S3 routes.
+func testRouteNames() *routeNames {
+	n := newRouteNames()
+	for _, prefix := range []string{"", apiV2Prefix} {
+		n.add(fasthttp.MethodPost, prefix+"/upload/{cid}", routeUpload)
+		n.add(fasthttp.MethodGet, prefix+"/get/{cid}/{oid}", routeGet)
+		n.add(fasthttp.MethodGet, prefix+"/get_by_attribute/{cid}/{attr_key}/{attr_val:*}", routeGetByAttribute)
+		n.add(fasthttp.MethodGet, prefix+"/zip/{cid}/{prefix:*}", routeZip)
+		n.add(fasthttp.MethodGet, prefix+"/checksum/{cid}/{oid}", routeChecksum)
+	}
+	n.add(fasthttp.MethodGet, webdav.Prefix+"/{cid}/{path:*}", routeWebDAV)
+	n.add(fasthttp.MethodPut, webdav.Prefix+"/{cid}/{path:*}", routeWebDAVWrite)
+	n.add(fasthttp.MethodDelete, webdav.Prefix+"/{cid}/{path:*}", routeWebDAVWrite)
+	n.add(webdav.MethodMkcol, webdav.Prefix+"/{cid}/{path:*}", routeWebDAVWrite)
+	n.add(webdav.MethodMove, webdav.Prefix+"/{cid}/{path:*}", routeWebDAVWrite)
+	n.add(fasthttp.MethodGet, s3.Prefix+"/{cid}/{key:*}", routeS3)
+	n.add(fasthttp.MethodPut, s3.Prefix+"/{cid}/{key:*}", routeS3Write)
+	return n
+}
+
+func TestRouteNames(t *testing.T) {
+	n := testRouteNames()
+	n.setS3Domain("s3.example.com")
+
+	routeOf := func(method, host, uri string) string {
+		var h fasthttp.RequestHeader
+		h.SetMethod(method)
+		h.SetHost(host)
+		h.SetRequestURI(uri)
+		return n.lookup(&h)
+	}
+
+	require.Equal(t, routeUpload, routeOf(fasthttp.MethodPost, "", "/upload/cid"))
+	require.Equal(t, routeUpload, routeOf(fasthttp.MethodPost, "", "/v2/upload/cid?async=true"))
+	require.Equal(t, routeChecksum, routeOf(fasthttp.MethodGet, "", "/checksum/cid/oid"))
+	require.Equal(t, routeWebDAV, routeOf(fasthttp.MethodGet, "", "/webdav/cid/dir/file"))
+	require.Equal(t, routeWebDAVWrite, routeOf(webdav.MethodMkcol, "", "/webdav/cid/dir"))
+	require.Equal(t, routeWebDAVWrite, routeOf(webdav.MethodMove, "", "/webdav/cid/dir"))
+	require.Equal(t, routeWebDAVWrite, routeOf(fasthttp.MethodDelete, "", "/webdav/cid/dir"))
+	require.Equal(t, routeS3Write, routeOf(fasthttp.MethodPut, "", "/s3/bucket/key"))
+	require.Equal(t, routeS3Write, routeOf(fasthttp.MethodPut, "bucket.s3.example.com", "/key"))
+	require.Equal(t, routeS3, routeOf(fasthttp.MethodGet, "bucket.s3.example.com:8080", "/dir/key"))
+	require.Equal(t, "", routeOf(fasthttp.MethodGet, "", "/metrics"))
+	require.Equal(t, "", routeOf(fasthttp.MethodPut, "", "/get/cid/oid"))
+}
+
+func TestRequestContainers(t *testing.T) {
+	containers := func(route, uri string) []containerOperation {
+		var c fasthttp.RequestCtx
+		c.Request.SetRequestURI(uri)
+		c.SetUserValue("cid", "src")
+		return requestContainers(route, &c)
+	}
+
+	require.Equal(t, []containerOperation{{"src", operationUpload}}, containers(routeUpload, "/upload/src"))
+	require.Equal(t, []containerOperation{{"src", operationDownload}}, containers(routeGet, "/get/src/oid"))
+	require.Equal(t, []containerOperation{
+		{"src", operationDownload},
+		{"dst", operationUpload},
+	}, containers(routeCopy, "/copy/src/oid?to=dst"))
+	require.Equal(t, []containerOperation{
+		{"src", operationDownload},
+		{"src", operationUpload},
+	}, containers(routeMove, "/move/src/oid"))
+	require.Equal(t, []containerOperation{
+		{"src", operationDownload},
+		{"src", operationUpload},
+		{"dst", operationUpload},
+	}, containers(routeMove, "/move/src/oid?to=dst"))
+}
+
+func TestWebDAVWriteRoutes(t *testing.T) {
+	methods := []string{fasthttp.MethodPut, fasthttp.MethodDelete, webdav.MethodMkcol, webdav.MethodMove}
+
+	serve := func(writable bool) fasthttp.RequestHandler {
+		v := viper.New()
+		v.Set(cfgWebDAVWriteEnabled, writable)
+		a := &app{cfg: v, log: zap.NewNop(), routeNames: newRouteNames()}
+		r := router.New()
+		a.attachWebDAV(r, webdav.New(context.Background(), &utils.AppParams{Logger: a.log}, webdav.Settings{Writable: writable}))
+		return r.Handler
+	}
+
+	h := serve(false)
+	for _, method := range methods {
+		var c fasthttp.RequestCtx
+		c.Request.Header.SetMethod(method)
+		c.Request.SetRequestURI("/webdav/cid/dir/file")
+		h(&c)
+		require.Equal(t, fasthttp.StatusMethodNotAllowed, c.Response.StatusCode(), method)
+	}
+
+	var c fasthttp.RequestCtx
+	c.Request.Header.SetMethod(fasthttp.MethodOptions)
+	c.Request.SetRequestURI("/webdav/cid")
+	h(&c)
+	require.Equal(t, "OPTIONS, PROPFIND, GET, HEAD", string(c.Response.Header.Peek(fasthttp.HeaderAllow)))
+
+	n := newRouteNames()
+	a := &app{cfg: viper.New(), log: zap.NewNop(), routeNames: n}
+	a.cfg.Set(cfgWebDAVWriteEnabled, true)
+	a.attachWebDAV(router.New(), webdav.New(context.Background(), &utils.AppParams{Logger: a.log}, webdav.Settings{Writable: true}))
+	for _, method := range methods {
+		var h fasthttp.RequestHeader
+		h.SetMethod(method)
+		h.SetRequestURI("/webdav/cid/dir/file")
+		require.Equal(t, routeWebDAVWrite, n.lookup(&h), method)
+	}
+}
@@ -0,0 +1,219 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redactedValue = "<redacted>"
+
+// runtimeSetting is a config key which can be changed at runtime: parse
+// validates the value, apply makes the gateway use the value set in
+// runtimeValues.
+type runtimeSetting struct {
+	parse func(a *app, value string) (interface{}, error)
+	apply func(a *app)
+}
+
+// runtimeValues are the values of the settings changed at runtime, they
+// override the config. The config itself isn't changed after start since
+// viper isn't safe for concurrent use.
+type runtimeValues struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+func (r *runtimeValues) get(key string) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.values[key]
+	return v, ok
+}
+
+func (r *runtimeValues) set(values map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values == nil {
+		r.values = make(map[string]interface{}, len(values))
+	}
+	for key, v := range values {
+		r.values[key] = v
+	}
+}
+
+// runtimeFloat64 returns the value of the setting changed at runtime, the
+// config value is returned if it isn't changed.
+func (a *app) runtimeFloat64(key string) float64 {
+	if v, ok := a.runtime.get(key); ok {
+		return v.(float64)
+	}
+	return a.cfg.GetFloat64(key)
+}
+
+var errRateLimitDisabled = errors.New("rate limiting is disabled on start")
+
+func parseRateLimit(a *app, value string) (interface{}, error) {
+	if a.rateLimiter == nil {
+		return nil, errRateLimitDisabled
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return nil, fmt.Errorf("non-negative number expected: %s", value)
+	}
+	return f, nil
+}
+
+func applyRateLimit(a *app) {
+	a.rateLimiter.setLimits(
+		a.runtimeFloat64(cfgRateLimitRequests),
+		a.runtimeFloat64(cfgRateLimitRequestsBurst),
+		a.runtimeFloat64(cfgRateLimitBandwidth),
+		a.runtimeFloat64(cfgRateLimitBandwidthBurst),
+	)
+}
+
+var rateLimitSetting = runtimeSetting{parse: parseRateLimit, apply: applyRateLimit}
+
+var runtimeSettings = map[string]runtimeSetting{
+	cfgLoggerLevel: {
+		parse: func(a *app, value string) (interface{}, error) {
+			if a.logLevel == nil {
+				return nil, errors.New("logger level can't be changed")
+			}
+			var lvl zapcore.Level
+			if err := lvl.UnmarshalText([]byte(value)); err != nil {
+				return nil, err
+			}
+			return lvl.String(), nil
+		},
+		apply: func(a *app) {
+			value, _ := a.runtime.get(cfgLoggerLevel)
+			var lvl zapcore.Level
+			_ = lvl.UnmarshalText([]byte(value.(string)))
+			a.logLevel.SetLevel(lvl)
+		},
+	},
+	cfgRateLimitRequests:       rateLimitSetting,
+	cfgRateLimitRequestsBurst:  rateLimitSetting,
+	cfgRateLimitBandwidth:      rateLimitSetting,
+	cfgRateLimitBandwidthBurst: rateLimitSetting,
+	cfgMaintenance: {
+		parse: func(_ *app, value string) (interface{}, error) {
+			return strconv.ParseBool(value)
+		},
+		apply: func(a *app) {
+			value, _ := a.runtime.get(cfgMaintenance)
+			a.setMaintenance(value.(bool))
+		},
+	},
+}
+
+// isSecretKey checks if the config key holds a secret which isn't to be shown.
+func isSecretKey(key string) bool {
+	for _, suffix := range []string{"passphrase", "password", "token", "secret"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(key, cfgAPIKeys+".") && strings.HasSuffix(key, ".key")
+}
+
+// effectiveConfig returns all config values with secrets redacted, the
+// values changed at runtime replace config ones.
+func (a *app) effectiveConfig() map[string]interface{} {
+	res := make(map[string]interface{})
+	for _, key := range a.cfg.AllKeys() {
+		if isSecretKey(key) {
+			res[key] = redactedValue
+			continue
+		}
+		if v, ok := a.runtime.get(key); ok {
+			res[key] = v
+			continue
+		}
+		res[key] = a.cfg.Get(key)
+	}
+	return res
+}
+
+func (a *app) configHandler(c *fasthttp.RequestCtx) {
+	data, err := json.Marshal(a.effectiveConfig())
+	if err != nil {
+		a.log.Error("could not encode config", zap.Error(err))
+		response.Error(c, "could not encode config: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json")
+	c.SetBody(data)
+}
+
+// updateConfigHandler applies the settings from JSON object body, nothing is
+// applied if any of them is invalid. Changes aren't persisted and the config
+// isn't changed, they're kept in runtimeValues.
+func (a *app) updateConfigHandler(c *fasthttp.RequestCtx) {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(c.Request.Body(), &changes); err != nil {
+		response.Error(c, "could not decode settings: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	keys := make([]string, 0, len(changes))
+	values := make(map[string]interface{}, len(changes))
+	for key, raw := range changes {
+		setting, ok := runtimeSettings[key]
+		if !ok {
+			response.Error(c, "setting can't be changed at runtime: "+key, fasthttp.StatusBadRequest)
+			return
+		}
+		value, err := setting.parse(a, fmt.Sprint(raw))
+		if err != nil {
+			response.Error(c, "invalid "+key+": "+err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+	sort.Strings(keys)
+
+	// All values are set first since rate limits are applied together.
+	a.runtime.set(values)
+	for _, key := range keys {
+		runtimeSettings[key].apply(a)
+		a.log.Info("setting changed at runtime", zap.String("key", key), zap.Any("value", values[key]))
+	}
+
+	c.SetStatusCode(fasthttp.StatusOK)
+}
+
+func (a *app) setMaintenance(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&a.maintenance, v)
+}
+
+// maintenanceMode rejects requests with 503 status while the gateway is in
+// maintenance mode, the mode can be switched at runtime.
+func (a *app) maintenanceMode(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		if atomic.LoadInt32(&a.maintenance) == 1 {
+			response.Error(c, "gateway is in maintenance mode", fasthttp.StatusServiceUnavailable)
+			return
+		}
+
+		h(c)
+	}
+}
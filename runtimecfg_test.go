@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRuntimeConfig(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgLoggerLevel, "debug")
+	v.Set(cfgMaintenance, false)
+	v.Set(cfgWalletPassphrase, "pwd")
+	v.Set(cfgAdminToken, "secret")
+	v.Set(cfgAPIKeys+".0.key", "key")
+	v.Set(cfgAPIKeys+".0.operations", "upload")
+	v.Set(cfgWebhooksEndpoints+".0.secret", "hmac")
+
+	lvl := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	a := &app{cfg: v, log: zap.NewNop(), logLevel: &lvl}
+
+	patch := func(body string) int {
+		var c fasthttp.RequestCtx
+		c.Request.SetBodyString(body)
+		a.updateConfigHandler(&c)
+		return c.Response.StatusCode()
+	}
+
+	t.Run("view", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		a.configHandler(&c)
+
+		var cfg map[string]interface{}
+		require.NoError(t, json.Unmarshal(c.Response.Body(), &cfg))
+		require.Equal(t, "debug", cfg[cfgLoggerLevel])
+		require.Equal(t, redactedValue, cfg[cfgWalletPassphrase])
+		require.Equal(t, redactedValue, cfg[cfgAdminToken])
+		require.Equal(t, redactedValue, cfg[cfgAPIKeys+".0.key"])
+		require.Equal(t, redactedValue, cfg[cfgWebhooksEndpoints+".0.secret"])
+		require.Equal(t, "upload", cfg[cfgAPIKeys+".0.operations"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Equal(t, fasthttp.StatusBadRequest, patch(`{"logger.level":"info","listen_address":":80"}`))
+		require.Equal(t, fasthttp.StatusBadRequest, patch(`{"logger.level":"info","maintenance":"maybe"}`))
+		require.Equal(t, fasthttp.StatusBadRequest, patch(`{"rate_limit.requests":10}`))
+		require.Equal(t, fasthttp.StatusBadRequest, patch(`[]`))
+		require.Equal(t, "debug", v.GetString(cfgLoggerLevel))
+		require.Equal(t, zapcore.DebugLevel, lvl.Level())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		a.rateLimiter = &rateLimiter{key: rateLimitKeyIP, requests: 1, clients: make(map[string]*clientLimits)}
+
+		require.Equal(t, fasthttp.StatusOK, patch(`{"logger.level":"warn","maintenance":true,"rate_limit.requests":5}`))
+		require.Equal(t, zapcore.WarnLevel, lvl.Level())
+		require.EqualValues(t, 5, a.rateLimiter.requests)
+		require.Equal(t, "debug", v.GetString(cfgLoggerLevel))
+		require.Equal(t, "warn", a.effectiveConfig()[cfgLoggerLevel])
+		require.Equal(t, true, a.effectiveConfig()[cfgMaintenance])
+
+		h := a.maintenanceMode(func(c *fasthttp.RequestCtx) {
+			c.SetStatusCode(fasthttp.StatusNoContent)
+		})
+
+		var c fasthttp.RequestCtx
+		h(&c)
+		require.Equal(t, fasthttp.StatusServiceUnavailable, c.Response.StatusCode())
+
+		require.Equal(t, fasthttp.StatusOK, patch(`{"maintenance":false}`))
+		c.Response.Reset()
+		h(&c)
+		require.Equal(t, fasthttp.StatusNoContent, c.Response.StatusCode())
+	})
+}
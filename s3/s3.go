@@ -0,0 +1,465 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/resolver"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Prefix is the path prefix of path-style S3 requests.
+const Prefix = "/s3"
+
+const (
+	// attributeFilePath is the attribute object keys are stored in, the same
+	// one is used by NeoFS S3 gateway.
+	attributeFilePath = "FilePath"
+
+	metadataHeaderPrefix = "X-Amz-Meta-"
+	contentSHA256Header  = "X-Amz-Content-Sha256"
+
+	// maxVersions is the maximum number of objects with the same key the newest
+	// one is selected from.
+	maxVersions = 100
+)
+
+// Handler serves GetObject, HeadObject and PutObject requests with buckets
+// being containers and keys being FilePath attributes. Request signatures
+// can't be verified since the gateway has no access keys, so only anonymous
+// requests and ones with NeoFS bearer token are served.
+type Handler struct {
+	appCtx            context.Context
+	log               *zap.Logger
+	pool              *utils.PoolHolder
+	containerResolver *resolver.ContainerResolver
+	notifier          utils.EventNotifier
+	attributeLimits   utils.AttributeLimits
+	scanned           func(string, *cid.ID) bool
+}
+
+// request is the S3 request to the object.
+type request struct {
+	*fasthttp.RequestCtx
+	ctx    context.Context
+	log    *zap.Logger
+	pool   *pool.Pool
+	cnrID  *cid.ID
+	key    string
+	btoken *bearer.Token
+}
+
+// s3Error is the body of S3 error responses.
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+// New creates a new S3 handler.
+func New(ctx context.Context, params *utils.AppParams) *Handler {
+	return &Handler{
+		appCtx:            ctx,
+		log:               params.Logger,
+		pool:              params.Pool,
+		containerResolver: params.Resolver,
+		notifier:          params.Notifier,
+		attributeLimits:   params.AttributeLimits,
+		scanned:           params.Scanned,
+	}
+}
+
+// writeError replies with S3 error, there is no body for HEAD requests.
+func writeError(c *fasthttp.RequestCtx, code, msg string, status int) {
+	c.SetStatusCode(status)
+	if c.IsHead() {
+		return
+	}
+
+	data, err := xml.Marshal(s3Error{Code: code, Message: msg, Resource: string(c.Path())})
+	if err != nil {
+		return
+	}
+	c.SetContentType("application/xml")
+	c.SetBody(append([]byte(xml.Header), data...))
+}
+
+// signed tells whether the request is signed with AWS access key.
+func signed(h *fasthttp.RequestHeader, args *fasthttp.Args) bool {
+	auth := h.Peek(fasthttp.HeaderAuthorization)
+	return bytes.HasPrefix(auth, []byte("AWS4-HMAC-SHA256 ")) || bytes.HasPrefix(auth, []byte("AWS ")) ||
+		args.Has("X-Amz-Signature") || args.Has("Signature")
+}
+
+func (h *Handler) newRequest(c *fasthttp.RequestCtx) (*request, bool) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		key, _  = c.UserValue("key").(string)
+		log     = h.log.With(zap.String("bucket", scid), zap.String("key", key))
+		ctx     = utils.RequestContext(c, h.appCtx)
+	)
+
+	key, err := url.PathUnescape(key)
+	if err != nil || key == "" {
+		writeError(c, "InvalidArgument", "invalid object key", fasthttp.StatusBadRequest)
+		return nil, false
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch and store bearer token", zap.Error(err))
+		writeError(c, "InvalidToken", "could not fetch bearer token: "+err.Error(), fasthttp.StatusBadRequest)
+		return nil, false
+	}
+	btoken, _ := tokens.LoadBearerToken(c)
+	if btoken == nil && signed(&c.Request.Header, c.QueryArgs()) {
+		writeError(c, "AccessDenied", "signatures aren't verified, send anonymous requests "+
+			"or NeoFS bearer token as session token", fasthttp.StatusForbidden)
+		return nil, false
+	}
+
+	cnrID, err := utils.GetContainerID(ctx, scid, h.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		writeError(c, "NoSuchBucket", "bucket not found", fasthttp.StatusNotFound)
+		return nil, false
+	}
+
+	return &request{
+		RequestCtx: c,
+		ctx:        ctx,
+		log:        log,
+		pool:       h.pool.Acquire(c),
+		cnrID:      cnrID,
+		key:        key,
+		btoken:     btoken,
+	}, true
+}
+
+func (r *request) handleError(msg string, err error) {
+	r.log.Error(msg, zap.Error(err))
+
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		writeError(r.RequestCtx, "NoSuchKey", msg+": "+err.Error(), fasthttp.StatusNotFound)
+	case strings.Contains(err.Error(), "access denied"):
+		writeError(r.RequestCtx, "AccessDenied", msg+": "+err.Error(), fasthttp.StatusForbidden)
+	default:
+		writeError(r.RequestCtx, "InternalError", msg+": "+err.Error(), fasthttp.StatusBadGateway)
+	}
+}
+
+func (r *request) address(id oid.ID) address.Address {
+	var addr address.Address
+	addr.SetContainerID(*r.cnrID)
+	addr.SetObjectID(id)
+	return addr
+}
+
+// timestamp returns Timestamp attribute of the object, zero if there is none.
+func timestamp(obj *object.Object) int64 {
+	for _, attr := range obj.Attributes() {
+		if attr.Key() == object.AttributeTimestamp {
+			ts, _ := strconv.ParseInt(attr.Value(), 10, 64)
+			return ts
+		}
+	}
+	return 0
+}
+
+// find returns the newest (by Timestamp, then by ID) object with the key,
+// false if there is none.
+func (r *request) find() (*object.Object, bool) {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	filters.AddFilter(attributeFilePath, r.key, object.MatchStringEqual)
+
+	var prm pool.PrmObjectSearch
+	prm.SetContainerID(*r.cnrID)
+	prm.SetFilters(filters)
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	res, err := r.pool.SearchObjects(r.ctx, prm)
+	if err != nil {
+		r.handleError("could not search objects", err)
+		return nil, false
+	}
+	defer res.Close()
+
+	var ids []oid.ID
+	err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return len(ids) == maxVersions
+	})
+	if err != nil {
+		r.handleError("could not search objects", err)
+		return nil, false
+	}
+
+	var (
+		newest   *object.Object
+		newestID string
+	)
+	for _, id := range ids {
+		var prm pool.PrmObjectHead
+		prm.SetAddress(r.address(id))
+		if r.btoken != nil {
+			prm.UseBearer(*r.btoken)
+		}
+		if key := utils.RequestKey(r.RequestCtx); key != nil {
+			prm.UseKey(key)
+		}
+
+		obj, err := r.pool.HeadObject(r.ctx, prm)
+		if err != nil {
+			r.handleError("could not get object header", err)
+			return nil, false
+		}
+		obj.SetID(id)
+
+		ts := timestamp(obj)
+		if newest == nil || ts > timestamp(newest) || ts == timestamp(newest) && id.String() > newestID {
+			newest, newestID = obj, id.String()
+		}
+	}
+
+	if newest == nil {
+		writeError(r.RequestCtx, "NoSuchKey", "object not found", fasthttp.StatusNotFound)
+		return nil, false
+	}
+	return newest, true
+}
+
+// setHeaders sets object headers, attributes other than the ones the gateway
+// sets are user metadata.
+func setHeaders(h *fasthttp.ResponseHeader, id oid.ID, obj *object.Object) {
+	contentType := "application/octet-stream"
+	for _, attr := range obj.Attributes() {
+		switch key := attr.Key(); key {
+		case object.AttributeContentType:
+			contentType = attr.Value()
+		case object.AttributeTimestamp:
+			if ts, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+				h.Set(fasthttp.HeaderLastModified, time.Unix(ts, 0).UTC().Format(http.TimeFormat))
+			}
+		case attributeFilePath, object.AttributeFileName:
+		default:
+			if !strings.HasPrefix(key, utils.SystemAttributePrefix) {
+				h.Set(metadataHeaderPrefix+key, attr.Value())
+			}
+		}
+	}
+
+	h.SetContentType(contentType)
+	h.Set(fasthttp.HeaderContentLength, strconv.FormatUint(obj.PayloadSize(), 10))
+	h.Set(fasthttp.HeaderETag, `"`+id.String()+`"`)
+	h.Set(fasthttp.HeaderAcceptRanges, "none")
+}
+
+// GetObject handles GET and HEAD requests of the objects.
+func (h *Handler) GetObject(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+
+	obj, ok := r.find()
+	if !ok {
+		return
+	}
+	id, _ := obj.ID()
+
+	setHeaders(&c.Response.Header, id, obj)
+	if c.IsHead() {
+		return
+	}
+
+	var prm pool.PrmObjectGet
+	prm.SetAddress(r.address(id))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(c); key != nil {
+		prm.UseKey(key)
+	}
+
+	res, err := r.pool.GetObject(r.ctx, prm)
+	if err != nil {
+		r.handleError("could not receive object", err)
+		return
+	}
+
+	utils.SetBodyStream(c, res.Payload, int(obj.PayloadSize()))
+}
+
+// metadataAttributes returns object attributes for the key and user metadata
+// headers of the request, metadata can't override the attributes set by the
+// gateway and is checked against the limits.
+func metadataAttributes(h *fasthttp.RequestHeader, key string, now time.Time, limits utils.AttributeLimits) (map[string]string, error) {
+	attrs := map[string]string{
+		attributeFilePath:         key,
+		object.AttributeFileName:  key[strings.LastIndexByte(key, '/')+1:],
+		object.AttributeTimestamp: strconv.FormatInt(now.Unix(), 10),
+	}
+	if contentType := h.ContentType(); len(contentType) != 0 {
+		attrs[object.AttributeContentType] = string(contentType)
+	}
+
+	meta := make(map[string]string)
+	prefix := []byte(metadataHeaderPrefix)
+	h.VisitAll(func(k, v []byte) {
+		if bytes.HasPrefix(k, prefix) && len(k) > len(prefix) && len(v) != 0 {
+			name := string(k[len(prefix):])
+			for reserved := range attrs {
+				if strings.EqualFold(name, reserved) {
+					return
+				}
+			}
+			meta[name] = string(v)
+		}
+	})
+	if err := utils.ValidateAttributes(meta, limits); err != nil {
+		return nil, err
+	}
+
+	for k, v := range meta {
+		attrs[k] = v
+	}
+	return attrs, nil
+}
+
+// PutObject handles PUT requests storing the objects. Chunked payload signing
+// isn't supported. Payloads aren't scanned by antivirus, so uploads to the
+// containers scanned are refused.
+func (h *Handler) PutObject(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+
+	if bytes.HasPrefix(c.Request.Header.Peek(contentSHA256Header), []byte("STREAMING-")) {
+		writeError(c, "NotImplemented", "chunked payload signing isn't supported", fasthttp.StatusNotImplemented)
+		return
+	}
+
+	if scid, _ := c.UserValue("cid").(string); h.scanned != nil && h.scanned(scid, r.cnrID) {
+		writeError(c, "AccessDenied", "uploads to the bucket are scanned by antivirus, use upload route",
+			fasthttp.StatusForbidden)
+		return
+	}
+
+	var owner user.ID
+	switch key := utils.RequestKey(c); {
+	case r.btoken != nil:
+		owner, _ = r.btoken.Issuer()
+	case key != nil:
+		user.IDFromKey(&owner, key.PublicKey)
+	case r.pool.OwnerID() != nil:
+		owner = *r.pool.OwnerID()
+	}
+
+	attrs, err := metadataAttributes(&c.Request.Header, r.key, time.Now(), h.attributeLimits)
+	if err != nil {
+		writeError(c, "InvalidArgument", "invalid metadata: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	attributes := make([]object.Attribute, 0, len(attrs))
+	for k, v := range attrs {
+		attr := object.NewAttribute()
+		attr.SetKey(k)
+		attr.SetValue(v)
+		attributes = append(attributes, *attr)
+	}
+
+	obj := object.New()
+	obj.SetContainerID(*r.cnrID)
+	obj.SetOwnerID(&owner)
+	obj.SetAttributes(attributes...)
+
+	body := c.RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Request.Body())
+	}
+
+	var (
+		prm    pool.PrmObjectPut
+		stored int64
+	)
+	prm.SetHeader(*obj)
+	prm.SetPayload(utils.CountingReader(body, func(n int) { stored += int64(n) }))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(c); key != nil {
+		prm.UseKey(key)
+	}
+
+	id, err := r.pool.PutObject(r.ctx, prm)
+	if err != nil {
+		r.handleError("could not store object", err)
+		return
+	}
+
+	if h.notifier != nil {
+		h.notifier.Notify(utils.ObjectEvent{
+			Type:        utils.EventObjectCreated,
+			ContainerID: r.cnrID.String(),
+			ObjectID:    id.String(),
+			Size:        int64(c.Request.Header.ContentLength()),
+			Attributes:  attrs,
+			Time:        time.Now(),
+		})
+	}
+
+	utils.AddRequestObject(c, id.String())
+	utils.AddStoredBytes(c, stored)
+	c.Response.Header.Set(fasthttp.HeaderETag, `"`+id.String()+`"`)
+	c.SetStatusCode(fasthttp.StatusOK)
+}
+
+// VirtualHosts serves virtual-hosted-style requests to <bucket>.<domain> as
+// path-style ones.
+func VirtualHosts(domain string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		if bucket := VirtualHostBucket(domain, c.Host()); bucket != "" {
+			c.Request.SetRequestURI(Prefix + "/" + bucket + string(c.Request.Header.RequestURI()))
+		}
+		h(c)
+	}
+}
+
+// VirtualHostBucket returns the bucket of virtual-hosted-style request to
+// <bucket>.<domain> host, it's empty if the host isn't a subdomain of the
+// domain.
+func VirtualHostBucket(domain string, hostHeader []byte) string {
+	host := strings.ToLower(string(hostHeader))
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+
+	if bucket := strings.TrimSuffix(host, "."+strings.ToLower(domain)); bucket != host {
+		return bucket
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// secretFileSuffix is the suffix of the keys containing secret file paths,
+	// e.g. wallet.passphrase_file or HTTP_GW_WALLET_PASSPHRASE_FILE.
+	secretFileSuffix = "_file"
+	// vaultRefPrefix is the prefix of secret values to be read from Vault,
+	// e.g. vault:secret/data/neofs-http-gw#passphrase.
+	vaultRefPrefix = "vault:"
+
+	vaultTimeout = 10 * time.Second
+)
+
+// secretKeys are config keys which can be loaded from files or Vault. Vault
+// token goes first to be used for the others.
+var secretKeys = []string{
+	cfgVaultToken,
+	cfgWalletPassphrase,
+	cfgBasicAuthPassword,
+	cfgAdminToken,
+	cfgNATSPassword,
+	cfgNATSToken,
+}
+
+// readSecrets reads secrets from the files set in corresponding *_file keys
+// and Vault references. Only the secrets loaded this way are returned, the
+// config itself isn't changed, so they can be read again later (e.g. on key
+// reload).
+func readSecrets(v *viper.Viper) (map[string]string, error) {
+	secrets := make(map[string]string)
+
+	for _, key := range secretKeys {
+		value := v.GetString(key)
+		if path := v.GetString(key + secretFileSuffix); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("could not read %s: %w", key, err)
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+			secrets[key] = value
+		}
+
+		if key == cfgVaultToken || !strings.HasPrefix(value, vaultRefPrefix) {
+			continue
+		}
+
+		token, _ := secretValue(v, secrets, cfgVaultToken)
+		secret, err := readVaultSecret(v, token, strings.TrimPrefix(value, vaultRefPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s from vault: %w", key, err)
+		}
+		secrets[key] = secret
+	}
+
+	return secrets, nil
+}
+
+// secretValue returns the secret loaded by readSecrets or the plain config
+// value, ok is false if the secret isn't set at all.
+func secretValue(v *viper.Viper, secrets map[string]string, key string) (string, bool) {
+	if value, ok := secrets[key]; ok {
+		return value, true
+	}
+	return v.GetString(key), v.IsSet(key)
+}
+
+// readVaultSecret reads the field of KV (version 1 or 2) secret referenced as
+// path#field.
+func readVaultSecret(v *viper.Viper, token, ref string) (string, error) {
+	path, field, ok := cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid vault reference, path#field expected: %s", ref)
+	}
+
+	address := v.GetString(cfgVaultAddress)
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" {
+		return "", errors.New("vault address isn't set")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := v.GetString(cfgVaultNamespace); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := (&http.Client{Timeout: vaultTimeout}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status of %s: %s", path, resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("could not decode secret: %w", err)
+	}
+
+	data := secret.Data
+	// KV version 2 keeps the secret in the nested data with metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok = data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret %s has no string field %s", path, field)
+	}
+	return value, nil
+}
+
+// secret returns the secret config value loaded on start.
+func (a *app) secret(key string) string {
+	value, _ := secretValue(a.cfg, a.secrets, key)
+	return value
+}
@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "passphrase")
+		require.NoError(t, os.WriteFile(path, []byte("secret\n"), 0600))
+
+		v := viper.New()
+		v.Set(cfgWalletPassphrase+secretFileSuffix, path)
+		secrets, err := readSecrets(v)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{cfgWalletPassphrase: "secret"}, secrets)
+		require.False(t, v.IsSet(cfgWalletPassphrase))
+
+		pwd, ok := secretValue(v, secrets, cfgWalletPassphrase)
+		require.True(t, ok)
+		require.Equal(t, "secret", pwd)
+
+		v.Set(cfgWalletPassphrase+secretFileSuffix, filepath.Join(t.TempDir(), "missing"))
+		_, err = readSecrets(v)
+		require.Error(t, err)
+	})
+
+	t.Run("vault", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			switch r.URL.Path {
+			case "/v1/secret/data/gw":
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+					"data":     map[string]string{"passphrase": "kv2"},
+					"metadata": map[string]interface{}{"version": 1},
+				}})
+			case "/v1/kv/gw":
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"password": "kv1"}})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenPath, []byte("token"), 0600))
+
+		v := viper.New()
+		v.Set(cfgVaultAddress, srv.URL)
+		v.Set(cfgVaultToken+secretFileSuffix, tokenPath)
+		v.Set(cfgWalletPassphrase, "vault:secret/data/gw#passphrase")
+		v.Set(cfgBasicAuthPassword, "vault:kv/gw#password")
+		secrets, err := readSecrets(v)
+		require.NoError(t, err)
+		require.Equal(t, "kv2", secrets[cfgWalletPassphrase])
+		require.Equal(t, "kv1", secrets[cfgBasicAuthPassword])
+		// References are kept to be read again on key reload.
+		require.Equal(t, "vault:secret/data/gw#passphrase", v.GetString(cfgWalletPassphrase))
+
+		for _, ref := range []string{"vault:secret/data/gw#missing", "vault:secret/data/other#passphrase", "vault:secret/data/gw"} {
+			v.Set(cfgWalletPassphrase, ref)
+			_, err = readSecrets(v)
+			require.Error(t, err, ref)
+		}
+	})
+}
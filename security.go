@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	hdrStrictTransportSecurity = "Strict-Transport-Security"
+	hdrContentTypeOptions      = "X-Content-Type-Options"
+	hdrContentSecurityPolicy   = "Content-Security-Policy"
+	hdrReferrerPolicy          = "Referrer-Policy"
+)
+
+// securityHeaders adds configured security headers to all responses. HSTS
+// header is sent over TLS only, as browsers ignore it otherwise.
+func (a *app) securityHeaders(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	var (
+		hsts    string
+		headers [][2]string
+	)
+
+	if maxAge := a.cfg.GetDuration(cfgSecurityHSTSMaxAge); maxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(maxAge.Seconds()), 10)
+		if a.cfg.GetBool(cfgSecurityHSTSIncludeSubdomains) {
+			hsts += "; includeSubDomains"
+		}
+		if a.cfg.GetBool(cfgSecurityHSTSPreload) {
+			hsts += "; preload"
+		}
+	}
+	if a.cfg.GetBool(cfgSecurityNoSniff) {
+		headers = append(headers, [2]string{hdrContentTypeOptions, "nosniff"})
+	}
+	if csp := a.cfg.GetString(cfgSecurityCSP); csp != "" {
+		headers = append(headers, [2]string{hdrContentSecurityPolicy, csp})
+	}
+	if policy := a.cfg.GetString(cfgSecurityReferrerPolicy); policy != "" {
+		headers = append(headers, [2]string{hdrReferrerPolicy, policy})
+	}
+
+	if hsts == "" && len(headers) == 0 {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		h(c)
+
+		// headers are set after the handler, since error responses are reset
+		for _, hdr := range headers {
+			c.Response.Header.Set(hdr[0], hdr[1])
+		}
+		if hsts != "" && c.IsTLS() {
+			c.Response.Header.Set(hdrStrictTransportSecurity, hsts)
+		}
+	}
+}
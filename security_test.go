@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := func(c *fasthttp.RequestCtx) {
+		c.Error("not found", fasthttp.StatusNotFound)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New()}
+
+		var c fasthttp.RequestCtx
+		a.securityHeaders(handler)(&c)
+
+		require.Empty(t, c.Response.Header.Peek(hdrContentTypeOptions))
+		require.Empty(t, c.Response.Header.Peek(hdrStrictTransportSecurity))
+	})
+
+	v := viper.New()
+	v.Set(cfgSecurityHSTSMaxAge, 365*24*time.Hour)
+	v.Set(cfgSecurityHSTSIncludeSubdomains, true)
+	v.Set(cfgSecurityNoSniff, true)
+	v.Set(cfgSecurityCSP, "default-src 'none'; sandbox")
+	v.Set(cfgSecurityReferrerPolicy, "no-referrer")
+	a := &app{cfg: v}
+
+	t.Run("plain text", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		a.securityHeaders(handler)(&c)
+
+		require.Equal(t, fasthttp.StatusNotFound, c.Response.StatusCode())
+		require.Equal(t, "nosniff", string(c.Response.Header.Peek(hdrContentTypeOptions)))
+		require.Equal(t, "default-src 'none'; sandbox", string(c.Response.Header.Peek(hdrContentSecurityPolicy)))
+		require.Equal(t, "no-referrer", string(c.Response.Header.Peek(hdrReferrerPolicy)))
+		require.Empty(t, c.Response.Header.Peek(hdrStrictTransportSecurity))
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		c.Init2(new(tlsAdaptorConn), nil, false)
+		a.securityHeaders(handler)(&c)
+
+		require.Equal(t, "max-age=31536000; includeSubDomains", string(c.Response.Header.Peek(hdrStrictTransportSecurity)))
+	})
+}
@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+const (
+	unixSchemePrefix      = "unix://"
+	defaultUnixSocketMode = 0660
+)
+
+type (
+	// serverInfo describes a single listener of the gateway.
+	serverInfo struct {
+		Address    string
+		SocketMode os.FileMode
+		HTTP2      bool
+		TLS        serverTLSInfo
+	}
+
+	serverTLSInfo struct {
+		Enabled  bool
+		CertFile string
+		KeyFile  string
+	}
+)
+
+// fetchServers returns listeners configured in server section. If there are
+// none, a single listener is made of legacy listen_address, tls_certificate
+// and tls_key parameters.
+func fetchServers(v *viper.Viper) []serverInfo {
+	var servers []serverInfo
+
+	for i := 0; ; i++ {
+		key := cfgServer + "." + strconv.Itoa(i) + "."
+
+		var srv serverInfo
+		srv.Address = v.GetString(key + "address")
+		srv.SocketMode = socketMode(v, key+cfgUnixSocketMode)
+		srv.HTTP2 = v.GetBool(key + cfgHTTP2)
+		srv.TLS.Enabled = v.GetBool(key + cfgTLSEnabled)
+		srv.TLS.CertFile = v.GetString(key + cfgTLSCertFile)
+		srv.TLS.KeyFile = v.GetString(key + cfgTLSKeyFile)
+
+		if srv.Address == "" {
+			break
+		}
+
+		servers = append(servers, srv)
+	}
+
+	if len(servers) != 0 {
+		return servers
+	}
+
+	certFile, keyFile := v.GetString(cfgTLSCertificate), v.GetString(cfgTLSKey)
+	return []serverInfo{{
+		Address:    v.GetString(cfgListenAddress),
+		SocketMode: socketMode(v, cfgUnixSocketMode),
+		HTTP2:      v.GetBool(cfgHTTP2),
+		TLS: serverTLSInfo{
+			Enabled:  certFile != "" || keyFile != "" || v.GetBool(cfgTLSAutocertEnabled),
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}}
+}
+
+// socketMode parses octal unix socket permissions, it falls back to the
+// default ones if the value is invalid or isn't set.
+func socketMode(v *viper.Viper, key string) os.FileMode {
+	mode, err := strconv.ParseUint(v.GetString(key), 8, 32)
+	if err != nil {
+		return defaultUnixSocketMode
+	}
+	return os.FileMode(mode)
+}
+
+// listen opens listener for the server or takes one of the activated ones.
+// TLS-enabled servers use configured certificate files if any, otherwise they
+// use certificate manager.
+func (a *app) listen(srv serverInfo, m *autocert.Manager, activated map[string]net.Listener) (net.Listener, error) {
+	var tlsCfg *tls.Config
+
+	if srv.TLS.Enabled {
+		var err error
+		if tlsCfg, err = a.tlsConfig(); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case srv.TLS.CertFile != "" || srv.TLS.KeyFile != "":
+			cert, err := tls.LoadX509KeyPair(srv.TLS.CertFile, srv.TLS.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not load TLS key pair: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		case m != nil:
+			tlsCfg.GetCertificate = m.GetCertificate
+			tlsCfg.NextProtos = append(tlsCfg.NextProtos, "http/1.1", acme.ALPNProto)
+		default:
+			return nil, errors.New("TLS is enabled, but neither certificate nor automatic certificates are configured")
+		}
+
+		if srv.HTTP2 {
+			protos := []string{http2.NextProtoTLS}
+			if len(tlsCfg.NextProtos) == 0 {
+				protos = append(protos, "http/1.1")
+			}
+			tlsCfg.NextProtos = append(protos, tlsCfg.NextProtos...)
+		}
+	}
+
+	var (
+		ln  net.Listener
+		err error
+	)
+	if strings.HasPrefix(srv.Address, systemdSchemePrefix) {
+		name := strings.TrimPrefix(srv.Address, systemdSchemePrefix)
+		var ok bool
+		if ln, ok = activated[name]; !ok {
+			return nil, fmt.Errorf("listener %s isn't passed by systemd", name)
+		}
+	} else if ln, err = listenAddress(srv.Address, srv.SocketMode); err != nil {
+		return nil, err
+	}
+
+	ln = chunkDeadlines(ln, a.cfg.GetDuration(cfgWebWriteChunkTimeout), a.log)
+	ln = throttle(ln, a.uploadBandwidth, a.downloadBandwidth)
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
+
+	a.log.Info("listening", zap.String("address", srv.Address),
+		zap.Bool("tls", srv.TLS.Enabled), zap.Bool("http2", srv.HTTP2))
+
+	return ln, nil
+}
+
+// listenAddress opens TCP listener or unix socket listener if the address has
+// unix:// scheme. Stale socket file is removed before binding.
+func listenAddress(address string, mode os.FileMode) (net.Listener, error) {
+	if !strings.HasPrefix(address, unixSchemePrefix) {
+		return net.Listen("tcp", address)
+	}
+
+	path := strings.TrimPrefix(address, unixSchemePrefix)
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err = os.Remove(path); err != nil {
+			return nil, fmt.Errorf("could not remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(path, mode); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("could not set socket permissions: %w", err)
+	}
+
+	return ln, nil
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchServers(t *testing.T) {
+	t.Run("legacy", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgListenAddress, "0.0.0.0:443")
+		v.Set(cfgTLSCertificate, "cert.pem")
+		v.Set(cfgTLSKey, "key.pem")
+
+		require.Equal(t, []serverInfo{{
+			Address:    "0.0.0.0:443",
+			SocketMode: defaultUnixSocketMode,
+			TLS:        serverTLSInfo{Enabled: true, CertFile: "cert.pem", KeyFile: "key.pem"},
+		}}, fetchServers(v))
+	})
+
+	t.Run("legacy autocert", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgListenAddress, "0.0.0.0:443")
+		v.Set(cfgTLSAutocertEnabled, true)
+
+		require.Equal(t, []serverInfo{{
+			Address:    "0.0.0.0:443",
+			SocketMode: defaultUnixSocketMode,
+			TLS:        serverTLSInfo{Enabled: true},
+		}}, fetchServers(v))
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		v := viper.New()
+		v.Set(cfgListenAddress, "0.0.0.0:8082")
+		v.Set(cfgServer+".0.address", "unix:///run/neofs-http-gw.sock")
+		v.Set(cfgServer+".0."+cfgUnixSocketMode, "0600")
+		v.Set(cfgServer+".1.address", "[::]:8443")
+		v.Set(cfgServer+".1."+cfgHTTP2, true)
+		v.Set(cfgServer+".1."+cfgTLSEnabled, true)
+		v.Set(cfgServer+".1."+cfgTLSCertFile, "cert.pem")
+		v.Set(cfgServer+".1."+cfgTLSKeyFile, "key.pem")
+
+		require.Equal(t, []serverInfo{
+			{Address: "unix:///run/neofs-http-gw.sock", SocketMode: 0600},
+			{
+				Address:    "[::]:8443",
+				SocketMode: defaultUnixSocketMode,
+				HTTP2:      true,
+				TLS:        serverTLSInfo{Enabled: true, CertFile: "cert.pem", KeyFile: "key.pem"},
+			},
+		}, fetchServers(v))
+	})
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gw.sock")
+
+	ln, err := listenAddress(unixSchemePrefix+path, 0600)
+	require.NoError(t, err)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	require.NoError(t, ln.Close())
+}
+
+func TestSystemdFDNames(t *testing.T) {
+	require.Equal(t, []string{"", ""}, systemdFDNames(2, ""))
+	require.Equal(t, []string{"http", "https"}, systemdFDNames(2, "http:https"))
+	require.Equal(t, []string{"http", ""}, systemdFDNames(2, "http"))
+	require.Equal(t, []string{"http"}, systemdFDNames(1, "http:https"))
+}
@@ -1,14 +1,17 @@
-package main
+package gateway
 
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-http-gw/downloader"
 	"github.com/nspcc-dev/neofs-http-gw/resolver"
+	"github.com/nspcc-dev/neofs-http-gw/uploader"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/valyala/fasthttp"
@@ -19,25 +22,182 @@ const (
 	defaultRequestTimeout = 15 * time.Second
 	defaultConnectTimeout = 30 * time.Second
 
+	// defaultSessionExpiration is the lifetime of pool sessions in epochs,
+	// they're reopened after expiration.
+	defaultSessionExpiration = 100
+
+	// defaultUploadQuotaWindow is the period upload quotas are counted in.
+	defaultUploadQuotaWindow = 24 * time.Hour
+
+	// defaultStartupRetryBackoff is the delay before the first retry to
+	// connect to NeoFS if the gateway starts without it.
+	defaultStartupRetryBackoff = time.Second
+
 	cfgListenAddress  = "listen_address"
 	cfgTLSCertificate = "tls_certificate"
 	cfgTLSKey         = "tls_key"
+	cfgUnixSocketMode = "unix_socket_mode"
+	cfgHTTP2          = "http2"
+
+	// Servers.
+	cfgServer      = "server"
+	cfgTLSEnabled  = "tls.enabled"
+	cfgTLSCertFile = "tls.cert_file"
+	cfgTLSKeyFile  = "tls.key_file"
+
+	// TLS parameters.
+	cfgTLSMinVersion       = "tls.min_version"
+	cfgTLSCipherSuites     = "tls.cipher_suites"
+	cfgTLSCurvePreferences = "tls.curve_preferences"
+	cfgTLSClientCA         = "tls.client_ca"
+	cfgTLSClientAuth       = "tls.client_auth"
+	cfgTLSClientContainers = "tls.client_containers"
+	cfgTLSRedirectAddress  = "tls.redirect_address"
+
+	// Automatic TLS certificates.
+	cfgTLSAutocertEnabled  = "tls.autocert.enabled"
+	cfgTLSAutocertHosts    = "tls.autocert.hosts"
+	cfgTLSAutocertCacheDir = "tls.autocert.cache_dir"
+	cfgTLSAutocertEmail    = "tls.autocert.email"
+
+	// CORS.
+	cfgCORSAllowOrigins     = "cors.allow_origins"
+	cfgCORSAllowMethods     = "cors.allow_methods"
+	cfgCORSAllowHeaders     = "cors.allow_headers"
+	cfgCORSExposeHeaders    = "cors.expose_headers"
+	cfgCORSAllowCredentials = "cors.allow_credentials"
+	cfgCORSMaxAge           = "cors.max_age"
+
+	// Security headers.
+	cfgSecurityHSTSMaxAge            = "security_headers.hsts.max_age"
+	cfgSecurityHSTSIncludeSubdomains = "security_headers.hsts.include_subdomains"
+	cfgSecurityHSTSPreload           = "security_headers.hsts.preload"
+	cfgSecurityNoSniff               = "security_headers.nosniff"
+	cfgSecurityCSP                   = "security_headers.content_security_policy"
+	cfgSecurityReferrerPolicy        = "security_headers.referrer_policy"
+
+	// Static response headers.
+	cfgResponseHeaders = "response_headers"
+
+	// Rate limiting.
+	cfgRateLimitKey            = "rate_limit.key"
+	cfgRateLimitBearerOwners   = "rate_limit.bearer_owners"
+	cfgRateLimitRequests       = "rate_limit.requests"
+	cfgRateLimitRequestsBurst  = "rate_limit.requests_burst"
+	cfgRateLimitBandwidth      = "rate_limit.bandwidth"
+	cfgRateLimitBandwidthBurst = "rate_limit.bandwidth_burst"
+
+	// Audit log.
+	cfgAuditOutput        = "audit.output"
+	cfgAuditFile          = "audit.file"
+	cfgAuditSyslogAddress = "audit.syslog_address"
+
+	// Usage accounting.
+	cfgUsageEnabled = "usage.enabled"
+
+	// Upload quotas.
+	cfgUploadQuotaBytes        = "upload_quota.bytes"
+	cfgUploadQuotaBearerOwners = "upload_quota.bearer_owners"
+	cfgUploadQuotaWindow       = "upload_quota.window"
+
+	// Concurrency limits.
+	cfgConcurrencyUpload   = "concurrency.upload"
+	cfgConcurrencyDownload = "concurrency.download"
+
+	// Client IP filters.
+	cfgIPFilterAllow    = "ip_filter.allow"
+	cfgIPFilterDeny     = "ip_filter.deny"
+	cfgIPFilterUpload   = "ip_filter.upload"
+	cfgIPFilterDownload = "ip_filter.download"
+
+	// Basic authentication of uploads.
+	cfgBasicAuthUser     = "basic_auth.user"
+	cfgBasicAuthPassword = "basic_auth.password"
+	cfgBasicAuthHtpasswd = "basic_auth.htpasswd"
+	cfgBasicAuthRealm    = "basic_auth.realm"
+
+	// OIDC authentication.
+	cfgOIDCIssuer              = "oidc.issuer"
+	cfgOIDCAudience            = "oidc.audience"
+	cfgOIDCJWKSURL             = "oidc.jwks_url"
+	cfgOIDCJWKSRefreshInterval = "oidc.jwks_refresh_interval"
+	cfgOIDCPermissions         = "oidc.permissions"
+
+	// API keys.
+	cfgAPIKeys     = "api_keys.keys"
+	cfgAPIKeysFile = "api_keys.file"
+
+	// Container access policy.
+	cfgAccessPolicyDefault  = "access_policy.default"
+	cfgAccessPolicyUpload   = "access_policy.upload"
+	cfgAccessPolicyDownload = "access_policy.download"
+	cfgAccessPolicyDeny     = "access_policy.deny"
+
+	// Vault secrets.
+	cfgVaultAddress   = "vault.address"
+	cfgVaultToken     = "vault.token"
+	cfgVaultNamespace = "vault.namespace"
+
+	// Gateway key rotation.
+	cfgKeyRotationDrainTimeout = "key_rotation.drain_timeout"
+
+	// Additional gateway identities.
+	cfgIdentities                = "identities"
+	cfgIdentitiesHeaderSelection = "identities.header_selection"
+
+	// Admin API.
+	cfgAdminToken = "admin.token"
+
+	// Legacy routes without API version prefix.
+	cfgLegacyRoutes = "legacy_routes"
+
+	// Maintenance mode.
+	cfgMaintenance = "maintenance"
+
+	// Drain mode.
+	cfgDrainRetryAfter = "drain.retry_after"
 
 	// Web.
 	cfgWebReadBufferSize     = "web.read_buffer_size"
 	cfgWebWriteBufferSize    = "web.write_buffer_size"
 	cfgWebReadTimeout        = "web.read_timeout"
 	cfgWebWriteTimeout       = "web.write_timeout"
+	cfgWebWriteChunkTimeout  = "web.write_chunk_timeout"
 	cfgWebStreamRequestBody  = "web.stream_request_body"
 	cfgWebMaxRequestBodySize = "web.max_request_body_size"
 
+	// Per-route web timeouts.
+	cfgWebUpload   = "web.upload"
+	cfgWebDownload = "web.download"
+
+	cfgWebUploadMaxMemory = cfgWebUpload + ".max_memory"
+
 	// Timeouts.
 	cfgConTimeout = "connect_timeout"
 	cfgReqTimeout = "request_timeout"
 	cfgRebalance  = "rebalance_timer"
 
+	// Lifetime of sessions opened by the connection pool in epochs.
+	cfgSessionExpiration = "session_expiration"
+
+	// Network epoch tracking.
+	cfgEpochRefreshInterval = "epoch.refresh_interval"
+
+	// Gateway account balance metric.
+	cfgBalanceRefreshInterval = "balance.refresh_interval"
+
+	// Startup without NeoFS nodes available.
+	cfgStartupWaitForNodes    = "startup.wait_for_nodes"
+	cfgStartupRetryBackoff    = "startup.retry_backoff"
+	cfgStartupRetryMaxBackoff = "startup.retry_max_backoff"
+
 	// Logger.
-	cfgLoggerLevel = "logger.level"
+	cfgLoggerLevel         = "logger.level"
+	cfgLoggerSyslogEnabled = "logger.syslog.enabled"
+	cfgLoggerSyslogAddress = "logger.syslog.address"
+	cfgLoggerSyslogTag     = "logger.syslog.tag"
+	cfgLoggerGELFAddress   = "logger.gelf.address"
+	cfgLoggerGELFProtocol  = "logger.gelf.protocol"
 
 	// Wallet.
 	cfgWalletPassphrase = "wallet.passphrase"
@@ -46,6 +206,90 @@ const (
 
 	// Uploader Header.
 	cfgUploaderHeaderEnableDefaultTimestamp = "upload_header.use_default_timestamp"
+	cfgUploaderHeaderPartContentType        = "upload_header.use_part_content_type"
+	cfgUploaderHeaderURLPath                = "upload_header.use_url_path"
+
+	// Per-container upload limits.
+	cfgUploadSizeLimits = "upload_size_limits"
+
+	// Attributes of uploads.
+	cfgUploadAttributesMaxCount     = "upload_attributes.max_count"
+	cfgUploadAttributesMaxKeySize   = "upload_attributes.max_key_size"
+	cfgUploadAttributesMaxValueSize = "upload_attributes.max_value_size"
+	cfgUploadAttributesAllowSystem  = "upload_attributes.allow_system"
+	cfgUploadAttributesDuplicates   = "upload_attributes.duplicates"
+
+	// Allowed types of uploads.
+	cfgUploadAllowedContentTypes = "upload_allowed_types.content_types"
+	cfgUploadAllowedExtensions   = "upload_allowed_types.extensions"
+	cfgUploadAllowedContainers   = "upload_allowed_types.containers"
+
+	// Antivirus checks of uploads.
+	cfgAntivirusEngine     = "antivirus.engine"
+	cfgAntivirusAddress    = "antivirus.address"
+	cfgAntivirusTimeout    = "antivirus.timeout"
+	cfgAntivirusFailOpen   = "antivirus.fail_open"
+	cfgAntivirusContainers = "antivirus.containers"
+
+	// Upload deduplication.
+	cfgUploadDedupEnabled = "upload_dedup.enabled"
+
+	// Moving objects.
+	cfgUploadMoveEnabled = "upload_move.enabled"
+
+	// Container creation.
+	cfgContainerCreationEnabled      = "container_creation.enabled"
+	cfgContainerCreationWaitTimeout  = "container_creation.wait_timeout"
+	cfgContainerCreationPollInterval = "container_creation.poll_interval"
+
+	// Container deletion.
+	cfgContainerDeletionEnabled      = "container_deletion.enabled"
+	cfgContainerDeletionWaitTimeout  = "container_deletion.wait_timeout"
+	cfgContainerDeletionPollInterval = "container_deletion.poll_interval"
+
+	// Extended ACL changes.
+	cfgContainerEACLEnabled      = "container_eacl.enabled"
+	cfgContainerEACLWaitTimeout  = "container_eacl.wait_timeout"
+	cfgContainerEACLPollInterval = "container_eacl.poll_interval"
+
+	// Asynchronous uploads.
+	cfgUploadJobsEnabled  = "upload_jobs.enabled"
+	cfgUploadJobsSpoolDir = "upload_jobs.spool_dir"
+	cfgUploadJobsTTL      = "upload_jobs.ttl"
+
+	// Webhooks.
+	cfgWebhooksEndpoints  = "webhooks.endpoints"
+	cfgWebhooksRetries    = "webhooks.retries"
+	cfgWebhooksBackoff    = "webhooks.backoff"
+	cfgWebhooksMaxBackoff = "webhooks.max_backoff"
+	cfgWebhooksTimeout    = "webhooks.timeout"
+	cfgWebhooksQueueSize  = "webhooks.queue_size"
+
+	// NATS.
+	cfgNATSURL            = "nats.url"
+	cfgNATSUser           = "nats.user"
+	cfgNATSPassword       = "nats.password"
+	cfgNATSToken          = "nats.token"
+	cfgNATSSubjects       = "nats.subjects"
+	cfgNATSDefaultSubject = "nats.default_subject"
+	cfgNATSTimeout        = "nats.timeout"
+	cfgNATSQueueSize      = "nats.queue_size"
+
+	// WebDAV.
+	cfgWebDAVEnabled      = "webdav.enabled"
+	cfgWebDAVWriteEnabled = "webdav.write.enabled"
+
+	// Bandwidth throttling.
+	cfgThrottleUpload   = "throttle.upload"
+	cfgThrottleDownload = "throttle.download"
+
+	// S3.
+	cfgS3Enabled = "s3.enabled"
+	cfgS3Domain  = "s3.domain"
+
+	// Upload progress.
+	cfgUploadProgressEnabled  = "upload_progress.enabled"
+	cfgUploadProgressInterval = "upload_progress.interval"
 
 	// Peers.
 	cfgPeers = "peers"
@@ -59,6 +303,52 @@ const (
 	// Zip compression.
 	cfgZipCompression = "zip.compression"
 
+	// Object selection of attribute downloads.
+	cfgAttributeSelection = "attribute_selection"
+
+	// Circuit breakers of NeoFS nodes.
+	cfgCircuitBreakerEnabled     = "circuit_breaker.enabled"
+	cfgCircuitBreakerFailures    = "circuit_breaker.failures"
+	cfgCircuitBreakerOpenTimeout = "circuit_breaker.open_timeout"
+
+	// Re-resolution of peer host names.
+	cfgPeerDNSRefreshInterval = "peer_dns.refresh_interval"
+
+	// Minimum healthy nodes.
+	cfgPoolMinHealthyNodes = "pool.min_healthy_nodes"
+	cfgPoolDegradedMode    = "pool.degraded_mode"
+
+	// Node selection by header.
+	cfgNodeOverrideEnabled = "node_override.enabled"
+
+	// Retries of NeoFS operations.
+	cfgPoolRetryRetries    = "pool_retry.retries"
+	cfgPoolRetryBackoff    = "pool_retry.backoff"
+	cfgPoolRetryMaxBackoff = "pool_retry.max_backoff"
+	cfgPoolRetryRetryable  = "pool_retry.retryable"
+
+	// Hedging of object reads.
+	cfgPoolHedgeDelay = "pool_hedge.delay"
+
+	// Download failover.
+	cfgDownloadFailoverAttempts = "download_failover.attempts"
+
+	// Download payload verification.
+	cfgDownloadVerifyPayload = "download_verify.enabled"
+
+	// Image thumbnails.
+	cfgThumbnailsEnabled       = "thumbnails.enabled"
+	cfgThumbnailsMaxSourceSize = "thumbnails.max_source_size"
+	cfgThumbnailsMaxDimension  = "thumbnails.max_dimension"
+	cfgThumbnailsMaxPixels     = "thumbnails.max_source_pixels"
+	cfgThumbnailsCacheSize     = "thumbnails.cache_size"
+
+	// Request processing middlewares.
+	cfgMiddlewares = "middlewares"
+
+	// Error responses.
+	cfgErrorsHTMLTemplate = "errors.html_template"
+
 	// Command line args.
 	cmdHelp    = "help"
 	cmdVersion = "version"
@@ -67,15 +357,27 @@ const (
 	cmdWallet  = "wallet"
 	cmdAddress = "address"
 	cmdConfig  = "config"
+
+	cmdValidateConfig = "validate-config"
+	cmdPrintConfig    = "print-config"
+	cmdMock           = "mock"
 )
 
 var ignore = map[string]struct{}{
 	cfgPeers:   {},
+	cfgServer:  {},
 	cmdHelp:    {},
 	cmdVersion: {},
+
+	cmdValidateConfig: {},
+	cmdPrintConfig:    {},
+	cmdMock:           {},
 }
 
-func settings() *viper.Viper {
+// NewConfig creates configuration with default values that can be overridden
+// with environment variables. Embedding applications can use it with
+// WithConfig, the binary uses Settings.
+func NewConfig() *viper.Viper {
 	v := viper.New()
 	v.AutomaticEnv()
 	v.SetEnvPrefix(Prefix)
@@ -83,6 +385,160 @@ func settings() *viper.Viper {
 	v.SetConfigType("yaml")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	// set defaults:
+
+	// logger:
+	v.SetDefault(cfgLoggerLevel, "debug")
+	v.SetDefault(cfgLoggerSyslogTag, "neofs-http-gw")
+	v.SetDefault(cfgLoggerGELFProtocol, gelfProtocolUDP)
+
+	// pool:
+	v.SetDefault(cfgConTimeout, defaultConnectTimeout)
+	v.SetDefault(cfgReqTimeout, defaultRequestTimeout)
+	v.SetDefault(cfgRebalance, defaultRebalanceTimer)
+	v.SetDefault(cfgSessionExpiration, defaultSessionExpiration)
+	v.SetDefault(cfgEpochRefreshInterval, 30*time.Second)
+	v.SetDefault(cfgBalanceRefreshInterval, time.Minute)
+	v.SetDefault(cfgStartupWaitForNodes, false)
+	v.SetDefault(cfgStartupRetryBackoff, defaultStartupRetryBackoff)
+	v.SetDefault(cfgStartupRetryMaxBackoff, 30*time.Second)
+
+	// listen address and container resolving:
+	v.SetDefault(cfgListenAddress, "0.0.0.0:8082")
+	v.SetDefault(cfgResolveOrder, []string{resolver.NNSResolver, resolver.DNSResolver})
+
+	// web-server:
+	v.SetDefault(cfgWebReadBufferSize, 4096)
+	v.SetDefault(cfgWebWriteBufferSize, 4096)
+	v.SetDefault(cfgWebReadTimeout, time.Minute*10)
+	v.SetDefault(cfgWebWriteTimeout, time.Minute*5)
+	v.SetDefault(cfgWebWriteChunkTimeout, 30*time.Second)
+	v.SetDefault(cfgWebStreamRequestBody, true)
+	v.SetDefault(cfgWebMaxRequestBodySize, fasthttp.DefaultMaxRequestBodySize)
+	v.SetDefault(cfgWebUploadMaxMemory, 1<<20)
+
+	// automatic TLS certificates:
+	v.SetDefault(cfgTLSAutocertEnabled, false)
+
+	// cors:
+	v.SetDefault(cfgCORSAllowMethods, []string{fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodPost})
+
+	// basic auth:
+	v.SetDefault(cfgBasicAuthRealm, "neofs-http-gw")
+
+	// oidc:
+	v.SetDefault(cfgOIDCJWKSRefreshInterval, time.Hour)
+
+	// access policy:
+	v.SetDefault(cfgAccessPolicyDefault, accessPolicyAllow)
+
+	// key rotation:
+	v.SetDefault(cfgKeyRotationDrainTimeout, time.Minute)
+
+	// routes:
+	v.SetDefault(cfgLegacyRoutes, true)
+
+	// maintenance mode:
+	v.SetDefault(cfgMaintenance, false)
+
+	// drain mode:
+	v.SetDefault(cfgDrainRetryAfter, 30*time.Second)
+
+	// rate limiting:
+	v.SetDefault(cfgRateLimitKey, rateLimitKeyIP)
+	v.SetDefault(cfgUploadQuotaWindow, defaultUploadQuotaWindow)
+
+	// upload header
+	v.SetDefault(cfgUploaderHeaderEnableDefaultTimestamp, false)
+	v.SetDefault(cfgUploaderHeaderPartContentType, false)
+	v.SetDefault(cfgUploaderHeaderURLPath, false)
+
+	// asynchronous uploads:
+	v.SetDefault(cfgUploadJobsEnabled, false)
+	v.SetDefault(cfgUploadJobsTTL, time.Hour)
+	v.SetDefault(cfgAntivirusTimeout, 30*time.Second)
+	v.SetDefault(cfgUploadAttributesMaxCount, 64)
+	v.SetDefault(cfgUploadAttributesMaxKeySize, 256)
+	v.SetDefault(cfgUploadAttributesMaxValueSize, 4096)
+	v.SetDefault(cfgUploadAttributesAllowSystem, false)
+	v.SetDefault(cfgUploadAttributesDuplicates, uploader.DuplicatesFirst)
+	v.SetDefault(cfgAntivirusFailOpen, false)
+	v.SetDefault(cfgUploadDedupEnabled, false)
+	v.SetDefault(cfgUploadMoveEnabled, false)
+
+	// container creation
+	v.SetDefault(cfgContainerCreationEnabled, false)
+	v.SetDefault(cfgContainerCreationWaitTimeout, 2*time.Minute)
+	v.SetDefault(cfgContainerCreationPollInterval, 5*time.Second)
+
+	// container deletion
+	v.SetDefault(cfgContainerDeletionEnabled, false)
+	v.SetDefault(cfgContainerDeletionWaitTimeout, 2*time.Minute)
+	v.SetDefault(cfgContainerDeletionPollInterval, 5*time.Second)
+
+	// extended ACL changes
+	v.SetDefault(cfgContainerEACLEnabled, false)
+	v.SetDefault(cfgContainerEACLWaitTimeout, 2*time.Minute)
+	v.SetDefault(cfgContainerEACLPollInterval, 5*time.Second)
+
+	// webhooks:
+	v.SetDefault(cfgWebhooksRetries, 5)
+	v.SetDefault(cfgWebhooksBackoff, time.Second)
+	v.SetDefault(cfgWebhooksMaxBackoff, 30*time.Second)
+	v.SetDefault(cfgWebhooksTimeout, 10*time.Second)
+	v.SetDefault(cfgWebhooksQueueSize, 1000)
+
+	// NATS:
+	v.SetDefault(cfgNATSTimeout, 5*time.Second)
+	v.SetDefault(cfgNATSQueueSize, 1000)
+
+	// WebDAV:
+	v.SetDefault(cfgWebDAVEnabled, false)
+	v.SetDefault(cfgWebDAVWriteEnabled, false)
+
+	// S3:
+	v.SetDefault(cfgS3Enabled, false)
+
+	// upload progress:
+	v.SetDefault(cfgUploadProgressEnabled, false)
+	v.SetDefault(cfgUploadProgressInterval, 500*time.Millisecond)
+
+	// zip:
+	v.SetDefault(cfgZipCompression, false)
+
+	// attribute downloads:
+	v.SetDefault(cfgAttributeSelection, downloader.AttributeSelectionAny)
+	v.SetDefault(cfgDownloadFailoverAttempts, 2)
+	v.SetDefault(cfgDownloadVerifyPayload, false)
+	v.SetDefault(cfgThumbnailsEnabled, false)
+	v.SetDefault(cfgThumbnailsMaxSourceSize, 20<<20)
+	v.SetDefault(cfgThumbnailsMaxDimension, 2048)
+	v.SetDefault(cfgThumbnailsMaxPixels, 50000000)
+	v.SetDefault(cfgThumbnailsCacheSize, 64<<20)
+	v.SetDefault(cfgCircuitBreakerEnabled, false)
+	v.SetDefault(cfgCircuitBreakerFailures, 3)
+	v.SetDefault(cfgCircuitBreakerOpenTimeout, time.Minute)
+	v.SetDefault(cfgNodeOverrideEnabled, false)
+	v.SetDefault(cfgPeerDNSRefreshInterval, 0)
+	v.SetDefault(cfgPoolMinHealthyNodes, 0)
+	v.SetDefault(cfgPoolDegradedMode, degradedNone)
+	v.SetDefault(cfgPoolRetryRetries, 2)
+	v.SetDefault(cfgPoolRetryBackoff, 100*time.Millisecond)
+	v.SetDefault(cfgPoolRetryMaxBackoff, time.Second)
+	v.SetDefault(cfgPoolRetryRetryable, []string{"Unavailable", "ResourceExhausted", "refused", "reset", "EOF", "healthy", "closing"})
+
+	// middlewares:
+	v.SetDefault(cfgMiddlewares, defaultMiddlewares)
+
+	return v
+}
+
+// Settings creates configuration from command line flags, environment
+// variables and config file. Help, version, config validation and printing
+// flags are handled here and the process exits after them.
+func Settings() *viper.Viper {
+	v := NewConfig()
+
 	// flags setup:
 	flags := pflag.NewFlagSet("commandline", pflag.ExitOnError)
 	flags.SetOutput(os.Stdout)
@@ -97,36 +553,21 @@ func settings() *viper.Viper {
 	flags.StringP(cmdWallet, "w", "", `path to the wallet`)
 	flags.String(cmdAddress, "", `address of wallet account`)
 	config := flags.String(cmdConfig, "", "config path")
+	validate := flags.Bool(cmdValidateConfig, false, "validate configuration, print problems and exit")
+	printCfg := flags.Bool(cmdPrintConfig, false, "print effective configuration with value sources and exit")
+	flags.Bool(cmdMock, false, "serve requests from in-memory NeoFS mock instead of peers (for development only)")
 	flags.Duration(cfgConTimeout, defaultConnectTimeout, "gRPC connect timeout")
 	flags.Duration(cfgReqTimeout, defaultRequestTimeout, "gRPC request timeout")
 	flags.Duration(cfgRebalance, defaultRebalanceTimer, "gRPC connection rebalance timer")
 
-	flags.String(cfgListenAddress, "0.0.0.0:8082", "address to listen")
+	flags.String(cfgListenAddress, "0.0.0.0:8082", "address to listen (unix:///path/to/socket for unix socket)")
 	flags.String(cfgTLSCertificate, "", "TLS certificate path")
 	flags.String(cfgTLSKey, "", "TLS key path")
+	flags.Bool(cfgHTTP2, false, "enable HTTP/2 (h2 over TLS, h2c otherwise)")
 	peers := flags.StringArrayP(cfgPeers, "p", nil, "NeoFS nodes")
 
 	resolveMethods := flags.StringSlice(cfgResolveOrder, []string{resolver.NNSResolver, resolver.DNSResolver}, "set container name resolve order")
 
-	// set defaults:
-
-	// logger:
-	v.SetDefault(cfgLoggerLevel, "debug")
-
-	// web-server:
-	v.SetDefault(cfgWebReadBufferSize, 4096)
-	v.SetDefault(cfgWebWriteBufferSize, 4096)
-	v.SetDefault(cfgWebReadTimeout, time.Minute*10)
-	v.SetDefault(cfgWebWriteTimeout, time.Minute*5)
-	v.SetDefault(cfgWebStreamRequestBody, true)
-	v.SetDefault(cfgWebMaxRequestBodySize, fasthttp.DefaultMaxRequestBodySize)
-
-	// upload header
-	v.SetDefault(cfgUploaderHeaderEnableDefaultTimestamp, false)
-
-	// zip:
-	v.SetDefault(cfgZipCompression, false)
-
 	if err := v.BindPFlags(flags); err != nil {
 		panic(err)
 	}
@@ -166,6 +607,17 @@ func settings() *viper.Viper {
 		fmt.Printf("%s_%s_[N]_ADDRESS = string\n", Prefix, strings.ToUpper(cfgPeers))
 		fmt.Printf("%s_%s_[N]_WEIGHT = float\n", Prefix, strings.ToUpper(cfgPeers))
 
+		fmt.Println()
+		fmt.Println("Servers preset:")
+		fmt.Println()
+
+		fmt.Printf("%s_%s_[N]_ADDRESS = string\n", Prefix, strings.ToUpper(cfgServer))
+		fmt.Printf("%s_%s_[N]_UNIX_SOCKET_MODE = string\n", Prefix, strings.ToUpper(cfgServer))
+		fmt.Printf("%s_%s_[N]_HTTP2 = bool\n", Prefix, strings.ToUpper(cfgServer))
+		fmt.Printf("%s_%s_[N]_TLS_ENABLED = bool\n", Prefix, strings.ToUpper(cfgServer))
+		fmt.Printf("%s_%s_[N]_TLS_CERT_FILE = string\n", Prefix, strings.ToUpper(cfgServer))
+		fmt.Printf("%s_%s_[N]_TLS_KEY_FILE = string\n", Prefix, strings.ToUpper(cfgServer))
+
 		os.Exit(0)
 	case version != nil && *version:
 		fmt.Printf("NeoFS HTTP Gateway %s\n", Version)
@@ -173,9 +625,7 @@ func settings() *viper.Viper {
 	}
 
 	if v.IsSet(cmdConfig) {
-		if cfgFile, err := os.Open(*config); err != nil {
-			panic(err)
-		} else if err := v.ReadConfig(cfgFile); err != nil {
+		if err := readConfig(v, *config); err != nil {
 			panic(err)
 		}
 	}
@@ -188,5 +638,47 @@ func settings() *viper.Viper {
 		}
 	}
 
+	if validate != nil && *validate {
+		problems := validateConfig(v)
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		if len(problems) != 0 {
+			os.Exit(1)
+		}
+		fmt.Println("configuration is valid")
+		os.Exit(0)
+	}
+
+	if printCfg != nil && *printCfg {
+		err := printConfig(os.Stdout, v, func(key string) string {
+			return configSource(v, flags, key)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	return v
 }
+
+// readConfig reads the config file, its format is detected by the extension:
+// .json and .toml files are supported as well as YAML ones (the default).
+func readConfig(v *viper.Viper, path string) error {
+	cfgFile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer cfgFile.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".toml":
+		v.SetConfigType(ext[1:])
+	default:
+		v.SetConfigType("yaml")
+	}
+
+	return v.ReadConfig(cfgFile)
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfig(t *testing.T) {
+	for name, data := range map[string]string{
+		"config.yaml": "peers:\n  0:\n    address: s01.neofs.devenv:8080\nlogger:\n  level: info\n",
+		"config.yml":  "peers:\n  0:\n    address: s01.neofs.devenv:8080\nlogger:\n  level: info\n",
+		"config.json": `{"peers": {"0": {"address": "s01.neofs.devenv:8080"}}, "logger": {"level": "info"}}`,
+		"config.toml": "[peers.0]\naddress = \"s01.neofs.devenv:8080\"\n\n[logger]\nlevel = \"info\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+			v := viper.New()
+			require.NoError(t, readConfig(v, path))
+			require.Equal(t, "info", v.GetString(cfgLoggerLevel))
+			require.Equal(t, []peerInfo{{Address: "s01.neofs.devenv:8080", Weight: 1, Priority: 1}}, fetchPeers(v))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte("logger:\n  level: info\n"), 0600))
+		require.Error(t, readConfig(viper.New(), path))
+	})
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Setenv(Prefix+"_LOGGER_LEVEL", "info")
+
+	v := NewConfig()
+	require.Equal(t, "info", v.GetString(cfgLoggerLevel))
+	require.Equal(t, defaultRequestTimeout, v.GetDuration(cfgReqTimeout))
+	require.Equal(t, "0.0.0.0:8082", v.GetString(cfgListenAddress))
+	require.Equal(t, 4096, v.GetInt(cfgWebReadBufferSize))
+}
+
+func TestFetchAntivirusContainers(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgAntivirusFailOpen, true)
+	v.Set(cfgAntivirusContainers+".0.container", "uploads")
+	v.Set(cfgAntivirusContainers+".1.container", "Dxhf4PNprrJHWWTG5RGLdfLkJiSQ3AQqit1MSnEPRkDZ")
+	v.Set(cfgAntivirusContainers+".1.fail_open", false)
+
+	require.Equal(t, map[string]bool{
+		"uploads": true,
+		"Dxhf4PNprrJHWWTG5RGLdfLkJiSQ3AQqit1MSnEPRkDZ": false,
+	}, fetchAntivirusContainers(v))
+}
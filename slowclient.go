@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// chunkDeadlineListener accepts connections writing data in chunks with
+	// their own deadlines.
+	chunkDeadlineListener struct {
+		net.Listener
+		timeout time.Duration
+		log     *zap.Logger
+	}
+
+	// chunkDeadlineConn fails writes if the client doesn't receive a chunk in
+	// time, so that a stalled client doesn't hold the response body stream
+	// (and NeoFS stream behind it) until the write timeout of the whole
+	// response. Deadlines set by the server are kept if they are earlier.
+	chunkDeadlineConn struct {
+		net.Conn
+		timeout time.Duration
+		log     *zap.Logger
+
+		mu       sync.Mutex
+		deadline time.Time
+	}
+)
+
+// chunkDeadlines wraps the listener if chunk write timeout is set.
+func chunkDeadlines(ln net.Listener, timeout time.Duration, log *zap.Logger) net.Listener {
+	if timeout <= 0 {
+		return ln
+	}
+	return &chunkDeadlineListener{Listener: ln, timeout: timeout, log: log}
+}
+
+func (l *chunkDeadlineListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &chunkDeadlineConn{Conn: conn, timeout: l.timeout, log: l.log}, nil
+}
+
+func (c *chunkDeadlineConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *chunkDeadlineConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+	return c.Conn.SetWriteDeadline(t)
+}
+
+// chunkDeadline returns the deadline of the chunk written now and whether
+// it's earlier than the server one.
+func (c *chunkDeadlineConn) chunkDeadline(now time.Time) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := now.Add(c.timeout)
+	if !c.deadline.IsZero() && c.deadline.Before(d) {
+		return c.deadline, false
+	}
+	return d, true
+}
+
+func (c *chunkDeadlineConn) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > throttleChunk {
+			chunk = chunk[:throttleChunk]
+		}
+
+		deadline, own := c.chunkDeadline(time.Now())
+		if err := c.Conn.SetWriteDeadline(deadline); err != nil {
+			return written, err
+		}
+
+		n, err := c.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			if own && errors.Is(err, os.ErrDeadlineExceeded) {
+				c.log.Warn("client doesn't receive response, connection is closed",
+					zap.Stringer("remote", c.RemoteAddr()), zap.Duration("timeout", c.timeout))
+			}
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
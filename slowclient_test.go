@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestChunkDeadlineConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	conn := &chunkDeadlineConn{Conn: server, timeout: 50 * time.Millisecond, log: zap.NewNop()}
+	require.NoError(t, conn.SetWriteDeadline(time.Now().Add(time.Hour)))
+
+	t.Run("receiving client", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			buf := make([]byte, 2*throttleChunk)
+			for received := 0; received < len(buf); {
+				time.Sleep(10 * time.Millisecond)
+				n, err := client.Read(buf[received:])
+				require.NoError(t, err)
+				received += n
+			}
+		}()
+
+		n, err := conn.Write(make([]byte, 2*throttleChunk))
+		require.NoError(t, err)
+		require.Equal(t, 2*throttleChunk, n)
+		<-done
+	})
+
+	t.Run("stalled client", func(t *testing.T) {
+		start := time.Now()
+		_, err := conn.Write(make([]byte, throttleChunk))
+		require.True(t, errors.Is(err, os.ErrDeadlineExceeded), err)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("earlier server deadline", func(t *testing.T) {
+		deadline := time.Now().Add(time.Millisecond)
+		require.NoError(t, conn.SetWriteDeadline(deadline))
+
+		d, own := conn.chunkDeadline(time.Now())
+		require.False(t, own)
+		require.Equal(t, deadline, d)
+	})
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func (a *app) isConnecting() bool {
+	return atomic.LoadInt32(&a.connecting) == 1
+}
+
+// waitForNodes returns the handler rejecting requests with 503 status while
+// the connection pool is being dialed in background, the gateway handler
+// serves requests after that.
+func (a *app) waitForNodes(ctx context.Context) fasthttp.RequestHandler {
+	var handler atomic.Value
+	handler.Store(fasthttp.RequestHandler(a.unreadyHandler))
+
+	go func() {
+		if !a.connectPool(ctx) {
+			return
+		}
+		handler.Store(a.Handler(ctx))
+		a.log.Info("connected to NeoFS, gateway is ready")
+	}()
+
+	return func(c *fasthttp.RequestCtx) {
+		handler.Load().(fasthttp.RequestHandler)(c)
+	}
+}
+
+// unreadyHandler replies to requests while the gateway is connecting to
+// NeoFS, readiness probe fails too.
+func (a *app) unreadyHandler(c *fasthttp.RequestCtx) {
+	if string(c.Path()) == "/-/ready" {
+		response.Error(c, "connecting", fasthttp.StatusServiceUnavailable)
+		return
+	}
+	response.Error(c, "gateway is connecting to NeoFS", fasthttp.StatusServiceUnavailable)
+}
+
+// connectPool dials the connection pool until it succeeds, the delay between
+// attempts is doubled after every failure. It returns false if the context is
+// done first.
+func (a *app) connectPool(ctx context.Context) bool {
+	backoff := a.cfg.GetDuration(cfgStartupRetryBackoff)
+	if backoff <= 0 {
+		a.log.Warn("invalid startup retry backoff, default one is used", zap.Duration("backoff", backoff))
+		backoff = defaultStartupRetryBackoff
+	}
+	maxBackoff := a.cfg.GetDuration(cfgStartupRetryMaxBackoff)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+
+		a.keyMu.Lock()
+		key := a.key
+		a.keyMu.Unlock()
+
+		p, err := a.newPool(ctx, key)
+		if err == nil {
+			a.pool.Replace(p, 0)
+			atomic.StoreInt32(&a.connecting, 0)
+			return true
+		}
+
+		if backoff *= 2; maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		a.log.Warn("could not connect to NeoFS, retrying", zap.Duration("backoff", backoff), zap.Error(err))
+	}
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestWaitForNodes(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := viper.New()
+	v.Set(cfgPeers+".0.address", "127.0.0.1:1")
+	v.Set(cfgConTimeout, 100*time.Millisecond)
+	v.Set(cfgReqTimeout, 100*time.Millisecond)
+	v.Set(cfgStartupRetryBackoff, 10*time.Millisecond)
+
+	a := &app{
+		cfg:        v,
+		log:        zap.NewNop(),
+		pool:       utils.NewPoolHolder(nil),
+		key:        key,
+		connecting: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := a.Handler(ctx)
+
+	for _, path := range []string{"/-/ready", "/upload/container"} {
+		var c fasthttp.RequestCtx
+		c.Request.SetRequestURI(path)
+		h(&c)
+		require.Equal(t, fasthttp.StatusServiceUnavailable, c.Response.StatusCode(), path)
+	}
+	require.True(t, a.isConnecting())
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, a.connectPool(ctx))
+	})
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	systemdSchemePrefix = "systemd://"
+
+	// sdListenFDsStart is the first file descriptor passed by systemd.
+	sdListenFDsStart = 3
+
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+)
+
+// systemdListeners returns listeners passed by systemd socket activation
+// indexed both by their names (from FileDescriptorName= option of the socket
+// unit) and positions. It returns nil if the process wasn't socket-activated.
+// Activation environment is cleared so that it's not inherited by children.
+func systemdListeners() (map[string]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv(envListenPID))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv(envListenFDs))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+
+	names := systemdFDNames(count, os.Getenv(envListenFDNames))
+
+	for _, env := range []string{envListenPID, envListenFDs, envListenFDNames} {
+		_ = os.Unsetenv(env)
+	}
+
+	listeners := make(map[string]net.Listener, 2*count)
+	for i := 0; i < count; i++ {
+		f := os.NewFile(uintptr(sdListenFDsStart+i), names[i])
+
+		ln, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not use passed file descriptor %d (%s): %w", sdListenFDsStart+i, names[i], err)
+		}
+
+		listeners[strconv.Itoa(i)] = ln
+		if names[i] != "" {
+			listeners[names[i]] = ln
+		}
+	}
+
+	return listeners, nil
+}
+
+// systemdFDNames splits LISTEN_FDNAMES value into exactly count names.
+func systemdFDNames(count int, names string) []string {
+	res := make([]string, count)
+	if names != "" {
+		copy(res, strings.Split(names, ":"))
+	}
+	return res
+}
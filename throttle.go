@@ -0,0 +1,157 @@
+package gateway
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// throttleChunk is the maximum number of bytes read or written at once by
+// throttled connections, so that bandwidth is shared between them smoothly.
+const throttleChunk = 16 << 10
+
+type (
+	// bandwidthLimit limits bytes received from (uploads) or sent to
+	// (downloads) clients: the global limit is shared by all connections and
+	// every connection has its own one too.
+	bandwidthLimit struct {
+		mu            sync.Mutex
+		global        *tokenBucket
+		perConnection float64
+	}
+
+	// throttledListener accepts connections with limited bandwidth.
+	throttledListener struct {
+		net.Listener
+		upload, download *bandwidthLimit
+	}
+
+	throttledConn struct {
+		net.Conn
+		upload, download *bandwidthLimit
+		read, write      *tokenBucket
+	}
+)
+
+// newBandwidthLimit creates bandwidth limit from the section of throttle
+// configuration, it returns nil if there is no limit.
+func newBandwidthLimit(v *viper.Viper, section string) *bandwidthLimit {
+	global := v.GetFloat64(section + ".global")
+	perConnection := v.GetFloat64(section + ".per_connection")
+	if global <= 0 && perConnection <= 0 {
+		return nil
+	}
+
+	l := &bandwidthLimit{perConnection: perConnection}
+	if global > 0 {
+		l.global = newTokenBucket(global, 0, time.Now())
+	}
+	return l
+}
+
+// connectionBucket returns the bucket of a new connection, nil if there is no
+// per-connection limit.
+func (l *bandwidthLimit) connectionBucket(now time.Time) *tokenBucket {
+	if l == nil || l.perConnection <= 0 {
+		return nil
+	}
+	return newTokenBucket(l.perConnection, 0, now)
+}
+
+// delay charges n bytes transferred over the connection and returns the time
+// to wait for the buckets to be out of debt.
+func (l *bandwidthLimit) delay(conn *tokenBucket, n int, now time.Time) time.Duration {
+	var d time.Duration
+
+	if conn != nil {
+		conn.charge(now, float64(n))
+		d = debtDuration(conn)
+	}
+
+	if l.global != nil {
+		l.mu.Lock()
+		l.global.charge(now, float64(n))
+		if global := debtDuration(l.global); global > d {
+			d = global
+		}
+		l.mu.Unlock()
+	}
+
+	return d
+}
+
+func debtDuration(b *tokenBucket) time.Duration {
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait charges n bytes and sleeps until the buckets are out of debt.
+func (l *bandwidthLimit) wait(conn *tokenBucket, n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	if d := l.delay(conn, n, time.Now()); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// throttle wraps the listener if bandwidth is limited.
+func throttle(ln net.Listener, upload, download *bandwidthLimit) net.Listener {
+	if upload == nil && download == nil {
+		return ln
+	}
+	return &throttledListener{Listener: ln, upload: upload, download: download}
+}
+
+func (l *throttledListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &throttledConn{
+		Conn:     conn,
+		upload:   l.upload,
+		download: l.download,
+		read:     l.upload.connectionBucket(now),
+		write:    l.download.connectionBucket(now),
+	}, nil
+}
+
+func (c *throttledConn) Read(p []byte) (int, error) {
+	if c.upload != nil && len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+
+	n, err := c.Conn.Read(p)
+	c.upload.wait(c.read, n)
+	return n, err
+}
+
+func (c *throttledConn) Write(p []byte) (int, error) {
+	if c.download == nil {
+		return c.Conn.Write(p)
+	}
+
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > throttleChunk {
+			chunk = chunk[:throttleChunk]
+		}
+
+		n, err := c.Conn.Write(chunk)
+		written += n
+		c.download.wait(c.write, n)
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidthLimit(t *testing.T) {
+	v := viper.New()
+	require.Nil(t, newBandwidthLimit(v, cfgThrottleDownload))
+
+	v.Set(cfgThrottleDownload+".global", 1000)
+	v.Set(cfgThrottleDownload+".per_connection", 100)
+	l := newBandwidthLimit(v, cfgThrottleDownload)
+	require.NotNil(t, l)
+
+	now := time.Now()
+	l.global.last = now
+	conn := l.connectionBucket(now)
+
+	// Burst is one second of the limit.
+	require.Zero(t, l.delay(conn, 100, now))
+	require.Equal(t, time.Second, l.delay(conn, 100, now))
+
+	// The global limit is shared by connections.
+	require.Equal(t, 500*time.Millisecond, l.delay(nil, 1300, now))
+	require.Equal(t, 2*time.Second, l.delay(l.connectionBucket(now), 300, now))
+
+	var nilLimit *bandwidthLimit
+	require.Nil(t, nilLimit.connectionBucket(now))
+}
+
+func TestThrottledConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	v := viper.New()
+	v.Set(cfgThrottleDownload+".per_connection", 1<<20)
+	download := newBandwidthLimit(v, cfgThrottleDownload)
+	conn := &throttledConn{Conn: server, download: download, write: download.connectionBucket(time.Now())}
+
+	data := bytes.Repeat([]byte{1}, 3*throttleChunk+1)
+	go func() {
+		n, err := conn.Write(data)
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+		require.NoError(t, conn.Close())
+	}()
+
+	received, err := io.ReadAll(client)
+	require.NoError(t, err)
+	require.Equal(t, data, received)
+}
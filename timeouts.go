@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+)
+
+// routeTimeouts are timeouts of upload or download routes, zero values mean
+// server-wide ones.
+type routeTimeouts struct {
+	Read    time.Duration
+	Write   time.Duration
+	Handler time.Duration
+}
+
+func fetchRouteTimeouts(v *viper.Viper, route string) routeTimeouts {
+	section := cfgWebDownload
+	if routeOperation(route) == operationUpload {
+		section = cfgWebUpload
+	}
+
+	return routeTimeouts{
+		Read:    v.GetDuration(section + ".read_timeout"),
+		Write:   v.GetDuration(section + ".write_timeout"),
+		Handler: v.GetDuration(section + ".handler_timeout"),
+	}
+}
+
+// routeRequestConfig returns fasthttp hook setting read and write timeouts of
+// the request depending on its route.
+func routeRequestConfig(names *routeNames, upload, download routeTimeouts) func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	return func(h *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		switch route := names.lookup(h); {
+		case route == "":
+			return fasthttp.RequestConfig{}
+		case routeOperation(route) == operationUpload:
+			return fasthttp.RequestConfig{ReadTimeout: upload.Read, WriteTimeout: upload.Write}
+		default:
+			return fasthttp.RequestConfig{ReadTimeout: download.Read, WriteTimeout: download.Write}
+		}
+	}
+}
+
+// handlerTimeout limits the time of NeoFS operations of the request including
+// response body streaming.
+func (a *app) handlerTimeout(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	timeout := fetchRouteTimeouts(a.cfg, route).Handler
+	if timeout <= 0 {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		utils.SetRequestTimeout(c, timeout)
+		h(c)
+	}
+}
@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-http-gw/webdav"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouteRequestConfig(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgWebUpload+".read_timeout", time.Hour)
+	v.Set(cfgWebDownload+".write_timeout", 5*time.Minute)
+
+	hook := routeRequestConfig(testRouteNames(), fetchRouteTimeouts(v, routeUpload), fetchRouteTimeouts(v, routeZip))
+
+	configFor := func(method, uri string) fasthttp.RequestConfig {
+		var h fasthttp.RequestHeader
+		h.SetMethod(method)
+		h.SetRequestURI(uri)
+		return hook(&h)
+	}
+
+	require.Equal(t, fasthttp.RequestConfig{ReadTimeout: time.Hour}, configFor(fasthttp.MethodPost, "/upload/cid"))
+	require.Equal(t, fasthttp.RequestConfig{WriteTimeout: 5 * time.Minute}, configFor(fasthttp.MethodGet, "/get/cid/oid"))
+	require.Equal(t, fasthttp.RequestConfig{WriteTimeout: 5 * time.Minute}, configFor(fasthttp.MethodGet, "/get_by_attribute/cid/key/value"))
+	require.Equal(t, fasthttp.RequestConfig{WriteTimeout: 5 * time.Minute}, configFor(fasthttp.MethodGet, "/zip/cid/prefix"))
+	require.Equal(t, fasthttp.RequestConfig{ReadTimeout: time.Hour}, configFor(fasthttp.MethodPost, "/v2/upload/cid"))
+	require.Equal(t, fasthttp.RequestConfig{WriteTimeout: 5 * time.Minute}, configFor(fasthttp.MethodGet, "/v2/get/cid/oid"))
+	require.Equal(t, fasthttp.RequestConfig{WriteTimeout: 5 * time.Minute}, configFor(fasthttp.MethodGet, "/checksum/cid/oid"))
+	require.Equal(t, fasthttp.RequestConfig{ReadTimeout: time.Hour}, configFor(webdav.MethodMkcol, "/webdav/cid/dir"))
+	require.Equal(t, fasthttp.RequestConfig{}, configFor(fasthttp.MethodGet, "/metrics"))
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	v := viper.New()
+	v.Set(cfgWebUpload+".handler_timeout", time.Hour)
+	a := &app{cfg: v}
+
+	var ctx context.Context
+	h := func(c *fasthttp.RequestCtx) {
+		ctx = utils.RequestContext(c, context.Background())
+	}
+
+	var c fasthttp.RequestCtx
+	a.handlerTimeout(routeGet, h)(&c)
+	require.Equal(t, context.Background(), ctx)
+
+	a.handlerTimeout(routeUpload, h)(&c)
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+
+	// the context is cancelled after the response is sent
+	c.ResetUserValues()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsCurves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+var tlsClientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// tlsConfig creates base TLS configuration for the gateway listeners.
+func (a *app) tlsConfig() (*tls.Config, error) {
+	cfg, err := newTLSConfig(
+		a.cfg.GetString(cfgTLSMinVersion),
+		a.cfg.GetStringSlice(cfgTLSCipherSuites),
+		a.cfg.GetStringSlice(cfgTLSCurvePreferences),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if caPath := a.cfg.GetString(cfgTLSClientCA); caPath != "" {
+		if cfg.ClientCAs, err = loadCertPool(caPath); err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if authType := a.cfg.GetString(cfgTLSClientAuth); authType != "" {
+		var ok bool
+		if cfg.ClientAuth, ok = tlsClientAuthTypes[authType]; !ok {
+			return nil, fmt.Errorf("unknown client authentication type: %s", authType)
+		}
+	}
+
+	return cfg, nil
+}
+
+// loadCertPool reads PEM-encoded CA certificates bundle.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+
+	return pool, nil
+}
+
+// clientCertAccess restricts access to containers according to the common
+// name of the verified client certificate subject. It does nothing if there
+// are no container restrictions configured.
+func (a *app) clientCertAccess(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	allowed := a.cfg.GetStringMapStringSlice(cfgTLSClientContainers)
+	if len(allowed) == 0 {
+		return h
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		state := c.TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			response.Error(c, "verified client certificate required", fasthttp.StatusForbidden)
+			return
+		}
+
+		// viper keeps map keys in lower case
+		subject := strings.ToLower(state.VerifiedChains[0][0].Subject.CommonName)
+		cnr, _ := c.UserValue("cid").(string)
+
+		for _, allowedCnr := range allowed[subject] {
+			if allowedCnr == cnr {
+				h(c)
+				return
+			}
+		}
+
+		a.log.Warn("container access denied for client certificate",
+			zap.String("subject", subject), zap.String("cid", cnr))
+		response.Error(c, "access to container is denied", fasthttp.StatusForbidden)
+	}
+}
+
+// newTLSConfig creates TLS configuration with the specified minimal protocol
+// version, cipher suites and curve preferences. Empty values leave the
+// library defaults. Only cipher suites without known security issues are
+// accepted.
+func newTLSConfig(minVersion string, cipherSuites, curves []string) (*tls.Config, error) {
+	cfg := new(tls.Config)
+
+	if minVersion != "" {
+		version, ok := tlsVersions[minVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS version: %s", minVersion)
+		}
+		cfg.MinVersion = version
+	}
+
+	if len(cipherSuites) != 0 {
+		supported := make(map[string]uint16)
+		for _, suite := range tls.CipherSuites() {
+			supported[suite.Name] = suite.ID
+		}
+
+		cfg.CipherSuites = make([]uint16, 0, len(cipherSuites))
+		for _, name := range cipherSuites {
+			id, ok := supported[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown or insecure cipher suite: %s", name)
+			}
+			cfg.CipherSuites = append(cfg.CipherSuites, id)
+		}
+	}
+
+	if len(curves) != 0 {
+		cfg.CurvePreferences = make([]tls.CurveID, 0, len(curves))
+		for _, name := range curves {
+			curve, ok := tlsCurves[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown curve: %s", name)
+			}
+			cfg.CurvePreferences = append(cfg.CurvePreferences, curve)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := newTLSConfig("", nil, nil)
+		require.NoError(t, err)
+		require.Zero(t, cfg.MinVersion)
+		require.Nil(t, cfg.CipherSuites)
+		require.Nil(t, cfg.CurvePreferences)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		cfg, err := newTLSConfig("1.2",
+			[]string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+			[]string{"X25519", "P256"})
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+		require.Equal(t, []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		}, cfg.CipherSuites)
+		require.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256}, cfg.CurvePreferences)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := newTLSConfig("1.4", nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("insecure cipher suite", func(t *testing.T) {
+		_, err := newTLSConfig("", []string{"TLS_RSA_WITH_RC4_128_SHA"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid curve", func(t *testing.T) {
+		_, err := newTLSConfig("", nil, []string{"P224"})
+		require.Error(t, err)
+	})
+}
+
+func TestLoadCertPool(t *testing.T) {
+	_, err := loadCertPool("/nonexistent/ca.pem")
+	require.Error(t, err)
+
+	f, err := os.CreateTemp(t.TempDir(), "ca")
+	require.NoError(t, err)
+	_, err = f.WriteString("not a certificate")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = loadCertPool(f.Name())
+	require.Error(t, err)
+}
@@ -16,6 +16,9 @@ type fromHandler = func(h *fasthttp.RequestHeader) []byte
 const (
 	bearerTokenHdr = "Bearer"
 	bearerTokenKey = "__context_bearer_token_key"
+
+	// amzSecurityTokenHdr is the header S3 clients send session token in.
+	amzSecurityTokenHdr = "X-Amz-Security-Token"
 )
 
 // BearerToken usage:
@@ -48,7 +51,18 @@ func BearerTokenFromCookie(h *fasthttp.RequestHeader) []byte {
 	return auth
 }
 
-// StoreBearerToken extracts a bearer token from the header or cookie and stores
+// BearerTokenFromAmzHeader extracts a bearer token from X-Amz-Security-Token
+// header, so that S3 clients can pass it as a session token.
+func BearerTokenFromAmzHeader(h *fasthttp.RequestHeader) []byte {
+	auth := h.Peek(amzSecurityTokenHdr)
+	if len(auth) == 0 {
+		return nil
+	}
+
+	return auth
+}
+
+// StoreBearerToken extracts a bearer token from the headers or cookie and stores
 // it in the request context.
 func StoreBearerToken(ctx *fasthttp.RequestCtx) error {
 	tkn, err := fetchBearerToken(ctx)
@@ -80,7 +94,7 @@ func fetchBearerToken(ctx *fasthttp.RequestCtx) (*bearer.Token, error) {
 		buf []byte
 		tkn = new(bearer.Token)
 	)
-	for _, parse := range []fromHandler{BearerTokenFromHeader, BearerTokenFromCookie} {
+	for _, parse := range []fromHandler{BearerTokenFromHeader, BearerTokenFromCookie, BearerTokenFromAmzHeader} {
 		if buf = parse(&ctx.Request.Header); buf == nil {
 			continue
 		} else if data, err := base64.StdEncoding.DecodeString(string(buf)); err != nil {
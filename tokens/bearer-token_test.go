@@ -58,6 +58,14 @@ func Test_fromHeader(t *testing.T) {
 	}
 }
 
+func Test_fromAmzHeader(t *testing.T) {
+	header := new(fasthttp.RequestHeader)
+	require.Nil(t, BearerTokenFromAmzHeader(header))
+
+	header.Set(amzSecurityTokenHdr, "TOKEN")
+	require.Equal(t, []byte("TOKEN"), BearerTokenFromAmzHeader(header))
+}
+
 func Test_fetchBearerToken(t *testing.T) {
 	var uid user.ID
 
@@ -0,0 +1,114 @@
+package uploader
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// sniffSize is the size of the payload head content type is detected by, see
+// http.DetectContentType.
+const sniffSize = 512
+
+// TypeFilter lists content types and file name extensions allowed for
+// uploads, empty list allows anything.
+type TypeFilter struct {
+	// ContentTypes are media types (like image/png) or wildcards (like
+	// image/*), both declared and detected types of the payload must match.
+	ContentTypes []string
+	// Extensions are file name extensions with the dot (like .png), they're
+	// case-insensitive.
+	Extensions []string
+}
+
+func (f TypeFilter) empty() bool {
+	return len(f.ContentTypes) == 0 && len(f.Extensions) == 0
+}
+
+// allowsExtension checks the extension of the file name.
+func (f TypeFilter) allowsExtension(filename string) bool {
+	if len(f.Extensions) == 0 {
+		return true
+	}
+	ext := path.Ext(filename)
+	for _, allowed := range f.Extensions {
+		if strings.EqualFold(ext, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsContentType checks the content type ignoring its parameters.
+func (f TypeFilter) allowsContentType(contentType string) bool {
+	if len(f.ContentTypes) == 0 {
+		return true
+	}
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range f.ContentTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == media || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(media, allowed[:len(allowed)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeFilter returns the filter of the container specified by name or ID in
+// request path, the global one is used if there is no filter for it.
+func (u *Uploader) typeFilter(scid string, cnrID *cid.ID) TypeFilter {
+	if f, ok := u.settings.ContainerAllowedTypes[scid]; ok {
+		return f
+	}
+	if f, ok := u.settings.ContainerAllowedTypes[cnrID.String()]; ok {
+		return f
+	}
+	return u.settings.AllowedTypes
+}
+
+// checkType checks the file name, declared content type and the one detected
+// from the payload head against the filter. The payload to be stored is
+// returned, otherwise the response is 415 error and false is returned.
+func checkType(c *fasthttp.RequestCtx, log *zap.Logger, f TypeFilter, payload io.Reader,
+	filename, declared string) (io.Reader, bool) {
+	reject := func(msg string) (io.Reader, bool) {
+		log.Warn("upload type isn't allowed", zap.String("reason", msg),
+			zap.String("filename", filename), zap.String("content_type", declared))
+		response.Error(c, msg, fasthttp.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	if !f.allowsExtension(filename) {
+		return reject("file extension isn't allowed: " + path.Ext(filename))
+	}
+	if len(f.ContentTypes) == 0 {
+		return payload, true
+	}
+	if declared != "" && !f.allowsContentType(declared) {
+		return reject("content type isn't allowed: " + declared)
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(payload, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		log.Error("could not read payload", zap.Error(err))
+		response.Error(c, "could not read payload: "+err.Error(), fasthttp.StatusBadRequest)
+		return nil, false
+	}
+	head = head[:n]
+	if detected := http.DetectContentType(head); !f.allowsContentType(detected) {
+		return reject("detected content type isn't allowed: " + detected)
+	}
+	return io.MultiReader(bytes.NewReader(head), payload), true
+}
@@ -0,0 +1,66 @@
+package uploader
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestTypeFilter(t *testing.T) {
+	f := TypeFilter{
+		ContentTypes: []string{"image/*", "application/pdf"},
+		Extensions:   []string{".jpg", ".PNG", ".pdf"},
+	}
+
+	require.True(t, f.allowsExtension("cat.JPG"))
+	require.True(t, f.allowsExtension("dir/cat.png"))
+	require.False(t, f.allowsExtension("cat.exe"))
+	require.False(t, f.allowsExtension("cat"))
+
+	require.True(t, f.allowsContentType("image/png"))
+	require.True(t, f.allowsContentType("Application/PDF; charset=binary"))
+	require.False(t, f.allowsContentType("application/octet-stream"))
+	require.False(t, f.allowsContentType("imagex/png"))
+	require.False(t, f.allowsContentType("invalid type"))
+
+	require.True(t, TypeFilter{}.allowsExtension("cat.exe"))
+	require.True(t, TypeFilter{}.allowsContentType("application/octet-stream"))
+	require.True(t, TypeFilter{}.empty())
+}
+
+func TestCheckType(t *testing.T) {
+	const png = "\x89PNG\r\n\x1a\n rest of the image"
+	f := TypeFilter{ContentTypes: []string{"image/png"}, Extensions: []string{".png"}}
+
+	check := func(payload, filename, declared string) (string, int) {
+		var c fasthttp.RequestCtx
+		r, ok := checkType(&c, zap.NewNop(), f, strings.NewReader(payload), filename, declared)
+		if !ok {
+			return "", c.Response.StatusCode()
+		}
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(data), fasthttp.StatusOK
+	}
+
+	data, status := check(png, "cat.png", "image/png")
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, png, data)
+
+	data, status = check(png, "cat.png", "")
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, png, data)
+
+	_, status = check(png, "cat.exe", "image/png")
+	require.Equal(t, fasthttp.StatusUnsupportedMediaType, status)
+
+	_, status = check(png, "cat.png", "application/x-msdownload")
+	require.Equal(t, fasthttp.StatusUnsupportedMediaType, status)
+
+	_, status = check("MZ\x90\x00 executable", "cat.png", "image/png")
+	require.Equal(t, fasthttp.StatusUnsupportedMediaType, status)
+}
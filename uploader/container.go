@@ -0,0 +1,323 @@
+package uploader
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/acl"
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	// sessionTokenHeader is the header with base64-encoded session token of
+	// the container creation.
+	sessionTokenHeader = "X-Neofs-Session-Token"
+
+	// systemContainerAttributePrefix is the prefix of container attributes
+	// reserved by NeoFS, they're set with request fields.
+	systemContainerAttributePrefix = "__NEOFS__"
+
+	// defaultBasicACL is the basic ACL of containers created without it.
+	defaultBasicACL = "private"
+)
+
+type (
+	// containerRequest is the body of container creation request.
+	containerRequest struct {
+		PlacementPolicy string            `json:"placement_policy"`
+		BasicACL        string            `json:"basic_acl"`
+		Attributes      map[string]string `json:"attributes"`
+		Name            string            `json:"name"`
+		Zone            string            `json:"zone"`
+
+		policy   *netmap.PlacementPolicy
+		basicACL acl.BasicACL
+	}
+
+	// containerResponse is the reply to container creation request.
+	containerResponse struct {
+		ContainerID string `json:"container_id"`
+		Name        string `json:"name,omitempty"`
+	}
+
+	// ContainerWait is the time to wait for the container operation to be
+	// reflected in the network and the interval to check it.
+	ContainerWait struct {
+		Timeout      time.Duration
+		PollInterval time.Duration
+	}
+)
+
+// params returns pool wait parameters, false is returned if any of the
+// settings isn't positive, so pool defaults are to be used.
+func (w ContainerWait) params() (pool.WaitParams, bool) {
+	var res pool.WaitParams
+	if w.Timeout <= 0 || w.PollInterval <= 0 {
+		return res, false
+	}
+	res.SetTimeout(w.Timeout)
+	res.SetPollInterval(w.PollInterval)
+	return res, true
+}
+
+// parseContainerRequest decodes and validates container creation request body.
+func parseContainerRequest(data []byte) (*containerRequest, error) {
+	var req containerRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	if req.PlacementPolicy == "" {
+		return nil, errors.New("placement_policy must be set")
+	}
+	var err error
+	if req.policy, err = policy.Parse(req.PlacementPolicy); err != nil {
+		return nil, fmt.Errorf("couldn't parse placement policy: %w", err)
+	}
+
+	if req.BasicACL == "" {
+		req.BasicACL = defaultBasicACL
+	}
+	if req.basicACL, err = acl.ParseBasicACL(req.BasicACL); err != nil {
+		return nil, fmt.Errorf("couldn't parse basic acl: %w", err)
+	}
+
+	for key := range req.Attributes {
+		switch {
+		case key == "":
+			return nil, errors.New("attribute key must not be empty")
+		case strings.HasPrefix(key, systemContainerAttributePrefix):
+			return nil, fmt.Errorf("attribute %s is reserved, name and zone fields must be used instead", key)
+		}
+	}
+	if req.Zone != "" && req.Name == "" {
+		return nil, errors.New("zone can't be set without name")
+	}
+
+	return &req, nil
+}
+
+// container returns the container of the owner described by the request.
+// Timestamp attribute is set to the current time if it's not in the request.
+func (r *containerRequest) container(now time.Time) *container.Container {
+	opts := []container.Option{
+		container.WithPolicy(r.policy),
+		container.WithCustomBasicACL(r.basicACL),
+	}
+	for key, val := range r.Attributes {
+		opts = append(opts, container.WithAttribute(key, val))
+	}
+	if _, ok := r.Attributes[container.AttributeTimestamp]; !ok {
+		opts = append(opts, container.WithAttribute(container.AttributeTimestamp, strconv.FormatInt(now.Unix(), 10)))
+	}
+
+	cnr := container.New(opts...)
+	switch {
+	case r.Zone != "":
+		container.SetNativeNameWithZone(cnr, r.Name, r.Zone)
+	case r.Name != "":
+		container.SetNativeName(cnr, r.Name)
+	}
+	return cnr
+}
+
+// containerSessionToken returns the session token of the container operation
+// given in the header, nil is returned if there is no token. The operation is
+// checked with the method of the token context, e.g. IsForPut.
+func containerSessionToken(h *fasthttp.RequestHeader, isFor func(*session.ContainerContext) bool) (*session.Token, error) {
+	value := h.Peek(sessionTokenHeader)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(string(value))
+	if err != nil {
+		return nil, fmt.Errorf("can't base64-decode session token: %w", err)
+	}
+	tkn := session.NewToken()
+	if err = tkn.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("can't unmarshal session token: %w", err)
+	}
+	if ctx := session.GetContainerContext(tkn); ctx == nil || !isFor(ctx) {
+		return nil, errors.New("session token isn't issued for the operation")
+	}
+	if tkn.OwnerID() == nil {
+		return nil, errors.New("session token has no owner")
+	}
+	return tkn, nil
+}
+
+// CreateContainer handles requests creating the container with the placement
+// policy, basic ACL and attributes from the request body. The container is
+// owned by the issuer of the session token given in X-Neofs-Session-Token
+// header or by the gateway if there is no token, the name is registered in
+// NNS if it's set. The reply is sent when the container is persisted.
+func (u *Uploader) CreateContainer(c *fasthttp.RequestCtx) {
+	var (
+		log        = u.log
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
+	)
+
+	req, err := parseContainerRequest(c.Request.Body())
+	if err != nil {
+		log.Error("could not parse request", zap.Error(err))
+		response.Error(c, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	tkn, err := containerSessionToken(&c.Request.Header, (*session.ContainerContext).IsForPut)
+	if err != nil {
+		log.Error("could not fetch session token", zap.Error(err))
+		response.Error(c, "could not fetch session token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	cnr := req.container(time.Now())
+	if tkn != nil {
+		cnr.SetOwnerID(tkn.OwnerID())
+		cnr.SetSessionToken(tkn)
+	} else {
+		cnr.SetOwnerID(clientPool.OwnerID())
+	}
+
+	var prm pool.PrmContainerPut
+	prm.SetContainer(*cnr)
+	if wait, ok := u.settings.ContainerCreation.params(); ok {
+		prm.SetWaitParams(wait)
+	}
+
+	idCnr, err := clientPool.PutContainer(ctx, prm)
+	if idCnr != nil {
+		utils.SetRequestContainer(c, idCnr)
+	}
+	if err != nil && idCnr != nil {
+		log.Error("container isn't persisted", zap.Stringer("cid", idCnr), zap.Error(err))
+		response.Error(c, "container "+idCnr.String()+" is requested, but it isn't persisted yet: "+err.Error(),
+			fasthttp.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		log.Error("could not create container", zap.Error(err))
+		response.Error(c, "could not create container: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	log.Info("container is created", zap.Stringer("cid", idCnr), zap.String("name", req.Name))
+
+	data, err := json.Marshal(containerResponse{ContainerID: idCnr.String(), Name: req.Name})
+	if err != nil {
+		log.Error("could not encode response", zap.Error(err))
+		response.Error(c, "could not encode response: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
+
+// checkContainerOwner checks the container can be changed by the gateway: it's
+// owned by the gateway if there is no session token or by the token issuer
+// otherwise. The response is an error if it can't, false is returned then.
+func (u *Uploader) checkContainerOwner(c *fasthttp.RequestCtx, log *zap.Logger, clientPool *pool.Pool,
+	idCnr *cid.ID, tkn *session.Token) bool {
+	var prm pool.PrmContainerGet
+	prm.SetContainerID(*idCnr)
+
+	cnr, err := clientPool.GetContainer(utils.RequestContext(c, u.appCtx), prm)
+	if err != nil {
+		log.Error("could not get container", zap.Error(err))
+		if strings.Contains(err.Error(), "not found") {
+			response.ErrorCode(c, response.CodeContainerNotFound, "container not found", fasthttp.StatusNotFound)
+			return false
+		}
+		response.Error(c, "could not get container: "+err.Error(), fasthttp.StatusBadRequest)
+		return false
+	}
+
+	owner := cnr.OwnerID()
+	switch {
+	case owner == nil:
+		log.Error("container has no owner")
+		response.Error(c, "container has no owner", fasthttp.StatusBadRequest)
+		return false
+	case tkn == nil && !owner.Equals(*clientPool.OwnerID()):
+		log.Error("container isn't owned by the gateway", zap.Stringer("owner", owner))
+		response.Error(c, "session token is required, container is owned by "+owner.String(), fasthttp.StatusForbidden)
+		return false
+	case tkn != nil && !owner.Equals(*tkn.OwnerID()):
+		log.Error("session token isn't issued by the container owner", zap.Stringer("owner", owner))
+		response.Error(c, "session token isn't issued by the container owner "+owner.String(), fasthttp.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// DeleteContainer handles requests deleting the container. NeoFS accepts the
+// deletion signed by the container owner only, so the session token of
+// container deletion issued by the owner to the gateway key must be given in
+// X-Neofs-Session-Token header unless the container is owned by the gateway.
+// The reply is sent when the container is removed.
+func (u *Uploader) DeleteContainer(c *fasthttp.RequestCtx) {
+	var (
+		scid, _    = c.UserValue("cid").(string)
+		log        = u.log.With(zap.String("cid", scid))
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
+	)
+
+	idCnr, err := utils.GetContainerID(ctx, scid, u.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	tkn, err := containerSessionToken(&c.Request.Header, (*session.ContainerContext).IsForDelete)
+	if err != nil {
+		log.Error("could not fetch session token", zap.Error(err))
+		response.Error(c, "could not fetch session token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if !u.checkContainerOwner(c, log, clientPool, idCnr, tkn) {
+		return
+	}
+
+	var prm pool.PrmContainerDelete
+	prm.SetContainerID(*idCnr)
+	if tkn != nil {
+		prm.SetSessionToken(*tkn)
+	}
+	if wait, ok := u.settings.ContainerDeletion.params(); ok {
+		prm.SetWaitParams(wait)
+	}
+
+	if err = clientPool.DeleteContainer(ctx, prm); err != nil {
+		log.Error("could not delete container", zap.Error(err))
+		if errors.Is(err, context.DeadlineExceeded) {
+			response.Error(c, "container deletion is requested, but it isn't removed yet: "+err.Error(),
+				fasthttp.StatusGatewayTimeout)
+			return
+		}
+		response.Error(c, "could not delete container: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	log.Info("container is deleted")
+
+	c.SetStatusCode(fasthttp.StatusNoContent)
+}
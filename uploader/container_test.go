@@ -0,0 +1,52 @@
+package uploader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseContainerRequest(t *testing.T) {
+	req, err := parseContainerRequest([]byte(`{"placement_policy":"REP 2","attributes":{"Owner":"team"},"name":"photos"}`))
+	require.NoError(t, err)
+	require.Equal(t, defaultBasicACL, req.BasicACL)
+	require.Equal(t, "team", req.Attributes["Owner"])
+	require.Equal(t, "photos", req.Name)
+
+	req, err = parseContainerRequest([]byte(`{"placement_policy":"REP 1","basic_acl":"public-read"}`))
+	require.NoError(t, err)
+	require.Equal(t, "public-read", req.BasicACL)
+
+	for _, body := range []string{
+		`{`,
+		`{"basic_acl":"public-read"}`,
+		`{"placement_policy":"REP 1","attributes":{"":"value"}}`,
+		`{"placement_policy":"REP 1","attributes":{"__NEOFS__NAME":"photos"}}`,
+		`{"placement_policy":"REP 1","zone":"container"}`,
+	} {
+		_, err = parseContainerRequest([]byte(body))
+		require.Error(t, err, body)
+	}
+}
+
+func TestContainerSessionToken(t *testing.T) {
+	var h fasthttp.RequestHeader
+	tkn, err := containerSessionToken(&h, (*session.ContainerContext).IsForPut)
+	require.NoError(t, err)
+	require.Nil(t, tkn)
+
+	h.Set(sessionTokenHeader, "not base64")
+	_, err = containerSessionToken(&h, (*session.ContainerContext).IsForPut)
+	require.Error(t, err)
+}
+
+func TestContainerWaitParams(t *testing.T) {
+	_, ok := ContainerWait{Timeout: time.Minute, PollInterval: time.Second}.params()
+	require.True(t, ok)
+
+	_, ok = ContainerWait{Timeout: time.Minute}.params()
+	require.False(t, ok)
+}
@@ -0,0 +1,262 @@
+package uploader
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// mergeAttributes returns the attributes of the source object with the values
+// of the given ones, they're added if the source object doesn't have them.
+func mergeAttributes(source []object.Attribute, values map[string]string) []object.Attribute {
+	res := make([]object.Attribute, 0, len(source)+len(values))
+	for _, attr := range source {
+		if _, ok := values[attr.Key()]; !ok {
+			res = append(res, attr)
+		}
+	}
+	for key, val := range values {
+		attr := object.NewAttribute()
+		attr.SetKey(key)
+		attr.SetValue(val)
+		res = append(res, *attr)
+	}
+	return res
+}
+
+// Copy handles requests to copy the object to the container given with "to"
+// argument, the payload is streamed from the source object to the new one by
+// the gateway. Attributes set with X-Attribute-* headers are merged with the
+// attributes of the source object. The copy is checked like uploads to the
+// target container: its attributes are validated, its type is checked against
+// the allowlist and its payload is scanned by antivirus.
+func (u *Uploader) Copy(c *fasthttp.RequestCtx) {
+	u.copy(c, false)
+}
+
+// Move handles requests to move or rename the object: it's copied like with
+// Copy (to the same container if "to" argument isn't set) and the source
+// object is deleted then.
+func (u *Uploader) Move(c *fasthttp.RequestCtx) {
+	u.copy(c, true)
+}
+
+// isRenamed reports whether the attributes change the name or the path of the
+// object.
+func isRenamed(attributes map[string]string) bool {
+	_, name := attributes[object.AttributeFileName]
+	_, path := attributes[attributeFilePath]
+	return name || path
+}
+
+// copy stores the copy of the object, the source object is deleted after that
+// if it's moved.
+func (u *Uploader) copy(c *fasthttp.RequestCtx, move bool) {
+	var (
+		scid, _    = c.UserValue("cid").(string)
+		soid, _    = c.UserValue("oid").(string)
+		sto        = string(c.QueryArgs().Peek("to"))
+		log        = u.log.With(zap.String("cid", scid), zap.String("oid", soid), zap.String("to", sto))
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
+	)
+
+	if err := tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch bearer token", zap.Error(err))
+		response.Error(c, "could not fetch bearer token", fasthttp.StatusBadRequest)
+		return
+	}
+
+	srcCnr, err := utils.GetContainerID(ctx, scid, u.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+	var objID oid.ID
+	if err = objID.DecodeString(soid); err != nil {
+		log.Error("wrong object id", zap.Error(err))
+		response.Error(c, "wrong object id", fasthttp.StatusBadRequest)
+		return
+	}
+	dstCnr := srcCnr
+	switch {
+	case sto != "":
+		if dstCnr, err = utils.GetContainerID(ctx, sto, u.containerResolver); err != nil {
+			log.Error("wrong target container id", zap.Error(err))
+			response.Error(c, "wrong target container id", fasthttp.StatusBadRequest)
+			return
+		}
+	case move:
+		sto = scid
+	default:
+		log.Error("target container isn't set")
+		response.Error(c, "target container isn't set with 'to' argument", fasthttp.StatusBadRequest)
+		return
+	}
+
+	filtered, ok := u.headerAttributes(c, log, clientPool)
+	if !ok {
+		return
+	}
+	if move && dstCnr.String() == srcCnr.String() && !isRenamed(filtered) {
+		log.Error("object isn't renamed")
+		response.Error(c, "neither target container nor "+object.AttributeFileName+" or "+
+			attributeFilePath+" attribute is set", fasthttp.StatusBadRequest)
+		return
+	}
+
+	id, bt := fetchOwnerAndBearerToken(c, clientPool)
+	key := utils.RequestKey(c)
+
+	src := address.NewAddress()
+	src.SetContainerID(*srcCnr)
+	src.SetObjectID(objID)
+
+	var prmGet pool.PrmObjectGet
+	prmGet.SetAddress(*src)
+	if bt != nil {
+		prmGet.UseBearer(*bt)
+	}
+	if key != nil {
+		prmGet.UseKey(key)
+	}
+
+	var res *pool.ResGetObject
+	err = u.retry.Do(ctx, func() error {
+		var err error
+		res, err = clientPool.GetObject(ctx, prmGet)
+		return err
+	})
+	if err != nil {
+		log.Error("could not get source object", zap.Error(err))
+		if strings.Contains(err.Error(), "not found") {
+			response.ErrorCode(c, response.CodeObjectNotFound, "object not found", fasthttp.StatusNotFound)
+			return
+		}
+		response.Error(c, "could not get source object: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	defer res.Payload.Close()
+
+	attributes := mergeAttributes(res.Header.Attributes(), filtered)
+	values := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		values[attr.Key()] = attr.Value()
+	}
+	if err = utils.ValidateAttributes(values, u.attributeLimits); err != nil {
+		log.Error("invalid attributes", zap.Error(err))
+		response.Error(c, "invalid attributes: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	var body io.Reader = res.Payload
+	if filter := u.typeFilter(sto, dstCnr); !filter.empty() {
+		if body, ok = checkType(c, log, filter, body, values[object.AttributeFileName],
+			values[object.AttributeContentType]); !ok {
+			return
+		}
+	}
+
+	payload := &sizeLimitedReader{r: body, limit: -1}
+	if limit, ok := u.maxObjectSize(sto, dstCnr); ok {
+		payload.limit = limit
+		if res.Header.PayloadSize() > uint64(limit) {
+			log.Error("object is too large", zap.Int64("limit", limit))
+			response.Error(c, "object size exceeds the limit of "+strconv.FormatInt(limit, 10)+
+				" bytes for container "+sto, fasthttp.StatusRequestEntityTooLarge)
+			return
+		}
+	}
+
+	if failOpen, scanning := u.settings.Scan.Policy(sto, dstCnr); scanning {
+		spooled, ok := u.spool(c, log, payload, nil, sto)
+		if !ok {
+			return
+		}
+		defer spooled.remove()
+		if !u.scan(c, log, spooled, nil, failOpen) {
+			return
+		}
+		payload = &sizeLimitedReader{r: spooled, limit: -1}
+	}
+
+	obj := object.New()
+	obj.SetContainerID(*dstCnr)
+	obj.SetOwnerID(id)
+	obj.SetAttributes(attributes...)
+
+	var prmPut pool.PrmObjectPut
+	prmPut.SetHeader(*obj)
+	prmPut.SetPayload(payload)
+	if bt != nil {
+		prmPut.UseBearer(*bt)
+	}
+	if key != nil {
+		prmPut.UseKey(key)
+	}
+
+	var idObj *oid.ID
+	err = u.retry.Do(ctx, func() error {
+		var err error
+		idObj, err = clientPool.PutObject(ctx, prmPut)
+		if err != nil && payload.read > 0 {
+			// the payload stream can't be rewound
+			return utils.NoRetry(err)
+		}
+		return err
+	})
+	if err != nil {
+		if payload.exceeded {
+			log.Error("object is too large", zap.Int64("limit", payload.limit))
+			response.Error(c, "object size exceeds the limit of "+strconv.FormatInt(payload.limit, 10)+
+				" bytes for container "+sto, fasthttp.StatusRequestEntityTooLarge)
+			return
+		}
+		log.Error("could not store object copy in neofs", zap.Error(err))
+		response.Error(c, "could not store object copy in neofs: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	addr := address.NewAddress()
+	addr.SetContainerID(*dstCnr)
+	addr.SetObjectID(*idObj)
+	u.notify(utils.EventObjectCreated, dstCnr, idObj, payload.read, attributes)
+	utils.AddRequestObject(c, idObj.String())
+	utils.AddStoredBytes(c, payload.read)
+
+	if move {
+		var prmDelete pool.PrmObjectDelete
+		prmDelete.SetAddress(*src)
+		if bt != nil {
+			prmDelete.UseBearer(*bt)
+		}
+		if key != nil {
+			prmDelete.UseKey(key)
+		}
+
+		err = u.retry.Do(ctx, func() error {
+			return clientPool.DeleteObject(ctx, prmDelete)
+		})
+		if err != nil {
+			log.Error("could not delete source object", zap.Stringer("copy", idObj), zap.Error(err))
+			response.Error(c, "object is copied to "+idObj.String()+", but the source isn't deleted: "+err.Error(),
+				fasthttp.StatusInternalServerError)
+			return
+		}
+		u.notify(utils.EventObjectDeleted, srcCnr, &objID, int64(res.Header.PayloadSize()), res.Header.Attributes())
+		utils.AddRequestObject(c, objID.String())
+	}
+
+	u.replyPut(c, log, newPutResponse(addr))
+}
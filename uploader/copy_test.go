@@ -0,0 +1,32 @@
+package uploader
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAttributes(t *testing.T) {
+	source := make([]object.Attribute, 0, 2)
+	for key, val := range map[string]string{"FileName": "cat.jpeg", "Tag": "old"} {
+		attr := object.NewAttribute()
+		attr.SetKey(key)
+		attr.SetValue(val)
+		source = append(source, *attr)
+	}
+
+	merged := make(map[string]string)
+	for _, attr := range mergeAttributes(source, map[string]string{"Tag": "new", "Copy": "true"}) {
+		_, ok := merged[attr.Key()]
+		require.False(t, ok, attr.Key())
+		merged[attr.Key()] = attr.Value()
+	}
+	require.Equal(t, map[string]string{"FileName": "cat.jpeg", "Tag": "new", "Copy": "true"}, merged)
+}
+
+func TestIsRenamed(t *testing.T) {
+	require.False(t, isRenamed(map[string]string{"Tag": "new"}))
+	require.True(t, isRenamed(map[string]string{"FileName": "new.txt"}))
+	require.True(t, isRenamed(map[string]string{"FilePath": "dir/new.txt"}))
+}
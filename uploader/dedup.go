@@ -0,0 +1,117 @@
+package uploader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	v2object "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+)
+
+// maxDuplicateCandidates limits the number of objects with the same payload
+// and attributes checked for other attributes.
+const maxDuplicateCandidates = 16
+
+// duplicateFilters returns filters of root objects with the payload hash and
+// the attributes.
+func duplicateFilters(sum [sha256.Size]byte, attributes map[string]string) object.SearchFilters {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	filters.AddFilter(v2object.FilterHeaderPayloadHash, hex.EncodeToString(sum[:]), object.MatchStringEqual)
+	for key, val := range attributes {
+		filters.AddFilter(key, val, object.MatchStringEqual)
+	}
+	return filters
+}
+
+// sameAttributes checks that the object has exactly the attributes (including
+// expiration ones). Timestamp attribute is ignored unless it's set with the
+// upload since the gateway sets it to the time of the upload.
+func sameAttributes(obj *object.Object, attributes map[string]string) bool {
+	matched := 0
+	for _, attr := range obj.Attributes() {
+		val, ok := attributes[attr.Key()]
+		switch {
+		case !ok && attr.Key() == object.AttributeTimestamp:
+			continue
+		case !ok || val != attr.Value():
+			return false
+		}
+		matched++
+	}
+	return matched == len(attributes)
+}
+
+// findDuplicate returns the ID of the object in the container with the same
+// payload and attributes, nil is returned if there is no such object.
+func (u *Uploader) findDuplicate(ctx context.Context, c *fasthttp.RequestCtx, clientPool *pool.Pool, cnrID *cid.ID,
+	bt *bearer.Token, sum [sha256.Size]byte, attributes map[string]string) (*oid.ID, error) {
+	key := utils.RequestKey(c)
+
+	var prm pool.PrmObjectSearch
+	prm.SetContainerID(*cnrID)
+	prm.SetFilters(duplicateFilters(sum, attributes))
+	if bt != nil {
+		prm.UseBearer(*bt)
+	}
+	if key != nil {
+		prm.UseKey(key)
+	}
+
+	var res *pool.ResObjectSearch
+	err := u.retry.Do(ctx, func() error {
+		var err error
+		res, err = clientPool.SearchObjects(ctx, prm)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []oid.ID
+	err = res.Iterate(func(found oid.ID) bool {
+		candidates = append(candidates, found)
+		return len(candidates) == maxDuplicateCandidates
+	})
+	res.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		addr := address.NewAddress()
+		addr.SetContainerID(*cnrID)
+		addr.SetObjectID(candidates[i])
+
+		var prmHead pool.PrmObjectHead
+		prmHead.SetAddress(*addr)
+		if bt != nil {
+			prmHead.UseBearer(*bt)
+		}
+		if key != nil {
+			prmHead.UseKey(key)
+		}
+
+		var header *object.Object
+		err = u.retry.Do(ctx, func() error {
+			var err error
+			header, err = clientPool.HeadObject(ctx, prmHead)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if sameAttributes(header, attributes) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,43 @@
+package uploader
+
+import (
+	"testing"
+
+	v2object "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameAttributes(t *testing.T) {
+	newObject := func(kv ...string) *object.Object {
+		attrs := make([]object.Attribute, 0, len(kv)/2)
+		for i := 0; i < len(kv); i += 2 {
+			attr := object.NewAttribute()
+			attr.SetKey(kv[i])
+			attr.SetValue(kv[i+1])
+			attrs = append(attrs, *attr)
+		}
+		obj := object.New()
+		obj.SetAttributes(attrs...)
+		return obj
+	}
+
+	attributes := map[string]string{
+		object.AttributeFileName:      "cat.jpg",
+		v2object.SysAttributeExpEpoch: "100",
+	}
+
+	require.True(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg",
+		v2object.SysAttributeExpEpoch, "100"), attributes))
+	require.True(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg",
+		v2object.SysAttributeExpEpoch, "100", object.AttributeTimestamp, "1650000000"), attributes))
+	require.False(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg"), attributes))
+	require.False(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg",
+		v2object.SysAttributeExpEpoch, "200"), attributes))
+	require.False(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg",
+		v2object.SysAttributeExpEpoch, "100", "Tag", "backup"), attributes))
+
+	attributes[object.AttributeTimestamp] = "1650000000"
+	require.False(t, sameAttributes(newObject(object.AttributeFileName, "cat.jpg",
+		v2object.SysAttributeExpEpoch, "100", object.AttributeTimestamp, "1660000000"), attributes))
+}
@@ -0,0 +1,114 @@
+package uploader
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// eaclContainerIDField is the field of container ID in JSON of eACL table.
+const eaclContainerIDField = "containerID"
+
+// tableFromJSON decodes eACL table of the container from its JSON (as it's
+// created by neofs-cli), the container ID is set if it's missing, the table
+// of other container is an error.
+func tableFromJSON(data []byte, cnrID cid.ID) (*eacl.Table, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("could not decode table: %w", err)
+	}
+	if _, ok := fields[eaclContainerIDField]; !ok {
+		value := make([]byte, 32)
+		cnrID.Encode(value)
+		id, err := json.Marshal(map[string]string{"value": base64.StdEncoding.EncodeToString(value)})
+		if err != nil {
+			return nil, fmt.Errorf("could not encode container id: %w", err)
+		}
+		fields[eaclContainerIDField] = id
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, fmt.Errorf("could not encode table: %w", err)
+		}
+	}
+
+	table := eacl.NewTable()
+	if err := table.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("could not decode table: %w", err)
+	}
+	if id, ok := table.CID(); ok && !id.Equals(cnrID) {
+		return nil, fmt.Errorf("table is for container %s", id)
+	}
+	return table, nil
+}
+
+// SetContainerEACL handles requests setting extended ACL table of the
+// container from JSON in the request body. The table is signed by the gateway,
+// so it must own the container or the session token of eACL change issued by
+// the owner to the gateway key must be given in X-Neofs-Session-Token header.
+// The reply is sent when the table is applied.
+func (u *Uploader) SetContainerEACL(c *fasthttp.RequestCtx) {
+	var (
+		scid, _    = c.UserValue("cid").(string)
+		log        = u.log.With(zap.String("cid", scid))
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
+	)
+
+	idCnr, err := utils.GetContainerID(ctx, scid, u.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	table, err := tableFromJSON(c.Request.Body(), *idCnr)
+	if err != nil {
+		log.Error("invalid eacl table", zap.Error(err))
+		response.Error(c, "invalid eacl table: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	tkn, err := containerSessionToken(&c.Request.Header, (*session.ContainerContext).IsForSetEACL)
+	if err != nil {
+		log.Error("could not fetch session token", zap.Error(err))
+		response.Error(c, "could not fetch session token: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if !u.checkContainerOwner(c, log, clientPool, idCnr, tkn) {
+		return
+	}
+	if tkn != nil {
+		table.SetSessionToken(tkn)
+	}
+
+	var prm pool.PrmContainerSetEACL
+	prm.SetTable(*table)
+	if wait, ok := u.settings.ContainerEACL.params(); ok {
+		prm.SetWaitParams(wait)
+	}
+
+	if err = clientPool.SetEACL(ctx, prm); err != nil {
+		log.Error("could not set eacl", zap.Error(err))
+		if errors.Is(err, context.DeadlineExceeded) {
+			response.Error(c, "eacl change is requested, but it isn't applied yet: "+err.Error(),
+				fasthttp.StatusGatewayTimeout)
+			return
+		}
+		response.Error(c, "could not set eacl: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	log.Info("eacl is set")
+
+	c.SetStatusCode(fasthttp.StatusNoContent)
+}
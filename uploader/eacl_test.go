@@ -0,0 +1,31 @@
+package uploader
+
+import (
+	"encoding/base64"
+	"testing"
+
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableFromJSON(t *testing.T) {
+	var cnrID, otherID cid.ID
+	require.NoError(t, cnrID.DecodeString("Dxhf4PNprrJHWWTG5RGLdfLkJiSQ3AQqit1MSnEPRkDZ"))
+	require.NoError(t, otherID.DecodeString("5HZTn5qkRnmgSz9gSrw22CEdPPk6nQhkwf2Mgzyvkikv"))
+
+	table, err := tableFromJSON([]byte(`{"records":[]}`), cnrID)
+	require.NoError(t, err)
+	id, ok := table.CID()
+	require.True(t, ok)
+	require.True(t, id.Equals(cnrID))
+
+	other := make([]byte, 32)
+	otherID.Encode(other)
+	_, err = tableFromJSON([]byte(`{"containerID":{"value":"`+base64.StdEncoding.EncodeToString(other)+`"}}`), cnrID)
+	require.Error(t, err)
+
+	for _, body := range []string{``, `{`, `[]`} {
+		_, err = tableFromJSON([]byte(body), cnrID)
+		require.Error(t, err, body)
+	}
+}
@@ -25,9 +25,30 @@ func systemTranslator(key, prefix []byte) []byte {
 	return bytes.ToUpper(key)
 }
 
+// Policies of resolving conflicting values of the same attribute.
+const (
+	// DuplicatesReject rejects uploads with different values of the attribute.
+	DuplicatesReject = "reject"
+	// DuplicatesFirst uses the first value, headers precede the multipart
+	// file name.
+	DuplicatesFirst = "first"
+	// DuplicatesLast uses the last value.
+	DuplicatesLast = "last"
+)
+
 func filterHeaders(l *zap.Logger, header *fasthttp.RequestHeader) map[string]string {
-	result := make(map[string]string)
-	prefix := []byte(utils.UserAttributeHeaderPrefix)
+	result, _ := filterHeadersPolicy(l, header, DuplicatesLast)
+	return result
+}
+
+// filterHeadersPolicy returns attributes set with headers, values of the same
+// attribute set several times are resolved according to the policy.
+func filterHeadersPolicy(l *zap.Logger, header *fasthttp.RequestHeader, policy string) (map[string]string, error) {
+	var (
+		result   = make(map[string]string)
+		prefix   = []byte(utils.UserAttributeHeaderPrefix)
+		conflict error
+	)
 
 	header.VisitAll(func(key, val []byte) {
 		// checks that the key and the val not empty
@@ -59,6 +80,18 @@ func filterHeaders(l *zap.Logger, header *fasthttp.RequestHeader) map[string]str
 		// make string representation of key / val
 		k, v := string(key), string(val)
 
+		if prev, ok := result[k]; ok && prev != v {
+			switch policy {
+			case DuplicatesReject:
+				if conflict == nil {
+					conflict = fmt.Errorf("attribute %s is set several times with different values", k)
+				}
+				return
+			case DuplicatesFirst:
+				l.Debug("ignore duplicate attribute", zap.String("key", k), zap.String("val", v))
+				return
+			}
+		}
 		result[k] = v
 
 		l.Debug("add attribute to result object",
@@ -66,7 +99,7 @@ func filterHeaders(l *zap.Logger, header *fasthttp.RequestHeader) map[string]str
 			zap.String("val", v))
 	})
 
-	return result
+	return result, conflict
 }
 
 func prepareExpirationHeader(headers map[string]string, epochDurations *epochDurations) error {
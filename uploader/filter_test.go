@@ -172,3 +172,82 @@ func TestPrepareExpirationHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterDuplicates(t *testing.T) {
+	req := &fasthttp.RequestHeader{}
+	req.DisableNormalizing()
+	req.Add("X-Attribute-Type", "first")
+	req.Add("X-Attribute-Type", "last")
+	req.Add("X-Attribute-Same", "value")
+	req.Add("X-Attribute-Same", "value")
+
+	result, err := filterHeadersPolicy(zap.NewNop(), req, DuplicatesFirst)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Type": "first", "Same": "value"}, result)
+
+	result, err = filterHeadersPolicy(zap.NewNop(), req, DuplicatesLast)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Type": "last", "Same": "value"}, result)
+
+	_, err = filterHeadersPolicy(zap.NewNop(), req, DuplicatesReject)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "attribute Type is set several times")
+}
+
+func TestResolveFileName(t *testing.T) {
+	header := map[string]string{"FileName": "header.txt"}
+
+	for _, policy := range []string{DuplicatesFirst, DuplicatesLast, DuplicatesReject} {
+		name, err := resolveFileName(map[string]string{}, "form.txt", policy)
+		require.NoError(t, err)
+		require.Equal(t, "form.txt", name)
+
+		name, err = resolveFileName(map[string]string{"FileName": "form.txt"}, "form.txt", policy)
+		require.NoError(t, err)
+		require.Equal(t, "form.txt", name)
+	}
+
+	name, err := resolveFileName(header, "form.txt", DuplicatesFirst)
+	require.NoError(t, err)
+	require.Equal(t, "header.txt", name)
+
+	name, err = resolveFileName(header, "form.txt", DuplicatesLast)
+	require.NoError(t, err)
+	require.Equal(t, "form.txt", name)
+
+	_, err = resolveFileName(header, "form.txt", DuplicatesReject)
+	require.Error(t, err)
+}
+
+func TestResolveFilePath(t *testing.T) {
+	for _, tc := range []struct {
+		urlPath, expected string
+	}{
+		{urlPath: "", expected: ""},
+		{urlPath: "docs/a.txt", expected: "docs/a.txt"},
+		{urlPath: "/docs//b/../a.txt", expected: "docs/a.txt"},
+		{urlPath: "docs/", expected: "docs/form.txt"},
+		{urlPath: "/", expected: "form.txt"},
+	} {
+		filePath, err := resolveFilePath(map[string]string{}, tc.urlPath, "form.txt", DuplicatesReject)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, filePath, tc.urlPath)
+	}
+
+	header := map[string]string{"FilePath": "header/a.txt"}
+
+	filePath, err := resolveFilePath(header, "", "form.txt", DuplicatesReject)
+	require.NoError(t, err)
+	require.Equal(t, "header/a.txt", filePath)
+
+	filePath, err = resolveFilePath(header, "url/a.txt", "form.txt", DuplicatesFirst)
+	require.NoError(t, err)
+	require.Equal(t, "header/a.txt", filePath)
+
+	filePath, err = resolveFilePath(header, "url/a.txt", "form.txt", DuplicatesLast)
+	require.NoError(t, err)
+	require.Equal(t, "url/a.txt", filePath)
+
+	_, err = resolveFilePath(header, "url/a.txt", "form.txt", DuplicatesReject)
+	require.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package uploader
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+var errAsyncDisabled = errors.New("asynchronous uploads are disabled")
+
+// Statuses of asynchronous upload jobs.
+const (
+	JobPending = "pending"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+type (
+	// job is the state of asynchronous upload.
+	job struct {
+		ID          string `json:"job_id"`
+		Status      string `json:"status"`
+		ContainerID string `json:"container_id"`
+		ObjectID    string `json:"object_id,omitempty"`
+		Error       string `json:"error,omitempty"`
+
+		finished time.Time
+	}
+
+	// jobs keeps the state of asynchronous uploads, finished ones are kept
+	// for ttl.
+	jobs struct {
+		mu   sync.Mutex
+		ttl  time.Duration
+		list map[string]*job
+	}
+)
+
+func newJobs(ttl time.Duration) *jobs {
+	return &jobs{ttl: ttl, list: make(map[string]*job)}
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// add registers a new pending job and drops expired ones.
+func (j *jobs) add(cnrID *cid.ID) (job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return job{}, err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range j.list {
+		if !v.finished.IsZero() && now.Sub(v.finished) > j.ttl {
+			delete(j.list, k)
+		}
+	}
+
+	res := &job{ID: id, Status: JobPending, ContainerID: cnrID.String()}
+	j.list[id] = res
+	return *res, nil
+}
+
+// finish records the result of the job.
+func (j *jobs) finish(id string, objID *oid.ID, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	res, ok := j.list[id]
+	if !ok {
+		return
+	}
+
+	res.finished = time.Now()
+	if err != nil {
+		res.Status = JobFailed
+		res.Error = err.Error()
+		return
+	}
+	res.Status = JobDone
+	res.ObjectID = objID.String()
+}
+
+// get returns the job if it's known and isn't expired.
+func (j *jobs) get(id string) (job, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	res, ok := j.list[id]
+	if !ok || !res.finished.IsZero() && time.Since(res.finished) > j.ttl {
+		return job{}, false
+	}
+	return *res, true
+}
+
+// uploadAsync spools the payload to a temporary file, responds with the job
+// and puts the object to NeoFS in background. It returns false if the
+// response is an error.
+func (u *Uploader) uploadAsync(c *fasthttp.RequestCtx, log *zap.Logger, clientPool *pool.Pool, prm pool.PrmObjectPut,
+	payload *sizeLimitedReader, pr *progress, scid string, idCnr *cid.ID, attributes []object.Attribute) bool {
+	if u.jobs == nil {
+		pr.finish(errAsyncDisabled)
+		log.Error("asynchronous uploads are disabled")
+		response.Error(c, "asynchronous uploads are disabled", fasthttp.StatusBadRequest)
+		return false
+	}
+
+	spool, err := os.CreateTemp(u.settings.SpoolDir, "upload-")
+	if err != nil {
+		pr.finish(err)
+		log.Error("could not create spool file", zap.Error(err))
+		response.Error(c, "could not create spool file", fasthttp.StatusInternalServerError)
+		return false
+	}
+	removeSpool := func() {
+		_ = spool.Close()
+		if err := os.Remove(spool.Name()); err != nil {
+			log.Warn("could not remove spool file", zap.String("file", spool.Name()), zap.Error(err))
+		}
+	}
+
+	if _, err = io.Copy(spool, payload); err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		pr.finish(err)
+		removeSpool()
+		if payload.exceeded {
+			log.Error("object is too large", zap.Int64("limit", payload.limit))
+			response.Error(c, "object size exceeds the limit of "+strconv.FormatInt(payload.limit, 10)+
+				" bytes for container "+scid, fasthttp.StatusRequestEntityTooLarge)
+			return false
+		}
+		log.Error("could not spool file", zap.Error(err))
+		response.Error(c, "could not spool file: "+err.Error(), fasthttp.StatusBadRequest)
+		return false
+	}
+
+	j, err := u.jobs.add(idCnr)
+	if err != nil {
+		pr.finish(err)
+		removeSpool()
+		log.Error("could not create upload job", zap.Error(err))
+		response.Error(c, "could not create upload job", fasthttp.StatusInternalServerError)
+		return false
+	}
+
+	prm.SetPayload(pr.storedReader(spool))
+	log = log.With(zap.String("job", j.ID))
+
+	go func() {
+		defer removeSpool()
+
+		var idObj *oid.ID
+		err := u.retry.Do(u.appCtx, func() error {
+			if _, err := spool.Seek(0, io.SeekStart); err != nil {
+				return utils.NoRetry(err)
+			}
+			pr.restartStored()
+
+			var err error
+			idObj, err = clientPool.PutObject(u.appCtx, prm)
+			return err
+		})
+		if err != nil {
+			log.Error("could not store file in neofs", zap.Error(err))
+		} else {
+			log.Info("upload job finished", zap.Stringer("oid", idObj))
+			u.notify(utils.EventObjectCreated, idCnr, idObj, payload.read, attributes)
+		}
+		u.jobs.finish(j.ID, idObj, err)
+		pr.finish(err)
+	}()
+
+	if err = json.NewEncoder(c).Encode(j); err != nil {
+		log.Error("could not encode response", zap.Error(err))
+		response.Error(c, "could not encode response", fasthttp.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// JobStatus handles requests for the state of asynchronous upload.
+func (u *Uploader) JobStatus(c *fasthttp.RequestCtx) {
+	id, _ := c.UserValue("id").(string)
+
+	var (
+		j  job
+		ok bool
+	)
+	if u.jobs != nil {
+		j, ok = u.jobs.get(id)
+	}
+	if !ok {
+		response.Error(c, "job not found", fasthttp.StatusNotFound)
+		return
+	}
+
+	data, err := json.Marshal(j)
+	if err != nil {
+		u.log.Error("could not encode job", zap.String("job", id), zap.Error(err))
+		response.Error(c, "could not encode job", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.Response.Header.SetContentType(jsonHeader)
+	c.SetBody(data)
+}
@@ -0,0 +1,65 @@
+package uploader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobs(t *testing.T) {
+	j := newJobs(time.Hour)
+
+	ok, err := j.add(new(cid.ID))
+	require.NoError(t, err)
+	require.Equal(t, JobPending, ok.Status)
+
+	failed, err := j.add(new(cid.ID))
+	require.NoError(t, err)
+	require.NotEqual(t, ok.ID, failed.ID)
+
+	res, found := j.get(ok.ID)
+	require.True(t, found)
+	require.Equal(t, ok, res)
+
+	j.finish(ok.ID, new(oid.ID), nil)
+	j.finish(failed.ID, nil, errors.New("no space left"))
+
+	res, found = j.get(ok.ID)
+	require.True(t, found)
+	require.Equal(t, JobDone, res.Status)
+	require.Empty(t, res.Error)
+
+	res, found = j.get(failed.ID)
+	require.True(t, found)
+	require.Equal(t, JobFailed, res.Status)
+	require.Equal(t, "no space left", res.Error)
+
+	_, found = j.get("unknown")
+	require.False(t, found)
+
+	t.Run("expired", func(t *testing.T) {
+		j := newJobs(time.Millisecond)
+
+		done, err := j.add(new(cid.ID))
+		require.NoError(t, err)
+		pending, err := j.add(new(cid.ID))
+		require.NoError(t, err)
+
+		j.finish(done.ID, new(oid.ID), nil)
+		time.Sleep(10 * time.Millisecond)
+
+		_, found := j.get(done.ID)
+		require.False(t, found)
+		_, found = j.get(pending.ID)
+		require.True(t, found)
+
+		_, err = j.add(new(cid.ID))
+		require.NoError(t, err)
+		require.NotContains(t, j.list, done.ID)
+		require.Contains(t, j.list, pending.ID)
+	})
+}
@@ -0,0 +1,177 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	v2object "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Headers of uploads to be protected from deletion with LOCK object.
+const (
+	lockUntilEpochHeader = "X-Neofs-Lock-Until-Epoch"
+	lockDurationHeader   = "X-Neofs-Lock-Duration"
+)
+
+// lockRequest is the lock requested with the headers: the last epoch or the
+// duration of the lock.
+type lockRequest struct {
+	untilEpoch uint64
+	duration   time.Duration
+}
+
+// parseLockHeaders returns the lock requested with the headers, nil is
+// returned if there are no lock headers.
+func parseLockHeaders(h *fasthttp.RequestHeader) (*lockRequest, error) {
+	epoch, duration := h.Peek(lockUntilEpochHeader), h.Peek(lockDurationHeader)
+	switch {
+	case len(epoch) == 0 && len(duration) == 0:
+		return nil, nil
+	case len(epoch) != 0 && len(duration) != 0:
+		return nil, fmt.Errorf("only one of %s and %s headers can be set", lockUntilEpochHeader, lockDurationHeader)
+	case len(epoch) != 0:
+		value, err := strconv.ParseUint(string(epoch), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse value %s of header %s", epoch, lockUntilEpochHeader)
+		}
+		return &lockRequest{untilEpoch: value}, nil
+	default:
+		value, err := time.ParseDuration(string(duration))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse value %s of header %s", duration, lockDurationHeader)
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("value %s of header %s must be positive", duration, lockDurationHeader)
+		}
+		return &lockRequest{duration: value}, nil
+	}
+}
+
+// epoch returns the last epoch the object is locked in, the duration is
+// rounded up to the whole number of epochs.
+func (l *lockRequest) epoch(durations *epochDurations) (uint64, error) {
+	if l.duration == 0 {
+		if l.untilEpoch <= durations.currentEpoch {
+			return 0, fmt.Errorf("value %d of header %s must be greater than the current epoch %d",
+				l.untilEpoch, lockUntilEpochHeader, durations.currentEpoch)
+		}
+		return l.untilEpoch, nil
+	}
+
+	return durations.epochAfter(l.duration), nil
+}
+
+// epochAfter returns the epoch the duration ends in, it's rounded up to the
+// whole number of epochs.
+func (d *epochDurations) epochAfter(duration time.Duration) uint64 {
+	epochDuration := d.msPerBlock * int64(d.blockPerEpoch)
+	numEpoch := (duration.Milliseconds() + epochDuration - 1) / epochDuration
+	return d.currentEpoch + uint64(numEpoch)
+}
+
+// lock stores LOCK object protecting the object from deletion until the end of
+// the epoch.
+func (u *Uploader) lock(ctx context.Context, clientPool *pool.Pool, cnrID *cid.ID, owner *user.ID, objID *oid.ID,
+	untilEpoch uint64, bt *bearer.Token, key *ecdsa.PrivateKey) (*oid.ID, error) {
+	var lock object.Lock
+	lock.WriteMembers([]oid.ID{*objID})
+
+	expiration := object.NewAttribute()
+	expiration.SetKey(v2object.SysAttributeExpEpoch)
+	expiration.SetValue(strconv.FormatUint(untilEpoch, 10))
+
+	obj := object.New()
+	obj.SetContainerID(*cnrID)
+	obj.SetOwnerID(owner)
+	obj.SetType(object.TypeLock)
+	obj.SetAttributes(*expiration)
+
+	var prm pool.PrmObjectPut
+	prm.SetHeader(*obj)
+	if bt != nil {
+		prm.UseBearer(*bt)
+	}
+	if key != nil {
+		prm.UseKey(key)
+	}
+
+	var idLock *oid.ID
+	err := u.retry.Do(ctx, func() error {
+		prm.SetPayload(bytes.NewReader(lock.Marshal()))
+
+		var err error
+		idLock, err = clientPool.PutObject(ctx, prm)
+		return err
+	})
+	return idLock, err
+}
+
+// lockEpoch returns the last epoch the upload is to be locked in, it's zero if
+// the lock isn't requested. The response is an error if lock headers are
+// invalid, false is returned then.
+func (u *Uploader) lockEpoch(c *fasthttp.RequestCtx, log *zap.Logger, clientPool *pool.Pool) (uint64, bool) {
+	req, err := parseLockHeaders(&c.Request.Header)
+	if err == nil && req != nil && c.QueryArgs().GetBool("async") {
+		err = errors.New("asynchronous uploads can't be locked")
+	}
+	if err != nil {
+		log.Error("invalid lock headers", zap.Error(err))
+		response.Error(c, "invalid lock headers: "+err.Error(), fasthttp.StatusBadRequest)
+		return 0, false
+	}
+	if req == nil {
+		return 0, true
+	}
+
+	durations, err := u.getEpochDurations(c, clientPool)
+	if err != nil {
+		log.Error("could not get epoch durations from network info", zap.Error(err))
+		response.Error(c, "could not get epoch durations from network info: "+err.Error(), fasthttp.StatusBadRequest)
+		return 0, false
+	}
+	epoch, err := req.epoch(durations)
+	if err != nil {
+		log.Error("invalid lock headers", zap.Error(err))
+		response.Error(c, "invalid lock headers: "+err.Error(), fasthttp.StatusBadRequest)
+		return 0, false
+	}
+	return epoch, true
+}
+
+// lockStored locks the stored object and adds the lock to the response. The
+// response is an error if the object isn't locked, false is returned then.
+func (u *Uploader) lockStored(c *fasthttp.RequestCtx, log *zap.Logger, clientPool *pool.Pool, owner *user.ID,
+	addr *address.Address, untilEpoch uint64, bt *bearer.Token, res *putResponse) bool {
+	cnrID, _ := addr.ContainerID()
+	objID, _ := addr.ObjectID()
+
+	idLock, err := u.lock(utils.RequestContext(c, u.appCtx), clientPool, &cnrID, owner, &objID, untilEpoch, bt, utils.RequestKey(c))
+	if err != nil {
+		log.Error("could not lock object", zap.Stringer("oid", &objID), zap.Error(err))
+		response.Error(c, "object is stored as "+objID.String()+", but it isn't locked: "+err.Error(),
+			fasthttp.StatusInternalServerError)
+		return false
+	}
+
+	log.Debug("object is locked", zap.Stringer("oid", &objID), zap.Stringer("lock", idLock),
+		zap.Uint64("until_epoch", untilEpoch))
+	res.LockID = idLock.String()
+	res.LockedUntil = untilEpoch
+	return true
+}
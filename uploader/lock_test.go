@@ -0,0 +1,60 @@
+package uploader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseLockHeaders(t *testing.T) {
+	parse := func(headers map[string]string) (*lockRequest, error) {
+		var h fasthttp.RequestHeader
+		for k, v := range headers {
+			h.Set(k, v)
+		}
+		return parseLockHeaders(&h)
+	}
+
+	l, err := parse(nil)
+	require.NoError(t, err)
+	require.Nil(t, l)
+
+	l, err = parse(map[string]string{lockUntilEpochHeader: "100"})
+	require.NoError(t, err)
+	require.Equal(t, &lockRequest{untilEpoch: 100}, l)
+
+	l, err = parse(map[string]string{lockDurationHeader: "24h"})
+	require.NoError(t, err)
+	require.Equal(t, &lockRequest{duration: 24 * time.Hour}, l)
+
+	for _, headers := range []map[string]string{
+		{lockUntilEpochHeader: "100", lockDurationHeader: "24h"},
+		{lockUntilEpochHeader: "-1"},
+		{lockDurationHeader: "day"},
+		{lockDurationHeader: "-1h"},
+	} {
+		_, err = parse(headers)
+		require.Error(t, err, headers)
+	}
+}
+
+func TestLockEpoch(t *testing.T) {
+	durations := &epochDurations{currentEpoch: 10, msPerBlock: 1000, blockPerEpoch: 60}
+
+	epoch, err := (&lockRequest{untilEpoch: 20}).epoch(durations)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, epoch)
+
+	_, err = (&lockRequest{untilEpoch: 10}).epoch(durations)
+	require.Error(t, err)
+
+	epoch, err = (&lockRequest{duration: time.Hour}).epoch(durations)
+	require.NoError(t, err)
+	require.EqualValues(t, 70, epoch)
+
+	epoch, err = (&lockRequest{duration: 90 * time.Second}).epoch(durations)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, epoch)
+}
@@ -2,8 +2,10 @@ package uploader
 
 import (
 	"io"
+	"mime"
 
 	"github.com/nspcc-dev/neofs-http-gw/uploader/multipart"
+	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
@@ -14,14 +16,50 @@ type MultipartFile interface {
 	FileName() string
 }
 
-func fetchMultipartFile(l *zap.Logger, r io.Reader, boundary string) (MultipartFile, error) {
+// pooledPart is the file part releasing the buffer of its reader on close.
+type pooledPart struct {
+	*multipart.Part
+	reader *multipart.Reader
+}
+
+func (f pooledPart) Close() error {
+	err := f.Part.Close()
+	f.reader.Release()
+	return err
+}
+
+// fileContentType returns Content-Type header of the multipart file part.
+func fileContentType(f MultipartFile) string {
+	if p, ok := f.(pooledPart); ok {
+		return p.Header.Get(fasthttp.HeaderContentType)
+	}
+	return ""
+}
+
+// partContentType returns Content-Type of the multipart file part to be stored
+// as the attribute. Generic application/octet-stream is ignored, so the type
+// is detected on download then.
+func partContentType(f MultipartFile) string {
+	contentType := fileContentType(f)
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil || media == "application/octet-stream" {
+		return ""
+	}
+	return contentType
+}
+
+// fetchMultipartFile returns the first file part of the multipart body, it's
+// read as a stream, part headers are limited by maxHeaderBytes if it's positive.
+func fetchMultipartFile(l *zap.Logger, r io.Reader, boundary string, maxHeaderBytes int) (MultipartFile, error) {
 	// To have a custom buffer (3mb) the custom multipart reader is used.
 	// https://github.com/nspcc-dev/neofs-http-gw/issues/148
 	reader := multipart.NewReader(r, boundary)
+	reader.SetMaxHeaderBytes(maxHeaderBytes)
 
 	for {
 		part, err := reader.NextPart()
 		if err != nil {
+			reader.Release()
 			return nil, err
 		}
 
@@ -40,6 +78,6 @@ func fetchMultipartFile(l *zap.Logger, r io.Reader, boundary string) (MultipartF
 			continue
 		}
 
-		return part, nil
+		return pooledPart{Part: part, reader: reader}, nil
 	}
 }
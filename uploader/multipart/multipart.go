@@ -15,22 +15,34 @@ package multipart
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"mime/quotedprintable"
 	"net/textproto"
 	"strings"
+	"sync"
 )
 
 var emptyParams = make(map[string]string)
 
+// ErrHeaderTooLarge is returned if part headers exceed the limit set with
+// SetMaxHeaderBytes.
+var ErrHeaderTooLarge = errors.New("multipart: part headers are too large")
+
 // This constant needs to be at least 76 for this package to work correctly.
 // This is because \r\n--separator_of_len_70- would fill the buffer and it
 // wouldn't be safe to consume a single byte from it.
 // This constant is different from the constant in stdlib. The standard value is 4096.
 const peekBufferSize = 3 << 20
 
+// bufReaders is the pool of peek buffers, they're too large to be allocated
+// for every request.
+var bufReaders = sync.Pool{New: func() interface{} {
+	return bufio.NewReaderSize(nil, peekBufferSize)
+}}
+
 // A Part represents a single part in a multipart body.
 type Part struct {
 	// The headers of the body, if any, with the keys canonicalized
@@ -94,8 +106,10 @@ func (p *Part) parseContentDisposition() {
 // parse such headers.
 func NewReader(r io.Reader, boundary string) *Reader {
 	b := []byte("\r\n--" + boundary + "--")
+	bufReader := bufReaders.Get().(*bufio.Reader)
+	bufReader.Reset(&stickyErrorReader{r: r})
 	return &Reader{
-		bufReader:        bufio.NewReaderSize(&stickyErrorReader{r: r}, peekBufferSize),
+		bufReader:        bufReader,
 		nl:               b[:2],
 		nlDashBoundary:   b[:len(b)-2],
 		dashBoundaryDash: b[2:],
@@ -143,6 +157,9 @@ func newPart(mr *Reader, rawPart bool) (*Part, error) {
 }
 
 func (p *Part) populateHeaders() error {
+	if limit := p.mr.maxHeaderBytes; limit > 0 && !headersWithin(p.mr.bufReader, limit) {
+		return ErrHeaderTooLarge
+	}
 	r := textproto.NewReader(p.mr.bufReader)
 	header, err := r.ReadMIMEHeader()
 	if err == nil {
@@ -151,6 +168,17 @@ func (p *Part) populateHeaders() error {
 	return err
 }
 
+// headersWithin reports whether the header section ends within the first n
+// bytes of the reader, n is the size of the peek buffer at most.
+func headersWithin(r *bufio.Reader, n int) bool {
+	if n > peekBufferSize {
+		n = peekBufferSize
+	}
+	buf, _ := r.Peek(n)
+	return bytes.HasPrefix(buf, []byte("\r\n")) || bytes.HasPrefix(buf, []byte("\n")) ||
+		bytes.Contains(buf, []byte("\n\r\n")) || bytes.Contains(buf, []byte("\n\n"))
+}
+
 // Read reads the body of a part, after its headers and before the
 // next part (if any) begins.
 func (p *Part) Read(d []byte) (n int, err error) {
@@ -288,8 +316,9 @@ func (p *Part) Close() error {
 type Reader struct {
 	bufReader *bufio.Reader
 
-	currentPart *Part
-	partsRead   int
+	currentPart    *Part
+	partsRead      int
+	maxHeaderBytes int
 
 	nl               []byte // "\r\n" or "\n" (set after seeing first boundary line)
 	nlDashBoundary   []byte // nl + "--boundary"
@@ -297,6 +326,23 @@ type Reader struct {
 	dashBoundary     []byte // "--boundary"
 }
 
+// SetMaxHeaderBytes limits the size of headers of every part, headers can't
+// exceed the size of the peek buffer anyway. Zero means no limit.
+func (r *Reader) SetMaxHeaderBytes(n int) {
+	r.maxHeaderBytes = n
+}
+
+// Release returns the buffer of the Reader to the pool, neither the Reader
+// nor its parts can be used after that.
+func (r *Reader) Release() {
+	if r.bufReader == nil {
+		return
+	}
+	r.bufReader.Reset(nil)
+	bufReaders.Put(r.bufReader)
+	r.bufReader = nil
+}
+
 // NextPart returns the next part in the multipart or an error.
 // When there are no more parts, the error io.EOF is returned.
 //
@@ -1,13 +1,17 @@
 package uploader
 
 import (
+	"bytes"
 	"crypto/rand"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/textproto"
 	"os"
+	"runtime"
 	"testing"
 
+	mp "github.com/nspcc-dev/neofs-http-gw/uploader/multipart"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -89,15 +93,69 @@ func customMultipart(filename string) error {
 		return err
 	}
 
-	file, err := fetchMultipartFile(logger, r, bound)
+	file, err := fetchMultipartFile(logger, r, bound, 0)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	_, err = io.Copy(io.Discard, file)
 	return err
 }
 
+func TestMultipartHeaderLimit(t *testing.T) {
+	var body bytes.Buffer
+	m := multipart.NewWriter(&body)
+	part, err := m.CreateFormFile("myFile", "foo.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("content"))
+	require.NoError(t, err)
+	require.NoError(t, m.Close())
+
+	file, err := fetchMultipartFile(zap.NewNop(), bytes.NewReader(body.Bytes()), m.Boundary(), 1024)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+	require.NoError(t, file.Close())
+
+	_, err = fetchMultipartFile(zap.NewNop(), bytes.NewReader(body.Bytes()), m.Boundary(), 16)
+	require.ErrorIs(t, err, mp.ErrHeaderTooLarge)
+}
+
+// BenchmarkConcurrentUploads parses multipart bodies of 1000 concurrent
+// uploads, parsers of "alloc" aren't released, so that every one allocates
+// a new peek buffer.
+func BenchmarkConcurrentUploads(b *testing.B) {
+	var body bytes.Buffer
+	m := multipart.NewWriter(&body)
+	part, err := m.CreateFormFile("myFile", "foo.txt")
+	require.NoError(b, err)
+	_, err = io.CopyN(part, rand.Reader, 64<<10)
+	require.NoError(b, err)
+	require.NoError(b, m.Close())
+
+	upload := func(b *testing.B, release bool) {
+		b.ReportAllocs()
+		b.SetBytes(int64(body.Len()))
+		b.SetParallelism(1000/runtime.GOMAXPROCS(0) + 1)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				file, err := fetchMultipartFile(zap.NewNop(), bytes.NewReader(body.Bytes()), m.Boundary(), 0)
+				require.NoError(b, err)
+				_, err = io.Copy(io.Discard, file)
+				require.NoError(b, err)
+				if release {
+					require.NoError(b, file.Close())
+				}
+			}
+		})
+	}
+
+	b.Run("alloc", func(b *testing.B) { upload(b, false) })
+	b.Run("pool", func(b *testing.B) { upload(b, true) })
+}
+
 func fetchMultipartFileDefault(l *zap.Logger, r io.Reader, boundary string) (MultipartFile, error) {
 	reader := multipart.NewReader(r, boundary)
 
@@ -159,3 +217,31 @@ func multipartFile(filename string) (*io.PipeReader, string) {
 
 	return r, m.Boundary()
 }
+
+func TestPartContentType(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		"image/png":                 "image/png",
+		"text/plain; charset=utf-8": "text/plain; charset=utf-8",
+		"application/octet-stream":  "",
+		"invalid type":              "",
+		"":                          "",
+	} {
+		var body bytes.Buffer
+		m := multipart.NewWriter(&body)
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="file"; filename="cat.png"`)
+		if contentType != "" {
+			h.Set("Content-Type", contentType)
+		}
+		part, err := m.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("content"))
+		require.NoError(t, err)
+		require.NoError(t, m.Close())
+
+		file, err := fetchMultipartFile(zap.NewNop(), bytes.NewReader(body.Bytes()), m.Boundary(), 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, partContentType(file), contentType)
+		require.NoError(t, file.Close())
+	}
+}
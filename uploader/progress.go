@@ -0,0 +1,220 @@
+package uploader
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	// progressWait is the time a subscriber waits for the upload to start.
+	progressWait = 30 * time.Second
+	// progressKeep is the time the state of finished uploads is kept for.
+	progressKeep = time.Minute
+)
+
+// Upload progress statuses.
+const (
+	progressWaiting   = "waiting"
+	progressReceiving = "receiving"
+	progressDone      = "done"
+	progressFailed    = "failed"
+)
+
+type (
+	// progress is the state of upload: the number of bytes received from the
+	// client and passed to NeoFS.
+	progress struct {
+		received int64
+		stored   int64
+		total    int64
+
+		mu       sync.Mutex
+		err      error
+		finished time.Time
+	}
+
+	// progressEvent is the data of progress event.
+	progressEvent struct {
+		Status   string `json:"status"`
+		Received int64  `json:"received"`
+		Stored   int64  `json:"stored"`
+		Total    int64  `json:"total,omitempty"`
+		Error    string `json:"error,omitempty"`
+	}
+
+	// progressTracker keeps the progress of uploads by client-supplied IDs.
+	progressTracker struct {
+		mu   sync.Mutex
+		list map[string]*progress
+	}
+
+	countingReader struct {
+		r io.Reader
+		n *int64
+	}
+)
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	atomic.AddInt64(r.n, int64(n))
+	return n, err
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{list: make(map[string]*progress)}
+}
+
+// start registers the upload with the ID and drops expired ones. Total is the
+// size of request body, it's not known if it's not positive.
+func (t *progressTracker) start(id string, total int64) *progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range t.list {
+		if finished, _ := v.result(); !finished.IsZero() && now.Sub(finished) > progressKeep {
+			delete(t.list, k)
+		}
+	}
+
+	res := &progress{total: total}
+	t.list[id] = res
+	return res
+}
+
+func (t *progressTracker) get(id string) *progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.list[id]
+}
+
+// receivedReader returns the reader counting bytes received from the client.
+func (p *progress) receivedReader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	return countingReader{r: r, n: &p.received}
+}
+
+// storedReader returns the reader counting bytes passed to NeoFS.
+func (p *progress) storedReader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	return countingReader{r: r, n: &p.stored}
+}
+
+// restartStored resets the number of bytes passed to NeoFS when the upload
+// is retried.
+func (p *progress) restartStored() {
+	if p == nil {
+		return
+	}
+	atomic.StoreInt64(&p.stored, 0)
+}
+
+// finish records the result of the upload.
+func (p *progress) finish(err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.err = err
+	p.finished = time.Now()
+	p.mu.Unlock()
+}
+
+func (p *progress) result() (time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.finished, p.err
+}
+
+func (p *progress) event() progressEvent {
+	ev := progressEvent{
+		Status:   progressReceiving,
+		Received: atomic.LoadInt64(&p.received),
+		Stored:   atomic.LoadInt64(&p.stored),
+		Total:    p.total,
+	}
+	if finished, err := p.result(); !finished.IsZero() {
+		ev.Status = progressDone
+		if err != nil {
+			ev.Status = progressFailed
+			ev.Error = err.Error()
+		}
+	}
+	return ev
+}
+
+// startProgress returns the progress of the upload if it's requested with
+// progress_id argument and enabled.
+func (u *Uploader) startProgress(c *fasthttp.RequestCtx) *progress {
+	id := string(c.QueryArgs().Peek("progress_id"))
+	if u.progress == nil || id == "" {
+		return nil
+	}
+	return u.progress.start(id, int64(c.Request.Header.ContentLength()))
+}
+
+// Progress handles requests for the progress of upload with the ID given by
+// the client, it's sent as Server-Sent Events until the upload is finished.
+func (u *Uploader) Progress(c *fasthttp.RequestCtx) {
+	if u.progress == nil {
+		response.Error(c, "upload progress is disabled", fasthttp.StatusNotFound)
+		return
+	}
+
+	id, _ := c.UserValue("id").(string)
+	log := u.log.With(zap.String("progress_id", id))
+
+	c.Response.Header.SetContentType("text/event-stream")
+	c.Response.Header.Set(fasthttp.HeaderCacheControl, "no-cache")
+	utils.SetBodyStreamWriter(c, func(w *bufio.Writer) {
+		ticker := time.NewTicker(u.settings.ProgressInterval)
+		defer ticker.Stop()
+
+		waitUntil := time.Now().Add(progressWait)
+		for {
+			var ev progressEvent
+			if p := u.progress.get(id); p != nil {
+				ev = p.event()
+			} else if time.Now().Before(waitUntil) {
+				ev.Status = progressWaiting
+			} else {
+				ev = progressEvent{Status: progressFailed, Error: "upload not found"}
+			}
+
+			data, err := json.Marshal(ev)
+			if err != nil {
+				log.Error("could not encode progress", zap.Error(err))
+				return
+			}
+			if _, err = w.WriteString("data: " + string(data) + "\n\n"); err == nil {
+				err = w.Flush()
+			}
+			if err != nil {
+				log.Debug("progress subscriber is gone", zap.Error(err))
+				return
+			}
+			if ev.Status == progressDone || ev.Status == progressFailed {
+				return
+			}
+
+			select {
+			case <-u.appCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package uploader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	tracker := newProgressTracker()
+	require.Nil(t, tracker.get("id"))
+
+	p := tracker.start("id", 100)
+	require.Equal(t, p, tracker.get("id"))
+	require.Equal(t, progressEvent{Status: progressReceiving, Total: 100}, p.event())
+
+	_, err := io.ReadAll(p.storedReader(p.receivedReader(strings.NewReader("payload"))))
+	require.NoError(t, err)
+	require.Equal(t, progressEvent{Status: progressReceiving, Received: 7, Stored: 7, Total: 100}, p.event())
+
+	p.finish(nil)
+	require.Equal(t, progressDone, p.event().Status)
+
+	p = tracker.start("id", 0)
+	p.finish(errors.New("could not store"))
+	require.Equal(t, progressEvent{Status: progressFailed, Error: "could not store"}, p.event())
+
+	t.Run("disabled", func(t *testing.T) {
+		var p *progress
+
+		r := strings.NewReader("payload")
+		require.Equal(t, r, p.receivedReader(r))
+		require.Equal(t, r, p.storedReader(r))
+		p.finish(nil)
+	})
+}
@@ -0,0 +1,315 @@
+package uploader
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Antivirus engines.
+const (
+	ScanEngineClamAV = "clamav"
+	ScanEngineICAP   = "icap"
+)
+
+// clamdChunkSize is the size of INSTREAM chunks, it's less than the default
+// StreamMaxLength of clamd.
+const clamdChunkSize = 64 << 10
+
+// Scanner checks payloads for malware.
+type Scanner interface {
+	// Scan returns the name of the threat found in the payload of the given
+	// size, it's empty if the payload is clean.
+	Scan(ctx context.Context, payload io.Reader, size int64) (string, error)
+}
+
+// ScanSettings are the settings of upload scanning.
+type ScanSettings struct {
+	// Scanner checks uploads, nil disables scanning.
+	Scanner Scanner
+	// FailOpen makes uploads stored if the scanner fails, otherwise they're
+	// rejected.
+	FailOpen bool
+	// Containers limits scanning to the containers (by ID or by name as it's
+	// specified in request path) with their fail-open policies, all uploads
+	// are scanned if it's empty.
+	Containers map[string]bool
+}
+
+// NewScanner creates the scanner of the engine at the address: tcp://host:port
+// or unix:///path/to/socket of clamd, icap://host:port/service of ICAP server.
+func NewScanner(engine, address string, timeout time.Duration) (Scanner, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scanner address: %w", err)
+	}
+
+	switch engine {
+	case ScanEngineClamAV:
+		switch u.Scheme {
+		case "tcp":
+			return &clamdScanner{network: "tcp", address: u.Host, timeout: timeout}, nil
+		case "unix":
+			return &clamdScanner{network: "unix", address: u.Path, timeout: timeout}, nil
+		}
+		return nil, fmt.Errorf("clamd address must be tcp:// or unix:// URL, got %q", address)
+	case ScanEngineICAP:
+		if u.Scheme != "icap" || u.Host == "" {
+			return nil, fmt.Errorf("ICAP address must be icap:// URL, got %q", address)
+		}
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "1344")
+		}
+		return &icapScanner{address: host, service: u.String(), timeout: timeout}, nil
+	}
+	return nil, fmt.Errorf("unknown antivirus engine %q", engine)
+}
+
+// clamdScanner sends payloads to clamd with INSTREAM command.
+type clamdScanner struct {
+	network, address string
+	timeout          time.Duration
+}
+
+func dial(ctx context.Context, network, address string, timeout time.Duration) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+	return conn, nil
+}
+
+func (s *clamdScanner) Scan(ctx context.Context, payload io.Reader, _ int64) (string, error) {
+	conn, err := dial(ctx, s.network, s.address, s.timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	w := bufio.NewWriter(conn)
+	if _, err = w.WriteString("zINSTREAM\x00"); err != nil {
+		return "", err
+	}
+
+	var (
+		buf  = make([]byte, clamdChunkSize)
+		size [4]byte
+	)
+	for {
+		n, rerr := payload.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err = w.Write(size[:]); err == nil {
+				_, err = w.Write(buf[:n])
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return "", rerr
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err = w.Write(size[:]); err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(err == io.EOF && reply != "") {
+		return "", err
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply parses "stream: OK", "stream: <threat> FOUND" and
+// "<message> ERROR" replies.
+func parseClamdReply(reply string) (string, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return "", nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return strings.TrimSuffix(reply, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamd: %s", reply)
+	}
+}
+
+// icapScanner sends payloads to ICAP server as RESPMOD requests.
+type icapScanner struct {
+	address string
+	service string
+	timeout time.Duration
+}
+
+// icapThreatHeaders are the headers ICAP servers report found threats in.
+var icapThreatHeaders = []string{"X-Infection-Found", "X-Violations-Found", "X-Virus-Id", "X-Virus-Name"}
+
+func (s *icapScanner) Scan(ctx context.Context, payload io.Reader, size int64) (string, error) {
+	conn, err := dial(ctx, "tcp", s.address, s.timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	httpHeader := "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\nContent-Length: " +
+		strconv.FormatInt(size, 10) + "\r\n\r\n"
+
+	w := bufio.NewWriter(conn)
+	_, err = fmt.Fprintf(w, "RESPMOD %s ICAP/1.0\r\nHost: %s\r\nAllow: 204\r\nEncapsulated: res-hdr=0, res-body=%d\r\n\r\n%s",
+		s.service, s.address, len(httpHeader), httpHeader)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	for {
+		n, rerr := payload.Read(buf)
+		if n > 0 {
+			if _, err = fmt.Fprintf(w, "%x\r\n%s\r\n", n, buf[:n]); err != nil {
+				return "", err
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return "", rerr
+		}
+	}
+	if _, err = w.WriteString("0\r\n\r\n"); err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return readICAPReply(bufio.NewReader(conn))
+}
+
+// readICAPReply reads the status and the headers of ICAP reply: 204 means the
+// payload is clean, 200 means it's modified because of a threat.
+func readICAPReply(r *bufio.Reader) (string, error) {
+	status, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(status)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return "", fmt.Errorf("invalid ICAP reply %q", strings.TrimSpace(status))
+	}
+
+	headers := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if i := strings.IndexByte(line, ':'); i > 0 {
+			headers[strings.ToLower(strings.TrimSpace(line[:i]))] = strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	switch fields[1] {
+	case "204":
+		return "", nil
+	case "200":
+		for _, h := range icapThreatHeaders {
+			if v, ok := headers[strings.ToLower(h)]; ok {
+				return icapThreat(v), nil
+			}
+		}
+		return "unknown threat", nil
+	default:
+		return "", fmt.Errorf("ICAP server replied %s", strings.TrimSpace(status))
+	}
+}
+
+// icapThreat extracts the threat name from "Type=0; Resolution=2;
+// Threat=EICAR;" header value, the value is returned as is if it's not in
+// this format.
+func icapThreat(v string) string {
+	for _, part := range strings.Split(v, ";") {
+		if kv := strings.SplitN(strings.TrimSpace(part), "=", 2); len(kv) == 2 && kv[0] == "Threat" {
+			return kv[1]
+		}
+	}
+	return v
+}
+
+var errInfected = errors.New("infected content")
+
+// Policy returns the fail-open policy of the container specified by name or
+// ID in request path, false is returned if uploads to it aren't scanned.
+func (s ScanSettings) Policy(scid string, cnrID *cid.ID) (bool, bool) {
+	if s.Scanner == nil {
+		return false, false
+	}
+	if len(s.Containers) == 0 {
+		return s.FailOpen, true
+	}
+	if failOpen, ok := s.Containers[scid]; ok {
+		return failOpen, true
+	}
+	failOpen, ok := s.Containers[cnrID.String()]
+	return failOpen, ok
+}
+
+// scan sends the spooled payload to the scanner. It replies with 422 if a
+// threat is found, scanner failures are ignored for fail-open containers.
+// False is returned if the payload isn't to be stored, the response is an
+// error then, neither scanner errors nor threat names are sent to the client.
+func (u *Uploader) scan(c *fasthttp.RequestCtx, log *zap.Logger, spooled *spooledPayload, pr *progress, failOpen bool) bool {
+	threat, err := u.settings.Scan.Scanner.Scan(c, spooled, spooled.size)
+	if err == nil {
+		_, err = spooled.Seek(0, io.SeekStart)
+	}
+	switch {
+	case err != nil && failOpen:
+		log.Warn("could not scan payload, it's stored unchecked", zap.Error(err))
+		if _, err = spooled.Seek(0, io.SeekStart); err == nil {
+			return true
+		}
+		fallthrough
+	case err != nil:
+		pr.finish(err)
+		log.Error("could not scan payload", zap.Error(err))
+		response.Error(c, "could not scan payload", fasthttp.StatusServiceUnavailable)
+		return false
+	case threat != "":
+		pr.finish(errInfected)
+		log.Warn("infected upload is rejected", zap.String("threat", threat))
+		response.ErrorCode(c, response.CodeInfected, "infected content", fasthttp.StatusUnprocessableEntity)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package uploader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/require"
+)
+
+// serveOnce accepts one connection and handles it in background.
+func serveOnce(t *testing.T, handle func(net.Conn)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+	return l.Addr().String()
+}
+
+// fakeClamd reads INSTREAM payload and replies FOUND if it contains "EICAR".
+func fakeClamd(t *testing.T, received *bytes.Buffer) string {
+	return serveOnce(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		cmd, err := r.ReadString(0)
+		if err != nil || cmd != "zINSTREAM\x00" {
+			_, _ = conn.Write([]byte("UNKNOWN COMMAND ERROR\x00"))
+			return
+		}
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(received, r, int64(size)); err != nil {
+				return
+			}
+		}
+		reply := "stream: OK\x00"
+		if strings.Contains(received.String(), "EICAR") {
+			reply = "stream: Eicar-Test-Signature FOUND\x00"
+		}
+		_, _ = conn.Write([]byte(reply))
+	})
+}
+
+func TestClamdScanner(t *testing.T) {
+	for _, tc := range []struct {
+		payload string
+		threat  string
+	}{
+		{payload: strings.Repeat("clean ", clamdChunkSize/3)},
+		{payload: "X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*", threat: "Eicar-Test-Signature"},
+	} {
+		var received bytes.Buffer
+		s, err := NewScanner(ScanEngineClamAV, "tcp://"+fakeClamd(t, &received), time.Second)
+		require.NoError(t, err)
+
+		threat, err := s.Scan(context.Background(), strings.NewReader(tc.payload), int64(len(tc.payload)))
+		require.NoError(t, err)
+		require.Equal(t, tc.threat, threat)
+		require.Equal(t, tc.payload, received.String())
+	}
+}
+
+func TestParseClamdReply(t *testing.T) {
+	threat, err := parseClamdReply("stream: OK")
+	require.NoError(t, err)
+	require.Empty(t, threat)
+
+	threat, err = parseClamdReply("stream: Win.Test.EICAR_HDB-1 FOUND")
+	require.NoError(t, err)
+	require.Equal(t, "Win.Test.EICAR_HDB-1", threat)
+
+	_, err = parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	require.Error(t, err)
+}
+
+func TestICAPScanner(t *testing.T) {
+	for _, tc := range []struct {
+		reply  string
+		threat string
+		err    bool
+	}{
+		{reply: "ICAP/1.0 204 No Content\r\nISTag: \"1\"\r\n\r\n"},
+		{reply: "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=EICAR;\r\n\r\n", threat: "EICAR"},
+		{reply: "ICAP/1.0 200 OK\r\nX-Virus-ID: Eicar\r\n\r\n", threat: "Eicar"},
+		{reply: "ICAP/1.0 500 Server Error\r\n\r\n", err: true},
+	} {
+		var request bytes.Buffer
+		addr := serveOnce(t, func(conn net.Conn) {
+			r := bufio.NewReader(conn)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				request.WriteString(line)
+				if line == "0\r\n" {
+					_, _ = r.ReadString('\n')
+					break
+				}
+			}
+			_, _ = conn.Write([]byte(tc.reply))
+		})
+
+		s, err := NewScanner(ScanEngineICAP, "icap://"+addr+"/avscan", time.Second)
+		require.NoError(t, err)
+
+		threat, err := s.Scan(context.Background(), strings.NewReader("payload"), 7)
+		if tc.err {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.threat, threat)
+		require.True(t, strings.HasPrefix(request.String(), "RESPMOD icap://"+addr+"/avscan ICAP/1.0\r\n"))
+		require.Contains(t, request.String(), "Content-Length: 7\r\n\r\n7\r\npayload\r\n")
+	}
+}
+
+func TestNewScanner(t *testing.T) {
+	_, err := NewScanner(ScanEngineClamAV, "unix:///var/run/clamd.sock", 0)
+	require.NoError(t, err)
+	_, err = NewScanner(ScanEngineClamAV, "icap://localhost/avscan", 0)
+	require.Error(t, err)
+	_, err = NewScanner(ScanEngineICAP, "tcp://localhost:1344", 0)
+	require.Error(t, err)
+	_, err = NewScanner("unknown", "tcp://localhost:3310", 0)
+	require.Error(t, err)
+}
+
+func TestScanSettingsPolicy(t *testing.T) {
+	var cnrID cid.ID
+	cnrID.SetSHA256([32]byte{1})
+
+	_, ok := ScanSettings{}.Policy("uploads", &cnrID)
+	require.False(t, ok)
+
+	s := ScanSettings{Scanner: brokenScanner{}, FailOpen: true}
+	failOpen, ok := s.Policy("uploads", &cnrID)
+	require.True(t, ok)
+	require.True(t, failOpen)
+
+	s.Containers = map[string]bool{"uploads": false, cnrID.String(): true}
+	failOpen, ok = s.Policy("uploads", &cnrID)
+	require.True(t, ok)
+	require.False(t, failOpen)
+	failOpen, ok = s.Policy(cnrID.String(), &cnrID)
+	require.True(t, ok)
+	require.True(t, failOpen)
+
+	var other cid.ID
+	other.SetSHA256([32]byte{2})
+	_, ok = s.Policy("other", &other)
+	require.False(t, ok)
+}
+
+// brokenScanner fails every scan.
+type brokenScanner struct{}
+
+func (brokenScanner) Scan(context.Context, io.Reader, int64) (string, error) {
+	return "", io.ErrUnexpectedEOF
+}
@@ -0,0 +1,63 @@
+package uploader
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// spooledPayload is the payload copied to the temporary file to be checked
+// before it's stored.
+type spooledPayload struct {
+	*os.File
+	log  *zap.Logger
+	size int64
+	sum  [sha256.Size]byte
+}
+
+func (p *spooledPayload) remove() {
+	_ = p.Close()
+	if err := os.Remove(p.Name()); err != nil {
+		p.log.Warn("could not remove spool file", zap.String("file", p.Name()), zap.Error(err))
+	}
+}
+
+// spool copies the payload to the temporary file computing its SHA-256 hash.
+// The response is an error if the payload can't be spooled, false is
+// returned then.
+func (u *Uploader) spool(c *fasthttp.RequestCtx, log *zap.Logger, payload *sizeLimitedReader, pr *progress,
+	scid string) (*spooledPayload, bool) {
+	file, err := os.CreateTemp(u.settings.SpoolDir, "spool-")
+	if err != nil {
+		pr.finish(err)
+		log.Error("could not create spool file", zap.Error(err))
+		response.Error(c, "could not create spool file", fasthttp.StatusInternalServerError)
+		return nil, false
+	}
+	res := &spooledPayload{File: file, log: log}
+
+	h := sha256.New()
+	if res.size, err = io.Copy(io.MultiWriter(file, h), payload); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		pr.finish(err)
+		res.remove()
+		if payload.exceeded {
+			log.Error("object is too large", zap.Int64("limit", payload.limit))
+			response.Error(c, "object size exceeds the limit of "+strconv.FormatInt(payload.limit, 10)+
+				" bytes for container "+scid, fasthttp.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		log.Error("could not spool file", zap.Error(err))
+		response.Error(c, "could not spool file: "+err.Error(), fasthttp.StatusBadRequest)
+		return nil, false
+	}
+	h.Sum(res.sum[:0])
+	return res, true
+}
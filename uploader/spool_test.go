@@ -0,0 +1,48 @@
+package uploader
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestSpool(t *testing.T) {
+	u := &Uploader{settings: Settings{SpoolDir: t.TempDir()}}
+
+	t.Run("payload", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		payload := &sizeLimitedReader{r: strings.NewReader("content"), limit: -1}
+
+		spooled, ok := u.spool(&c, zap.NewNop(), payload, nil, "cnr")
+		require.True(t, ok)
+		require.EqualValues(t, len("content"), spooled.size)
+		require.Equal(t, sha256.Sum256([]byte("content")), spooled.sum)
+
+		data, err := io.ReadAll(spooled)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(data))
+
+		spooled.remove()
+		_, err = os.Stat(spooled.Name())
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		var c fasthttp.RequestCtx
+		payload := &sizeLimitedReader{r: strings.NewReader("content"), limit: 3}
+
+		_, ok := u.spool(&c, zap.NewNop(), payload, nil, "cnr")
+		require.False(t, ok)
+		require.Equal(t, fasthttp.StatusRequestEntityTooLarge, c.Response.StatusCode())
+
+		entries, err := os.ReadDir(u.settings.SpoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
@@ -0,0 +1,284 @@
+package uploader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	v2object "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/storagegroup"
+	"github.com/nspcc-dev/tzhash/tz"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// maxStorageGroupMembers is the maximum number of objects in storage group.
+const maxStorageGroupMembers = 1000
+
+// storageGroupRequest is the body of storage group request: object IDs of the
+// members and the last epoch or the lifetime of the group.
+type storageGroupRequest struct {
+	Members         []string `json:"members"`
+	ExpirationEpoch uint64   `json:"expiration_epoch"`
+	Lifetime        string   `json:"lifetime"`
+
+	members  []oid.ID
+	lifetime time.Duration
+}
+
+// parseStorageGroupRequest decodes and validates storage group request body.
+func parseStorageGroupRequest(data []byte) (*storageGroupRequest, error) {
+	var req storageGroupRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	switch n := len(req.Members); {
+	case n == 0:
+		return nil, errors.New("no members of storage group")
+	case n > maxStorageGroupMembers:
+		return nil, fmt.Errorf("number of members must not exceed %d", maxStorageGroupMembers)
+	}
+
+	seen := make(map[string]struct{}, len(req.Members))
+	for _, s := range req.Members {
+		var id oid.ID
+		if err := id.DecodeString(s); err != nil {
+			return nil, fmt.Errorf("wrong object id %s: %w", s, err)
+		}
+		if _, ok := seen[s]; ok {
+			return nil, fmt.Errorf("object %s is listed several times", s)
+		}
+		seen[s] = struct{}{}
+		req.members = append(req.members, id)
+	}
+
+	switch {
+	case req.ExpirationEpoch != 0 && req.Lifetime != "":
+		return nil, errors.New("only one of expiration_epoch and lifetime can be set")
+	case req.ExpirationEpoch == 0 && req.Lifetime == "":
+		return nil, errors.New("expiration_epoch or lifetime must be set")
+	case req.Lifetime != "":
+		lifetime, err := time.ParseDuration(req.Lifetime)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse lifetime %s", req.Lifetime)
+		}
+		if lifetime <= 0 {
+			return nil, fmt.Errorf("lifetime %s must be positive", req.Lifetime)
+		}
+		req.lifetime = lifetime
+	}
+
+	return &req, nil
+}
+
+// expiration returns the last epoch of the storage group.
+func (r *storageGroupRequest) expiration(durations *epochDurations) (uint64, error) {
+	if r.lifetime != 0 {
+		return durations.epochAfter(r.lifetime), nil
+	}
+	if r.ExpirationEpoch <= durations.currentEpoch {
+		return 0, fmt.Errorf("expiration epoch %d must be greater than the current epoch %d",
+			r.ExpirationEpoch, durations.currentEpoch)
+	}
+	return r.ExpirationEpoch, nil
+}
+
+// storageGroupMember is the size and homomorphic hash of the member payload.
+type storageGroupMember struct {
+	size uint64
+	hash []byte
+}
+
+// newStorageGroup returns the storage group of the members with the total
+// size and the concatenation of homomorphic hashes of their payloads.
+func newStorageGroup(ids []oid.ID, members []storageGroupMember, expiration uint64) (*storagegroup.StorageGroup, error) {
+	var (
+		size   uint64
+		hashes = make([][]byte, 0, len(members))
+	)
+	for _, m := range members {
+		size += m.size
+		hashes = append(hashes, m.hash)
+	}
+
+	sum, err := tz.Concat(hashes)
+	if err != nil {
+		return nil, fmt.Errorf("could not concatenate homomorphic hashes: %w", err)
+	}
+	var hash [tz.Size]byte
+	copy(hash[:], sum)
+
+	var cs checksum.Checksum
+	cs.SetTillichZemor(hash)
+
+	var sg storagegroup.StorageGroup
+	sg.SetMembers(ids)
+	sg.SetValidationDataSize(size)
+	sg.SetValidationDataHash(cs)
+	sg.SetExpirationEpoch(expiration)
+	return &sg, nil
+}
+
+// StorageGroup handles requests creating the storage group of the objects in
+// the container for data audit. The group expires after the epoch given in
+// the request or after its lifetime, attributes set with X-Attribute-*
+// headers are added to the storage group object.
+func (u *Uploader) StorageGroup(c *fasthttp.RequestCtx) {
+	var (
+		scid, _    = c.UserValue("cid").(string)
+		log        = u.log.With(zap.String("cid", scid))
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
+	)
+
+	req, err := parseStorageGroupRequest(c.Request.Body())
+	if err != nil {
+		log.Error("could not parse request", zap.Error(err))
+		response.Error(c, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch bearer token", zap.Error(err))
+		response.Error(c, "could not fetch bearer token", fasthttp.StatusBadRequest)
+		return
+	}
+
+	idCnr, err := utils.GetContainerID(ctx, scid, u.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return
+	}
+
+	filtered, ok := u.headerAttributes(c, log, clientPool)
+	if !ok {
+		return
+	}
+
+	durations, err := u.getEpochDurations(c, clientPool)
+	if err != nil {
+		log.Error("could not get epoch durations from network info", zap.Error(err))
+		response.Error(c, "could not get epoch durations from network info: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	expiration, err := req.expiration(durations)
+	if err != nil {
+		log.Error("invalid expiration", zap.Error(err))
+		response.Error(c, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	id, bt := fetchOwnerAndBearerToken(c, clientPool)
+	key := utils.RequestKey(c)
+
+	members := make([]storageGroupMember, 0, len(req.members))
+	for _, objID := range req.members {
+		addr := address.NewAddress()
+		addr.SetContainerID(*idCnr)
+		addr.SetObjectID(objID)
+
+		var prm pool.PrmObjectHead
+		prm.SetAddress(*addr)
+		if bt != nil {
+			prm.UseBearer(*bt)
+		}
+		if key != nil {
+			prm.UseKey(key)
+		}
+
+		var header *object.Object
+		err = u.retry.Do(ctx, func() error {
+			var err error
+			header, err = clientPool.HeadObject(ctx, prm)
+			return err
+		})
+		if err != nil {
+			log.Error("could not get member header", zap.Stringer("oid", &objID), zap.Error(err))
+			response.Error(c, "could not get header of object "+objID.String()+": "+err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		hash, ok := header.PayloadHomomorphicHash()
+		if !ok {
+			log.Error("member has no homomorphic hash", zap.Stringer("oid", &objID))
+			response.Error(c, "object "+objID.String()+" has no homomorphic hash", fasthttp.StatusBadRequest)
+			return
+		}
+		members = append(members, storageGroupMember{size: header.PayloadSize(), hash: hash.Value()})
+	}
+
+	sg, err := newStorageGroup(req.members, members, expiration)
+	if err != nil {
+		log.Error("could not create storage group", zap.Error(err))
+		response.Error(c, "could not create storage group: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	payload, err := sg.Marshal()
+	if err != nil {
+		log.Error("could not encode storage group", zap.Error(err))
+		response.Error(c, "could not encode storage group: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	attributes := make([]object.Attribute, 0, len(filtered)+1)
+	for key, val := range filtered {
+		attribute := object.NewAttribute()
+		attribute.SetKey(key)
+		attribute.SetValue(val)
+		attributes = append(attributes, *attribute)
+	}
+	if _, ok := filtered[v2object.SysAttributeExpEpoch]; !ok {
+		attribute := object.NewAttribute()
+		attribute.SetKey(v2object.SysAttributeExpEpoch)
+		attribute.SetValue(strconv.FormatUint(expiration, 10))
+		attributes = append(attributes, *attribute)
+	}
+
+	obj := object.New()
+	obj.SetContainerID(*idCnr)
+	obj.SetOwnerID(id)
+	obj.SetType(object.TypeStorageGroup)
+	obj.SetAttributes(attributes...)
+
+	var prm pool.PrmObjectPut
+	prm.SetHeader(*obj)
+	if bt != nil {
+		prm.UseBearer(*bt)
+	}
+	if key != nil {
+		prm.UseKey(key)
+	}
+
+	var idObj *oid.ID
+	err = u.retry.Do(ctx, func() error {
+		prm.SetPayload(bytes.NewReader(payload))
+
+		var err error
+		idObj, err = clientPool.PutObject(ctx, prm)
+		return err
+	})
+	if err != nil {
+		log.Error("could not store storage group in neofs", zap.Error(err))
+		response.Error(c, "could not store storage group in neofs: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	addr := address.NewAddress()
+	addr.SetContainerID(*idCnr)
+	addr.SetObjectID(*idObj)
+	u.notify(utils.EventObjectCreated, idCnr, idObj, int64(len(payload)), attributes)
+	utils.AddRequestObject(c, idObj.String())
+	u.replyPut(c, log, newPutResponse(addr))
+}
@@ -0,0 +1,74 @@
+package uploader
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/tzhash/tz"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testObjectID1 = "9ANhbry2ryjJY1NZbcjryJMRXG5uGNKd73kD3V1sVFsX"
+	testObjectID2 = "2m8PtaoricLouCn5zE8hAFr3gZEBDCZFe9BEgVJTSocY"
+)
+
+func TestParseStorageGroupRequest(t *testing.T) {
+	req, err := parseStorageGroupRequest([]byte(`{"members":["` + testObjectID1 + `","` + testObjectID2 + `"],"lifetime":"24h"}`))
+	require.NoError(t, err)
+	require.Len(t, req.members, 2)
+	require.Equal(t, 24*time.Hour, req.lifetime)
+
+	req, err = parseStorageGroupRequest([]byte(`{"members":["` + testObjectID1 + `"],"expiration_epoch":100}`))
+	require.NoError(t, err)
+	require.EqualValues(t, 100, req.ExpirationEpoch)
+
+	tooMany := `"` + strings.TrimSuffix(strings.Repeat(testObjectID1+`","`, maxStorageGroupMembers+1), `","`) + `"`
+	for _, body := range []string{
+		`{`,
+		`{"members":[],"lifetime":"24h"}`,
+		`{"members":[` + tooMany + `],"lifetime":"24h"}`,
+		`{"members":["` + testObjectID1 + `","` + testObjectID1 + `"],"lifetime":"24h"}`,
+		`{"members":["` + testObjectID1 + `"]}`,
+		`{"members":["` + testObjectID1 + `"],"lifetime":"24h","expiration_epoch":100}`,
+		`{"members":["` + testObjectID1 + `"],"lifetime":"day"}`,
+		`{"members":["` + testObjectID1 + `"],"lifetime":"-1h"}`,
+	} {
+		_, err = parseStorageGroupRequest([]byte(body))
+		require.Error(t, err, body)
+	}
+}
+
+func TestStorageGroupExpiration(t *testing.T) {
+	durations := &epochDurations{currentEpoch: 10, msPerBlock: 1000, blockPerEpoch: 60}
+
+	epoch, err := (&storageGroupRequest{lifetime: time.Hour}).expiration(durations)
+	require.NoError(t, err)
+	require.EqualValues(t, 70, epoch)
+
+	epoch, err = (&storageGroupRequest{ExpirationEpoch: 20}).expiration(durations)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, epoch)
+
+	_, err = (&storageGroupRequest{ExpirationEpoch: 10}).expiration(durations)
+	require.Error(t, err)
+}
+
+func TestNewStorageGroup(t *testing.T) {
+	ids := make([]oid.ID, 2)
+	h1, h2 := tz.Sum([]byte("first")), tz.Sum([]byte("second"))
+
+	sg, err := newStorageGroup(ids, []storageGroupMember{
+		{size: 5, hash: h1[:]},
+		{size: 6, hash: h2[:]},
+	}, 100)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, sg.ValidationDataSize())
+	require.EqualValues(t, 100, sg.ExpirationEpoch())
+	require.Len(t, sg.Members(), 2)
+
+	_, err = newStorageGroup(ids, []storageGroupMember{{size: 5, hash: []byte("invalid")}}, 100)
+	require.Error(t, err)
+}
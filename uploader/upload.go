@@ -1,12 +1,15 @@
 package uploader
 
 import (
+	"bytes"
 	"context"
-	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nspcc-dev/neofs-http-gw/resolver"
@@ -14,7 +17,7 @@ import (
 	"github.com/nspcc-dev/neofs-http-gw/tokens"
 	"github.com/nspcc-dev/neofs-http-gw/utils"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
-	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	"github.com/nspcc-dev/neofs-sdk-go/object/address"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
@@ -27,15 +30,73 @@ import (
 const (
 	jsonHeader   = "application/json; charset=UTF-8"
 	drainBufSize = 4096
+
+	attributeFilePath = "FilePath"
 )
 
+// drainBuffers is the pool of buffers to drain the rest of request bodies.
+var drainBuffers = utils.NewBufferPool(drainBufSize)
+
 // Uploader is an upload request handler.
 type Uploader struct {
-	appCtx                 context.Context
-	log                    *zap.Logger
-	pool                   *pool.Pool
-	enableDefaultTimestamp bool
-	containerResolver      *resolver.ContainerResolver
+	appCtx            context.Context
+	log               *zap.Logger
+	pool              *utils.PoolHolder
+	settings          Settings
+	containerResolver *resolver.ContainerResolver
+	jobs              *jobs
+	progress          *progressTracker
+	notifier          utils.EventNotifier
+	retry             utils.RetryPolicy
+	epochs            *utils.EpochTracker
+	attributeLimits   utils.AttributeLimits
+}
+
+// Settings are upload parameters.
+type Settings struct {
+	// DefaultTimestamp enables Timestamp attribute for objects uploaded
+	// without it.
+	DefaultTimestamp bool
+	// PartContentType enables Content-Type attribute from multipart part
+	// header for objects uploaded without it.
+	PartContentType bool
+	// MaxObjectSize limits the size of uploaded objects per container (by ID
+	// or by name as it's specified in request path).
+	MaxObjectSize map[string]int64
+	// AsyncUploads enables uploads with async=true argument which are put to
+	// NeoFS in background.
+	AsyncUploads bool
+	// SpoolDir is the directory for payloads of asynchronous uploads, the
+	// default directory for temporary files is used if it's empty.
+	SpoolDir string
+	// JobTTL is the time the results of asynchronous uploads are kept for.
+	JobTTL time.Duration
+	// Progress enables the progress of uploads with progress_id argument.
+	Progress bool
+	// ProgressInterval is the interval of progress events.
+	ProgressInterval time.Duration
+	// MaxMemory limits the size of multipart part headers kept in memory,
+	// zero means no limit.
+	MaxMemory int
+	// Scan are the settings of antivirus checks of uploads.
+	Scan ScanSettings
+	// Deduplicate enables the search for the object with the same payload
+	// in the container, it's returned instead of storing the upload.
+	Deduplicate bool
+	// ContainerCreation, ContainerDeletion and ContainerEACL are the times to
+	// wait for the created container to be persisted, for the deleted one to
+	// be removed and for the extended ACL to be applied.
+	ContainerCreation ContainerWait
+	ContainerDeletion ContainerWait
+	ContainerEACL     ContainerWait
+	// DuplicateAttributes is the policy of resolving conflicting values of
+	// the same attribute, see Duplicates* constants.
+	DuplicateAttributes string
+	// AllowedTypes limits content types and file extensions of uploads.
+	AllowedTypes TypeFilter
+	// ContainerAllowedTypes override AllowedTypes for the containers (by ID
+	// or by name as it's specified in request path).
+	ContainerAllowedTypes map[string]TypeFilter
 }
 
 type epochDurations struct {
@@ -46,14 +107,28 @@ type epochDurations struct {
 
 // New creates a new Uploader using specified logger, connection pool and
 // other options.
-func New(ctx context.Context, params *utils.AppParams, enableDefaultTimestamp bool) *Uploader {
-	return &Uploader{
-		appCtx:                 ctx,
-		log:                    params.Logger,
-		pool:                   params.Pool,
-		enableDefaultTimestamp: enableDefaultTimestamp,
-		containerResolver:      params.Resolver,
+func New(ctx context.Context, params *utils.AppParams, settings Settings) *Uploader {
+	u := &Uploader{
+		appCtx:            ctx,
+		log:               params.Logger,
+		pool:              params.Pool,
+		settings:          settings,
+		containerResolver: params.Resolver,
+		notifier:          params.Notifier,
+		retry:             params.Retry,
+		epochs:            params.Epochs,
+		attributeLimits:   params.AttributeLimits,
 	}
+	if settings.DuplicateAttributes == "" {
+		u.settings.DuplicateAttributes = DuplicatesFirst
+	}
+	if settings.AsyncUploads {
+		u.jobs = newJobs(settings.JobTTL)
+	}
+	if settings.Progress {
+		u.progress = newProgressTracker()
+	}
+	return u
 }
 
 // Upload handles multipart upload request.
@@ -64,9 +139,16 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 		addr       = address.NewAddress()
 		scid, _    = c.UserValue("cid").(string)
 		log        = u.log.With(zap.String("cid", scid))
+		ctx        = utils.RequestContext(c, u.appCtx)
+		clientPool = u.pool.Acquire(c)
 		bodyStream = c.RequestBodyStream()
-		drainBuf   = make([]byte, drainBufSize)
+		drainBuf   = drainBuffers.Get()
 	)
+	defer drainBuffers.Put(drainBuf)
+	if bodyStream == nil {
+		// Request body streaming is disabled, so the body is already read.
+		bodyStream = bytes.NewReader(c.Request.Body())
+	}
 
 	if err := tokens.StoreBearerToken(c); err != nil {
 		log.Error("could not fetch bearer token", zap.Error(err))
@@ -74,7 +156,7 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 		return
 	}
 
-	idCnr, err := utils.GetContainerID(u.appCtx, scid, u.containerResolver)
+	idCnr, err := utils.GetContainerID(ctx, scid, u.containerResolver)
 	if err != nil {
 		log.Error("wrong container id", zap.Error(err))
 		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
@@ -95,23 +177,47 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 		)
 	}()
 	boundary := string(c.Request.Header.MultipartFormBoundary())
-	if file, err = fetchMultipartFile(u.log, bodyStream, boundary); err != nil {
+	if file, err = fetchMultipartFile(u.log, bodyStream, boundary, u.settings.MaxMemory); err != nil {
 		log.Error("could not receive multipart/form", zap.Error(err))
 		response.Error(c, "could not receive multipart/form: "+err.Error(), fasthttp.StatusBadRequest)
 		return
 	}
-	filtered := filterHeaders(u.log, &c.Request.Header)
-	if needParseExpiration(filtered) {
-		epochDuration, err := getEpochDurations(c, u.pool)
+	filtered, ok := u.headerAttributes(c, log, clientPool)
+	if !ok {
+		return
+	}
+	lockEpoch, ok := u.lockEpoch(c, log, clientPool)
+	if !ok {
+		return
+	}
+
+	// sets FileName attribute from multipart's filename if it wasn't set from
+	// header or the policy prefers it
+	filename, err := resolveFileName(filtered, file.FileName(), u.settings.DuplicateAttributes)
+	if err != nil {
+		log.Error("invalid attributes", zap.Error(err))
+		response.Error(c, "invalid attributes: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	filtered[object.AttributeFileName] = filename
+	// sets FilePath attribute from the path in upload URL if the route with it
+	// is enabled
+	if urlPath, ok := c.UserValue("path").(string); ok {
+		filePath, err := resolveFilePath(filtered, urlPath, filename, u.settings.DuplicateAttributes)
 		if err != nil {
-			log.Error("could not get epoch durations from network info", zap.Error(err))
-			response.Error(c, "could not get epoch durations from network info: "+err.Error(), fasthttp.StatusBadRequest)
+			log.Error("invalid attributes", zap.Error(err))
+			response.Error(c, "invalid attributes: "+err.Error(), fasthttp.StatusBadRequest)
 			return
 		}
-		if err = prepareExpirationHeader(filtered, epochDuration); err != nil {
-			log.Error("could not parse expiration header", zap.Error(err))
-			response.Error(c, "could not parse expiration header: "+err.Error(), fasthttp.StatusBadRequest)
-			return
+		if filePath != "" {
+			filtered[attributeFilePath] = filePath
+		}
+	}
+	// sets Content-Type attribute from multipart's part if it wasn't set from
+	// header and enabled by settings
+	if _, ok := filtered[object.AttributeContentType]; !ok && u.settings.PartContentType {
+		if contentType := partContentType(file); contentType != "" {
+			filtered[object.AttributeContentType] = contentType
 		}
 	}
 
@@ -123,36 +229,111 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 		attribute.SetValue(val)
 		attributes = append(attributes, *attribute)
 	}
-	// sets FileName attribute if it wasn't set from header
-	if _, ok := filtered[object.AttributeFileName]; !ok {
-		filename := object.NewAttribute()
-		filename.SetKey(object.AttributeFileName)
-		filename.SetValue(file.FileName())
-		attributes = append(attributes, *filename)
-	}
 	// sets Timestamp attribute if it wasn't set from header and enabled by settings
-	if _, ok := filtered[object.AttributeTimestamp]; !ok && u.enableDefaultTimestamp {
+	if _, ok := filtered[object.AttributeTimestamp]; !ok && u.settings.DefaultTimestamp {
 		timestamp := object.NewAttribute()
 		timestamp.SetKey(object.AttributeTimestamp)
 		timestamp.SetValue(strconv.FormatInt(time.Now().Unix(), 10))
 		attributes = append(attributes, *timestamp)
 	}
-	id, bt := u.fetchOwnerAndBearerToken(c)
+
+	var body io.Reader = file
+	if filter := u.typeFilter(scid, idCnr); !filter.empty() {
+		declared, ok := filtered[object.AttributeContentType]
+		if !ok {
+			declared = fileContentType(file)
+		}
+		if body, ok = checkType(c, log, filter, file, filename, declared); !ok {
+			return
+		}
+	}
+
+	id, bt := fetchOwnerAndBearerToken(c, clientPool)
 
 	obj := object.New()
 	obj.SetContainerID(*idCnr)
 	obj.SetOwnerID(id)
 	obj.SetAttributes(attributes...)
 
-	var prm pool.PrmObjectPut
+	var (
+		prm     pool.PrmObjectPut
+		pr      = u.startProgress(c)
+		payload = &sizeLimitedReader{r: pr.receivedReader(body), limit: -1}
+	)
+	if limit, ok := u.maxObjectSize(scid, idCnr); ok {
+		payload.limit = limit
+	}
 	prm.SetHeader(*obj)
-	prm.SetPayload(file)
+	prm.SetPayload(pr.storedReader(payload))
+
+	failOpen, scanning := u.settings.Scan.Policy(scid, idCnr)
+	if scanning || u.settings.Deduplicate {
+		spooled, ok := u.spool(c, log, payload, pr, scid)
+		if !ok {
+			return
+		}
+		defer spooled.remove()
+		if scanning && !u.scan(c, log, spooled, pr, failOpen) {
+			return
+		}
+		payload = &sizeLimitedReader{r: spooled, limit: -1}
+		prm.SetPayload(pr.storedReader(payload))
+
+		if u.settings.Deduplicate {
+			existing, err := u.findDuplicate(ctx, c, clientPool, idCnr, bt, spooled.sum, filtered)
+			if err != nil {
+				log.Warn("could not search for duplicate, object is stored", zap.Error(err))
+			} else if existing != nil {
+				pr.finish(nil)
+				addr.SetObjectID(*existing)
+				addr.SetContainerID(*idCnr)
+				log.Debug("upload is deduplicated", zap.Stringer("oid", existing))
+				utils.AddStoredBytes(c, 0)
+				drainBody(bodyStream, *drainBuf)
+				res := newPutResponse(addr)
+				res.Deduplicated = true
+				if lockEpoch == 0 || u.lockStored(c, log, clientPool, id, addr, lockEpoch, bt, res) {
+					u.replyPut(c, log, res)
+				}
+				return
+			}
+		}
+	}
 
 	if bt != nil {
 		prm.UseBearer(*bt)
 	}
+	if key := utils.RequestKey(c); key != nil {
+		prm.UseKey(key)
+	}
+
+	if c.QueryArgs().GetBool("async") {
+		if !u.uploadAsync(c, log, clientPool, prm, payload, pr, scid, idCnr, attributes) {
+			return
+		}
+		drainBody(bodyStream, *drainBuf)
+		c.Response.SetStatusCode(fasthttp.StatusAccepted)
+		c.Response.Header.SetContentType(jsonHeader)
+		return
+	}
 
-	if idObj, err = u.pool.PutObject(u.appCtx, prm); err != nil {
+	err = u.retry.Do(ctx, func() error {
+		var err error
+		idObj, err = clientPool.PutObject(ctx, prm)
+		if err != nil && payload.read > 0 {
+			// the payload stream can't be rewound
+			return utils.NoRetry(err)
+		}
+		return err
+	})
+	pr.finish(err)
+	if err != nil {
+		if payload.exceeded {
+			log.Error("object is too large", zap.Int64("limit", payload.limit))
+			response.Error(c, "object size exceeds the limit of "+strconv.FormatInt(payload.limit, 10)+
+				" bytes for container "+scid, fasthttp.StatusRequestEntityTooLarge)
+			return
+		}
 		log.Error("could not store file in neofs", zap.Error(err))
 		response.Error(c, "could not store file in neofs: "+err.Error(), fasthttp.StatusBadRequest)
 		return
@@ -160,42 +341,186 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 
 	addr.SetObjectID(*idObj)
 	addr.SetContainerID(*idCnr)
+	u.notify(utils.EventObjectCreated, idCnr, idObj, payload.read, attributes)
+	utils.AddRequestObject(c, idObj.String())
+	utils.AddStoredBytes(c, payload.read)
+	drainBody(bodyStream, *drainBuf)
+	res := newPutResponse(addr)
+	if lockEpoch == 0 || u.lockStored(c, log, clientPool, id, addr, lockEpoch, bt, res) {
+		u.replyPut(c, log, res)
+	}
+}
 
+// replyPut replies with the address of the stored object.
+func (u *Uploader) replyPut(c *fasthttp.RequestCtx, log *zap.Logger, res *putResponse) {
 	// Try to return the response, otherwise, if something went wrong, throw an error.
-	if err = newPutResponse(addr).encode(c); err != nil {
+	if err := res.encode(c); err != nil {
 		log.Error("could not encode response", zap.Error(err))
 		response.Error(c, "could not encode response", fasthttp.StatusBadRequest)
 
 		return
 	}
-	// Multipart is multipart and thus can contain more than one part which
-	// we ignore at the moment. Also, when dealing with chunked encoding
-	// the last zero-length chunk might be left unread (because multipart
-	// reader only cares about its boundary and doesn't look further) and
-	// it will be (erroneously) interpreted as the start of the next
-	// pipelined header. Thus we need to drain the body buffer.
+	// Report status code and content type.
+	c.Response.SetStatusCode(fasthttp.StatusOK)
+	c.Response.Header.SetContentType(jsonHeader)
+}
+
+// headerAttributes returns validated attributes set with X-Attribute-*
+// headers, expiration ones are converted to the epoch. The response is an
+// error if they're invalid, false is returned then.
+func (u *Uploader) headerAttributes(c *fasthttp.RequestCtx, log *zap.Logger, clientPool *pool.Pool) (map[string]string, bool) {
+	filtered, err := filterHeadersPolicy(u.log, &c.Request.Header, u.settings.DuplicateAttributes)
+	if err == nil {
+		err = utils.ValidateAttributes(filtered, u.attributeLimits)
+	}
+	if err != nil {
+		log.Error("invalid attributes", zap.Error(err))
+		response.Error(c, "invalid attributes: "+err.Error(), fasthttp.StatusBadRequest)
+		return nil, false
+	}
+	if needParseExpiration(filtered) {
+		epochDuration, err := u.getEpochDurations(c, clientPool)
+		if err != nil {
+			log.Error("could not get epoch durations from network info", zap.Error(err))
+			response.Error(c, "could not get epoch durations from network info: "+err.Error(), fasthttp.StatusBadRequest)
+			return nil, false
+		}
+		if err = prepareExpirationHeader(filtered, epochDuration); err != nil {
+			log.Error("could not parse expiration header", zap.Error(err))
+			response.Error(c, "could not parse expiration header: "+err.Error(), fasthttp.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return filtered, true
+}
+
+// resolveFileName returns FileName attribute value set with the header or the
+// multipart file name according to the policy.
+func resolveFileName(attributes map[string]string, formName, policy string) (string, error) {
+	return resolveAttribute(attributes, object.AttributeFileName, formName, policy)
+}
+
+// resolveFilePath returns FilePath attribute value set with the header or the
+// path after the container in upload URL according to the policy. The path
+// ending with a slash is the directory the file is put to with its name.
+func resolveFilePath(attributes map[string]string, urlPath, filename, policy string) (string, error) {
+	if urlPath == "" {
+		return attributes[attributeFilePath], nil
+	}
+	dir := strings.HasSuffix(urlPath, "/")
+	urlPath = strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if dir && filename != "" {
+		urlPath = path.Join(urlPath, filename)
+	}
+	return resolveAttribute(attributes, attributeFilePath, urlPath, policy)
+}
+
+// resolveAttribute returns the value of the attribute set with the header or
+// the value taken from the request according to the policy.
+func resolveAttribute(attributes map[string]string, key, value, policy string) (string, error) {
+	headerValue, ok := attributes[key]
+	if !ok {
+		return value, nil
+	}
+	if value == "" || headerValue == value {
+		return headerValue, nil
+	}
+
+	switch policy {
+	case DuplicatesReject:
+		return "", fmt.Errorf("%q differs from %s attribute %q", value, key, headerValue)
+	case DuplicatesLast:
+		return value, nil
+	default:
+		return headerValue, nil
+	}
+}
+
+// drainBody reads the rest of request body. Multipart is multipart and thus
+// can contain more than one part which we ignore at the moment. Also, when
+// dealing with chunked encoding the last zero-length chunk might be left
+// unread (because multipart reader only cares about its boundary and doesn't
+// look further) and it will be (erroneously) interpreted as the start of the
+// next pipelined header. Thus we need to drain the body buffer.
+func drainBody(bodyStream io.Reader, buf []byte) {
 	for {
-		_, err = bodyStream.Read(drainBuf)
-		if err == io.EOF || err == io.ErrUnexpectedEOF {
+		if _, err := bodyStream.Read(buf); err != nil {
 			break
 		}
 	}
-	// Report status code and content type.
-	c.Response.SetStatusCode(fasthttp.StatusOK)
-	c.Response.Header.SetContentType(jsonHeader)
 }
 
-func (u *Uploader) fetchOwnerAndBearerToken(ctx context.Context) (*user.ID, *bearer.Token) {
+// notify sends the object event if notifications are enabled.
+func (u *Uploader) notify(typ string, cnrID *cid.ID, objID *oid.ID, size int64, attributes []object.Attribute) {
+	if u.notifier == nil {
+		return
+	}
+
+	attrs := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		attrs[attr.Key()] = attr.Value()
+	}
+
+	u.notifier.Notify(utils.ObjectEvent{
+		Type:        typ,
+		ContainerID: cnrID.String(),
+		ObjectID:    objID.String(),
+		Size:        size,
+		Attributes:  attrs,
+		Time:        time.Now(),
+	})
+}
+
+func fetchOwnerAndBearerToken(ctx context.Context, p *pool.Pool) (*user.ID, *bearer.Token) {
 	if tkn, err := tokens.LoadBearerToken(ctx); err == nil && tkn != nil {
 		issuer, _ := tkn.Issuer()
 		return &issuer, tkn
 	}
-	return u.pool.OwnerID(), nil
+	if key := utils.RequestKey(ctx); key != nil {
+		var owner user.ID
+		user.IDFromKey(&owner, key.PublicKey)
+		return &owner, nil
+	}
+	return p.OwnerID(), nil
+}
+
+// maxObjectSize returns the object size limit of the container specified by
+// name or ID in request path.
+func (u *Uploader) maxObjectSize(scid string, cnrID *cid.ID) (int64, bool) {
+	if limit, ok := u.settings.MaxObjectSize[scid]; ok {
+		return limit, true
+	}
+	limit, ok := u.settings.MaxObjectSize[cnrID.String()]
+	return limit, ok
+}
+
+// sizeLimitedReader fails if more than limit bytes are read, negative limit
+// means no limit.
+type sizeLimitedReader struct {
+	r        io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+var errObjectTooLarge = errors.New("object is too large")
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if r.limit >= 0 && r.read > r.limit {
+		r.exceeded = true
+		return n, errObjectTooLarge
+	}
+	return n, err
 }
 
 type putResponse struct {
-	ObjectID    string `json:"object_id"`
-	ContainerID string `json:"container_id"`
+	ObjectID     string `json:"object_id"`
+	ContainerID  string `json:"container_id"`
+	Deduplicated bool   `json:"deduplicated,omitempty"`
+	LockID       string `json:"lock_id,omitempty"`
+	LockedUntil  uint64 `json:"locked_until_epoch,omitempty"`
 }
 
 func newPutResponse(addr *address.Address) *putResponse {
@@ -213,30 +538,27 @@ func (pr *putResponse) encode(w io.Writer) error {
 	return enc.Encode(pr)
 }
 
-func getEpochDurations(ctx context.Context, p *pool.Pool) (*epochDurations, error) {
-	networkInfo, err := p.NetworkInfo(ctx)
+// getEpochDurations returns the current epoch and durations of blocks and
+// epochs, the state of the tracker is used if it's enabled.
+func (u *Uploader) getEpochDurations(ctx context.Context, p *pool.Pool) (*epochDurations, error) {
+	var (
+		state *utils.NetworkState
+		err   error
+	)
+	if u.epochs != nil {
+		state, err = u.epochs.State(ctx)
+	} else {
+		state, err = utils.FetchNetworkState(ctx, p)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	res := &epochDurations{
-		currentEpoch: networkInfo.CurrentEpoch(),
-		msPerBlock:   networkInfo.MsPerBlock(),
-	}
-
-	networkInfo.NetworkConfig().IterateParameters(func(parameter *netmap.NetworkParameter) bool {
-		if string(parameter.Key()) == "EpochDuration" {
-			data := make([]byte, 8)
-			copy(data, parameter.Value())
-			res.blockPerEpoch = binary.LittleEndian.Uint64(data)
-			return true
-		}
-		return false
-	})
-	if res.blockPerEpoch == 0 {
-		return nil, fmt.Errorf("not found param: EpochDuration")
-	}
-	return res, nil
+	return &epochDurations{
+		currentEpoch:  state.Epoch,
+		msPerBlock:    state.MsPerBlock,
+		blockPerEpoch: state.BlocksPerEpoch,
+	}, nil
 }
 
 func needParseExpiration(headers map[string]string) bool {
@@ -0,0 +1,37 @@
+package uploader
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeLimitedReader(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		r := &sizeLimitedReader{r: strings.NewReader("payload"), limit: -1}
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "payload", string(data))
+		require.False(t, r.exceeded)
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		r := &sizeLimitedReader{r: strings.NewReader("payload"), limit: 7}
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "payload", string(data))
+		require.False(t, r.exceeded)
+	})
+
+	t.Run("exceeded", func(t *testing.T) {
+		r := &sizeLimitedReader{r: strings.NewReader("payload"), limit: 6}
+
+		_, err := io.ReadAll(r)
+		require.ErrorIs(t, err, errObjectTooLarge)
+		require.True(t, r.exceeded)
+	})
+}
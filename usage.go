@@ -0,0 +1,204 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Directions of the traffic accounted per container.
+const (
+	usageIngress = "ingress"
+	usageEgress  = "egress"
+)
+
+var (
+	// containerBytes shows request (ingress) and response (egress) body bytes
+	// of requests to containers.
+	containerBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "neofs_http_gw",
+		Name:      "container_bytes_total",
+		Help:      "Request (ingress) and response (egress) body bytes of requests to the container.",
+	}, []string{"cid", "direction"})
+
+	// containerRequests shows the number of requests to containers.
+	containerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "neofs_http_gw",
+		Name:      "container_requests_total",
+		Help:      "Requests to the container by operation (upload or download).",
+	}, []string{"cid", "operation"})
+)
+
+type (
+	// containerUsage is the traffic and the number of requests of the
+	// container returned by GET /-/usage.
+	containerUsage struct {
+		IngressBytes uint64 `json:"ingress_bytes"`
+		EgressBytes  uint64 `json:"egress_bytes"`
+		Uploads      uint64 `json:"uploads"`
+		Downloads    uint64 `json:"downloads"`
+	}
+
+	// usageReport is the usage of all containers since the start.
+	usageReport struct {
+		Since      time.Time                 `json:"since"`
+		Containers map[string]containerUsage `json:"containers"`
+	}
+
+	// usageAccounting counts the traffic and requests per container since
+	// the gateway start.
+	usageAccounting struct {
+		ctx   context.Context
+		since time.Time
+
+		mu         sync.Mutex
+		containers map[string]*containerUsage
+	}
+)
+
+// newUsageAccounting returns nil if usage accounting is disabled.
+func (a *app) newUsageAccounting(ctx context.Context) *usageAccounting {
+	if !a.cfg.GetBool(cfgUsageEnabled) {
+		return nil
+	}
+
+	return &usageAccounting{
+		ctx:        ctx,
+		since:      time.Now(),
+		containers: make(map[string]*containerUsage),
+	}
+}
+
+// container returns the usage of the container, u.mu must be held.
+func (u *usageAccounting) container(cnr string) *containerUsage {
+	usage, ok := u.containers[cnr]
+	if !ok {
+		usage = new(containerUsage)
+		u.containers[cnr] = usage
+	}
+	return usage
+}
+
+// add accounts the request of the operation to the container.
+func (u *usageAccounting) add(cnr, operation string, ingress, egress uint64) {
+	containerBytes.WithLabelValues(cnr, usageIngress).Add(float64(ingress))
+	containerBytes.WithLabelValues(cnr, usageEgress).Add(float64(egress))
+	containerRequests.WithLabelValues(cnr, operation).Inc()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	usage := u.container(cnr)
+	usage.IngressBytes += ingress
+	usage.EgressBytes += egress
+	if operation == operationUpload {
+		usage.Uploads++
+	} else {
+		usage.Downloads++
+	}
+}
+
+// addEgress accounts the bytes of streamed response sent to the client of
+// the container.
+func (u *usageAccounting) addEgress(cnr string, egress uint64) {
+	containerBytes.WithLabelValues(cnr, usageEgress).Add(float64(egress))
+
+	u.mu.Lock()
+	u.container(cnr).EgressBytes += egress
+	u.mu.Unlock()
+}
+
+func (u *usageAccounting) report() usageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	res := usageReport{
+		Since:      u.since,
+		Containers: make(map[string]containerUsage, len(u.containers)),
+	}
+	for cnr, usage := range u.containers {
+		res.Containers[cnr] = *usage
+	}
+	return res
+}
+
+// usageAccount counts the traffic and requests of the route per container,
+// container names are resolved, so the usage is accounted to their IDs. Only
+// the bytes actually read and sent are counted, streamed responses are
+// counted while they're sent. Failed requests aren't accounted, so random
+// container IDs don't make metric series.
+func (a *app) usageAccount(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	u := a.usage
+	if u == nil {
+		return h
+	}
+
+	operation := routeOperation(route)
+
+	return func(c *fasthttp.RequestCtx) {
+		scid, _ := c.UserValue("cid").(string)
+		if scid == "" {
+			h(c)
+			return
+		}
+
+		cnrID, err := a.requestContainer(u.ctx, c, scid)
+		if err != nil {
+			// The handler replies with the error.
+			h(c)
+			return
+		}
+
+		var (
+			cnr       = cnrID.String()
+			ingress   uint64
+			succeeded bool
+		)
+		if c.Request.IsBodyStream() {
+			c.Request.SetBodyStream(utils.CountingReader(c.RequestBodyStream(), func(n int) {
+				ingress += uint64(n)
+			}), c.Request.Header.ContentLength())
+		} else {
+			ingress = uint64(len(c.Request.Body()))
+		}
+		// Streams are read after the handler returns.
+		utils.AddBodyCounter(c, func(n int) {
+			if succeeded {
+				u.addEgress(cnr, uint64(n))
+			}
+		})
+
+		h(c)
+
+		if c.Response.StatusCode() >= fasthttp.StatusBadRequest {
+			return
+		}
+		succeeded = true
+
+		var egress uint64
+		if !c.Response.IsBodyStream() && !c.IsHead() {
+			egress = uint64(len(c.Response.Body()))
+		}
+		u.add(cnr, operation, ingress, egress)
+	}
+}
+
+// usageHandler returns the usage report of all containers.
+func (a *app) usageHandler(c *fasthttp.RequestCtx) {
+	data, err := json.Marshal(a.usage.report())
+	if err != nil {
+		a.log.Error("could not encode usage report", zap.Error(err))
+		response.Error(c, "could not encode usage report: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetContentType("application/json; charset=UTF-8")
+	c.SetBody(data)
+}
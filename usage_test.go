@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestUsageAccount(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := &app{cfg: viper.New(), log: zap.NewNop()}
+		require.Nil(t, a.newUsageAccounting(context.Background()))
+	})
+
+	const cnr = "BJeErH9MWmf52VsR1mLWKkgF3pRm3FkubYxM7TZkBP4K"
+
+	v := viper.New()
+	v.Set(cfgUsageEnabled, true)
+	a := &app{cfg: v, log: zap.NewNop()}
+	a.usage = a.newUsageAccounting(context.Background())
+
+	handler := func(c *fasthttp.RequestCtx) {
+		c.SetBodyString("hello")
+	}
+	request := func(route, scid string, body string) {
+		var c fasthttp.RequestCtx
+		c.Request.SetBodyString(body)
+		c.Request.Header.SetContentLength(len(body))
+		c.SetUserValue("cid", scid)
+		a.usageAccount(route, handler)(&c)
+	}
+
+	request(routeUpload, cnr, "payload")
+	request(routeGet, cnr, "")
+	request(routeGet, cnr, "")
+	request(routeGet, "", "")
+	request(routeGet, "not a container", "")
+
+	// Chunked response is counted while it's sent.
+	var c fasthttp.RequestCtx
+	c.SetUserValue("cid", cnr)
+	a.usageAccount(routeZip, func(c *fasthttp.RequestCtx) {
+		utils.SetBodyStreamWriter(c, func(w *bufio.Writer) {
+			_, _ = w.WriteString("zipped")
+		})
+	})(&c)
+	require.NoError(t, c.Response.BodyWriteTo(io.Discard))
+
+	// Failed requests aren't accounted.
+	const other = "HXSaMJXk2g8C14ht8HSi7BBaiYZ1HeWh2xnWPGQCg4H6"
+	var failed fasthttp.RequestCtx
+	failed.SetUserValue("cid", other)
+	a.usageAccount(routeGet, func(c *fasthttp.RequestCtx) {
+		c.Error("not found", fasthttp.StatusNotFound)
+	})(&failed)
+
+	var reply fasthttp.RequestCtx
+	a.usageHandler(&reply)
+
+	var report usageReport
+	require.NoError(t, json.Unmarshal(reply.Response.Body(), &report))
+	require.Equal(t, map[string]containerUsage{
+		cnr: {IngressBytes: 7, EgressBytes: 21, Uploads: 1, Downloads: 3},
+	}, report.Containers)
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "github.com/valyala/fasthttp"
+
+const apiVersionKey = "__api_version"
+
+// SetAPIVersion sets the version of the gateway API the request is made to.
+func SetAPIVersion(c *fasthttp.RequestCtx, version int) {
+	c.SetUserValue(apiVersionKey, version)
+}
+
+// APIVersion returns the version of the gateway API the request is made to,
+// it's 1 (legacy routes) if it isn't set.
+func APIVersion(c *fasthttp.RequestCtx) int {
+	if version, ok := c.UserValue(apiVersionKey).(int); ok {
+		return version
+	}
+	return 1
+}
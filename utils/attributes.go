@@ -1,5 +1,13 @@
 package utils
 
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/object"
+)
+
 const (
 	UserAttributeHeaderPrefix = "X-Attribute-"
 	SystemAttributePrefix     = "__NEOFS__"
@@ -8,3 +16,50 @@ const (
 	ExpirationTimestampAttr = SystemAttributePrefix + "EXPIRATION_TIMESTAMP"
 	ExpirationRFC3339Attr   = SystemAttributePrefix + "EXPIRATION_RFC3339"
 )
+
+// AttributeLimits restrict attributes of uploads, zero limits are disabled.
+type AttributeLimits struct {
+	// MaxCount limits the number of attributes set with headers.
+	MaxCount int
+	// MaxKeySize limits attribute key length in bytes.
+	MaxKeySize int
+	// MaxValueSize limits attribute value length in bytes.
+	MaxValueSize int
+	// AllowSystem allows system attributes (with __NEOFS__ prefix) other than
+	// expiration ones.
+	AllowSystem bool
+}
+
+// allowedSystemAttributes are system attributes any client can set.
+var allowedSystemAttributes = map[string]struct{}{
+	object.SysAttributeExpEpoch: {},
+	ExpirationDurationAttr:      {},
+	ExpirationTimestampAttr:     {},
+	ExpirationRFC3339Attr:       {},
+}
+
+// ValidateAttributes checks attributes set by clients against the limits.
+func ValidateAttributes(attributes map[string]string, limits AttributeLimits) error {
+	if limits.MaxCount > 0 && len(attributes) > limits.MaxCount {
+		return fmt.Errorf("too many attributes: %d, the limit is %d", len(attributes), limits.MaxCount)
+	}
+
+	for key, val := range attributes {
+		if limits.MaxKeySize > 0 && len(key) > limits.MaxKeySize {
+			return fmt.Errorf("attribute key is too long: %d bytes, the limit is %d", len(key), limits.MaxKeySize)
+		}
+		if limits.MaxValueSize > 0 && len(val) > limits.MaxValueSize {
+			return fmt.Errorf("value of attribute %s is too long: %d bytes, the limit is %d", key, len(val), limits.MaxValueSize)
+		}
+		if !utf8.ValidString(key) || !utf8.ValidString(val) {
+			return fmt.Errorf("attribute %q is not valid UTF-8", key)
+		}
+		if !limits.AllowSystem && strings.HasPrefix(strings.ToUpper(key), SystemAttributePrefix) {
+			if _, ok := allowedSystemAttributes[key]; !ok {
+				return fmt.Errorf("system attribute %s can't be set", key)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	limits := AttributeLimits{MaxCount: 3, MaxKeySize: 32, MaxValueSize: 16}
+
+	for name, tc := range map[string]struct {
+		attributes map[string]string
+		limits     AttributeLimits
+		err        string
+	}{
+		"valid": {
+			attributes: map[string]string{"FileName": "cat.jpeg", object.SysAttributeExpEpoch: "100"},
+		},
+		"expiration": {
+			attributes: map[string]string{ExpirationDurationAttr: "24h"},
+		},
+		"too many": {
+			attributes: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+			err:        "too many attributes",
+		},
+		"long key": {
+			attributes: map[string]string{strings.Repeat("k", 33): "1"},
+			err:        "attribute key is too long",
+		},
+		"long value": {
+			attributes: map[string]string{"Key": strings.Repeat("v", 17)},
+			err:        "value of attribute Key is too long",
+		},
+		"invalid utf-8": {
+			attributes: map[string]string{"Key": "\xff"},
+			err:        "not valid UTF-8",
+		},
+		"system": {
+			attributes: map[string]string{"__NEOFS__X": "1"},
+			err:        "system attribute __NEOFS__X can't be set",
+		},
+		"system lowercase": {
+			attributes: map[string]string{"__neofs__X": "1"},
+			err:        "can't be set",
+		},
+		"system allowed": {
+			attributes: map[string]string{"__NEOFS__X": "1"},
+			limits:     AttributeLimits{AllowSystem: true},
+		},
+		"no limits": {
+			attributes: map[string]string{"a": "1", "b": "2", "c": "3", "LongAttributeKey": strings.Repeat("v", 17)},
+			limits:     AttributeLimits{AllowSystem: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			l := limits
+			if tc.limits != (AttributeLimits{}) {
+				l = tc.limits
+			}
+			err := ValidateAttributes(tc.attributes, l)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/valyala/fasthttp"
+)
+
+const bodyCounterKey = "__body_counter"
+
+// countingReader reports the number of bytes read from the stream, it closes
+// the stream if it implements io.Closer.
+type countingReader struct {
+	r     io.Reader
+	count func(int)
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.count(n)
+	}
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// CountingReader returns the reader reporting the number of bytes read from
+// r to count. It closes r on Close if r implements io.Closer.
+func CountingReader(r io.Reader, count func(int)) io.ReadCloser {
+	return &countingReader{r: r, count: count}
+}
+
+// AddBodyCounter makes response body streams set with SetBodyStream and
+// SetBodyStreamWriter report the number of bytes sent to count. Counters are
+// called in the order they are added.
+func AddBodyCounter(c *fasthttp.RequestCtx, count func(int)) {
+	if prev, ok := c.UserValue(bodyCounterKey).(func(int)); ok {
+		next := count
+		count = func(n int) {
+			prev(n)
+			next(n)
+		}
+	}
+	c.SetUserValue(bodyCounterKey, count)
+}
+
+// SetBodyStream sets the response body stream like
+// fasthttp.Response.SetBodyStream, bytes sent are reported to counters added
+// with AddBodyCounter.
+func SetBodyStream(c *fasthttp.RequestCtx, r io.Reader, size int) {
+	if count, ok := c.UserValue(bodyCounterKey).(func(int)); ok {
+		r = CountingReader(r, count)
+	}
+	c.Response.SetBodyStream(r, size)
+}
+
+// SetBodyStreamWriter sets the response body writer like
+// fasthttp.RequestCtx.SetBodyStreamWriter, bytes sent are reported to
+// counters added with AddBodyCounter.
+func SetBodyStreamWriter(c *fasthttp.RequestCtx, sw func(*bufio.Writer)) {
+	SetBodyStream(c, fasthttp.NewStreamReader(sw), -1)
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "sync"
+
+// PayloadBufferSize is the size of buffers object payload is copied with,
+// it's the same as the size of upload buffers.
+const PayloadBufferSize = 3 << 20
+
+// PayloadBuffers is the pool of buffers to copy object payload with.
+var PayloadBuffers = NewBufferPool(PayloadBufferSize)
+
+// BufferPool is a pool of byte slices of the same size. Slices are stored by
+// pointers, so that Put doesn't allocate.
+type BufferPool struct {
+	pool sync.Pool
+}
+
+// NewBufferPool creates a pool of slices of the size.
+func NewBufferPool(size int) *BufferPool {
+	return &BufferPool{pool: sync.Pool{New: func() interface{} {
+		buf := make([]byte, size)
+		return &buf
+	}}}
+}
+
+// Get returns a slice from the pool, it's to be put back after use.
+func (p *BufferPool) Get() *[]byte {
+	return p.pool.Get().(*[]byte)
+}
+
+// Put returns the slice to the pool, it must not be used after that.
+func (p *BufferPool) Put(buf *[]byte) {
+	p.pool.Put(buf)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// transfers is the number of concurrent transfers in benchmarks.
+const transfers = 1000
+
+type (
+	// onlyReader and onlyWriter hide ReaderFrom and WriterTo implementations,
+	// so that io.CopyBuffer uses the buffer.
+	onlyReader struct{ io.Reader }
+	onlyWriter struct{ io.Writer }
+)
+
+func TestBufferPool(t *testing.T) {
+	p := NewBufferPool(16)
+
+	buf := p.Get()
+	require.Len(t, *buf, 16)
+	p.Put(buf)
+}
+
+// BenchmarkPayloadCopy compares copying object payloads by concurrent
+// transfers with buffers allocated per transfer and taken from the pool.
+func BenchmarkPayloadCopy(b *testing.B) {
+	payload := bytes.Repeat([]byte{1}, 1<<20)
+
+	copyPayload := func(buf []byte) {
+		_, err := io.CopyBuffer(onlyWriter{io.Discard}, onlyReader{bytes.NewReader(payload)}, buf)
+		require.NoError(b, err)
+	}
+
+	run := func(b *testing.B, transfer func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		b.SetParallelism(transfers/runtime.GOMAXPROCS(0) + 1)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				transfer()
+			}
+		})
+
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gc/op")
+	}
+
+	b.Run("alloc", func(b *testing.B) {
+		run(b, func() {
+			copyPayload(make([]byte, PayloadBufferSize))
+		})
+	})
+
+	b.Run("pool", func(b *testing.B) {
+		run(b, func() {
+			buf := PayloadBuffers.Get()
+			copyPayload(*buf)
+			PayloadBuffers.Put(buf)
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	requestTimeoutKey = "__request_timeout"
+	requestContextKey = "__request_context"
+)
+
+// requestContext is stored in request user values, fasthttp closes them after
+// the response is sent, so the context is cancelled then.
+type requestContext struct {
+	context.Context
+	cancel context.CancelFunc
+}
+
+func (c *requestContext) Close() error {
+	c.cancel()
+	return nil
+}
+
+// SetRequestTimeout sets the timeout of NeoFS operations of the request.
+func SetRequestTimeout(c *fasthttp.RequestCtx, timeout time.Duration) {
+	c.SetUserValue(requestTimeoutKey, timeout)
+}
+
+// RequestContext returns the context for NeoFS operations of the request. If
+// the request has a timeout, the context is derived from the parent one with
+// this timeout, it includes the time to stream the response body. Otherwise
+// the parent context is returned.
+func RequestContext(c *fasthttp.RequestCtx, parent context.Context) context.Context {
+	if ctx, ok := c.UserValue(requestContextKey).(*requestContext); ok {
+		return ctx
+	}
+
+	timeout, ok := c.UserValue(requestTimeoutKey).(time.Duration)
+	if !ok || timeout <= 0 {
+		return parent
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	rc := &requestContext{Context: ctx, cancel: cancel}
+	c.SetUserValue(requestContextKey, rc)
+
+	return rc
+}
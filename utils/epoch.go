@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"go.uber.org/zap"
+)
+
+// epochJitter is the part of the refresh interval it's randomly changed by,
+// so gateways don't request network info at the same time.
+const epochJitter = 0.1
+
+// Network configuration parameters.
+const (
+	NetworkParamEpochDuration     = "EpochDuration"
+	NetworkParamMaxObjectSize     = "MaxObjectSize"
+	NetworkParamBasicIncomeRate   = "BasicIncomeRate"
+	NetworkParamAuditFee          = "AuditFee"
+	NetworkParamContainerFee      = "ContainerFee"
+	NetworkParamContainerAliasFee = "ContainerAliasFee"
+	NetworkParamWithdrawFee       = "WithdrawFee"
+)
+
+// NetworkState is the current epoch and the parameters to translate
+// durations to epochs.
+type NetworkState struct {
+	Epoch          uint64
+	MsPerBlock     int64
+	BlocksPerEpoch uint64
+	// Parameters are all network configuration parameters decoded as
+	// little-endian integers, e.g. MaxObjectSize or ContainerFee.
+	Parameters map[string]uint64
+}
+
+// FetchNetworkState requests the network state from the node.
+func FetchNetworkState(ctx context.Context, p *pool.Pool) (*NetworkState, error) {
+	networkInfo, err := p.NetworkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &NetworkState{
+		Epoch:      networkInfo.CurrentEpoch(),
+		MsPerBlock: networkInfo.MsPerBlock(),
+		Parameters: make(map[string]uint64),
+	}
+
+	networkInfo.NetworkConfig().IterateParameters(func(parameter *netmap.NetworkParameter) bool {
+		data := make([]byte, 8)
+		copy(data, parameter.Value())
+		res.Parameters[string(parameter.Key())] = binary.LittleEndian.Uint64(data)
+		return false
+	})
+	res.BlocksPerEpoch = res.Parameters[NetworkParamEpochDuration]
+	if res.BlocksPerEpoch == 0 {
+		return nil, errors.New("not found param: " + NetworkParamEpochDuration)
+	}
+	return res, nil
+}
+
+// EpochTracker keeps the network state refreshed in background, so handlers
+// don't request it from nodes every time.
+type EpochTracker struct {
+	log      *zap.Logger
+	interval time.Duration
+	// fetch is replaced in tests.
+	fetch func(ctx context.Context) (*NetworkState, error)
+	// updated is called when the epoch is changed, it can be nil.
+	updated func(epoch uint64)
+
+	mu      sync.RWMutex
+	state   *NetworkState
+	fetched time.Time
+}
+
+// NewEpochTracker creates EpochTracker refreshing the state with the current
+// pool of the holder every interval, updated is called with new epochs.
+func NewEpochTracker(log *zap.Logger, holder *PoolHolder, interval time.Duration, updated func(epoch uint64)) *EpochTracker {
+	return &EpochTracker{
+		log:      log,
+		interval: interval,
+		updated:  updated,
+		fetch: func(ctx context.Context) (*NetworkState, error) {
+			return FetchNetworkState(ctx, holder.Pool())
+		},
+	}
+}
+
+// Start refreshes the state until the context is done.
+func (t *EpochTracker) Start(ctx context.Context) {
+	go func() {
+		for {
+			if _, err := t.refresh(ctx); err != nil && ctx.Err() == nil {
+				t.log.Warn("could not refresh network epoch", zap.Error(err))
+			}
+
+			timer := time.NewTimer(jittered(t.interval))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+}
+
+// State returns the cached state if it's refreshed within two intervals,
+// otherwise it's requested from the node.
+func (t *EpochTracker) State(ctx context.Context) (*NetworkState, error) {
+	t.mu.RLock()
+	state, fetched := t.state, t.fetched
+	t.mu.RUnlock()
+
+	if state != nil && time.Since(fetched) < 2*t.interval {
+		return state, nil
+	}
+	return t.refresh(ctx)
+}
+
+// refresh requests the state from the node and caches it.
+func (t *EpochTracker) refresh(ctx context.Context) (*NetworkState, error) {
+	state, err := t.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	changed := t.state == nil || t.state.Epoch != state.Epoch
+	t.state, t.fetched = state, time.Now()
+	t.mu.Unlock()
+
+	if changed {
+		t.log.Debug("network epoch is updated", zap.Uint64("epoch", state.Epoch))
+		if t.updated != nil {
+			t.updated(state.Epoch)
+		}
+	}
+	return state, nil
+}
+
+// jittered returns the interval randomly changed by epochJitter of it.
+func jittered(interval time.Duration) time.Duration {
+	delta := time.Duration(float64(interval) * epochJitter * (2*rand.Float64() - 1))
+	return interval + delta
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEpochTracker(t *testing.T) {
+	var (
+		calls   int
+		epoch   uint64 = 10
+		fail    bool
+		updates []uint64
+	)
+	tracker := &EpochTracker{
+		log:      zap.NewNop(),
+		interval: time.Hour,
+		updated:  func(epoch uint64) { updates = append(updates, epoch) },
+		fetch: func(context.Context) (*NetworkState, error) {
+			calls++
+			if fail {
+				return nil, errors.New("unavailable")
+			}
+			return &NetworkState{Epoch: epoch, MsPerBlock: 1000, BlocksPerEpoch: 60}, nil
+		},
+	}
+
+	state, err := tracker.State(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 10, state.Epoch)
+
+	epoch = 11
+	state, err = tracker.State(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 10, state.Epoch, "cached state is expected")
+	require.Equal(t, 1, calls)
+
+	_, err = tracker.refresh(context.Background())
+	require.NoError(t, err)
+	_, err = tracker.refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 11}, updates)
+
+	tracker.fetched = time.Now().Add(-3 * time.Hour)
+	fail = true
+	_, err = tracker.State(context.Background())
+	require.Error(t, err)
+}
+
+func TestJittered(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jittered(time.Minute)
+		require.GreaterOrEqual(t, d, 54*time.Second)
+		require.LessOrEqual(t, d, 66*time.Second)
+	}
+}
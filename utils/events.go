@@ -0,0 +1,27 @@
+package utils
+
+import "time"
+
+// Types of object events.
+const (
+	// EventObjectCreated is the type of events on objects stored by the gateway.
+	EventObjectCreated = "object_created"
+	// EventObjectDeleted is the type of events on objects deleted by the gateway.
+	EventObjectDeleted = "object_deleted"
+)
+
+// ObjectEvent describes an operation on object performed by the gateway.
+type ObjectEvent struct {
+	Type        string            `json:"type"`
+	ContainerID string            `json:"container_id"`
+	ObjectID    string            `json:"object_id"`
+	Size        int64             `json:"size"`
+	Attributes  map[string]string `json:"attributes"`
+	Time        time.Time         `json:"time"`
+}
+
+// EventNotifier delivers object events to external systems. It must not block
+// the caller.
+type EventNotifier interface {
+	Notify(ObjectEvent)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+)
+
+// HedgePolicy defines hedging of read operations: if the operation isn't
+// completed within the delay, its duplicate is issued and the first
+// successful result is taken. The duplicate is sent to another node if pools
+// of single nodes are available. The zero policy disables hedging.
+type HedgePolicy struct {
+	// Delay is the time to wait for the operation before its duplicate is
+	// issued.
+	Delay time.Duration
+	// Nodes returns connection pools of two different nodes for the
+	// operation and its duplicate, ok is false if they aren't available
+	// (e.g. there is a single node), the request pool is used for both then.
+	// It's nil if pools of single nodes aren't used.
+	Nodes func() (first, second *pool.Pool, ok bool)
+}
+
+// hedgeResult is the result of the attempt with the given index.
+type hedgeResult struct {
+	attempt int
+	res     interface{}
+	err     error
+}
+
+func noopCancel() {}
+
+// Do runs the operation hedging it according to the policy, the operation
+// gets the index of the attempt (1 for the duplicate). Every attempt has
+// its own context, the context of the returned result is cancelled by the
+// returned function which is to be called when the result isn't used anymore
+// (e.g. when the payload stream is closed). Successful results of other
+// attempts are passed to discard. The operation failed before the delay isn't
+// hedged, retries are up to RetryPolicy.
+func (p HedgePolicy) Do(ctx context.Context, op func(ctx context.Context, attempt int) (interface{}, error), discard func(interface{})) (interface{}, context.CancelFunc, error) {
+	if p.Delay <= 0 {
+		res, err := op(ctx, 0)
+		return res, noopCancel, err
+	}
+
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	run := func() {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			res, err := op(attemptCtx, attempt)
+			results <- hedgeResult{attempt: attempt, res: res, err: err}
+		}()
+	}
+
+	run()
+	timer := time.NewTimer(p.Delay)
+	defer timer.Stop()
+
+	var (
+		pending = 1
+		err     error
+	)
+	for {
+		select {
+		case <-timer.C:
+			pending++
+			run()
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				cancels[r.attempt]()
+				if err = r.err; pending == 0 {
+					return nil, noopCancel, err
+				}
+				continue
+			}
+
+			if pending > 0 {
+				for i := range cancels {
+					if i != r.attempt {
+						cancels[i]()
+					}
+				}
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if r := <-results; r.err == nil {
+							discard(r.res)
+						}
+					}
+				}(pending)
+			}
+			return r.res, cancels[r.attempt], nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgePolicy(t *testing.T) {
+	p := HedgePolicy{Delay: 10 * time.Millisecond}
+
+	t.Run("fast", func(t *testing.T) {
+		var calls int32
+		res, cancel, err := p.Do(context.Background(), func(context.Context, int) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return 1, nil
+		}, func(interface{}) {})
+		require.NoError(t, err)
+		require.Equal(t, 1, res)
+		cancel()
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		var (
+			calls     int32
+			discarded = make(chan interface{}, 1)
+			cancelled = make(chan struct{})
+		)
+		res, cancel, err := p.Do(context.Background(), func(ctx context.Context, _ int) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				close(cancelled)
+				return "slow", nil
+			}
+			return "hedged", nil
+		}, func(res interface{}) {
+			discarded <- res
+		})
+		require.NoError(t, err)
+		require.Equal(t, "hedged", res)
+		defer cancel()
+
+		<-cancelled
+		require.Equal(t, "slow", <-discarded)
+	})
+
+	t.Run("winner context", func(t *testing.T) {
+		var calls int32
+		res, cancel, err := p.Do(context.Background(), func(ctx context.Context, _ int) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(5 * p.Delay)
+				return nil, errors.New("unavailable")
+			}
+			return ctx, nil
+		}, func(interface{}) {})
+		require.NoError(t, err)
+
+		ctx := res.(context.Context)
+		time.Sleep(10 * p.Delay)
+		require.NoError(t, ctx.Err())
+		cancel()
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("failed before delay", func(t *testing.T) {
+		var calls int32
+		errNotFound := errors.New("not found")
+		_, cancel, err := p.Do(context.Background(), func(context.Context, int) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errNotFound
+		}, func(interface{}) {})
+		cancel()
+		require.ErrorIs(t, err, errNotFound)
+		time.Sleep(2 * p.Delay)
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		var calls int32
+		errUnavailable := errors.New("unavailable")
+		_, cancel, err := p.Do(context.Background(), func(context.Context, int) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(2 * p.Delay)
+			}
+			return nil, errUnavailable
+		}, func(interface{}) {})
+		cancel()
+		require.ErrorIs(t, err, errUnavailable)
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var zero HedgePolicy
+		ctx := context.Background()
+		res, cancel, err := zero.Do(ctx, func(ctx context.Context, _ int) (interface{}, error) {
+			return ctx, nil
+		}, func(interface{}) {})
+		require.NoError(t, err)
+		require.Equal(t, ctx, res)
+		cancel()
+	})
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+
+	"github.com/valyala/fasthttp"
+)
+
+const requestKeyKey = "__request_key"
+
+// SetRequestKey sets the key to sign NeoFS requests of the request with.
+func SetRequestKey(c *fasthttp.RequestCtx, key *ecdsa.PrivateKey) {
+	c.SetUserValue(requestKeyKey, key)
+}
+
+// RequestKey returns the key to sign NeoFS requests with stored in the
+// request context given, nil means the gateway key.
+func RequestKey(ctx context.Context) *ecdsa.PrivateKey {
+	key, _ := ctx.Value(requestKeyKey).(*ecdsa.PrivateKey)
+	return key
+}
@@ -2,12 +2,28 @@ package utils
 
 import (
 	"github.com/nspcc-dev/neofs-http-gw/resolver"
-	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"go.uber.org/zap"
 )
 
 type AppParams struct {
 	Logger   *zap.Logger
-	Pool     *pool.Pool
+	Pool     *PoolHolder
 	Resolver *resolver.ContainerResolver
+	// Notifier is notified of object events, it's nil if notifications are
+	// disabled.
+	Notifier EventNotifier
+	// Retry is the policy of retrying failed NeoFS operations.
+	Retry RetryPolicy
+	// Hedge is the policy of hedging object reads.
+	Hedge HedgePolicy
+	// AttributeLimits restrict attributes set by clients on uploads.
+	AttributeLimits AttributeLimits
+	// Scanned reports whether uploads to the container (by name as it's
+	// specified in request path or by ID) are scanned by antivirus, it's nil
+	// if scanning is disabled. Handlers not scanning payloads refuse them.
+	Scanned func(scid string, cnrID *cid.ID) bool
+	// Epochs keeps the network state, it's nil if the state is requested
+	// every time.
+	Epochs *EpochTracker
 }
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	poolReleaseKey  = "__pool_release"
+	poolOverrideKey = "__pool_override"
+)
+
+// poolGeneration is a connection pool with the number of requests using it.
+type poolGeneration struct {
+	pool *pool.Pool
+	wg   sync.WaitGroup
+}
+
+// poolRelease is stored in request user values, fasthttp closes them after
+// the response is sent, so the pool is released then.
+type poolRelease struct {
+	once sync.Once
+	gen  *poolGeneration
+}
+
+func (r *poolRelease) Close() error {
+	r.once.Do(r.gen.wg.Done)
+	return nil
+}
+
+// PoolHolder keeps the connection pool which can be replaced (e.g. when the
+// gateway key is rotated) without interrupting requests using the previous one.
+type PoolHolder struct {
+	mu  sync.RWMutex
+	gen *poolGeneration
+
+	// closePool is replaced in tests.
+	closePool func(*pool.Pool)
+}
+
+// NewPoolHolder creates PoolHolder with the pool given.
+func NewPoolHolder(p *pool.Pool) *PoolHolder {
+	return &PoolHolder{
+		gen:       &poolGeneration{pool: p},
+		closePool: (*pool.Pool).Close,
+	}
+}
+
+// Pool returns the current pool for the operations not bound to requests.
+func (h *PoolHolder) Pool() *pool.Pool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.gen.pool
+}
+
+// Acquire returns the pool for the request, the same pool is returned for
+// the request until the response is sent.
+func (h *PoolHolder) Acquire(c *fasthttp.RequestCtx) *pool.Pool {
+	if p, ok := c.UserValue(poolOverrideKey).(*pool.Pool); ok {
+		return p
+	}
+	if r, ok := c.UserValue(poolReleaseKey).(*poolRelease); ok {
+		return r.gen.pool
+	}
+
+	h.mu.RLock()
+	gen := h.gen
+	gen.wg.Add(1)
+	h.mu.RUnlock()
+
+	c.SetUserValue(poolReleaseKey, &poolRelease{gen: gen})
+	return gen.pool
+}
+
+// SetRequestPool makes Acquire return the pool given for the request instead
+// of the current one, e.g. the pool of a particular node.
+func SetRequestPool(c *fasthttp.RequestCtx, p *pool.Pool) {
+	c.SetUserValue(poolOverrideKey, p)
+}
+
+// HasRequestPool checks whether the pool of the request is set with
+// SetRequestPool.
+func HasRequestPool(c *fasthttp.RequestCtx) bool {
+	_, ok := c.UserValue(poolOverrideKey).(*pool.Pool)
+	return ok
+}
+
+// Replace makes the pool given the current one. The previous pool is closed
+// after the requests using it are finished or the drain timeout expires, the
+// returned channel is closed then. The holder created without a pool (e.g.
+// if NeoFS is unavailable at startup) gets it this way too.
+func (h *PoolHolder) Replace(p *pool.Pool, drainTimeout time.Duration) <-chan struct{} {
+	h.mu.Lock()
+	old := h.gen
+	h.gen = &poolGeneration{pool: p}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		drained := make(chan struct{})
+		go func() {
+			old.wg.Wait()
+			close(drained)
+		}()
+
+		select {
+		case <-drained:
+		case <-time.After(drainTimeout):
+		}
+
+		if old.pool != nil {
+			h.closePool(old.pool)
+		}
+		close(done)
+	}()
+
+	return done
+}
+
+// NetworkInfo requests network information with the current pool.
+func (h *PoolHolder) NetworkInfo(ctx context.Context) (*netmap.NetworkInfo, error) {
+	return h.Pool().NetworkInfo(ctx)
+}
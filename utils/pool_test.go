@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestPoolHolder(t *testing.T) {
+	oldPool, newPool := new(pool.Pool), new(pool.Pool)
+
+	closed := make(chan *pool.Pool, 1)
+	h := NewPoolHolder(oldPool)
+	h.closePool = func(p *pool.Pool) { closed <- p }
+
+	var c fasthttp.RequestCtx
+	require.True(t, h.Acquire(&c) == oldPool)
+
+	done := h.Replace(newPool, time.Hour)
+	require.True(t, h.Pool() == newPool)
+
+	// the request keeps using the previous pool until the response is sent
+	require.True(t, h.Acquire(&c) == oldPool)
+	select {
+	case <-done:
+		t.Fatal("pool is closed while it's used")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.ResetUserValues()
+	<-done
+	require.True(t, <-closed == oldPool)
+
+	var c2 fasthttp.RequestCtx
+	require.True(t, h.Acquire(&c2) == newPool)
+
+	t.Run("drain timeout", func(t *testing.T) {
+		<-h.Replace(oldPool, time.Millisecond)
+		require.True(t, <-closed == newPool)
+	})
+
+	t.Run("without pool", func(t *testing.T) {
+		h := NewPoolHolder(nil)
+		h.closePool = func(p *pool.Pool) { closed <- p }
+
+		<-h.Replace(newPool, time.Hour)
+		require.True(t, h.Pool() == newPool)
+		require.Empty(t, closed)
+	})
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	requestContainerKey = "__request_container"
+	requestObjectsKey   = "__request_objects"
+	storedBytesKey      = "__stored_bytes"
+)
+
+// SetRequestContainer sets the resolved container of the request, or the
+// container created by it.
+func SetRequestContainer(c *fasthttp.RequestCtx, cnrID *cid.ID) {
+	c.SetUserValue(requestContainerKey, cnrID)
+}
+
+// RequestContainer returns the container set with SetRequestContainer, nil
+// if it isn't set.
+func RequestContainer(c *fasthttp.RequestCtx) *cid.ID {
+	cnrID, _ := c.UserValue(requestContainerKey).(*cid.ID)
+	return cnrID
+}
+
+// AddRequestObject records the object stored or deleted by the request.
+func AddRequestObject(c *fasthttp.RequestCtx, id string) {
+	ids, _ := c.UserValue(requestObjectsKey).([]string)
+	c.SetUserValue(requestObjectsKey, append(ids, id))
+}
+
+// RequestObjects returns the objects stored or deleted by the request.
+func RequestObjects(c *fasthttp.RequestCtx) []string {
+	ids, _ := c.UserValue(requestObjectsKey).([]string)
+	return ids
+}
+
+// AddStoredBytes records the payload bytes stored by the request.
+func AddStoredBytes(c *fasthttp.RequestCtx, n int64) {
+	stored, _ := c.UserValue(storedBytesKey).(int64)
+	c.SetUserValue(storedBytesKey, stored+n)
+}
+
+// StoredBytes returns the payload bytes recorded with AddStoredBytes, false
+// is returned if nothing is recorded (e.g. the object is stored in
+// background).
+func StoredBytes(c *fasthttp.RequestCtx) (int64, bool) {
+	stored, ok := c.UserValue(storedBytesKey).(int64)
+	return stored, ok
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RetryPolicy defines how failed NeoFS operations are retried, the zero
+// policy makes a single attempt.
+type RetryPolicy struct {
+	// Retries is the number of attempts after the first one.
+	Retries int
+	// Backoff is the delay before the first retry, it's doubled for every
+	// next one.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between attempts, zero means no limit.
+	MaxBackoff time.Duration
+	// Retryable are substrings of error messages of transient failures, all
+	// errors are retried if it's empty.
+	Retryable []string
+}
+
+// noRetryError is returned by operations which can't be repeated.
+type noRetryError struct{ error }
+
+func (e noRetryError) Unwrap() error { return e.error }
+
+// NoRetry marks the error of the operation as the one not to be retried
+// regardless of the policy, e.g. if the request payload is already consumed.
+func NoRetry(err error) error {
+	if err == nil {
+		return nil
+	}
+	return noRetryError{err}
+}
+
+// retryable reports whether the operation failed with the error can be
+// repeated.
+func (p RetryPolicy) retryable(err error) bool {
+	var nr noRetryError
+	if errors.As(err, &nr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if len(p.Retryable) == 0 {
+		return true
+	}
+	msg := err.Error()
+	for _, s := range p.Retryable {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Do runs the operation retrying it while it fails with retryable errors and
+// the context isn't done. The error of the last attempt is returned.
+func (p RetryPolicy) Do(ctx context.Context, op func() error) error {
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= p.Retries || !p.retryable(err) {
+			var nr noRetryError
+			if errors.As(err, &nr) {
+				return nr.error
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
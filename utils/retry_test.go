@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	errTransient := errors.New("rpc error: code = Unavailable")
+	errNotFound := errors.New("object not found")
+
+	p := RetryPolicy{
+		Retries:   2,
+		Backoff:   time.Millisecond,
+		Retryable: []string{"Unavailable"},
+	}
+
+	run := func(errs ...error) (int, error) {
+		var calls int
+		err := p.Do(context.Background(), func() error {
+			calls++
+			if calls > len(errs) {
+				return nil
+			}
+			return errs[calls-1]
+		})
+		return calls, err
+	}
+
+	calls, err := run(errTransient, errTransient)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	calls, err = run(errTransient, errTransient, errTransient)
+	require.ErrorIs(t, err, errTransient)
+	require.Equal(t, 3, calls)
+
+	calls, err = run(errNotFound)
+	require.ErrorIs(t, err, errNotFound)
+	require.Equal(t, 1, calls)
+
+	calls, err = run(NoRetry(errTransient))
+	require.Equal(t, errTransient, err)
+	require.Equal(t, 1, calls)
+
+	calls, err = run(context.Canceled)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+
+	var zero RetryPolicy
+	var calls0 int
+	err = zero.Do(context.Background(), func() error {
+		calls0++
+		return errTransient
+	})
+	require.ErrorIs(t, err, errTransient)
+	require.Equal(t, 1, calls0)
+}
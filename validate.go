@@ -0,0 +1,311 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neofs-http-gw/resolver"
+	"github.com/nspcc-dev/neofs-http-gw/uploader"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+)
+
+// durationKeys are config keys with durations, they must be valid and not
+// negative.
+var durationKeys = []string{
+	cfgConTimeout,
+	cfgReqTimeout,
+	cfgRebalance,
+	cfgEpochRefreshInterval,
+	cfgUploadQuotaWindow,
+	cfgBalanceRefreshInterval,
+	cfgStartupRetryBackoff,
+	cfgStartupRetryMaxBackoff,
+	cfgWebReadTimeout,
+	cfgWebWriteTimeout,
+	cfgWebWriteChunkTimeout,
+	cfgWebUpload + ".read_timeout",
+	cfgWebUpload + ".write_timeout",
+	cfgWebUpload + ".handler_timeout",
+	cfgWebDownload + ".read_timeout",
+	cfgWebDownload + ".write_timeout",
+	cfgWebDownload + ".handler_timeout",
+	cfgOIDCJWKSRefreshInterval,
+	cfgKeyRotationDrainTimeout,
+	cfgDrainRetryAfter,
+	cfgUploadJobsTTL,
+	cfgContainerCreationWaitTimeout,
+	cfgContainerCreationPollInterval,
+	cfgContainerDeletionWaitTimeout,
+	cfgContainerDeletionPollInterval,
+	cfgContainerEACLWaitTimeout,
+	cfgContainerEACLPollInterval,
+	cfgAntivirusTimeout,
+	cfgWebhooksBackoff,
+	cfgWebhooksMaxBackoff,
+	cfgWebhooksTimeout,
+	cfgNATSTimeout,
+	cfgUploadProgressInterval,
+	cfgCircuitBreakerOpenTimeout,
+	cfgPeerDNSRefreshInterval,
+	cfgPoolRetryBackoff,
+	cfgPoolRetryMaxBackoff,
+	cfgPoolHedgeDelay,
+}
+
+// countKeys are config keys with sizes and counters, they must be integers
+// and not negative.
+var countKeys = []string{
+	cfgWebReadBufferSize,
+	cfgWebWriteBufferSize,
+	cfgSessionExpiration,
+	cfgWebMaxRequestBodySize,
+	cfgWebUploadMaxMemory,
+	cfgConcurrencyUpload,
+	cfgConcurrencyDownload,
+	cfgUploadQuotaBytes,
+	cfgWebhooksRetries,
+	cfgWebhooksQueueSize,
+	cfgNATSQueueSize,
+	cfgCircuitBreakerFailures,
+	cfgPoolRetryRetries,
+	cfgPoolMinHealthyNodes,
+	cfgDownloadFailoverAttempts,
+	cfgUploadAttributesMaxCount,
+	cfgUploadAttributesMaxKeySize,
+	cfgUploadAttributesMaxValueSize,
+	cfgThumbnailsMaxSourceSize,
+	cfgThumbnailsMaxDimension,
+	cfgThumbnailsMaxPixels,
+	cfgThumbnailsCacheSize,
+}
+
+// validateConfig checks the configuration without connecting anywhere and
+// returns all found problems.
+func validateConfig(v *viper.Viper) []string {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(v.GetString(cfgLoggerLevel))); err != nil {
+		report("%s: %v", cfgLoggerLevel, err)
+	}
+
+	for _, key := range durationKeys {
+		if !v.IsSet(key) {
+			continue
+		}
+		if d, err := parseDuration(v.GetString(key)); err != nil {
+			report("%s: invalid duration %q", key, v.GetString(key))
+		} else if d < 0 {
+			report("%s: must not be negative", key)
+		}
+	}
+
+	for _, key := range countKeys {
+		if !v.IsSet(key) {
+			continue
+		}
+		if n, err := strconv.ParseInt(v.GetString(key), 10, 64); err != nil {
+			report("%s: invalid number %q", key, v.GetString(key))
+		} else if n < 0 {
+			report("%s: must not be negative", key)
+		}
+	}
+
+	peers := fetchPeers(v)
+	if len(peers) == 0 && !v.GetBool(cmdMock) {
+		report("%s: no NeoFS peers configured", cfgPeers)
+	}
+	for i, peer := range peers {
+		if err := validatePeerAddress(peer.Address); err != nil {
+			report("%s.%d.address: %v", cfgPeers, i, err)
+		}
+	}
+
+	for _, name := range v.GetStringSlice(cfgResolveOrder) {
+		if name != resolver.NNSResolver && name != resolver.DNSResolver {
+			report("%s: unknown resolver %s", cfgResolveOrder, name)
+		}
+	}
+
+	if path := v.GetString(cfgErrorsHTMLTemplate); path != "" {
+		if _, err := template.ParseFiles(path); err != nil {
+			report("%s: %v", cfgErrorsHTMLTemplate, err)
+		}
+	}
+
+	switch policy := v.GetString(cfgUploadAttributesDuplicates); policy {
+	case "", uploader.DuplicatesReject, uploader.DuplicatesFirst, uploader.DuplicatesLast:
+	default:
+		report("%s: unknown policy %s", cfgUploadAttributesDuplicates, policy)
+	}
+
+	switch output := v.GetString(cfgAuditOutput); output {
+	case "", auditOutputSyslog:
+	case auditOutputFile:
+		if v.GetString(cfgAuditFile) == "" {
+			report("%s: audit log file isn't set", cfgAuditFile)
+		}
+	default:
+		report("%s: unknown output %s", cfgAuditOutput, output)
+	}
+
+	switch mode := v.GetString(cfgPoolDegradedMode); mode {
+	case "", degradedNone, degradedReadOnly, degradedUnavailable:
+	default:
+		report("%s: unknown mode %s", cfgPoolDegradedMode, mode)
+	}
+
+	if v.GetString(cfgLoggerGELFAddress) != "" {
+		switch protocol := v.GetString(cfgLoggerGELFProtocol); protocol {
+		case gelfProtocolUDP, gelfProtocolTCP:
+		default:
+			report("%s: unknown protocol %s", cfgLoggerGELFProtocol, protocol)
+		}
+	}
+
+	if engine := v.GetString(cfgAntivirusEngine); engine != "" {
+		if _, err := uploader.NewScanner(engine, v.GetString(cfgAntivirusAddress), 0); err != nil {
+			report("antivirus: %v", err)
+		}
+	}
+
+	for _, name := range v.GetStringSlice(cfgMiddlewares) {
+		if !knownMiddleware(name) {
+			report("%s: unknown middleware %s", cfgMiddlewares, name)
+		}
+	}
+
+	if secrets, err := readSecrets(v); err != nil {
+		report("secrets: %v", err)
+	} else if err = validateWallet(v, secrets); err != nil {
+		report("wallet: %v", err)
+	}
+
+	for _, err := range validateTLS(v) {
+		report("%v", err)
+	}
+
+	return problems
+}
+
+// parseDuration parses durations like viper does: numbers without units are
+// nanoseconds.
+func parseDuration(s string) (time.Duration, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// validatePeerAddress checks that the address is host:port with optional grpc
+// or grpcs scheme.
+func validatePeerAddress(address string) error {
+	host := address
+	if i := strings.Index(address, "://"); i >= 0 {
+		if scheme := address[:i]; scheme != "grpc" && scheme != "grpcs" {
+			return fmt.Errorf("unsupported scheme %s", scheme)
+		}
+		host = address[i+3:]
+	}
+	if i := strings.LastIndexByte(host, ':'); i <= 0 || i == len(host)-1 {
+		return fmt.Errorf("host:port expected, got %s", address)
+	}
+	return nil
+}
+
+// validateWallet checks that the wallet is readable and has the account used.
+// The account is decrypted only if the passphrase is set, otherwise it's asked
+// interactively at startup.
+func validateWallet(v *viper.Viper, secrets map[string]string) error {
+	walletPath := v.GetString(cmdWallet)
+	if len(walletPath) == 0 {
+		walletPath = v.GetString(cfgWalletPath)
+	}
+	if len(walletPath) == 0 {
+		return nil
+	}
+
+	w, err := wallet.NewWalletFromFile(walletPath)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", walletPath, err)
+	}
+
+	address := v.GetString(cmdAddress)
+	if len(address) == 0 {
+		address = v.GetString(cfgWalletAddress)
+	}
+
+	if pwd, ok := secretValue(v, secrets, cfgWalletPassphrase); ok {
+		_, err = getKeyFromWallet(w, address, &pwd)
+		return err
+	}
+
+	addr := w.GetChangeAddress()
+	if address != "" {
+		if addr, err = flags.ParseAddress(address); err != nil {
+			return fmt.Errorf("invalid address %s", address)
+		}
+	}
+	if w.GetAccount(addr) == nil {
+		return fmt.Errorf("couldn't find wallet account for %s", address)
+	}
+	return nil
+}
+
+// validateTLS checks TLS parameters and key pairs of the listeners.
+func validateTLS(v *viper.Viper) []error {
+	var errs []error
+
+	if _, err := newTLSConfig(
+		v.GetString(cfgTLSMinVersion),
+		v.GetStringSlice(cfgTLSCipherSuites),
+		v.GetStringSlice(cfgTLSCurvePreferences),
+	); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+	if caPath := v.GetString(cfgTLSClientCA); caPath != "" {
+		if _, err := loadCertPool(caPath); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cfgTLSClientCA, err))
+		}
+	}
+	if authType := v.GetString(cfgTLSClientAuth); authType != "" {
+		if _, ok := tlsClientAuthTypes[authType]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown client authentication type %s", cfgTLSClientAuth, authType))
+		}
+	}
+
+	autocert := v.GetBool(cfgTLSAutocertEnabled)
+	for _, srv := range fetchServers(v) {
+		if !srv.TLS.Enabled {
+			continue
+		}
+
+		var err error
+		switch {
+		case (srv.TLS.CertFile == "") != (srv.TLS.KeyFile == ""):
+			err = errors.New("both TLS certificate and key must be set")
+		case srv.TLS.CertFile != "":
+			if _, err = tls.LoadX509KeyPair(srv.TLS.CertFile, srv.TLS.KeyFile); err != nil {
+				err = fmt.Errorf("could not load TLS key pair: %w", err)
+			}
+		case !autocert:
+			err = errors.New("TLS is enabled, but neither certificate nor automatic certificates are configured")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", srv.Address, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	valid := func() *viper.Viper {
+		v := viper.New()
+		v.Set(cfgLoggerLevel, "info")
+		v.Set(cfgPeers+".0.address", "grpcs://s01.neofs.devenv:8080")
+		v.Set(cfgPeers+".1.address", "s02.neofs.devenv:8080")
+		v.Set(cfgReqTimeout, "15s")
+		v.Set(cfgConcurrencyUpload, 10)
+		v.Set(cfgResolveOrder, []string{"nns", "dns"})
+		return v
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.Empty(t, validateConfig(valid()))
+	})
+
+	t.Run("no peers", func(t *testing.T) {
+		v := valid()
+		v.Set(cfgPeers, nil)
+		require.Equal(t, []string{"peers: no NeoFS peers configured"}, validateConfig(v))
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		v := valid()
+		v.Set(cfgLoggerLevel, "verbose")
+		v.Set(cfgPeers+".1.address", "http://s02.neofs.devenv:8080")
+		v.Set(cfgReqTimeout, "15 seconds")
+		v.Set(cfgConTimeout, "-1s")
+		v.Set(cfgConcurrencyUpload, "many")
+		v.Set(cfgResolveOrder, []string{"nns", "ens"})
+		v.Set(cfgSessionExpiration, -1)
+		v.Set(cfgAuditOutput, "kafka")
+
+		require.Len(t, validateConfig(v), 8)
+	})
+
+	t.Run("wallet", func(t *testing.T) {
+		v := valid()
+		v.Set(cfgWalletPath, filepath.Join(t.TempDir(), "wallet.json"))
+		problems := validateConfig(v)
+		require.Len(t, problems, 1)
+		require.Contains(t, problems[0], "wallet: ")
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		v := valid()
+		v.Set(cfgTLSMinVersion, "1.4")
+		v.Set(cfgServer+".0.address", "0.0.0.0:443")
+		v.Set(cfgServer+".0."+cfgTLSEnabled, true)
+		v.Set(cfgServer+".0."+cfgTLSCertFile, "cert.pem")
+		v.Set(cfgServer+".1.address", "0.0.0.0:8443")
+		v.Set(cfgServer+".1."+cfgTLSEnabled, true)
+		v.Set(cfgServer+".1."+cfgTLSCertFile, filepath.Join(t.TempDir(), "cert.pem"))
+		v.Set(cfgServer+".1."+cfgTLSKeyFile, filepath.Join(t.TempDir(), "key.pem"))
+		v.Set(cfgServer+".2.address", "0.0.0.0:9443")
+		v.Set(cfgServer+".2."+cfgTLSEnabled, true)
+
+		require.Len(t, validateConfig(v), 4)
+
+		v.Set(cfgTLSAutocertEnabled, true)
+		require.Len(t, validateConfig(v), 3)
+	})
+}
@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const sdkModule = "github.com/nspcc-dev/neofs-sdk-go"
+
+// versionInfo is the build information returned by /-/version.
+type versionInfo struct {
+	Version    string   `json:"version"`
+	Commit     string   `json:"commit"`
+	GoVersion  string   `json:"go_version"`
+	SDKVersion string   `json:"sdk_version"`
+	Features   []string `json:"features"`
+}
+
+// sdkVersion returns the version of NeoFS SDK module the gateway is built with.
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == sdkModule {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// enabledFeatures lists optional gateway features enabled in config. It's to
+// be called after the application components are initialized.
+func (a *app) enabledFeatures() []string {
+	features := make([]string, 0)
+	add := func(name string, enabled bool) {
+		if enabled {
+			features = append(features, name)
+		}
+	}
+
+	var tls, http2 bool
+	for _, srv := range fetchServers(a.cfg) {
+		tls = tls || srv.TLS.Enabled
+		http2 = http2 || srv.HTTP2
+	}
+
+	add("tls", tls)
+	add("autocert", a.cfg.GetBool(cfgTLSAutocertEnabled))
+	add("http2", http2)
+	add("metrics", a.cfg.GetBool(cmdMetrics))
+	add("pprof", a.cfg.GetBool(cmdPprof))
+	add("zip_compression", a.cfg.GetBool(cfgZipCompression))
+	add("async_uploads", a.cfg.GetBool(cfgUploadJobsEnabled))
+	add("upload_progress", a.cfg.GetBool(cfgUploadProgressEnabled))
+	add("webhooks", a.webhooks != nil)
+	add("nats", a.nats != nil)
+	add("webdav", a.cfg.GetBool(cfgWebDAVEnabled))
+	add("throttle", a.uploadBandwidth != nil || a.downloadBandwidth != nil)
+	add("s3", a.cfg.GetBool(cfgS3Enabled))
+	add("cors", len(a.cfg.GetStringSlice(cfgCORSAllowOrigins)) != 0)
+	add("basic_auth", a.cfg.GetString(cfgBasicAuthUser) != "" || a.cfg.GetString(cfgBasicAuthHtpasswd) != "")
+	add("oidc", a.oidc != nil)
+	add("api_keys", a.apiKeys != nil)
+	add("access_policy", a.accessPolicy != nil)
+	add("identities", a.identities != nil)
+	add("rate_limit", a.rateLimiter != nil)
+	add("upload_quota", a.uploadQuota != nil)
+	add("usage", a.usage != nil)
+	add("audit", a.audit != nil)
+	add("concurrency_limit", a.uploadLimiter != nil || a.downloadLimiter != nil)
+	add("admin_api", a.secret(cfgAdminToken) != "")
+
+	return features
+}
+
+// versionHandler returns the handler of /-/version with the build information
+// collected once.
+func (a *app) versionHandler() fasthttp.RequestHandler {
+	data, err := json.Marshal(versionInfo{
+		Version:    Version,
+		Commit:     Commit,
+		GoVersion:  runtime.Version(),
+		SDKVersion: sdkVersion(),
+		Features:   a.enabledFeatures(),
+	})
+	if err != nil {
+		a.log.Error("could not encode version", zap.Error(err))
+		return func(c *fasthttp.RequestCtx) {
+			response.Error(c, "could not encode version", fasthttp.StatusInternalServerError)
+		}
+	}
+
+	return func(c *fasthttp.RequestCtx) {
+		c.SetContentType("application/json")
+		c.SetBody(data)
+	}
+}
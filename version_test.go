@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestVersionHandler(t *testing.T) {
+	v := viper.New()
+	v.Set(cmdMetrics, true)
+	v.Set(cfgTLSCertificate, "/path/to/cert")
+	v.Set(cfgAdminToken, "secret")
+	a := &app{cfg: v, log: zap.NewNop(), rateLimiter: &rateLimiter{}}
+
+	var c fasthttp.RequestCtx
+	a.versionHandler()(&c)
+	require.Equal(t, "application/json", string(c.Response.Header.ContentType()))
+
+	var info versionInfo
+	require.NoError(t, json.Unmarshal(c.Response.Body(), &info))
+	require.Equal(t, Version, info.Version)
+	require.Equal(t, Commit, info.Commit)
+	require.Equal(t, runtime.Version(), info.GoVersion)
+	require.NotEmpty(t, info.SDKVersion)
+	require.Equal(t, []string{"tls", "metrics", "rate_limit", "admin_api"}, info.Features)
+}
@@ -0,0 +1,89 @@
+package webdav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type (
+	multistatus struct {
+		XMLName   xml.Name      `xml:"D:multistatus"`
+		Namespace string        `xml:"xmlns:D,attr"`
+		Responses []davResponse `xml:"D:response"`
+	}
+
+	davResponse struct {
+		Href     string   `xml:"D:href"`
+		Propstat propstat `xml:"D:propstat"`
+	}
+
+	propstat struct {
+		Prop   prop   `xml:"D:prop"`
+		Status string `xml:"D:status"`
+	}
+
+	prop struct {
+		DisplayName   string       `xml:"D:displayname"`
+		ResourceType  resourceType `xml:"D:resourcetype"`
+		ContentLength string       `xml:"D:getcontentlength,omitempty"`
+		ContentType   string       `xml:"D:getcontenttype,omitempty"`
+		LastModified  string       `xml:"D:getlastmodified,omitempty"`
+		ETag          string       `xml:"D:getetag,omitempty"`
+	}
+
+	resourceType struct {
+		Collection *struct{} `xml:"D:collection"`
+	}
+)
+
+// href returns the escaped URL path of the node in the container. The root
+// has no trailing slash since it's routed without it.
+func href(base string, n node) string {
+	if n.path == "" {
+		return base
+	}
+	segments := strings.Split(n.path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return base + "/" + strings.Join(segments, "/")
+}
+
+func newResponse(base string, n node) davResponse {
+	p := prop{
+		DisplayName: path.Base("/" + strings.TrimSuffix(n.path, "/")),
+	}
+	if n.dir {
+		p.ResourceType.Collection = &struct{}{}
+	} else {
+		p.ContentLength = strconv.FormatUint(n.file.size, 10)
+		p.ContentType = n.file.contentType
+		p.ETag = `"` + n.file.id.String() + `"`
+		if !n.file.modified.IsZero() {
+			p.LastModified = n.file.modified.UTC().Format(http.TimeFormat)
+		}
+	}
+
+	return davResponse{
+		Href:     href(base, n),
+		Propstat: propstat{Prop: p, Status: "HTTP/1.1 200 OK"},
+	}
+}
+
+// encodeMultistatus returns PROPFIND response body with the nodes.
+func encodeMultistatus(base string, nodes []node) ([]byte, error) {
+	ms := multistatus{Namespace: "DAV:", Responses: make([]davResponse, 0, len(nodes))}
+	for _, n := range nodes {
+		ms.Responses = append(ms.Responses, newResponse(base, n))
+	}
+
+	data, err := xml.Marshal(ms)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
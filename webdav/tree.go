@@ -0,0 +1,153 @@
+package webdav
+
+import (
+	"mime"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// attributeFilePath is the attribute objects are mapped to the directory tree
+// by.
+const attributeFilePath = "FilePath"
+
+type (
+	// file is an object with FilePath attribute.
+	file struct {
+		path        string
+		id          oid.ID
+		size        uint64
+		modified    time.Time
+		contentType string
+	}
+
+	// node is a file or a directory in the tree.
+	node struct {
+		// path is relative to the container root, it has no leading slash,
+		// directories have trailing one (except for the root).
+		path string
+		dir  bool
+		file *file
+	}
+)
+
+// newFile returns the file of object, false if it has no FilePath.
+func newFile(id oid.ID, obj *object.Object) (file, bool) {
+	f := file{id: id, size: obj.PayloadSize()}
+
+	for _, attr := range obj.Attributes() {
+		switch attr.Key() {
+		case attributeFilePath:
+			f.path = cleanPath(attr.Value())
+		case object.AttributeTimestamp:
+			if ts, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+				f.modified = time.Unix(ts, 0)
+			}
+		case object.AttributeContentType:
+			f.contentType = attr.Value()
+		}
+	}
+
+	if f.contentType == "" {
+		f.contentType = mime.TypeByExtension(path.Ext(f.path))
+	}
+	if f.contentType == "" {
+		f.contentType = "application/octet-stream"
+	}
+
+	return f, f.path != ""
+}
+
+// cleanPath returns the path relative to the container root.
+func cleanPath(p string) string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// newerThan tells whether the file is to be shown instead of the other one
+// with the same path.
+func (f file) newerThan(other file) bool {
+	if !f.modified.Equal(other.modified) {
+		return f.modified.After(other.modified)
+	}
+	return f.id.String() > other.id.String()
+}
+
+// lookup returns the node at the path, false if there is neither a file nor
+// a directory. Files are to have the path or the directory prefix. The file is
+// preferred if there is a directory with the same path.
+func lookup(files []file, p string) (node, bool) {
+	p = cleanPath(p)
+	if p == "" {
+		return node{dir: true}, true
+	}
+
+	var (
+		f   *file
+		dir bool
+	)
+	for i := range files {
+		switch {
+		case files[i].path == p:
+			if f == nil || files[i].newerThan(*f) {
+				f = &files[i]
+			}
+		case strings.HasPrefix(files[i].path, p+"/"):
+			dir = true
+		}
+	}
+
+	switch {
+	case f != nil:
+		return node{path: p, file: f}, true
+	case dir:
+		return node{path: p + "/", dir: true}, true
+	}
+	return node{}, false
+}
+
+// children returns the nodes in the directory sorted by path, directory
+// markers are skipped. Files are to have the directory prefix.
+func children(files []file, dir string) []node {
+	var (
+		res   []node
+		index = make(map[string]int)
+	)
+	if dir = cleanPath(dir); dir != "" {
+		dir += "/"
+	}
+
+	for i := range files {
+		rest := strings.TrimPrefix(files[i].path, dir)
+		if rest == files[i].path && dir != "" {
+			continue
+		}
+
+		n := node{path: dir + rest, file: &files[i]}
+		if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+			n = node{path: dir + rest[:slash+1], dir: true}
+		} else if rest == dirMarker {
+			continue
+		}
+
+		j, ok := index[n.path]
+		switch {
+		case !ok:
+			index[n.path] = len(res)
+			res = append(res, n)
+		case !res[j].dir && !n.dir && n.file.newerThan(*res[j].file):
+			res[j] = n
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].path < res[j].path })
+	return res
+}
@@ -0,0 +1,79 @@
+package webdav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanPath(t *testing.T) {
+	for in, out := range map[string]string{
+		"":           "",
+		"/":          "",
+		"a/b":        "a/b",
+		"/a/b/":      "a/b",
+		"a//b/../c":  "a/c",
+		"../../etc":  "etc",
+		"./dir/file": "dir/file",
+	} {
+		require.Equal(t, out, cleanPath(in), in)
+	}
+}
+
+func TestTree(t *testing.T) {
+	files := []file{
+		{path: "a/b/c.txt"},
+		{path: "a/d.txt", modified: time.Unix(1, 0)},
+		{path: "a/d.txt", modified: time.Unix(2, 0), size: 2},
+		{path: "ab.txt"},
+		{path: "e.txt"},
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		n, ok := lookup(files, "/")
+		require.True(t, ok)
+		require.True(t, n.dir)
+
+		n, ok = lookup(files, "a")
+		require.True(t, ok)
+		require.Equal(t, node{path: "a/", dir: true}, n)
+
+		n, ok = lookup(files, "a/d.txt")
+		require.True(t, ok)
+		require.False(t, n.dir)
+		require.Equal(t, uint64(2), n.file.size)
+
+		_, ok = lookup(files, "a/b/c")
+		require.False(t, ok)
+	})
+
+	t.Run("children", func(t *testing.T) {
+		paths := func(nodes []node) []string {
+			res := make([]string, 0, len(nodes))
+			for _, n := range nodes {
+				res = append(res, n.path)
+			}
+			return res
+		}
+
+		require.Equal(t, []string{"a/", "ab.txt", "e.txt"}, paths(children(files, "")))
+
+		nodes := children(files, "a/")
+		require.Equal(t, []string{"a/b/", "a/d.txt"}, paths(nodes))
+		require.Equal(t, uint64(2), nodes[1].file.size)
+	})
+
+	t.Run("multistatus", func(t *testing.T) {
+		data, err := encodeMultistatus("/webdav/cid", []node{{dir: true}, {path: "a b/", dir: true}, {path: "a/d.txt", file: &files[2]}})
+		require.NoError(t, err)
+
+		s := string(data)
+		require.Contains(t, s, `<D:multistatus xmlns:D="DAV:">`)
+		require.Contains(t, s, `<D:href>/webdav/cid</D:href>`)
+		require.Contains(t, s, `<D:href>/webdav/cid/a%20b/</D:href>`)
+		require.Contains(t, s, `<D:displayname>a b</D:displayname><D:resourcetype><D:collection></D:collection></D:resourcetype>`)
+		require.Contains(t, s, `<D:getcontentlength>2</D:getcontentlength>`)
+		require.Contains(t, s, `<D:getlastmodified>Thu, 01 Jan 1970 00:00:02 GMT</D:getlastmodified>`)
+	})
+}
@@ -0,0 +1,310 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-http-gw/resolver"
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/tokens"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// Prefix is the path prefix of WebDAV share.
+const Prefix = "/webdav"
+
+// MethodPropfind is the WebDAV method to get resource properties.
+const MethodPropfind = "PROPFIND"
+
+// maxFiles is the maximum number of objects a directory listing is made of.
+const maxFiles = 10000
+
+var errTooManyFiles = errors.New("too many files")
+
+// Handler serves containers as WebDAV shares with directory tree made of
+// FilePath attributes.
+type Handler struct {
+	appCtx            context.Context
+	log               *zap.Logger
+	pool              *utils.PoolHolder
+	containerResolver *resolver.ContainerResolver
+	notifier          utils.EventNotifier
+	attributeLimits   utils.AttributeLimits
+	scanned           func(string, *cid.ID) bool
+	settings          Settings
+}
+
+// Settings are WebDAV share settings.
+type Settings struct {
+	// Writable enables PUT, DELETE, MKCOL and MOVE requests.
+	Writable bool
+}
+
+// request is the WebDAV request to the container.
+type request struct {
+	*fasthttp.RequestCtx
+	ctx      context.Context
+	log      *zap.Logger
+	pool     *pool.Pool
+	cnrID    *cid.ID
+	base     string
+	path     string
+	btoken   *bearer.Token
+	notifier utils.EventNotifier
+}
+
+// New creates a new WebDAV handler.
+func New(ctx context.Context, params *utils.AppParams, settings Settings) *Handler {
+	return &Handler{
+		appCtx:            ctx,
+		log:               params.Logger,
+		pool:              params.Pool,
+		containerResolver: params.Resolver,
+		notifier:          params.Notifier,
+		attributeLimits:   params.AttributeLimits,
+		scanned:           params.Scanned,
+		settings:          settings,
+	}
+}
+
+// Options handles OPTIONS requests advertising WebDAV support.
+func (h *Handler) Options(c *fasthttp.RequestCtx) {
+	c.Response.Header.Set("DAV", "1")
+	allow := "OPTIONS, PROPFIND, GET, HEAD"
+	if h.settings.Writable {
+		allow += ", PUT, DELETE, MKCOL, MOVE"
+	}
+	c.Response.Header.Set(fasthttp.HeaderAllow, allow)
+	c.Response.Header.Set("MS-Author-Via", "DAV")
+	c.SetStatusCode(fasthttp.StatusOK)
+}
+
+func (h *Handler) newRequest(c *fasthttp.RequestCtx) (*request, bool) {
+	var (
+		scid, _ = c.UserValue("cid").(string)
+		p, _    = c.UserValue("path").(string)
+		log     = h.log.With(zap.String("cid", scid), zap.String("path", p))
+		ctx     = utils.RequestContext(c, h.appCtx)
+	)
+
+	p, err := url.PathUnescape(p)
+	if err != nil {
+		log.Error("wrong path", zap.Error(err))
+		response.Error(c, "wrong path", fasthttp.StatusBadRequest)
+		return nil, false
+	}
+
+	cnrID, err := utils.GetContainerID(ctx, scid, h.containerResolver)
+	if err != nil {
+		log.Error("wrong container id", zap.Error(err))
+		response.Error(c, "wrong container id", fasthttp.StatusBadRequest)
+		return nil, false
+	}
+
+	if err = tokens.StoreBearerToken(c); err != nil {
+		log.Error("could not fetch and store bearer token", zap.Error(err))
+		response.Error(c, "could not fetch and store bearer token: "+err.Error(), fasthttp.StatusBadRequest)
+		return nil, false
+	}
+
+	r := &request{
+		RequestCtx: c,
+		ctx:        ctx,
+		log:        log,
+		pool:       h.pool.Acquire(c),
+		cnrID:      cnrID,
+		base:       Prefix + "/" + scid,
+		path:       cleanPath(p),
+		notifier:   h.notifier,
+	}
+	if tkn, err := tokens.LoadBearerToken(c); err == nil {
+		r.btoken = tkn
+	}
+	return r, true
+}
+
+// files returns the files with the path or the directory prefix.
+func (r *request) files() ([]file, error) {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	filters.AddFilter(attributeFilePath, r.path, object.MatchCommonPrefix)
+
+	var prm pool.PrmObjectSearch
+	prm.SetContainerID(*r.cnrID)
+	prm.SetFilters(filters)
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	res, err := r.pool.SearchObjects(r.ctx, prm)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var ids []oid.ID
+	err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return len(ids) > maxFiles
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > maxFiles {
+		return nil, errTooManyFiles
+	}
+
+	files := make([]file, 0, len(ids))
+	for _, id := range ids {
+		obj, err := r.head(id)
+		if err != nil {
+			return nil, err
+		}
+		if f, ok := newFile(id, obj); ok {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (r *request) address(id oid.ID) address.Address {
+	var addr address.Address
+	addr.SetContainerID(*r.cnrID)
+	addr.SetObjectID(id)
+	return addr
+}
+
+func (r *request) head(id oid.ID) (*object.Object, error) {
+	var prm pool.PrmObjectHead
+	prm.SetAddress(r.address(id))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	return r.pool.HeadObject(r.ctx, prm)
+}
+
+// lookup returns the node at the request path, it responds with error if it
+// isn't found.
+func (r *request) lookup() ([]file, node, bool) {
+	files, err := r.files()
+	if err != nil {
+		r.handleError("could not list files", err)
+		return nil, node{}, false
+	}
+
+	n, ok := lookup(files, r.path)
+	if !ok {
+		response.Error(r.RequestCtx, "not found", fasthttp.StatusNotFound)
+		return nil, node{}, false
+	}
+	return files, n, true
+}
+
+func (r *request) handleError(msg string, err error) {
+	r.log.Error(msg, zap.Error(err))
+
+	code := fasthttp.StatusBadGateway
+	switch {
+	case errors.Is(err, errTooManyFiles):
+		code = fasthttp.StatusInsufficientStorage
+	case strings.Contains(err.Error(), "not found"):
+		code = fasthttp.StatusNotFound
+	case strings.Contains(err.Error(), "access denied"):
+		code = fasthttp.StatusForbidden
+	}
+	response.Error(r.RequestCtx, msg+": "+err.Error(), code)
+}
+
+// Propfind handles PROPFIND requests with depth 0 or 1 (infinite depth is
+// served as 1), all properties are returned.
+func (h *Handler) Propfind(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+
+	files, n, ok := r.lookup()
+	if !ok {
+		return
+	}
+
+	nodes := []node{n}
+	if n.dir && string(c.Request.Header.Peek("Depth")) != "0" {
+		nodes = append(nodes, children(files, n.path)...)
+	}
+
+	data, err := encodeMultistatus(r.base, nodes)
+	if err != nil {
+		r.log.Error("could not encode properties", zap.Error(err))
+		response.Error(c, "could not encode properties: "+err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	c.SetStatusCode(fasthttp.StatusMultiStatus)
+	c.SetContentType("application/xml; charset=utf-8")
+	c.SetBody(data)
+}
+
+// Get handles GET and HEAD requests for files.
+func (h *Handler) Get(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+
+	_, n, ok := r.lookup()
+	if !ok {
+		return
+	}
+	if n.dir {
+		response.Error(c, "is a directory", fasthttp.StatusMethodNotAllowed)
+		return
+	}
+
+	c.Response.Header.Set(fasthttp.HeaderContentLength, strconv.FormatUint(n.file.size, 10))
+	c.Response.Header.Set(fasthttp.HeaderETag, `"`+n.file.id.String()+`"`)
+	if !n.file.modified.IsZero() {
+		c.Response.Header.Set(fasthttp.HeaderLastModified, n.file.modified.UTC().Format(http.TimeFormat))
+	}
+	c.SetContentType(n.file.contentType)
+
+	if c.IsHead() {
+		return
+	}
+
+	var prm pool.PrmObjectGet
+	prm.SetAddress(r.address(n.file.id))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(c); key != nil {
+		prm.UseKey(key)
+	}
+
+	res, err := r.pool.GetObject(r.ctx, prm)
+	if err != nil {
+		r.handleError("could not receive object", err)
+		return
+	}
+
+	utils.SetBodyStream(c, res.Payload, int(n.file.size))
+}
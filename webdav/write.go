@@ -0,0 +1,370 @@
+package webdav
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/response"
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/valyala/fasthttp"
+)
+
+// WebDAV methods changing the tree.
+const (
+	MethodMkcol = "MKCOL"
+	MethodMove  = "MOVE"
+)
+
+// dirMarker is the name of empty objects created by MKCOL to keep empty
+// directories, they aren't listed.
+const dirMarker = ".neofs-dir"
+
+// owner returns the owner of objects created by the request.
+func (r *request) owner() user.ID {
+	if r.btoken != nil {
+		issuer, _ := r.btoken.Issuer()
+		return issuer
+	}
+	var owner user.ID
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		user.IDFromKey(&owner, key.PublicKey)
+		return owner
+	}
+	if id := r.pool.OwnerID(); id != nil {
+		owner = *id
+	}
+	return owner
+}
+
+// put stores the object with the attributes, FilePath and FileName are set
+// from the path and Timestamp is set to the current time.
+func (r *request) put(p string, attrs []object.Attribute, payload io.Reader, size int64) (*oid.ID, error) {
+	set := map[string]string{
+		attributeFilePath:         p,
+		object.AttributeFileName:  path.Base(p),
+		object.AttributeTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	attributes := make([]object.Attribute, 0, len(attrs)+len(set))
+	for _, attr := range attrs {
+		if _, ok := set[attr.Key()]; !ok {
+			attributes = append(attributes, attr)
+		}
+	}
+	for key, val := range set {
+		attr := object.NewAttribute()
+		attr.SetKey(key)
+		attr.SetValue(val)
+		attributes = append(attributes, *attr)
+	}
+
+	owner := r.owner()
+	obj := object.New()
+	obj.SetContainerID(*r.cnrID)
+	obj.SetOwnerID(&owner)
+	obj.SetAttributes(attributes...)
+
+	var (
+		prm    pool.PrmObjectPut
+		stored int64
+	)
+	prm.SetHeader(*obj)
+	prm.SetPayload(utils.CountingReader(payload, func(n int) { stored += int64(n) }))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	id, err := r.pool.PutObject(r.ctx, prm)
+	if err == nil {
+		r.notify(utils.EventObjectCreated, *id, size, attributes)
+		utils.AddRequestObject(r.RequestCtx, id.String())
+		utils.AddStoredBytes(r.RequestCtx, stored)
+	}
+	return id, err
+}
+
+// delete removes the files.
+func (r *request) delete(files []file) error {
+	for _, f := range files {
+		var prm pool.PrmObjectDelete
+		prm.SetAddress(r.address(f.id))
+		if r.btoken != nil {
+			prm.UseBearer(*r.btoken)
+		}
+		if key := utils.RequestKey(r.RequestCtx); key != nil {
+			prm.UseKey(key)
+		}
+
+		if err := r.pool.DeleteObject(r.ctx, prm); err != nil {
+			return err
+		}
+
+		attr := object.NewAttribute()
+		attr.SetKey(attributeFilePath)
+		attr.SetValue(f.path)
+		r.notify(utils.EventObjectDeleted, f.id, int64(f.size), []object.Attribute{*attr})
+		utils.AddRequestObject(r.RequestCtx, f.id.String())
+	}
+	return nil
+}
+
+// copy stores the object of the file with the new path.
+func (r *request) copy(f file, p string) error {
+	var prm pool.PrmObjectGet
+	prm.SetAddress(r.address(f.id))
+	if r.btoken != nil {
+		prm.UseBearer(*r.btoken)
+	}
+	if key := utils.RequestKey(r.RequestCtx); key != nil {
+		prm.UseKey(key)
+	}
+
+	res, err := r.pool.GetObject(r.ctx, prm)
+	if err != nil {
+		return err
+	}
+	defer res.Payload.Close()
+
+	_, err = r.put(p, res.Header.Attributes(), res.Payload, int64(f.size))
+	return err
+}
+
+// notify sends the object event if notifications are enabled.
+func (r *request) notify(typ string, id oid.ID, size int64, attributes []object.Attribute) {
+	if r.notifier == nil {
+		return
+	}
+
+	attrs := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		attrs[attr.Key()] = attr.Value()
+	}
+
+	r.notifier.Notify(utils.ObjectEvent{
+		Type:        typ,
+		ContainerID: r.cnrID.String(),
+		ObjectID:    id.String(),
+		Size:        size,
+		Attributes:  attrs,
+		Time:        time.Now(),
+	})
+}
+
+// matching returns the file versions at the path or the files in the
+// directory.
+func matching(files []file, n node) []file {
+	var res []file
+	for _, f := range files {
+		if n.dir && strings.HasPrefix(f.path, n.path) || !n.dir && f.path == n.path {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Put handles PUT requests storing the file, the previous versions of the
+// file are deleted. Payloads aren't scanned by antivirus, so uploads to the
+// containers scanned are refused.
+func (h *Handler) Put(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+	if r.path == "" {
+		response.Error(c, "can't put the root", fasthttp.StatusMethodNotAllowed)
+		return
+	}
+
+	files, err := r.files()
+	if err != nil {
+		r.handleError("could not list files", err)
+		return
+	}
+	n, exists := lookup(files, r.path)
+	if exists && n.dir {
+		response.Error(c, "is a directory", fasthttp.StatusMethodNotAllowed)
+		return
+	}
+	if err = h.validatePath(r.path); err != nil {
+		response.Error(c, "invalid path: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if scid, _ := c.UserValue("cid").(string); h.scanned != nil && h.scanned(scid, r.cnrID) {
+		response.Error(c, "uploads to the container are scanned by antivirus, use upload route",
+			fasthttp.StatusForbidden)
+		return
+	}
+
+	body := c.RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Request.Body())
+	}
+	if _, err = r.put(r.path, nil, body, int64(c.Request.Header.ContentLength())); err != nil {
+		r.handleError("could not store file", err)
+		return
+	}
+
+	if exists {
+		if err = r.delete(matching(files, n)); err != nil {
+			r.handleError("could not delete previous file versions", err)
+			return
+		}
+		c.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+	c.SetStatusCode(fasthttp.StatusCreated)
+}
+
+// validatePath checks the attributes the file at the path is stored with
+// against the limits of attributes set by clients.
+func (h *Handler) validatePath(p string) error {
+	return utils.ValidateAttributes(map[string]string{
+		attributeFilePath:        p,
+		object.AttributeFileName: path.Base(p),
+	}, h.attributeLimits)
+}
+
+// Delete handles DELETE requests removing all versions of the file or all
+// files in the directory.
+func (h *Handler) Delete(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+	if r.path == "" {
+		response.Error(c, "can't delete the root", fasthttp.StatusForbidden)
+		return
+	}
+
+	files, n, ok := r.lookup()
+	if !ok {
+		return
+	}
+
+	if err := r.delete(matching(files, n)); err != nil {
+		r.handleError("could not delete files", err)
+		return
+	}
+	c.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// Mkcol handles MKCOL requests creating directories. NeoFS has no
+// directories, so an empty object is stored in the new one.
+func (h *Handler) Mkcol(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+	if len(c.Request.Body()) != 0 {
+		response.Error(c, "request body isn't supported", fasthttp.StatusUnsupportedMediaType)
+		return
+	}
+
+	files, err := r.files()
+	if err != nil {
+		r.handleError("could not list files", err)
+		return
+	}
+	if _, exists := lookup(files, r.path); exists {
+		response.Error(c, "already exists", fasthttp.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err = r.put(r.path+"/"+dirMarker, nil, bytes.NewReader(nil), 0); err != nil {
+		r.handleError("could not create directory", err)
+		return
+	}
+	c.SetStatusCode(fasthttp.StatusCreated)
+}
+
+// destination returns the path of MOVE destination in the same container.
+func (r *request) destination() (string, bool) {
+	u, err := url.Parse(string(r.Request.Header.Peek("Destination")))
+	if err != nil {
+		return "", false
+	}
+	p := path.Clean("/" + u.Path)
+	if !strings.HasPrefix(p, r.base+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(p, r.base+"/"), true
+}
+
+// Move handles MOVE requests. Objects can't be changed, so they're stored
+// with the new FilePath and the old ones are deleted.
+func (h *Handler) Move(c *fasthttp.RequestCtx) {
+	r, ok := h.newRequest(c)
+	if !ok {
+		return
+	}
+
+	dst, ok := r.destination()
+	if !ok || r.path == "" {
+		response.Error(c, "invalid destination", fasthttp.StatusBadRequest)
+		return
+	}
+	if dst == r.path || strings.HasPrefix(dst, r.path+"/") {
+		response.Error(c, "destination is the source or inside it", fasthttp.StatusForbidden)
+		return
+	}
+
+	files, n, ok := r.lookup()
+	if !ok {
+		return
+	}
+
+	var (
+		overwritten []file
+		status      = fasthttp.StatusCreated
+	)
+	dstFiles, err := r.filesAt(dst)
+	if err != nil {
+		r.handleError("could not list files", err)
+		return
+	}
+	if dstNode, exists := lookup(dstFiles, dst); exists {
+		if string(c.Request.Header.Peek("Overwrite")) == "F" {
+			response.Error(c, "destination exists", fasthttp.StatusPreconditionFailed)
+			return
+		}
+		overwritten = matching(dstFiles, dstNode)
+		status = fasthttp.StatusNoContent
+	}
+
+	moved := matching(files, n)
+	for _, f := range moved {
+		p := dst
+		if n.dir {
+			p = dst + "/" + strings.TrimPrefix(f.path, n.path)
+		}
+		if err = r.copy(f, p); err != nil {
+			r.handleError("could not copy file", err)
+			return
+		}
+	}
+
+	if err = r.delete(append(overwritten, moved...)); err != nil {
+		r.handleError("could not delete moved files", err)
+		return
+	}
+	c.SetStatusCode(status)
+}
+
+// filesAt returns the files with the path or the directory prefix.
+func (r *request) filesAt(p string) ([]file, error) {
+	src := r.path
+	r.path = p
+	defer func() { r.path = src }()
+	return r.files()
+}
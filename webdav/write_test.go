@@ -0,0 +1,60 @@
+package webdav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestDirMarker(t *testing.T) {
+	files := []file{
+		{path: "a/" + dirMarker},
+		{path: "b/" + dirMarker},
+		{path: "b/c.txt"},
+	}
+
+	n, ok := lookup(files, "a")
+	require.True(t, ok)
+	require.Equal(t, node{path: "a/", dir: true}, n)
+	require.Empty(t, children(files, "a"))
+
+	nodes := children(files, "b")
+	require.Len(t, nodes, 1)
+	require.Equal(t, "b/c.txt", nodes[0].path)
+}
+
+func TestMatching(t *testing.T) {
+	files := []file{
+		{path: "a/b.txt"},
+		{path: "a/c/d.txt"},
+		{path: "a.txt"},
+		{path: "a.txt"},
+		{path: "ab.txt"},
+	}
+
+	require.Len(t, matching(files, node{path: "a.txt"}), 2)
+	require.Len(t, matching(files, node{path: "a/", dir: true}), 2)
+	require.Len(t, matching(files, node{dir: true}), len(files))
+}
+
+func TestDestination(t *testing.T) {
+	for dst, expected := range map[string]string{
+		"http://localhost/webdav/cnr/a/b.txt":   "a/b.txt",
+		"/webdav/cnr/a%20b/c.txt":               "a b/c.txt",
+		"http://localhost/webdav/cnr/a/../b":    "b",
+		"http://localhost/webdav/cnr":           "",
+		"http://localhost/webdav/cnr/":          "",
+		"http://localhost/webdav/other/a.txt":   "",
+		"http://localhost/webdav/cnr-x/a.txt":   "",
+		"http://localhost/webdav/cnr/../../etc": "",
+	} {
+		var c fasthttp.RequestCtx
+		c.Request.Header.Set("Destination", dst)
+		r := &request{RequestCtx: &c, base: Prefix + "/cnr"}
+
+		p, ok := r.destination()
+		require.Equal(t, expected != "", ok, dst)
+		require.Equal(t, expected, p, dst)
+	}
+}
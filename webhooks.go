@@ -0,0 +1,197 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// webhookSignatureHeader is the header with HMAC-SHA256 of webhook request
+// body made with the endpoint secret.
+const webhookSignatureHeader = "X-Gateway-Signature"
+
+type (
+	webhookEndpoint struct {
+		URL        string
+		Secret     string
+		Containers []string
+	}
+
+	// webhookQueue is the queue of encoded events of the endpoint, every
+	// endpoint has its own one, so slow endpoints don't delay the others.
+	webhookQueue struct {
+		webhookEndpoint
+		events chan []byte
+	}
+
+	// webhooks posts object events to the configured endpoints in background,
+	// failed requests are retried with exponential backoff.
+	webhooks struct {
+		log        *zap.Logger
+		client     *http.Client
+		queues     []webhookQueue
+		retries    int
+		backoff    time.Duration
+		maxBackoff time.Duration
+	}
+)
+
+func fetchWebhookEndpoints(v *viper.Viper) []webhookEndpoint {
+	var endpoints []webhookEndpoint
+
+	for i := 0; ; i++ {
+		key := cfgWebhooksEndpoints + "." + strconv.Itoa(i) + "."
+
+		url := v.GetString(key + "url")
+		if url == "" {
+			break
+		}
+
+		endpoints = append(endpoints, webhookEndpoint{
+			URL:        url,
+			Secret:     v.GetString(key + "secret"),
+			Containers: v.GetStringSlice(key + "containers"),
+		})
+	}
+
+	return endpoints
+}
+
+// newWebhooks returns nil if there are no webhook endpoints.
+func (a *app) newWebhooks() *webhooks {
+	endpoints := fetchWebhookEndpoints(a.cfg)
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	w := &webhooks{
+		log:        a.log,
+		client:     &http.Client{Timeout: a.cfg.GetDuration(cfgWebhooksTimeout)},
+		retries:    a.cfg.GetInt(cfgWebhooksRetries),
+		backoff:    a.cfg.GetDuration(cfgWebhooksBackoff),
+		maxBackoff: a.cfg.GetDuration(cfgWebhooksMaxBackoff),
+	}
+	for _, e := range endpoints {
+		w.queues = append(w.queues, webhookQueue{
+			webhookEndpoint: e,
+			events:          make(chan []byte, a.cfg.GetInt(cfgWebhooksQueueSize)),
+		})
+	}
+	return w
+}
+
+// Notify implements utils.EventNotifier. Events are dropped if the queue of
+// the endpoint is full.
+func (w *webhooks) Notify(ev utils.ObjectEvent) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		w.log.Error("could not encode webhook event", zap.Error(err))
+		return
+	}
+
+	for _, q := range w.queues {
+		if !q.matches(ev.ContainerID) {
+			continue
+		}
+		select {
+		case q.events <- data:
+		default:
+			w.log.Warn("webhook queue is full, event is dropped", zap.String("url", q.URL),
+				zap.String("cid", ev.ContainerID), zap.String("oid", ev.ObjectID))
+		}
+	}
+}
+
+// run delivers queued events of every endpoint until the context is done.
+func (w *webhooks) run(ctx context.Context) {
+	for _, q := range w.queues {
+		go func(q webhookQueue) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case data := <-q.events:
+					w.deliver(ctx, q.webhookEndpoint, data)
+				}
+			}
+		}(q)
+	}
+}
+
+func (e webhookEndpoint) matches(cnrID string) bool {
+	if len(e.Containers) == 0 {
+		return true
+	}
+	for _, cnr := range e.Containers {
+		if cnr == cnrID {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver posts the event to the endpoint retrying on failures, the backoff
+// is doubled up to the maximum one.
+func (w *webhooks) deliver(ctx context.Context, e webhookEndpoint, data []byte) {
+	backoff := w.backoff
+	for attempt := 0; ; attempt++ {
+		err := w.post(ctx, e, data)
+		if err == nil {
+			return
+		}
+		if attempt >= w.retries {
+			w.log.Error("webhook delivery failed", zap.String("url", e.URL), zap.Error(err))
+			return
+		}
+
+		w.log.Warn("webhook delivery failed, retrying", zap.String("url", e.URL),
+			zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; w.maxBackoff > 0 && backoff > w.maxBackoff {
+			backoff = w.maxBackoff
+		}
+	}
+}
+
+func (w *webhooks) post(ctx context.Context, e webhookEndpoint, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, signWebhook(e.Secret, data))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+func signWebhook(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-http-gw/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWebhooks(t *testing.T) {
+	var (
+		calls  int32
+		events = make(chan utils.ObjectEvent, 1)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, signWebhook("secret", body), r.Header.Get(webhookSignatureHeader))
+
+		var ev utils.ObjectEvent
+		require.NoError(t, json.Unmarshal(body, &ev))
+		events <- ev
+	}))
+	defer srv.Close()
+
+	v := viper.New()
+	v.Set(cfgWebhooksEndpoints+".0.url", srv.URL)
+	v.Set(cfgWebhooksEndpoints+".0.secret", "secret")
+	v.Set(cfgWebhooksEndpoints+".0.containers", []string{"cid"})
+	v.Set(cfgWebhooksRetries, 1)
+	v.Set(cfgWebhooksBackoff, time.Millisecond)
+	v.Set(cfgWebhooksTimeout, time.Second)
+	v.Set(cfgWebhooksQueueSize, 2)
+
+	a := &app{cfg: v, log: zap.NewNop()}
+	require.Nil(t, (&app{cfg: viper.New(), log: zap.NewNop()}).newWebhooks())
+
+	w := a.newWebhooks()
+	require.NotNil(t, w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.run(ctx)
+
+	w.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "other", ObjectID: "skipped"})
+	w.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "cid", ObjectID: "oid", Size: 7})
+
+	select {
+	case ev := <-events:
+		require.Equal(t, "cid", ev.ContainerID)
+		require.Equal(t, "oid", ev.ObjectID)
+		require.Equal(t, int64(7), ev.Size)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook isn't delivered")
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestWebhooksFailingEndpoint(t *testing.T) {
+	var (
+		failed  int32
+		release = make(chan struct{})
+	)
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		atomic.AddInt32(&failed, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	events := make(chan struct{}, 2)
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events <- struct{}{}
+	}))
+	defer up.Close()
+
+	v := viper.New()
+	v.Set(cfgWebhooksEndpoints+".0.url", down.URL)
+	v.Set(cfgWebhooksEndpoints+".1.url", up.URL)
+	v.Set(cfgWebhooksRetries, 10)
+	v.Set(cfgWebhooksBackoff, 10*time.Millisecond)
+	v.Set(cfgWebhooksMaxBackoff, 10*time.Millisecond)
+	v.Set(cfgWebhooksTimeout, 5*time.Second)
+	v.Set(cfgWebhooksQueueSize, 2)
+
+	w := (&app{cfg: v, log: zap.NewNop()}).newWebhooks()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.run(ctx)
+
+	w.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "cid", ObjectID: "first"})
+	w.Notify(utils.ObjectEvent{Type: utils.EventObjectCreated, ContainerID: "cid", ObjectID: "second"})
+
+	// Events are delivered to the second endpoint while the first one hangs.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			t.Fatal("webhook isn't delivered")
+		}
+	}
+	close(release)
+
+	// Retries take 100ms per event with capped backoff.
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&failed) == 22
+	}, 5*time.Second, 10*time.Millisecond)
+}